@@ -31,8 +31,18 @@ type AgentSpec struct {
 	AgentConfig `json:",inline"`
 }
 
+const (
+	// AgentTypeZeroShot parses the actions from the ReAct text generated by the llm
+	AgentTypeZeroShot = "zeroShot"
+	// AgentTypeConversational is like zeroShot, with a prompt for conversations
+	AgentTypeConversational = "conversational"
+	// AgentTypeFunctionCalling passes the tools to the llm and uses the structured tool calls generated by the llm,
+	// falls back to zeroShot if the llm doesn't support tool calling
+	AgentTypeFunctionCalling = "functionCalling"
+)
+
 type AgentConfig struct {
-	// type, can be zeroShot or conversational
+	// type, can be zeroShot, conversational or functionCalling
 	//+kubebuilder:default="zeroShot"
 	Type string `json:"type,omitempty"`
 	// Prompt used to instruct the LLM of agent
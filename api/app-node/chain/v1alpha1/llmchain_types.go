@@ -78,6 +78,9 @@ type Memory struct {
 	// +kubebuilder:validation:Maximum=30
 	// +kubebuilder:default=5
 	ConversionWindowSize *int `json:"conversionWindowSize,omitempty"`
+	// SummaryTokenLimit is the maximum number of tokens to keep the recent turns in memory,
+	// the older turns will be summarized by the llm into a running summary. Can not be used with MaxTokenLimit or ConversionWindowSize.
+	SummaryTokenLimit int `json:"summaryTokenLimit,omitempty"`
 }
 
 // LLMChainStatus defines the observed state of LLMChain
@@ -39,3 +39,8 @@ const (
 func ConversationFilePath(appName string, conversationID string, fileName string) string {
 	return fmt.Sprintf("application/%s/conversation/%s/%s", appName, conversationID, fileName)
 }
+
+// FeedbackFilePath is the path in system storage for the feedback exported from an application
+func FeedbackFilePath(appName string, fileName string) string {
+	return fmt.Sprintf("application/%s/feedback/%s", appName, fileName)
+}
@@ -54,6 +54,19 @@ type ApplicationSpec struct {
 	// +kubebuilder:validation:Minimum:=1
 	// +kubebuilder:default:=60
 	ChatTimeoutSecond float64 `json:"chatTimeoutSecond,omitempty"`
+	// LongTermMemory remembers the facts about a user across the conversations with this application
+	LongTermMemory *LongTermMemory `json:"longTermMemory,omitempty"`
+}
+
+// LongTermMemory is the configuration of the long-term user memory.
+// The facts are extracted from each turn by the llm of the application, stored per user in the default vector store
+// with the default embedder, and the most relevant ones are injected as context on new conversations.
+type LongTermMemory struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// NumFacts is the maximum number of facts injected into a conversation
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=5
+	NumFacts int `json:"numFacts,omitempty"`
 }
 
 // WebConfig is the configuration for web interface
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultBatchRunQuestionColumn is the column of the questions if not set
+	DefaultBatchRunQuestionColumn = "question"
+	// BatchRunIDLayout is the time layout of the run id
+	BatchRunIDLayout = "20060102-150405"
+)
+
+// InputFormat of the question file, detected by the file extension if not set
+func (run ApplicationBatchRun) InputFormat() (BatchRunFileFormat, error) {
+	if run.Spec.Input.Format != "" {
+		return run.Spec.Input.Format, nil
+	}
+	switch strings.ToLower(path.Ext(run.Spec.Input.File)) {
+	case ".csv":
+		return BatchRunFileFormatCSV, nil
+	case ".jsonl":
+		return BatchRunFileFormatJSONL, nil
+	}
+	return "", fmt.Errorf("unknown format of the input file %s, set input.format to csv or jsonl", run.Spec.Input.File)
+}
+
+// QuestionColumn of the question file
+func (run ApplicationBatchRun) QuestionColumn() string {
+	if run.Spec.Input.QuestionColumn == "" {
+		return DefaultBatchRunQuestionColumn
+	}
+	return run.Spec.Input.QuestionColumn
+}
+
+// OutputFormat of the answer file
+func (run ApplicationBatchRun) OutputFormat() BatchRunFileFormat {
+	if run.Spec.Output.Format == "" {
+		return BatchRunFileFormatJSONL
+	}
+	return run.Spec.Output.Format
+}
+
+// OutputObject is the object path of the answer file of a run, in the bucket of the namespace
+func (run ApplicationBatchRun) OutputObject(runID string) string {
+	prefix := run.Spec.Output.Prefix
+	if prefix == "" {
+		prefix = fmt.Sprintf("application-batch-run/%s/", run.Name)
+	}
+	return fmt.Sprintf("%s%s.%s", prefix, runID, run.OutputFormat())
+}
+
+// LastRun is the latest run, nil if never run
+func (run ApplicationBatchRun) LastRun() *BatchRunRecord {
+	if len(run.Status.Runs) == 0 {
+		return nil
+	}
+	return &run.Status.Runs[0]
+}
+
+func (run ApplicationBatchRun) ReadyCondition() Condition {
+	currCon := run.Status.GetCondition(TypeReady)
+	// return current condition if condition not changed
+	if currCon.Status == corev1.ConditionTrue && currCon.Reason == ReasonAvailable {
+		return currCon
+	}
+	return Condition{
+		Type:               TypeReady,
+		Status:             corev1.ConditionTrue,
+		Reason:             ReasonAvailable,
+		Message:            "Check Success",
+		LastTransitionTime: metav1.Now(),
+		LastSuccessfulTime: metav1.Now(),
+	}
+}
+
+// RunningCondition when the questions are being answered
+func (run ApplicationBatchRun) RunningCondition() Condition {
+	currCon := run.Status.GetCondition(TypeReady)
+	// return current condition if condition not changed
+	if currCon.Status == corev1.ConditionFalse && currCon.Reason == "Running" {
+		return currCon
+	}
+	return Condition{
+		Type:               TypeReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             "Running",
+		Message:            "Answering the questions",
+		LastTransitionTime: metav1.Now(),
+		LastSuccessfulTime: currCon.LastSuccessfulTime,
+	}
+}
+
+func (run ApplicationBatchRun) ErrorCondition(msg string) Condition {
+	currCon := run.Status.GetCondition(TypeReady)
+	// return current condition if condition not changed
+	if currCon.Status == corev1.ConditionFalse && currCon.Reason == ReasonUnavailable && currCon.Message == msg {
+		return currCon
+	}
+	return Condition{
+		Type:               TypeReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             ReasonUnavailable,
+		Message:            msg,
+		LastSuccessfulTime: currCon.LastSuccessfulTime,
+		LastTransitionTime: metav1.Now(),
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BatchRunFileFormat is the format of the question file and the answer file
+type BatchRunFileFormat string
+
+const (
+	BatchRunFileFormatCSV   BatchRunFileFormat = "csv"
+	BatchRunFileFormatJSONL BatchRunFileFormat = "jsonl"
+)
+
+// ApplicationBatchRunSpec defines the desired state of ApplicationBatchRun
+type ApplicationBatchRunSpec struct {
+	CommonSpec `json:",inline"`
+
+	// Application to answer the questions, in the same namespace if namespace is not set
+	// +kubebuilder:validation:Required
+	Application *TypedObjectReference `json:"application"`
+
+	// Input is the question file, one question per row
+	// +kubebuilder:validation:Required
+	Input BatchRunInput `json:"input"`
+
+	// Output is where the answers are written
+	// +optional
+	Output BatchRunOutput `json:"output,omitempty"`
+
+	// Concurrency is the number of questions answered at the same time
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=32
+	// +kubebuilder:default:=1
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// TimeoutSeconds is the timeout to answer one question
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=300
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// Schedule is a cron expression(like `0 2 * * *` or `@every 24h`) to run the questions periodically.
+	// The questions are run once if it is empty, and run again when the spec changes.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Suspend stops starting new runs, and cancels the running one
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// HistoryLimit is the number of runs kept in status
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=10
+	HistoryLimit int `json:"historyLimit,omitempty"`
+}
+
+// BatchRunInput is a csv or jsonl question file
+type BatchRunInput struct {
+	// Source is the Datasource(OSS only) or VersionedDataset which has the file
+	// +kubebuilder:validation:Required
+	Source *TypedObjectReference `json:"source"`
+
+	// File is the object path in the bucket of the Datasource, or the file path in the VersionedDataset
+	// +kubebuilder:validation:Required
+	File string `json:"file"`
+
+	// Format of the file, which is detected by the file extension if not set
+	// +kubebuilder:validation:Enum=csv;jsonl
+	// +optional
+	Format BatchRunFileFormat `json:"format,omitempty"`
+
+	// QuestionColumn is the csv column or the jsonl field of the question,
+	// the other columns are copied to the answer file, like the expected answers for regression
+	// +kubebuilder:default:=question
+	QuestionColumn string `json:"questionColumn,omitempty"`
+}
+
+// BatchRunOutput is the answer file stored in the bucket of the namespace in the system datasource
+type BatchRunOutput struct {
+	// Format of the answer file
+	// +kubebuilder:validation:Enum=csv;jsonl
+	// +kubebuilder:default:=jsonl
+	Format BatchRunFileFormat `json:"format,omitempty"`
+
+	// Prefix of the answer files, `application-batch-run/<name>/` by default.
+	// Each run writes the answers to `<prefix><run id>.<format>`
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// BatchRunPhase is the phase of a run
+type BatchRunPhase string
+
+const (
+	BatchRunPhaseRunning   BatchRunPhase = "Running"
+	BatchRunPhaseSucceeded BatchRunPhase = "Succeeded"
+	BatchRunPhaseFailed    BatchRunPhase = "Failed"
+	// BatchRunPhaseCancelled means the run is stopped as the ApplicationBatchRun is suspended or its spec is changed
+	BatchRunPhaseCancelled BatchRunPhase = "Cancelled"
+)
+
+// BatchRunFailure is a question failed to answer
+type BatchRunFailure struct {
+	// Row of the question in the input file, starting from 1
+	Row int `json:"row"`
+	// Error of the question
+	Error string `json:"error"`
+}
+
+// BatchRunRecord is the observed state of a run
+type BatchRunRecord struct {
+	// ID of the run, which is the time it starts at, like `20240101-020000`
+	ID string `json:"id"`
+
+	// Phase of the run. The run succeeds if the answer file is written, even if some questions failed
+	Phase BatchRunPhase `json:"phase,omitempty"`
+
+	// Total questions in the input file
+	Total int `json:"total,omitempty"`
+	// Succeeded questions which are answered
+	Succeeded int `json:"succeeded,omitempty"`
+	// Failed questions
+	Failed int `json:"failed,omitempty"`
+
+	// Progress in percentage
+	Progress int `json:"progress,omitempty"`
+
+	// Output is the answer file, in `<bucket>/<object>` format
+	Output string `json:"output,omitempty"`
+
+	// Failures are the first failed questions
+	Failures []BatchRunFailure `json:"failures,omitempty"`
+
+	// Message about the run, like the error when failed
+	Message string `json:"message,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ApplicationBatchRunStatus defines the observed state of ApplicationBatchRun
+type ApplicationBatchRunStatus struct {
+	// ConditionedStatus is the current status
+	ConditionedStatus `json:",inline"`
+
+	// ObservedGeneration is the generation of the spec of the last run
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Runs are the latest runs, the newest first
+	// +optional
+	Runs []BatchRunRecord `json:"runs,omitempty"`
+
+	// NextRunTime is the time of the next scheduled run
+	// +optional
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="application",type=string,JSONPath=`.spec.application.name`
+//+kubebuilder:printcolumn:name="schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.runs[0].phase`
+//+kubebuilder:printcolumn:name="progress",type=integer,JSONPath=`.status.runs[0].progress`
+
+// ApplicationBatchRun runs the questions in a file through an application, once or periodically,
+// and writes the answers with references, latency and token usage to the system datasource.
+type ApplicationBatchRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationBatchRunSpec   `json:"spec,omitempty"`
+	Status ApplicationBatchRunStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApplicationBatchRunList contains a list of ApplicationBatchRun
+type ApplicationBatchRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationBatchRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApplicationBatchRun{}, &ApplicationBatchRunList{})
+}
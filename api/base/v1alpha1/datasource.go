@@ -41,6 +41,7 @@ const (
 	DatasourceTypeRDMA       DatasourceType = "RDMA"
 	DatasourceTypePostgreSQL DatasourceType = "postgresql"
 	DatasourceTypeWeb        DatasourceType = "web"
+	DatasourceTypeGit        DatasourceType = "git"
 	DatasourceTypeUnknown    DatasourceType = "unknown"
 )
 
@@ -54,11 +55,29 @@ func (ds DatasourceSpec) Type() DatasourceType {
 		return DatasourceTypePostgreSQL
 	case ds.Web != nil:
 		return DatasourceTypeWeb
+	case ds.Git != nil:
+		return DatasourceTypeGit
 	default:
 		return DatasourceTypeUnknown
 	}
 }
 
+// IsWebCrawler returns true if this datasource crawls a website recursively
+func (ds DatasourceSpec) IsWebCrawler() bool {
+	return ds.Web != nil && ds.Web.Crawler != nil
+}
+
+// TracksBranch returns true if the git repository follows the head of a branch instead of a tag or commit
+func (git *Git) TracksBranch() bool {
+	return git != nil && git.Tag == "" && git.Commit == ""
+}
+
+// WebCrawlObjectPrefix is the object prefix of crawled pages in the system datasource.
+// The bucket is the datasource's namespace.
+func (datasource Datasource) WebCrawlObjectPrefix() string {
+	return "web/" + datasource.Name + "/"
+}
+
 func (datasource Datasource) ReadyCondition() Condition {
 	currCon := datasource.Status.GetCondition(TypeReady)
 	// return current condition if condition not changed
@@ -95,3 +114,16 @@ func (datasource Datasource) ErrorCondition(msg string) Condition {
 		LastTransitionTime: metav1.Now(),
 	}
 }
+
+// IsQA returns true when rows are rendered to QA pairs
+func (source PostgreSQLSource) IsQA() bool {
+	return source.QuestionColumn != "" && source.AnswerColumn != ""
+}
+
+// ObjectName returns the object name of this source
+func (source PostgreSQLSource) ObjectName() string {
+	if source.IsQA() {
+		return source.Name + ".csv"
+	}
+	return source.Name + ".txt"
+}
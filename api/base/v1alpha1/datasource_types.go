@@ -38,6 +38,9 @@ type DatasourceSpec struct {
 
 	// Web defines info for web resources
 	Web *Web `json:"web,omitempty"`
+
+	// Git defines info for git repository
+	Git *Git `json:"git,omitempty"`
 }
 
 type RDMA struct {
@@ -83,6 +86,44 @@ type PostgreSQL struct {
 	TargetSessionAttrs string `json:"PGTARGETSESSIONATTRS,omitempty"`
 	Service            string `json:"PGSERVICE,omitempty"`
 	ServiceFile        string `json:"PGSERVICEFILE,omitempty"`
+
+	// Sources exposes tables, views or sql queries as ingestible objects.
+	// Each source is listed as an object named `<name>.txt`,
+	// or `<name>.csv` when it is rendered to QA pairs.
+	Sources []PostgreSQLSource `json:"sources,omitempty"`
+}
+
+// PostgreSQLSource defines how rows of a table, view or query are rendered to a document
+type PostgreSQLSource struct {
+	// Name of this source, used as the object name
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	Name string `json:"name"`
+
+	// Table is the table or view to read, can be qualified with a schema like `public.products`.
+	// Only one of Table and Query can be set.
+	Table string `json:"table,omitempty"`
+
+	// Query is a saved sql query whose result rows are read
+	Query string `json:"query,omitempty"`
+
+	// Columns to be selected from Table, all columns if empty
+	Columns []string `json:"columns,omitempty"`
+
+	// Template is a go text/template to render one row into text, the row columns can be referred by name like `{{.title}}`.
+	// Rows are rendered as `column: value` lines if empty.
+	Template string `json:"template,omitempty"`
+
+	// QuestionColumn and AnswerColumn render rows into QA pairs instead of text
+	QuestionColumn string `json:"questionColumn,omitempty"`
+	AnswerColumn   string `json:"answerColumn,omitempty"`
+
+	// UpdatedAtColumn is a timestamp column which records the last modification time of a row.
+	// When set, the object version follows the latest modification.
+	UpdatedAtColumn string `json:"updatedAtColumn,omitempty"`
+
+	// KeyColumn identifies a row, like the primary key.
+	// When set together with UpdatedAtColumn, only changed rows are synced and the previous content of them is replaced.
+	KeyColumn string `json:"keyColumn,omitempty"`
 }
 
 const (
@@ -96,12 +137,105 @@ const (
 type Web struct {
 	// RecommendIntervalTime is the recommended interval time for this crawler
 	RecommendIntervalTime int `json:"recommendIntervalTime,omitempty"`
+
+	// Crawler enables crawling the website recursively.
+	// Crawled pages are stored in the system datasource under the bucket of this datasource's namespace.
+	Crawler *WebCrawler `json:"crawler,omitempty"`
 }
 
+// WebCrawler defines how to crawl a website recursively
+type WebCrawler struct {
+	// StartURLs are the urls where the crawler begins. Defaults to `endpoint.url`
+	StartURLs []string `json:"startURLs,omitempty"`
+
+	// AllowedDomains restricts the domains which can be crawled. Defaults to the domains of start urls
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// IncludePatterns are regular expressions. If set, only urls matching one of them will be crawled
+	IncludePatterns []string `json:"includePatterns,omitempty"`
+
+	// ExcludePatterns are regular expressions. Urls matching one of them will not be crawled
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+
+	// MaxDepth is the max link depth from start urls. 0 means no limit
+	// +kubebuilder:default=3
+	MaxDepth int `json:"maxDepth,omitempty"`
+
+	// MaxPages is the max number of pages stored in one crawl. 0 means no limit
+	MaxPages int `json:"maxPages,omitempty"`
+
+	// Sitemap enables seeding the crawler with urls from sitemap.xml of each start url's host
+	Sitemap bool `json:"sitemap,omitempty"`
+
+	// IgnoreRobotsTxt disables the robots.txt check
+	IgnoreRobotsTxt bool `json:"ignoreRobotsTxt,omitempty"`
+
+	// Delay is the politeness delay in milliseconds between requests to the same domain
+	// +kubebuilder:default=1000
+	Delay int `json:"delay,omitempty"`
+
+	// Schedule is a cron expression(like `0 */6 * * *` or `@every 12h`) to re-crawl the website.
+	// The website will be crawled only once if empty.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// Git defines info for a git repository. The repository url is `endpoint.url`.
+//
+// Credentials are stored in the secret pointed to by `endpoint.authSecret`:
+// - `username` and `password`(or a token) for http(s) repositories
+// - `ssh-privatekey` and optional `known_hosts` for ssh repositories
+type Git struct {
+	// Branch to checkout. Defaults to the default branch of the repository
+	Branch string `json:"branch,omitempty"`
+
+	// Tag to checkout. Tag takes precedence over branch
+	Tag string `json:"tag,omitempty"`
+
+	// Commit pins the repository to a commit. Commit takes precedence over tag and branch
+	Commit string `json:"commit,omitempty"`
+
+	// Paths are glob patterns(like `docs/**/*.md`) of files to expose. All files are exposed if empty
+	Paths []string `json:"paths,omitempty"`
+}
+
+const (
+	GitUsername      = "username"
+	GitPassword      = "password"
+	GitSSHPrivateKey = "ssh-privatekey"
+	GitKnownHosts    = "known_hosts"
+)
+
 // DatasourceStatus defines the observed state of Datasource
 type DatasourceStatus struct {
 	// ConditionedStatus is the current status
 	ConditionedStatus `json:",inline"`
+
+	// Crawl is the status of the latest web crawl
+	Crawl *WebCrawlStatus `json:"crawl,omitempty"`
+
+	// Commit is the resolved git commit of a git datasource
+	Commit string `json:"commit,omitempty"`
+}
+
+// WebCrawlStatus defines the observed state of a web crawler
+type WebCrawlStatus struct {
+	// ObservedGeneration is the generation of datasource used by the latest crawl
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastCrawlTime is the time when the latest crawl finished
+	LastCrawlTime metav1.Time `json:"lastCrawlTime,omitempty"`
+
+	// NextCrawlTime is the time when the next crawl will start
+	NextCrawlTime *metav1.Time `json:"nextCrawlTime,omitempty"`
+
+	// Pages is the number of pages stored in the latest crawl
+	Pages int `json:"pages,omitempty"`
+
+	// ChangedPages is the number of new or changed pages in the latest crawl
+	ChangedPages int `json:"changedPages,omitempty"`
+
+	// Message is the error message of the latest crawl if failed
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -88,6 +88,8 @@ func (e Embedder) Get3rdPartyModels() []string {
 		return embeddings.OpenAIModels
 	case embeddings.Gemini:
 		return embeddings.GeminiModels
+	case embeddings.DashScope:
+		return embeddings.DashScopeModels
 	}
 
 	return []string{}
@@ -38,6 +38,18 @@ type EmbedderSpec struct {
 	// Models provided by this LLM
 	// If not set,we will use default model list based on LLMType
 	Models []string `json:"models,omitempty"`
+
+	// RateLimit limits the calls to this embedding service
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+}
+
+// RateLimit limits the calls to a model service. 0 means no limit.
+type RateLimit struct {
+	// RequestsPerMinute is the max number of requests per minute
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	// TokensPerMinute is the max number of tokens per minute.
+	// Tokens are estimated from the length of texts.
+	TokensPerMinute int `json:"tokensPerMinute,omitempty"`
 }
 
 // EmbeddingsStatus defines the observed state of Embedder
@@ -24,9 +24,16 @@ func (kb *KnowledgeBase) IsTypeConversation() bool {
 const (
 	// UpdateSourceFileAnnotationKey is the key of the update source file annotation
 	UpdateSourceFileAnnotationKey = Group + "/update-source-file-time"
+	// UpdateSourceFileForFailed as the value of UpdateSourceFileAnnotationKey only retries the failed files
+	UpdateSourceFileForFailed = "for-failed"
+	// UpdateSourceFileForChanged as the value of UpdateSourceFileAnnotationKey rechecks the processed files,
+	// only files whose checksum changed will be embedded again
+	UpdateSourceFileForChanged    = "for-changed"
 	DefaultChunkSize              = 300
 	DefaultChunkOverlap           = 10
 	DefaultBatchSize              = 10
+	DefaultDeduplicationThreshold = 0.9
+	DefaultMaxAttempts            = 3
 )
 
 func (kb *KnowledgeBase) EmbeddingOptions() EmbeddingOptions {
@@ -40,6 +47,22 @@ func (kb *KnowledgeBase) EmbeddingOptions() EmbeddingOptions {
 	if kb.Spec.EmbeddingOptions.BatchSize == 0 {
 		options.BatchSize = DefaultBatchSize
 	}
+	if kb.Spec.EmbeddingOptions.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if kb.Spec.EmbeddingOptions.Deduplication != nil {
+		dedup := *kb.Spec.EmbeddingOptions.Deduplication
+		if dedup.Policy == "" {
+			dedup.Policy = DeduplicationPolicySkip
+		}
+		if dedup.Algorithm == "" {
+			dedup.Algorithm = DeduplicationAlgorithmMinHash
+		}
+		if dedup.Threshold <= 0 || dedup.Threshold > 1 {
+			dedup.Threshold = DefaultDeduplicationThreshold
+		}
+		options.Deduplication = &dedup
+	}
 	return options
 }
 
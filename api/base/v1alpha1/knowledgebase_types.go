@@ -51,6 +51,50 @@ type EmbeddingOptions struct {
 	// BatchSize for text splitter
 	// +kubebuilder:default=10
 	BatchSize int `json:"batchSize,omitempty"`
+	// Deduplication detects duplicated chunks across the whole knowledgebase before embedding
+	Deduplication *Deduplication `json:"deduplication,omitempty"`
+	// MaxAttempts is the max number of attempts to process a file when transient errors happen,
+	// like rate limited or unavailable embedding service.
+	// +kubebuilder:default=3
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+}
+
+// DeduplicationPolicy defines how to handle the duplicated chunks
+type DeduplicationPolicy string
+
+const (
+	// DeduplicationPolicySkip drops the duplicated chunks
+	DeduplicationPolicySkip DeduplicationPolicy = "Skip"
+	// DeduplicationPolicyMerge drops the duplicated chunks and records their files in the kept chunk's metadata `duplicate_files`
+	DeduplicationPolicyMerge DeduplicationPolicy = "Merge"
+)
+
+// DeduplicationAlgorithm defines the algorithm to detect near-duplicated chunks
+type DeduplicationAlgorithm string
+
+const (
+	// DeduplicationAlgorithmNone only detects exact duplicates
+	DeduplicationAlgorithmNone DeduplicationAlgorithm = "None"
+	// DeduplicationAlgorithmMinHash estimates the jaccard similarity of chunks
+	DeduplicationAlgorithmMinHash DeduplicationAlgorithm = "MinHash"
+	// DeduplicationAlgorithmSimHash estimates the cosine similarity of chunks
+	DeduplicationAlgorithmSimHash DeduplicationAlgorithm = "SimHash"
+)
+
+// Deduplication defines how to detect and handle the duplicated chunks.
+// Exact duplicates(same content after lowercasing and collapsing spaces) are always detected.
+type Deduplication struct {
+	// Policy to handle the duplicated chunks
+	// +kubebuilder:validation:Enum=Skip;Merge
+	// +kubebuilder:default=Skip
+	Policy DeduplicationPolicy `json:"policy,omitempty"`
+	// Algorithm to detect near-duplicated chunks
+	// +kubebuilder:validation:Enum=None;MinHash;SimHash
+	// +kubebuilder:default=MinHash
+	Algorithm DeduplicationAlgorithm `json:"algorithm,omitempty"`
+	// Threshold is the similarity in (0, 1] above which two chunks are near-duplicated
+	// +kubebuilder:default=0.9
+	Threshold float64 `json:"threshold,omitempty"`
 }
 
 type FileGroupDetail struct {
@@ -91,6 +135,12 @@ type FileDetails struct {
 
 	// Version file version
 	Version string `json:"version,omitempty"`
+
+	// DuplicateCount defines the number of chunks in the file skipped or merged as duplicates
+	DuplicateCount int `json:"duplicateCount,omitempty"`
+
+	// Attempts defines the number of attempts to process the file
+	Attempts int `json:"attempts,omitempty"`
 }
 
 type FileProcessPhase string
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,6 +44,16 @@ func (llmStatus LLMStatus) LLMReady() (string, bool) {
 	return "", true
 }
 
+// HasCapability checks whether the models of this llm have the capability
+func (llm LLM) HasCapability(capability LLMCapability) bool {
+	for _, c := range llm.Spec.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLLMBaseURL returns the llm's url
 func (llm LLM) Get3rdPartyLLMBaseURL() string {
 	return llm.Spec.Endpoint.URL
@@ -50,6 +61,9 @@ func (llm LLM) Get3rdPartyLLMBaseURL() string {
 
 // GetModelList returns a model list provided by this LLM based on different provider
 func (llm LLM) GetModelList() []string {
+	if llm.Spec.Type == llms.Composite {
+		return llm.GetCompositeModels()
+	}
 	switch llm.Spec.Provider.GetType() {
 	case ProviderTypeWorker:
 		return llm.GetWorkerModels()
@@ -65,6 +79,10 @@ func (llm LLM) GetWorkerModels() []string {
 	ownerObj := llm.GetOwnerReferences()
 	if len(ownerObj) > 0 {
 		if ownerObj[0].Kind == "Worker" {
+			// models of the worker and its adapters
+			if len(llm.Spec.Models) != 0 {
+				return llm.Spec.Models
+			}
 			return []string{string(ownerObj[0].UID)}
 		}
 	}
@@ -82,6 +100,11 @@ func (llm LLM) Get3rdPartyModels() []string {
 		return llm.Spec.Models
 	}
 
+	// models discovered from the llm service
+	if len(llm.Status.Models) != 0 {
+		return llm.Status.Models
+	}
+
 	switch llm.Spec.Type {
 	case llms.ZhiPuAI:
 		return llms.ZhiPuAIModels
@@ -89,10 +112,66 @@ func (llm LLM) Get3rdPartyModels() []string {
 		return llms.OpenAIModels
 	case llms.Gemini:
 		return llms.GeminiModels
+	case llms.DashScope:
+		return llms.DashScopeModels
+	case llms.Anthropic:
+		return llms.AnthropicModels
 	}
 	return []string{}
 }
 
+// GetCompositeModels returns the models provided by a composite llm,
+// which are the customized models or the models in the mappings of all backends
+func (llm LLM) GetCompositeModels() []string {
+	if len(llm.Spec.Models) != 0 {
+		return llm.Spec.Models
+	}
+	if llm.Spec.Composite == nil {
+		return []string{}
+	}
+	models := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, backend := range llm.Spec.Composite.Backends {
+		for model := range backend.ModelMapping {
+			if !seen[model] {
+				seen[model] = true
+				models = append(models, model)
+			}
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// GetBackendStatus returns the status of the backend, nil if not found
+func (llmStatus LLMStatus) GetBackendStatus(namespace, name string) *LLMBackendStatus {
+	for i := range llmStatus.Backends {
+		if llmStatus.Backends[i].Namespace == namespace && llmStatus.Backends[i].Name == name {
+			return &llmStatus.Backends[i]
+		}
+	}
+	return nil
+}
+
+// SetBackendStatus adds or updates the status of the backend,
+// the LastTransitionTime is kept if the health status is not changed
+func (llmStatus *LLMStatus) SetBackendStatus(status LLMBackendStatus) {
+	curr := llmStatus.GetBackendStatus(status.Namespace, status.Name)
+	if curr == nil {
+		if status.LastTransitionTime.IsZero() {
+			status.LastTransitionTime = metav1.Now()
+		}
+		llmStatus.Backends = append(llmStatus.Backends, status)
+		return
+	}
+	if curr.Healthy == status.Healthy {
+		status.LastTransitionTime = curr.LastTransitionTime
+	} else if status.LastTransitionTime.IsZero() {
+		status.LastTransitionTime = metav1.Now()
+	}
+	*curr = status
+}
+
 func (llm LLM) ReadyCondition(msg string) Condition {
 	currCon := llm.Status.GetCondition(TypeReady)
 	// return current condition if condition not changed
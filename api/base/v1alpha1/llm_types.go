@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubeagi/arcadia/pkg/llms"
+	"github.com/kubeagi/arcadia/pkg/llms/composite"
 )
 
 // LLMSpec defines the desired state of LLM
@@ -35,12 +36,114 @@ type LLMSpec struct {
 	// Models provided by this LLM
 	// If not set,we will use default model list based on LLMType
 	Models []string `json:"models,omitempty"`
+
+	// Composite defines the backend llms when the type is composite
+	// +optional
+	Composite *CompositeLLMSpec `json:"composite,omitempty"`
+
+	// Capabilities of the models provided by this LLM besides text generation,
+	// the inputs which need a capability not listed here are rejected by the app runtime
+	// +optional
+	Capabilities []LLMCapability `json:"capabilities,omitempty"`
+}
+
+// LLMCapability is a capability of llm models besides text generation
+// +kubebuilder:validation:Enum=vision
+type LLMCapability string
+
+const (
+	// LLMCapabilityVision means the models accept images as input
+	LLMCapabilityVision LLMCapability = "vision"
+)
+
+// CompositeLLMSpec defines how to route requests to multiple llms
+type CompositeLLMSpec struct {
+	// Strategy defines how to choose the backend for each request
+	// +kubebuilder:validation:Enum=failover;weighted;latency
+	// +kubebuilder:default=failover
+	Strategy composite.Strategy `json:"strategy,omitempty"`
+
+	// Backends are the llms behind this composite llm.
+	// For failover strategy, they are tried in order.
+	// +kubebuilder:validation:MinItems=1
+	Backends []LLMBackend `json:"backends"`
+
+	// MaxRetries is the times to retry a backend on rate limiting and server errors before failover to the next one
+	// +kubebuilder:default=1
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+
+	// CircuitBreaker stops sending requests to a backend after continuous failures
+	// +optional
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty"`
+}
+
+// LLMBackend is a llm behind a composite llm
+type LLMBackend struct {
+	// LLM refers to the backend llm, which can't be a composite llm
+	LLM TypedObjectReference `json:"llm"`
+
+	// Model is the default model of this backend.
+	// If not set, the first model of the backend llm is used.
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// Weight is used by the weighted strategy
+	// +kubebuilder:default=1
+	// +optional
+	Weight int `json:"weight,omitempty"`
+
+	// ModelMapping maps the model requested by callers to the model served by this backend
+	// +optional
+	ModelMapping map[string]string `json:"modelMapping,omitempty"`
+}
+
+// CircuitBreaker defines when to open and close the circuit of a backend
+type CircuitBreaker struct {
+	// FailureThreshold is the count of continuous failures to open the circuit
+	// +kubebuilder:default=3
+	// +optional
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// CooldownSeconds is how long the circuit keeps open, after that requests are sent to the backend again
+	// +kubebuilder:default=60
+	// +optional
+	CooldownSeconds int `json:"cooldownSeconds,omitempty"`
 }
 
 // LLMStatus defines the observed state of LLM
 type LLMStatus struct {
 	// ConditionedStatus is the current status
 	ConditionedStatus `json:",inline"`
+
+	// Models are the models discovered from the llm service,
+	// only for the types which support model discovery
+	// +optional
+	Models []string `json:"models,omitempty"`
+
+	// Backends are the health status of the backends of a composite llm
+	// +optional
+	Backends []LLMBackendStatus `json:"backends,omitempty"`
+}
+
+// LLMBackendStatus is the health status of a backend
+type LLMBackendStatus struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Healthy is false if the backend llm is not ready or its circuit is open
+	Healthy bool `json:"healthy"`
+
+	// Message is the reason why the backend is unhealthy
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// CircuitOpenUntil is set when the circuit is opened because of continuous failures
+	// +optional
+	CircuitOpenUntil *metav1.Time `json:"circuitOpenUntil,omitempty"`
+
+	// LastTransitionTime is the last time the health status changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -33,6 +35,9 @@ const (
 	LabelModelReranking = Group + "/reranking"
 	// LabelModelFullPath indicates the full path in storage
 	LabelModelFullPath = Group + "/full-path"
+	// ModelGitCommitAnnotation records the git commit whose files are copied into the system datasource,
+	// so that the files are only copied again when the commit changes
+	ModelGitCommitAnnotation = Group + "/git-commit"
 )
 
 func (model Model) TypedObjectReference() *TypedObjectReference {
@@ -74,6 +79,23 @@ func (model Model) IsRerankingModel() bool {
 	return false
 }
 
+// IsImported checks whether the model files have been imported into the system datasource from a model hub
+func (model Model) IsImported() bool {
+	return model.Spec.Import != nil && model.Status.Import != nil &&
+		model.Status.Import.Phase == ModelImportPhaseSucceeded && model.Status.Import.Digest == model.ImportDigest()
+}
+
+// ImportDigest of the repo,revision and import spec, which identifies an import
+func (model Model) ImportDigest() string {
+	if model.Spec.Import == nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n", model.Spec.HuggingFaceRepo, model.Spec.ModelScopeRepo, model.Spec.Revision, model.Spec.Import.HubURL)
+	fmt.Fprintf(h, "%s\n%s", strings.Join(model.Spec.Import.Include, ","), strings.Join(model.Spec.Import.Exclude, ","))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
 // FullPath with bucket and object path
 func (model Model) FullPath() string {
 	return fmt.Sprintf("%s/%s", model.Namespace, model.ObjectPath())
@@ -100,6 +122,23 @@ func (model Model) ReadyCondition() Condition {
 	}
 }
 
+// ImportingCondition when the model files are being imported
+func (model Model) ImportingCondition() Condition {
+	currCon := model.Status.GetCondition(TypeReady)
+	// return current condition if condition not changed
+	if currCon.Status == corev1.ConditionFalse && currCon.Reason == "Importing" {
+		return currCon
+	}
+	return Condition{
+		Type:               TypeReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             "Importing",
+		Message:            "Importing model files from model hub",
+		LastTransitionTime: metav1.Now(),
+		LastSuccessfulTime: currCon.LastSuccessfulTime,
+	}
+}
+
 func (model Model) ErrorCondition(msg string) Condition {
 	currCon := model.Status.GetCondition(TypeReady)
 	// return current condition if condition not changed
@@ -47,16 +47,87 @@ type ModelSpec struct {
 
 	// MaxContextLength defines the max context length allowed in this model
 	MaxContextLength int `json:"maxContextLength,omitempty"`
+
+	// Import the model files from huggingFaceRepo or modelScopeRepo into the system datasource,
+	// then workers load the model files from the system datasource instead of the internet.
+	// +optional
+	Import *ModelImport `json:"import,omitempty"`
+}
+
+// ModelImport defines how to import model files from a model hub
+type ModelImport struct {
+	// Include only the files matching these patterns, all files by default.
+	// Patterns are matched against the file path and the file name, like `*.json` or `*Q4_K_M.gguf`
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude the files matching these patterns
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// HubURL to use instead of the official one, like a mirror site
+	// +optional
+	HubURL string `json:"hubURL,omitempty"`
+
+	// TokenSecret is the secret with a `token` to access private or gated repos
+	// +optional
+	TokenSecret *TypedObjectReference `json:"tokenSecret,omitempty"`
+}
+
+// ModelImportPhase is the phase of a model import
+type ModelImportPhase string
+
+const (
+	ModelImportPhaseRunning   ModelImportPhase = "Running"
+	ModelImportPhaseSucceeded ModelImportPhase = "Succeeded"
+	ModelImportPhaseFailed    ModelImportPhase = "Failed"
+)
+
+// ModelImportStatus is the observed state of a model import
+type ModelImportStatus struct {
+	// Phase of the import
+	Phase ModelImportPhase `json:"phase,omitempty"`
+
+	// Commit resolved from the revision, which is the exact version imported
+	Commit string `json:"commit,omitempty"`
+
+	// TotalFiles to import
+	TotalFiles int `json:"totalFiles,omitempty"`
+	// ImportedFiles which are verified and stored
+	ImportedFiles int `json:"importedFiles,omitempty"`
+
+	// TotalSize in bytes to import
+	TotalSize int64 `json:"totalSize,omitempty"`
+	// ImportedSize in bytes
+	ImportedSize int64 `json:"importedSize,omitempty"`
+
+	// Progress in percentage
+	Progress int `json:"progress,omitempty"`
+
+	// Message about the import, like the error when failed
+	Message string `json:"message,omitempty"`
+
+	// Digest of the repo,revision and import spec being imported, the import restarts when it changes
+	Digest string `json:"digest,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // ModelStatus defines the observed state of Model
 type ModelStatus struct {
 	// ConditionedStatus is the current status
 	ConditionedStatus `json:",inline"`
+
+	// Import is the observed state of the model import if configured
+	// +optional
+	Import *ModelImportStatus `json:"import,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="import",type=string,JSONPath=`.status.import.phase`
+//+kubebuilder:printcolumn:name="progress",type=integer,JSONPath=`.status.import.progress`
 
 // Model is the Schema for the models API
 type Model struct {
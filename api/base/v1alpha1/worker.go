@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -31,7 +33,9 @@ const (
 	WorkerTypeFastchatNormal WorkerType = "fastchat"
 	WorkerTypeFastchatVLLM   WorkerType = "fastchat-vllm"
 	WorkerTypeKubeAGI        WorkerType = "kubeagi"
-	WorkerTypeUnknown        WorkerType = "unknown"
+	// WorkerTypeLlamaCpp runs GGUF models on CPUs with llama.cpp
+	WorkerTypeLlamaCpp WorkerType = "llamacpp"
+	WorkerTypeUnknown  WorkerType = "unknown"
 )
 
 const (
@@ -43,6 +47,10 @@ const (
 	WorkerModelTypesLabel  = Group + "/modeltypes"
 
 	DefaultWorkerPort = 21002
+
+	// WorkerRequestsAnnotationPrefix is the prefix of the annotations reported by the callers of a worker, which drive the autoscaling.
+	// Each caller process reports into its own annotation, and the concurrent requests of the worker are the sum of them.
+	WorkerRequestsAnnotationPrefix = Group + "/requests."
 )
 
 func DefaultWorkerType() WorkerType {
@@ -73,11 +81,47 @@ func (worker Worker) Model() TypedObjectReference {
 	}
 }
 
+// DesiredReplicas returns the replicas decided by the autoscaling if configured,otherwise the replicas in spec
+func (worker Worker) DesiredReplicas() *int32 {
+	if worker.Spec.Autoscaling != nil && worker.Status.Autoscaling != nil {
+		return pointer.Int32(worker.Status.Autoscaling.Replicas)
+	}
+	return worker.Spec.Replicas
+}
+
 // MakeRegistrationModelName generates a model name used to register itself into fastchat controller
 func (worker Worker) MakeRegistrationModelName() string {
 	return string(worker.UID)
 }
 
+// Adapter returns the reference to the adapter model with its namespace
+func (worker Worker) Adapter(i int) TypedObjectReference {
+	adapterNs := worker.Namespace
+	if worker.Spec.Adapters[i].Namespace != nil {
+		adapterNs = *worker.Spec.Adapters[i].Namespace
+	}
+	return TypedObjectReference{
+		APIGroup:  pointer.String(GroupVersion.String()),
+		Kind:      "Model",
+		Name:      worker.Spec.Adapters[i].Name,
+		Namespace: &adapterNs,
+	}
+}
+
+// MakeAdapterRegistrationModelName generates a model name used to register the adapter into fastchat controller
+func (worker Worker) MakeAdapterRegistrationModelName(adapter string) string {
+	return fmt.Sprintf("%s-%s", worker.UID, adapter)
+}
+
+// RegistrationModelNames are the model names of this worker and its adapters
+func (worker Worker) RegistrationModelNames() []string {
+	names := []string{worker.MakeRegistrationModelName()}
+	for _, adapter := range worker.Spec.Adapters {
+		names = append(names, worker.MakeAdapterRegistrationModelName(adapter.Name))
+	}
+	return names
+}
+
 func (worker Worker) PendingCondition() Condition {
 	currCon := worker.Status.GetCondition(TypeReady)
 	// return current condition if condition not changed
@@ -136,6 +180,28 @@ func (worker Worker) OfflineCondition() Condition {
 	}
 }
 
+// ScaledCondition keeps this worker ready when it is scaled to zero or being woken up,
+// because the requests will wake it up and wait until it is running.
+func (worker Worker) ScaledCondition(state WorkerScaleState) Condition {
+	currCon := worker.Status.GetCondition(TypeReady)
+	// return current condition if condition not changed
+	if currCon.Status == corev1.ConditionTrue && currCon.Reason == ConditionReason(state) {
+		return currCon
+	}
+	msg := "Worker is scaled to zero and will be woken up by requests"
+	if state == WorkerScaleStateWaking {
+		msg = "Worker is being woken up by requests"
+	}
+	return Condition{
+		Type:               TypeReady,
+		Status:             corev1.ConditionTrue,
+		Reason:             ConditionReason(state),
+		Message:            msg,
+		LastTransitionTime: metav1.Now(),
+		LastSuccessfulTime: currCon.LastSuccessfulTime,
+	}
+}
+
 func (worker Worker) ErrorCondition(msg string) Condition {
 	currCon := worker.Status.GetCondition(TypeReady)
 	// return current condition if condition not changed
@@ -179,7 +245,7 @@ func (worker Worker) BuildEmbedder() *Embedder {
 }
 
 func (worker Worker) BuildLLM() *LLM {
-	return &LLM{
+	llm := &LLM{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: worker.Namespace,
 			Name:      worker.Name,
@@ -197,4 +263,9 @@ func (worker Worker) BuildLLM() *LLM {
 			},
 		},
 	}
+	// the adapters are registered with their own model names
+	if len(worker.Spec.Adapters) > 0 {
+		llm.Spec.Models = worker.RegistrationModelNames()
+	}
+	return llm
 }
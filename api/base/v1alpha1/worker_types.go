@@ -41,11 +41,21 @@ type WorkerSpec struct {
 	// Model this worker wants to use
 	Model *TypedObjectReference `json:"model"`
 
+	// Adapters are the LoRA adapter models of the model, which are served together with the model.
+	// Each adapter is registered with its own model name.Only supported by fastchat-vllm worker now.
+	// +optional
+	Adapters []TypedObjectReference `json:"adapters,omitempty"`
+
 	// Replicas of this worker instance(1 by default)
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Maximum=1
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling scales this worker by the concurrent requests.
+	// Replicas will be ignored when autoscaling is configured.
+	// +optional
+	Autoscaling *WorkerAutoscaling `json:"autoscaling,omitempty"`
+
 	// Resource request&limits including
 	// - CPU or GPU
 	// - Memory
@@ -64,12 +74,78 @@ type WorkerSpec struct {
 	Runner Image `json:"runner,omitempty"`
 }
 
+// WorkerAutoscaling defines how to scale a worker by its concurrent requests
+type WorkerAutoscaling struct {
+	// MinReplicas of this worker.The worker will be scaled to zero when idle if it is 0.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas of this worker
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+
+	// TargetConcurrentRequests is the number of concurrent requests each replica should handle
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	TargetConcurrentRequests int32 `json:"targetConcurrentRequests,omitempty"`
+
+	// IdleTimeout is the duration without any request before scaling to zero.
+	// Only works when minReplicas is 0.(15m by default)
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+}
+
+// WorkerScaleState is the scale state of an autoscaling worker
+type WorkerScaleState string
+
+const (
+	// WorkerScaleStateActive means the worker is running with the desired replicas
+	WorkerScaleStateActive WorkerScaleState = "Active"
+	// WorkerScaleStateScalingUp means the worker is scaling up to handle more requests
+	WorkerScaleStateScalingUp WorkerScaleState = "ScalingUp"
+	// WorkerScaleStateScalingDown means the worker is scaling down as requests decrease
+	WorkerScaleStateScalingDown WorkerScaleState = "ScalingDown"
+	// WorkerScaleStateScaledToZero means the worker is idle and has no replicas
+	WorkerScaleStateScaledToZero WorkerScaleState = "ScaledToZero"
+	// WorkerScaleStateWaking means the worker is woken up by a request and not ready yet
+	WorkerScaleStateWaking WorkerScaleState = "Waking"
+)
+
+// WorkerAutoscalingStatus is the observed state of an autoscaling worker
+type WorkerAutoscalingStatus struct {
+	// ScaleState of this worker
+	ScaleState WorkerScaleState `json:"scaleState,omitempty"`
+
+	// Replicas desired by the autoscaling
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas which are able to handle requests
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// ConcurrentRequests observed most recently
+	ConcurrentRequests int32 `json:"concurrentRequests"`
+
+	// LastRequestTime is the time when the latest request observed
+	// +optional
+	LastRequestTime *metav1.Time `json:"lastRequestTime,omitempty"`
+
+	// LastScaleTime is the time when the replicas changed
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
 // WorkerStatus defines the observed state of Worker
 type WorkerStatus struct {
 	// PodStatus is the observed stated of Worker pod
 	// +optional
 	PodStatus corev1.PodStatus `json:"podStatus,omitempty"`
 
+	// Autoscaling is the observed state of autoscaling if configured
+	// +optional
+	Autoscaling *WorkerAutoscalingStatus `json:"autoscaling,omitempty"`
+
 	// ConditionedStatus is the current status
 	ConditionedStatus `json:",inline"`
 }
@@ -78,6 +154,7 @@ type WorkerStatus struct {
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="type",type=string,JSONPath=`.spec.type`
 //+kubebuilder:printcolumn:name="model",type=string,JSONPath=`.spec.model.name`
+//+kubebuilder:printcolumn:name="scale",type=string,JSONPath=`.status.autoscaling.scaleState`
 
 // Worker is the Schema for the workers API
 type Worker struct {
@@ -24,6 +24,7 @@ package v1alpha1
 import (
 	"github.com/kubeagi/arcadia/pkg/llms/zhipuai"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -54,6 +55,115 @@ func (in *Application) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationBatchRun) DeepCopyInto(out *ApplicationBatchRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationBatchRun.
+func (in *ApplicationBatchRun) DeepCopy() *ApplicationBatchRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationBatchRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationBatchRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationBatchRunList) DeepCopyInto(out *ApplicationBatchRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ApplicationBatchRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationBatchRunList.
+func (in *ApplicationBatchRunList) DeepCopy() *ApplicationBatchRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationBatchRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationBatchRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationBatchRunSpec) DeepCopyInto(out *ApplicationBatchRunSpec) {
+	*out = *in
+	out.CommonSpec = in.CommonSpec
+	if in.Application != nil {
+		in, out := &in.Application, &out.Application
+		*out = new(TypedObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Input.DeepCopyInto(&out.Input)
+	out.Output = in.Output
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationBatchRunSpec.
+func (in *ApplicationBatchRunSpec) DeepCopy() *ApplicationBatchRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationBatchRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationBatchRunStatus) DeepCopyInto(out *ApplicationBatchRunStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]BatchRunRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextRunTime != nil {
+		in, out := &in.NextRunTime, &out.NextRunTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationBatchRunStatus.
+func (in *ApplicationBatchRunStatus) DeepCopy() *ApplicationBatchRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationBatchRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationList) DeepCopyInto(out *ApplicationList) {
 	*out = *in
@@ -98,6 +208,11 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LongTermMemory != nil {
+		in, out := &in.LongTermMemory, &out.LongTermMemory
+		*out = new(LongTermMemory)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
@@ -126,6 +241,84 @@ func (in *ApplicationStatus) DeepCopy() *ApplicationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchRunFailure) DeepCopyInto(out *BatchRunFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchRunFailure.
+func (in *BatchRunFailure) DeepCopy() *BatchRunFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchRunFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchRunInput) DeepCopyInto(out *BatchRunInput) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(TypedObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchRunInput.
+func (in *BatchRunInput) DeepCopy() *BatchRunInput {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchRunInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchRunOutput) DeepCopyInto(out *BatchRunOutput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchRunOutput.
+func (in *BatchRunOutput) DeepCopy() *BatchRunOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchRunOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchRunRecord) DeepCopyInto(out *BatchRunRecord) {
+	*out = *in
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]BatchRunFailure, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchRunRecord.
+func (in *BatchRunRecord) DeepCopy() *BatchRunRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchRunRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Chroma) DeepCopyInto(out *Chroma) {
 	*out = *in
@@ -141,6 +334,21 @@ func (in *Chroma) DeepCopy() *Chroma {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreaker) DeepCopyInto(out *CircuitBreaker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreaker.
+func (in *CircuitBreaker) DeepCopy() *CircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
 	*out = *in
@@ -156,6 +364,33 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeLLMSpec) DeepCopyInto(out *CompositeLLMSpec) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]LLMBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreaker)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeLLMSpec.
+func (in *CompositeLLMSpec) DeepCopy() *CompositeLLMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeLLMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -363,12 +598,17 @@ func (in *DatasourceSpec) DeepCopyInto(out *DatasourceSpec) {
 	if in.PostgreSQL != nil {
 		in, out := &in.PostgreSQL, &out.PostgreSQL
 		*out = new(PostgreSQL)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Web != nil {
 		in, out := &in.Web, &out.Web
 		*out = new(Web)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(Git)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -386,6 +626,11 @@ func (in *DatasourceSpec) DeepCopy() *DatasourceSpec {
 func (in *DatasourceStatus) DeepCopyInto(out *DatasourceStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Crawl != nil {
+		in, out := &in.Crawl, &out.Crawl
+		*out = new(WebCrawlStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasourceStatus.
@@ -398,6 +643,21 @@ func (in *DatasourceStatus) DeepCopy() *DatasourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Deduplication) DeepCopyInto(out *Deduplication) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Deduplication.
+func (in *Deduplication) DeepCopy() *Deduplication {
+	if in == nil {
+		return nil
+	}
+	out := new(Deduplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Embedder) DeepCopyInto(out *Embedder) {
 	*out = *in
@@ -467,6 +727,11 @@ func (in *EmbedderSpec) DeepCopyInto(out *EmbedderSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbedderSpec.
@@ -503,6 +768,11 @@ func (in *EmbeddingOptions) DeepCopyInto(out *EmbeddingOptions) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Deduplication != nil {
+		in, out := &in.Deduplication, &out.Deduplication
+		*out = new(Deduplication)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddingOptions.
@@ -646,6 +916,26 @@ func (in *FileWithVersion) DeepCopy() *FileWithVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Git) DeepCopyInto(out *Git) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Git.
+func (in *Git) DeepCopy() *Git {
+	if in == nil {
+		return nil
+	}
+	out := new(Git)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
@@ -804,6 +1094,49 @@ func (in *LLM) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LLMBackend) DeepCopyInto(out *LLMBackend) {
+	*out = *in
+	in.LLM.DeepCopyInto(&out.LLM)
+	if in.ModelMapping != nil {
+		in, out := &in.ModelMapping, &out.ModelMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLMBackend.
+func (in *LLMBackend) DeepCopy() *LLMBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(LLMBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LLMBackendStatus) DeepCopyInto(out *LLMBackendStatus) {
+	*out = *in
+	if in.CircuitOpenUntil != nil {
+		in, out := &in.CircuitOpenUntil, &out.CircuitOpenUntil
+		*out = (*in).DeepCopy()
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLMBackendStatus.
+func (in *LLMBackendStatus) DeepCopy() *LLMBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LLMBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LLMList) DeepCopyInto(out *LLMList) {
 	*out = *in
@@ -846,6 +1179,16 @@ func (in *LLMSpec) DeepCopyInto(out *LLMSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Composite != nil {
+		in, out := &in.Composite, &out.Composite
+		*out = new(CompositeLLMSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]LLMCapability, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLMSpec.
@@ -862,6 +1205,18 @@ func (in *LLMSpec) DeepCopy() *LLMSpec {
 func (in *LLMStatus) DeepCopyInto(out *LLMStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]LLMBackendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLMStatus.
@@ -874,6 +1229,21 @@ func (in *LLMStatus) DeepCopy() *LLMStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LongTermMemory) DeepCopyInto(out *LongTermMemory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LongTermMemory.
+func (in *LongTermMemory) DeepCopy() *LongTermMemory {
+	if in == nil {
+		return nil
+	}
+	out := new(LongTermMemory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Model) DeepCopyInto(out *Model) {
 	*out = *in
@@ -901,6 +1271,59 @@ func (in *Model) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelImport) DeepCopyInto(out *ModelImport) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(TypedObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelImport.
+func (in *ModelImport) DeepCopy() *ModelImport {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelImportStatus) DeepCopyInto(out *ModelImportStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelImportStatus.
+func (in *ModelImportStatus) DeepCopy() *ModelImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelList) DeepCopyInto(out *ModelList) {
 	*out = *in
@@ -942,6 +1365,11 @@ func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
 		*out = new(TypedObjectReference)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(ModelImport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
@@ -958,6 +1386,11 @@ func (in *ModelSpec) DeepCopy() *ModelSpec {
 func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(ModelImportStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
@@ -1049,6 +1482,13 @@ func (in *PGVector) DeepCopy() *PGVector {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgreSQL) DeepCopyInto(out *PostgreSQL) {
 	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]PostgreSQLSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgreSQL.
@@ -1061,6 +1501,26 @@ func (in *PostgreSQL) DeepCopy() *PostgreSQL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgreSQLSource) DeepCopyInto(out *PostgreSQLSource) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgreSQLSource.
+func (in *PostgreSQLSource) DeepCopy() *PostgreSQLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgreSQLSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Prompt) DeepCopyInto(out *Prompt) {
 	*out = *in
@@ -1213,6 +1673,21 @@ func (in *RDMA) DeepCopy() *RDMA {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypedObjectReference) DeepCopyInto(out *TypedObjectReference) {
 	*out = *in
@@ -1457,6 +1932,11 @@ func (in *VersionedDatasetStatus) DeepCopy() *VersionedDatasetStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Web) DeepCopyInto(out *Web) {
 	*out = *in
+	if in.Crawler != nil {
+		in, out := &in.Crawler, &out.Crawler
+		*out = new(WebCrawler)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Web.
@@ -1489,6 +1969,61 @@ func (in *WebConfig) DeepCopy() *WebConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebCrawlStatus) DeepCopyInto(out *WebCrawlStatus) {
+	*out = *in
+	in.LastCrawlTime.DeepCopyInto(&out.LastCrawlTime)
+	if in.NextCrawlTime != nil {
+		in, out := &in.NextCrawlTime, &out.NextCrawlTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebCrawlStatus.
+func (in *WebCrawlStatus) DeepCopy() *WebCrawlStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WebCrawlStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebCrawler) DeepCopyInto(out *WebCrawler) {
+	*out = *in
+	if in.StartURLs != nil {
+		in, out := &in.StartURLs, &out.StartURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedDomains != nil {
+		in, out := &in.AllowedDomains, &out.AllowedDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludePatterns != nil {
+		in, out := &in.IncludePatterns, &out.IncludePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludePatterns != nil {
+		in, out := &in.ExcludePatterns, &out.ExcludePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebCrawler.
+func (in *WebCrawler) DeepCopy() *WebCrawler {
+	if in == nil {
+		return nil
+	}
+	out := new(WebCrawler)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Worker) DeepCopyInto(out *Worker) {
 	*out = *in
@@ -1516,6 +2051,49 @@ func (in *Worker) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerAutoscaling) DeepCopyInto(out *WorkerAutoscaling) {
+	*out = *in
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerAutoscaling.
+func (in *WorkerAutoscaling) DeepCopy() *WorkerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerAutoscalingStatus) DeepCopyInto(out *WorkerAutoscalingStatus) {
+	*out = *in
+	if in.LastRequestTime != nil {
+		in, out := &in.LastRequestTime, &out.LastRequestTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerAutoscalingStatus.
+func (in *WorkerAutoscalingStatus) DeepCopy() *WorkerAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerList) DeepCopyInto(out *WorkerList) {
 	*out = *in
@@ -1557,11 +2135,23 @@ func (in *WorkerSpec) DeepCopyInto(out *WorkerSpec) {
 		*out = new(TypedObjectReference)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Adapters != nil {
+		in, out := &in.Adapters, &out.Adapters
+		*out = make([]TypedObjectReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(WorkerAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.MatchExpressions != nil {
 		in, out := &in.MatchExpressions, &out.MatchExpressions
@@ -1600,6 +2190,11 @@ func (in *WorkerSpec) DeepCopy() *WorkerSpec {
 func (in *WorkerStatus) DeepCopyInto(out *WorkerStatus) {
 	*out = *in
 	in.PodStatus.DeepCopyInto(&out.PodStatus)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(WorkerAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
@@ -56,6 +56,11 @@ type ServerConfig struct {
 
 	// DataProcessURL is the URL of the data process service
 	DataProcessURL string
+
+	// ChatSearchConfig is the PostgreSQL text search configuration to search conversations
+	ChatSearchConfig string
+	// ChatStoragePath is the sqlite database file to store chats when no relational datasource is configured
+	ChatStoragePath string
 }
 
 func NewServerFlags() ServerConfig {
@@ -72,6 +77,8 @@ func NewServerFlags() ServerConfig {
 	flag.StringVar(&s.ClientSecret, "client-secret", "", "oidc client secret(required when enable odic)")
 	flag.StringVar(&s.DataProcessURL, "data-processing-url", "http://127.0.0.1:28888", "url to access data processing server")
 	flag.BoolVar(&s.Debug, "debug", false, "debug model for apiserver")
+	flag.StringVar(&s.ChatSearchConfig, "chat-search-config", "simple", "PostgreSQL text search configuration to search conversations, such as chinese provided by zhparser to tokenize chinese words. The words in chinese sentences are also found by LIKE with simple, which is slower on many messages")
+	flag.StringVar(&s.ChatStoragePath, "chat-storage-path", "", "sqlite database file to store chats when no relational datasource is configured, chats are kept in memory if empty")
 
 	klog.InitFlags(nil)
 	flag.Parse()
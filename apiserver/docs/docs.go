@@ -922,6 +922,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat.ErrorResp"
                         }
                     },
+                    "429": {
+                        "description": "quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1030,6 +1036,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/chat/conversations/export": {
+            "post": {
+                "description": "export current user's conversations with all messages as a json or markdown file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "export conversations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.ExportReqBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "json format returns the conversations, markdown format returns a markdown file",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/storage.Conversation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
         "/chat/conversations/file": {
             "post": {
                 "description": "receive conversational files for one conversation",
@@ -1094,9 +1157,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/messages": {
+        "/chat/conversations/search": {
             "post": {
-                "description": "get all messages history for one conversation",
+                "description": "search the queries and answers in current user's conversations, the matched words are highlighted with \u003cem\u003e in the snippets",
                 "consumes": [
                     "application/json"
                 ],
@@ -1106,7 +1169,7 @@ const docTemplate = `{
                 "tags": [
                     "application"
                 ],
-                "summary": "get all messages history for one conversation",
+                "summary": "search conversations",
                 "parameters": [
                     {
                         "type": "string",
@@ -1121,7 +1184,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat.ConversationReqBody"
+                            "$ref": "#/definitions/chat.SearchReqBody"
                         }
                     }
                 ],
@@ -1129,7 +1192,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/storage.Conversation"
+                            "$ref": "#/definitions/chat.SearchRespBody"
                         }
                     },
                     "400": {
@@ -1147,9 +1210,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/messages/:messageID/references": {
+        "/chat/messages": {
             "post": {
-                "description": "get one message's references",
+                "description": "get the messages of the selected branch with the sibling ids of each message, or all messages as a tree linked by parent_id when tree is true",
                 "consumes": [
                     "application/json"
                 ],
@@ -1159,7 +1222,7 @@ const docTemplate = `{
                 "tags": [
                     "application"
                 ],
-                "summary": "get one message references",
+                "summary": "get all messages history for one conversation",
                 "parameters": [
                     {
                         "type": "string",
@@ -1169,11 +1232,10 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "messageID",
-                        "name": "messageID",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "return all messages of the conversation as a tree",
+                        "name": "tree",
+                        "in": "query"
                     },
                     {
                         "description": "query params",
@@ -1181,7 +1243,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat.MessageReqBody"
+                            "$ref": "#/definitions/chat.ConversationReqBody"
                         }
                     }
                 ],
@@ -1189,10 +1251,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/retriever.Reference"
-                            }
+                            "$ref": "#/definitions/storage.Conversation"
                         }
                     },
                     "400": {
@@ -1210,9 +1269,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/prompt-starter": {
+        "/chat/messages/:messageID/active": {
             "post": {
-                "description": "get app's prompt starters",
+                "description": "select the branch of a message to continue the conversation from, the latest messages following it are selected as well",
                 "consumes": [
                     "application/json"
                 ],
@@ -1222,7 +1281,7 @@ const docTemplate = `{
                 "tags": [
                     "application"
                 ],
-                "summary": "get app's prompt starters",
+                "summary": "select the branch of a message",
                 "parameters": [
                     {
                         "type": "string",
@@ -1232,10 +1291,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "how many prompts you need should \u003e 0 and \u003c 10",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "messageID",
+                        "name": "messageID",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "description": "query params",
@@ -1243,7 +1303,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat.APPMetadata"
+                            "$ref": "#/definitions/chat.MessageReqBody"
                         }
                     }
                 ],
@@ -1251,10 +1311,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/storage.Conversation"
                         }
                     },
                     "400": {
@@ -1272,9 +1329,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/rags/detail": {
-            "get": {
-                "description": "Get detail data of a rag",
+        "/chat/messages/:messageID/feedback": {
+            "post": {
+                "description": "rate the answer of a message with like or dislike, the reason, comment and corrected answer are optional",
                 "consumes": [
                     "application/json"
                 ],
@@ -1282,80 +1339,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RAG"
+                    "application"
                 ],
-                "summary": "Get detail data of a rag",
+                "summary": "rate the answer of a message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rag name",
-                        "name": "ragName",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Namespace of the rag",
+                        "description": "namespace this request is in",
                         "name": "namespace",
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "default is 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "default is 10",
-                        "name": "size",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "rag metrcis",
-                        "name": "sortBy",
-                        "in": "query"
+                        "description": "messageID",
+                        "name": "messageID",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "desc or asc",
-                        "name": "order",
-                        "in": "query"
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.FeedbackReqBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rag.ReportDetail"
+                            "$ref": "#/definitions/storage.Feedback"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat.ErrorResp"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat.ErrorResp"
                         }
                     }
                 }
-            }
-        },
-        "/rags/report": {
-            "get": {
-                "description": "Get a summary of rag",
+            },
+            "delete": {
+                "description": "delete current user's feedback on a message",
                 "consumes": [
                     "application/json"
                 ],
@@ -1363,56 +1397,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RAG"
+                    "application"
                 ],
-                "summary": "Get a summary of rag",
+                "summary": "delete the feedback of a message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rag name",
-                        "name": "ragName",
-                        "in": "query",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Namespace of the rag",
-                        "name": "namespace",
-                        "in": "header",
+                        "description": "messageID",
+                        "name": "messageID",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.MessageReqBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rag.Report"
+                            "$ref": "#/definitions/chat.SimpleResp"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat.ErrorResp"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat.ErrorResp"
                         }
                     }
                 }
             }
         },
-        "/rags/scatter": {
-            "get": {
-                "description": "Get scatter data of a rag",
+        "/chat/messages/:messageID/references": {
+            "post": {
+                "description": "get one message's references",
                 "consumes": [
                     "application/json"
                 ],
@@ -1420,74 +1457,942 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RAG"
+                    "application"
                 ],
-                "summary": "Get scatter data of a rag",
+                "summary": "get one message references",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "rag name",
-                        "name": "ragName",
-                        "in": "query",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Namespace of the rag",
-                        "name": "namespace",
-                        "in": "header",
+                        "description": "messageID",
+                        "name": "messageID",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.MessageReqBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rag.ReportDetail"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/retriever.Reference"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat.ErrorResp"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat.ErrorResp"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "chat.APPMetadata": {
-            "type": "object",
-            "required": [
-                "app_name"
-            ],
-            "properties": {
-                "app_name": {
-                    "description": "AppName, the name of the application",
-                    "type": "string",
-                    "example": "chat-with-llm"
-                }
-            }
+        },
+        "/chat/prompt-starter": {
+            "post": {
+                "description": "get app's prompt starters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "get app's prompt starters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "how many prompts you need should \u003e 0 and \u003c 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.APPMetadata"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/public/app": {
+            "get": {
+                "description": "get the information of the application shared by the token, for the anonymous users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "get the shared application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer share token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the random secret of the anonymous user, at least 16 characters",
+                        "name": "visitor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/chat.SharedAppRespBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/public/widget.js": {
+            "get": {
+                "description": "the script of the chat widget, which can be embedded in any page with a share token:\n` + "`" + `\u003cscript src=\"\u003capiserver\u003e/chat/public/widget.js\" data-token=\"\u003cshare token\u003e\" async\u003e\u003c/script\u003e` + "`" + `",
+                "produces": [
+                    "application/javascript"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "the embeddable chat widget",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/chat/share-tokens": {
+            "get": {
+                "description": "list the share tokens of application, including the expired and revoked ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "list the share tokens of application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the name of the application",
+                        "name": "app_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/storage.ShareToken"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a share token, which allows the anonymous users to chat with the application until it expires or is revoked.\nThe token is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "create a share token of application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.ShareTokenReqBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ShareTokenRespBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/share-tokens/{tokenID}": {
+            "delete": {
+                "description": "revoke a share token, the anonymous users can't chat with it any more",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "revoke a share token of application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the id of the share token",
+                        "name": "tokenID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the name of the application",
+                        "name": "app_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/chat.SimpleResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/ws": {
+            "get": {
+                "description": "chat with application in websocket. The client sends chat.WebSocketRequest frames to ask, continue or cancel a message,\nand the server sends chat.WebSocketEvent frames with the same id. Browsers can pass the token and namespace in query.",
+                "tags": [
+                    "application"
+                ],
+                "summary": "chat with application in websocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the bearer token, for the clients can't set the header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Should the chat request be treated as debugging?",
+                        "name": "debug",
+                        "in": "query"
+                    },
+                    {
+                        "description": "frames sent by the client",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/chat.WebSocketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "frames sent by the server",
+                        "schema": {
+                            "$ref": "#/definitions/chat.WebSocketEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/rags/detail": {
+            "get": {
+                "description": "Get detail data of a rag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RAG"
+                ],
+                "summary": "Get detail data of a rag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "rag name",
+                        "name": "ragName",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace of the rag",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "default is 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "default is 10",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "rag metrcis",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc or asc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rag.ReportDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rags/report": {
+            "get": {
+                "description": "Get a summary of rag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RAG"
+                ],
+                "summary": "Get a summary of rag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "rag name",
+                        "name": "ragName",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace of the rag",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rag.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rags/scatter": {
+            "get": {
+                "description": "Get scatter data of a rag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RAG"
+                ],
+                "summary": "Get scatter data of a rag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "rag name",
+                        "name": "ragName",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace of the rag",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rag.ReportDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/completions": {
+            "post": {
+                "description": "chat with application in OpenAI's chat completions format, the model is the application in ` + "`" + `namespace/name` + "`" + ` format",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "chat with application in OpenAI's chat completions format",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in, can be omitted if the model contains namespace",
+                        "name": "namespace",
+                        "in": "header"
+                    },
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIChatCompletionReqBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "blocking mode returns a chat.completion object; streaming mode returns chat.completion.chunk objects as Server-Sent Events",
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIChatCompletionRespBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIErrorResp"
+                        }
+                    },
+                    "429": {
+                        "description": "quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/models": {
+            "get": {
+                "description": "list the ready applications in the namespace as the models of chat completions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "application"
+                ],
+                "summary": "list applications as the models of chat completions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "namespace this request is in",
+                        "name": "namespace",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIModelList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/chat.OpenAIErrorResp"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "chat.APPMetadata": {
+            "type": "object",
+            "required": [
+                "app_name"
+            ],
+            "properties": {
+                "app_name": {
+                    "description": "AppName, the name of the application",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                }
+            }
         },
         "chat.ChatReqBody": {
             "type": "object",
             "required": [
-                "app_name",
-                "query",
-                "response_mode"
+                "app_name",
+                "response_mode"
+            ],
+            "properties": {
+                "app_name": {
+                    "description": "AppName, the name of the application",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "conversation_id": {
+                    "description": "ConversationID, if it is empty, a new conversation will be created",
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "files": {
+                    "description": "Files this conversation will use in the context.\nImages are sent to the llm with the query, which requires a vision capable llm",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "test.pdf",
+                        "song.mp3"
+                    ]
+                },
+                "parent_message_id": {
+                    "description": "ParentMessageID is the message this query follows, which is the last message of the selected branch by default.\nSet it to the parent of a message to edit that message's query in a new branch,\nthe conversation id is the parent of the first round.",
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "query": {
+                    "description": "Query user query string\nIt is required unless regenerating an answer",
+                    "type": "string",
+                    "example": "旷工最小计算单位为多少天？"
+                },
+                "regenerate_message_id": {
+                    "description": "RegenerateMessageID is the message to regenerate the answer in a new branch, its query and files are reused",
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "response_mode": {
+                    "description": "ResponseMode:\n* Blocking - means the response is returned in a blocking manner\n* Streaming - means the response will use Server-Sent Events",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.ResponseMode"
+                        }
+                    ],
+                    "example": "blocking"
+                }
+            }
+        },
+        "chat.ChatRespBody": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action indicates what is this chat for",
+                    "type": "string",
+                    "example": "CHAT"
+                },
+                "conversation_id": {
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "created_at": {
+                    "description": "CreatedAt is the time when the message is created",
+                    "type": "string",
+                    "example": "2023-12-21T10:21:06.389359092+08:00"
+                },
+                "document": {
+                    "description": "Documents in this chat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.DocumentRespBody"
+                        }
+                    ]
+                },
+                "latency": {
+                    "description": "Latency(ms) is how much time the server cost to process a certain request.",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "message": {
+                    "description": "Message is what AI say",
+                    "type": "string",
+                    "example": "旷工最小计算单位为0.5天。"
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "references": {
+                    "description": "References is the list of references",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/retriever.Reference"
+                    }
+                }
+            }
+        },
+        "chat.ConversationReqBody": {
+            "type": "object",
+            "required": [
+                "app_name"
+            ],
+            "properties": {
+                "app_name": {
+                    "description": "AppName, the name of the application",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "conversation_id": {
+                    "description": "ConversationID, if it is empty, a new conversation will be created",
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                }
+            }
+        },
+        "chat.DocumentRespBody": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "8b833028-5d8d-418c-9f28-8aaa23c972b0"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "example.pdf"
+                },
+                "object": {
+                    "type": "string",
+                    "example": "application/base-chat-document-assistant/conversation/f54f5122-28fb-474e-8593-39f5b3760eaa/90fe100fb9ee6e6cb9ccb091fd91b6264f5f5444dea6d93b3c8ed7418e20c37d.pdf"
+                }
+            }
+        },
+        "chat.ErrorResp": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "conversation is not found"
+                }
+            }
+        },
+        "chat.ExportReqBody": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "description": "AppName, export the conversations of the application, all applications in the namespace if it is empty",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "conversation_id": {
+                    "description": "ConversationID, export this conversation only if it is not empty",
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "format": {
+                    "description": "Format, json or markdown, json by default",
+                    "type": "string",
+                    "enum": [
+                        "json",
+                        "markdown"
+                    ],
+                    "example": "markdown"
+                }
+            }
+        },
+        "chat.FeedbackReqBody": {
+            "type": "object",
+            "required": [
+                "app_name",
+                "rating"
+            ],
+            "properties": {
+                "app_name": {
+                    "description": "AppName, the name of the application",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "comment": {
+                    "description": "Comment is the free text comment on the answer",
+                    "type": "string",
+                    "example": "旷工最小计算单位应为1天"
+                },
+                "conversation_id": {
+                    "description": "ConversationID, if it is empty, a new conversation will be created",
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "corrected_answer": {
+                    "description": "CorrectedAnswer is the correct answer given by user, which is used as ground truth when exported",
+                    "type": "string",
+                    "example": "旷工最小计算单位为1天。"
+                },
+                "message_id": {
+                    "description": "MessageID, single message id",
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "rating": {
+                    "description": "Rating of the answer, like or dislike",
+                    "enum": [
+                        "like",
+                        "dislike"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/storage.FeedbackRating"
+                        }
+                    ],
+                    "example": "dislike"
+                },
+                "reason": {
+                    "description": "Reason category of the rating, one of inaccurate,irrelevant,incomplete,harmful,helpful and other",
+                    "enum": [
+                        "inaccurate",
+                        "irrelevant",
+                        "incomplete",
+                        "harmful",
+                        "helpful",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/storage.FeedbackReason"
+                        }
+                    ],
+                    "example": "inaccurate"
+                }
+            }
+        },
+        "chat.MessageReqBody": {
+            "type": "object",
+            "required": [
+                "app_name"
             ],
             "properties": {
                 "app_name": {
@@ -1500,165 +2405,446 @@ const docTemplate = `{
                     "type": "string",
                     "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
                 },
-                "files": {
-                    "description": "Files this conversation will use in the context",
+                "message_id": {
+                    "description": "MessageID, single message id",
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                }
+            }
+        },
+        "chat.OpenAIChatCompletionReqBody": {
+            "type": "object",
+            "required": [
+                "messages",
+                "model"
+            ],
+            "properties": {
+                "conversation_id": {
+                    "description": "ConversationID is an extension to store this chat into an existing conversation, a new conversation will be created if it is empty",
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "messages": {
+                    "description": "Messages are the history of this chat, the last one must be from user.\nSystem messages are ignored as the prompt is defined by the application.",
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "test.pdf",
-                        "song.mp3"
-                    ]
+                        "$ref": "#/definitions/chat.OpenAIMessage"
+                    }
                 },
-                "query": {
-                    "description": "Query user query string",
+                "model": {
+                    "description": "Model is the application in ` + "`" + `namespace/name` + "`" + ` format, or the application name in the namespace of request header",
                     "type": "string",
-                    "example": "旷工最小计算单位为多少天？"
+                    "example": "arcadia/chat-with-llm"
                 },
-                "response_mode": {
-                    "description": "ResponseMode:\n* Blocking - means the response is returned in a blocking manner\n* Streaming - means the response will use Server-Sent Events",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/chat.ResponseMode"
-                        }
-                    ],
-                    "example": "blocking"
+                "stream": {
+                    "type": "boolean"
                 }
             }
         },
-        "chat.ChatRespBody": {
+        "chat.OpenAIChatCompletionRespBody": {
             "type": "object",
             "properties": {
-                "action": {
-                    "description": "Action indicates what is this chat for",
-                    "type": "string",
-                    "example": "CHAT"
+                "choices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat.OpenAIChoice"
+                    }
                 },
                 "conversation_id": {
+                    "description": "ConversationID is an extension for the conversation this chat is stored in",
                     "type": "string",
                     "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
                 },
+                "created": {
+                    "type": "integer",
+                    "example": 1703125266
+                },
+                "id": {
+                    "type": "string",
+                    "example": "chatcmpl-4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "arcadia/chat-with-llm"
+                },
+                "object": {
+                    "type": "string",
+                    "example": "chat.completion"
+                },
+                "references": {
+                    "description": "References is an extension for the references of the answer,only in the last chunk of streaming mode",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/retriever.Reference"
+                    }
+                },
+                "usage": {
+                    "$ref": "#/definitions/chat.OpenAIUsage"
+                }
+            }
+        },
+        "chat.OpenAIChoice": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "$ref": "#/definitions/chat.OpenAIMessage"
+                },
+                "finish_reason": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/chat.OpenAIMessage"
+                }
+            }
+        },
+        "chat.OpenAIError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "application not ready"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "invalid_request_error"
+                }
+            }
+        },
+        "chat.OpenAIErrorResp": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/chat.OpenAIError"
+                }
+            }
+        },
+        "chat.OpenAIMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Content is a string, or an array of content parts of which only the text parts are used",
+                    "type": "string",
+                    "example": "旷工最小计算单位为多少天？"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "chat.OpenAIModel": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 1703125266
+                },
+                "id": {
+                    "type": "string",
+                    "example": "arcadia/chat-with-llm"
+                },
+                "object": {
+                    "type": "string",
+                    "example": "model"
+                },
+                "owned_by": {
+                    "type": "string",
+                    "example": "arcadia"
+                }
+            }
+        },
+        "chat.OpenAIModelList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat.OpenAIModel"
+                    }
+                },
+                "object": {
+                    "type": "string",
+                    "example": "list"
+                }
+            }
+        },
+        "chat.OpenAIUsage": {
+            "type": "object",
+            "properties": {
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat.ResponseMode": {
+            "type": "string",
+            "enum": [
+                "blocking",
+                "streaming"
+            ],
+            "x-enum-varnames": [
+                "Blocking",
+                "Streaming"
+            ]
+        },
+        "chat.SearchReqBody": {
+            "type": "object",
+            "required": [
+                "keyword"
+            ],
+            "properties": {
+                "app_name": {
+                    "description": "AppName, search in the conversations of the application, all applications in the namespace if it is empty",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "2023-12-22T00:00:00+08:00"
+                },
+                "keyword": {
+                    "description": "Keyword, the words to search in the queries and answers, the messages containing all words are matched",
+                    "type": "string",
+                    "example": "旷工"
+                },
+                "page": {
+                    "description": "Page starts from 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "start_time": {
+                    "description": "StartTime and EndTime limit the update time of conversations",
+                    "type": "string",
+                    "example": "2023-12-21T00:00:00+08:00"
+                }
+            }
+        },
+        "chat.SearchRespBody": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.MessageHit"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "chat.ShareTokenReqBody": {
+            "type": "object",
+            "required": [
+                "app_name"
+            ],
+            "properties": {
+                "app_name": {
+                    "description": "AppName, the name of the application",
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is the time the token expires at, the token never expires if it is empty",
+                    "type": "string",
+                    "example": "2024-12-21T00:00:00+08:00"
+                },
+                "name": {
+                    "description": "Name of the share token, such as the page where the widget is embedded",
+                    "type": "string",
+                    "example": "intranet hr bot"
+                },
+                "requests_per_minute": {
+                    "description": "RequestsPerMinute limits the chat requests of all anonymous users with this token, 10 by default",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
+                }
+            }
+        },
+        "chat.ShareTokenRespBody": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "app_namespace": {
+                    "type": "string",
+                    "example": "arcadia"
+                },
                 "created_at": {
-                    "description": "CreatedAt is the time when the message is created",
                     "type": "string",
                     "example": "2023-12-21T10:21:06.389359092+08:00"
                 },
-                "document": {
-                    "description": "Documents in this chat",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/chat.DocumentRespBody"
-                        }
-                    ]
+                "creator": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-12-21T10:21:06.389359092+08:00"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9b2c3a4e-1f7d-4c55-8a1e-2f4d5c6b7a80"
                 },
-                "latency": {
-                    "description": "Latency(ms) is how much time the server cost to process a certain request.",
+                "name": {
+                    "type": "string",
+                    "example": "intranet hr bot"
+                },
+                "requests_per_minute": {
+                    "description": "RequestsPerMinute limits the chat requests of all anonymous users with this token",
                     "type": "integer",
-                    "example": 1000
+                    "example": 10
                 },
-                "message": {
-                    "description": "Message is what AI say",
+                "revoked_at": {
                     "type": "string",
-                    "example": "旷工最小计算单位为0.5天。"
+                    "example": "2024-06-21T10:21:06.389359092+08:00"
                 },
-                "message_id": {
+                "token": {
+                    "description": "Token is used by the anonymous users to chat, which is only returned when the token is created",
                     "type": "string",
-                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
-                },
-                "references": {
-                    "description": "References is the list of references",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/retriever.Reference"
-                    }
+                    "example": "st_Jm9zZ3Vlc3MtdGhpcy1pcy1ub3QtYS1yZWFsLXRva2Vu"
                 }
             }
         },
-        "chat.ConversationReqBody": {
+        "chat.SharedAppRespBody": {
             "type": "object",
-            "required": [
-                "app_name"
-            ],
             "properties": {
                 "app_name": {
-                    "description": "AppName, the name of the application",
                     "type": "string",
                     "example": "chat-with-llm"
                 },
-                "conversation_id": {
-                    "description": "ConversationID, if it is empty, a new conversation will be created",
+                "app_namespace": {
                     "type": "string",
-                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
-                }
-            }
-        },
-        "chat.DocumentRespBody": {
-            "type": "object",
-            "properties": {
-                "id": {
+                    "example": "arcadia"
+                },
+                "description": {
                     "type": "string",
-                    "example": "8b833028-5d8d-418c-9f28-8aaa23c972b0"
+                    "example": "answer the questions about HR policies"
                 },
-                "name": {
+                "display_name": {
                     "type": "string",
-                    "example": "example.pdf"
+                    "example": "HR Assistant"
                 },
-                "object": {
+                "icon": {
+                    "type": "string"
+                },
+                "prologue": {
                     "type": "string",
-                    "example": "application/base-chat-document-assistant/conversation/f54f5122-28fb-474e-8593-39f5b3760eaa/90fe100fb9ee6e6cb9ccb091fd91b6264f5f5444dea6d93b3c8ed7418e20c37d.pdf"
+                    "example": "Hi, how can I help you?"
                 }
             }
         },
-        "chat.ErrorResp": {
+        "chat.SimpleResp": {
             "type": "object",
             "properties": {
-                "error": {
+                "message": {
                     "type": "string",
-                    "example": "conversation is not found"
+                    "example": "ok"
                 }
             }
         },
-        "chat.MessageReqBody": {
+        "chat.WebSocketEvent": {
             "type": "object",
-            "required": [
-                "app_name"
-            ],
             "properties": {
-                "app_name": {
-                    "description": "AppName, the name of the application",
-                    "type": "string",
-                    "example": "chat-with-llm"
+                "data": {
+                    "description": "Data is the answer, only the piece in the message event",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.ChatRespBody"
+                        }
+                    ]
                 },
-                "conversation_id": {
-                    "description": "ConversationID, if it is empty, a new conversation will be created",
+                "error": {
+                    "description": "Error is the reason of the error event",
                     "type": "string",
-                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                    "example": "conversation is not found"
                 },
-                "message_id": {
-                    "description": "MessageID, single message id",
+                "id": {
+                    "description": "ID is the id in the request frame",
                     "type": "string",
-                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                    "example": "1"
+                },
+                "type": {
+                    "description": "Type is one of message, done, error and cancelled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.WebSocketFrameType"
+                        }
+                    ],
+                    "example": "message"
                 }
             }
         },
-        "chat.ResponseMode": {
+        "chat.WebSocketFrameType": {
             "type": "string",
             "enum": [
-                "blocking",
-                "streaming"
+                "chat",
+                "continue",
+                "cancel",
+                "message",
+                "done",
+                "error",
+                "cancelled"
             ],
             "x-enum-varnames": [
-                "Blocking",
-                "Streaming"
+                "FrameChat",
+                "FrameContinue",
+                "FrameCancel",
+                "EventMessage",
+                "EventDone",
+                "EventError",
+                "EventCancelled"
             ]
         },
-        "chat.SimpleResp": {
+        "chat.WebSocketRequest": {
             "type": "object",
             "properties": {
-                "message": {
+                "chat": {
+                    "description": "Chat is the chat request for chat and continue frames.\nFor continue frames, the query is ignored and parent_message_id is the message to continue",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.ChatReqBody"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID is chosen by the client to identify the message in this socket, the events of the message have the same id",
                     "type": "string",
-                    "example": "ok"
+                    "example": "1"
+                },
+                "type": {
+                    "description": "Type is one of chat, continue and cancel",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat.WebSocketFrameType"
+                        }
+                    ],
+                    "example": "chat"
                 }
             }
         },
@@ -2086,6 +3272,11 @@ const docTemplate = `{
         "storage.Conversation": {
             "type": "object",
             "properties": {
+                "active_message_id": {
+                    "description": "ActiveMessageID is the last message of the selected branch",
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
                 "app_name": {
                     "type": "string",
                     "example": "chat-with-llm"
@@ -2108,10 +3299,19 @@ const docTemplate = `{
                         "$ref": "#/definitions/storage.Message"
                     }
                 },
+                "share_token_id": {
+                    "description": "ShareTokenID is the share token the conversation is started with, which is empty for the signed-in users",
+                    "type": "string",
+                    "example": "9b2c3a4e-1f7d-4c55-8a1e-2f4d5c6b7a80"
+                },
                 "started_at": {
                     "type": "string",
                     "example": "2023-12-21T10:21:06.389359092+08:00"
                 },
+                "summary": {
+                    "description": "Summary is the running summary of the turns until SummaryMessageID, which are not sent to the llm any more",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2023-12-22T10:21:06.389359092+08:00"
@@ -2139,6 +3339,97 @@ const docTemplate = `{
                 }
             }
         },
+        "storage.Feedback": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "example": "旷工最小计算单位为0.5天。"
+                },
+                "app_name": {
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "app_namespace": {
+                    "type": "string",
+                    "example": "arcadia"
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "旷工最小计算单位应为1天"
+                },
+                "conversation_id": {
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "corrected_answer": {
+                    "type": "string",
+                    "example": "旷工最小计算单位为1天。"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-12-21T10:21:06.389359092+08:00"
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "旷工最小计算单位为多少天？"
+                },
+                "rating": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/storage.FeedbackRating"
+                        }
+                    ],
+                    "example": "dislike"
+                },
+                "reason": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/storage.FeedbackReason"
+                        }
+                    ],
+                    "example": "inaccurate"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-12-21T10:21:06.389359092+08:00"
+                }
+            }
+        },
+        "storage.FeedbackRating": {
+            "type": "string",
+            "enum": [
+                "like",
+                "dislike"
+            ],
+            "x-enum-varnames": [
+                "FeedbackRatingLike",
+                "FeedbackRatingDislike"
+            ]
+        },
+        "storage.FeedbackReason": {
+            "type": "string",
+            "enum": [
+                "inaccurate",
+                "irrelevant",
+                "incomplete",
+                "harmful",
+                "helpful",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "FeedbackReasonInaccurate",
+                "FeedbackReasonIrrelevant",
+                "FeedbackReasonIncomplete",
+                "FeedbackReasonHarmful",
+                "FeedbackReasonHelpful",
+                "FeedbackReasonOther"
+            ]
+        },
         "storage.Message": {
             "type": "object",
             "properties": {
@@ -2151,6 +3442,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "旷工最小计算单位为0.5天。"
                 },
+                "completion_tokens": {
+                    "type": "integer",
+                    "example": 20
+                },
                 "documents": {
                     "description": "For Action Upload",
                     "type": "array",
@@ -2158,6 +3453,14 @@ const docTemplate = `{
                         "$ref": "#/definitions/storage.Document"
                     }
                 },
+                "feedback": {
+                    "description": "Feedback of current user on the answer, only valid in messages history api",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/storage.Feedback"
+                        }
+                    ]
+                },
                 "files": {
                     "description": "Files that shall be used in this Chat",
                     "type": "array",
@@ -2169,10 +3472,27 @@ const docTemplate = `{
                     "type": "string",
                     "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
                 },
+                "images": {
+                    "description": "Images that are sent to the llm in this Chat, which are the object names in the system datasource",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "latency": {
                     "type": "integer",
                     "example": 1000
                 },
+                "parent_id": {
+                    "description": "ParentID is the message this one follows, which is the conversation id for the first round",
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "prompt_tokens": {
+                    "description": "Token usage of all llm calls to answer this message",
+                    "type": "integer",
+                    "example": 100
+                },
                 "query": {
                     "description": "For Action Chat",
                     "type": "string",
@@ -2183,6 +3503,92 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/retriever.Reference"
                     }
+                },
+                "sibling_ids": {
+                    "description": "SiblingIDs are the messages with the same parent including this one,\nwhich are the regenerated or edited versions. Only valid in messages history api",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total_tokens": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "storage.MessageHit": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "example": "\u003cem\u003e旷工\u003c/em\u003e最小计算单位为0.5天。"
+                },
+                "app_name": {
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "app_namespace": {
+                    "type": "string",
+                    "example": "arcadia"
+                },
+                "conversation_id": {
+                    "type": "string",
+                    "example": "5a41f3ca-763b-41ec-91c3-4bbbb00736d0"
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "\u003cem\u003e旷工\u003c/em\u003e最小计算单位为多少天？"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-12-22T10:21:06.389359092+08:00"
+                }
+            }
+        },
+        "storage.ShareToken": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "type": "string",
+                    "example": "chat-with-llm"
+                },
+                "app_namespace": {
+                    "type": "string",
+                    "example": "arcadia"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-12-21T10:21:06.389359092+08:00"
+                },
+                "creator": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-12-21T10:21:06.389359092+08:00"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9b2c3a4e-1f7d-4c55-8a1e-2f4d5c6b7a80"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "intranet hr bot"
+                },
+                "requests_per_minute": {
+                    "description": "RequestsPerMinute limits the chat requests of all anonymous users with this token",
+                    "type": "integer",
+                    "example": 10
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-06-21T10:21:06.389359092+08:00"
                 }
             }
         }
@@ -127,12 +127,15 @@ type ComplexityRoot struct {
 	ApplicationMutation struct {
 		CreateApplication       func(childComplexity int, input CreateApplicationMetadataInput) int
 		DeleteApplication       func(childComplexity int, input DeleteCommonInput) int
+		ExportFeedback          func(childComplexity int, input ExportFeedbackInput) int
 		UpdateApplication       func(childComplexity int, input UpdateApplicationMetadataInput) int
 		UpdateApplicationConfig func(childComplexity int, input UpdateApplicationConfigInput) int
 	}
 
 	ApplicationQuery struct {
 		GetApplication          func(childComplexity int, name string, namespace string) int
+		GetFeedbackStats        func(childComplexity int, input FeedbackStatsInput) int
+		GetUsage                func(childComplexity int, input UsageInput) int
 		ListApplicationMetadata func(childComplexity int, input ListCommonInput) int
 	}
 
@@ -360,6 +363,11 @@ type ComplexityRoot struct {
 		URL        func(childComplexity int) int
 	}
 
+	ExportFeedbackResult struct {
+		Count  func(childComplexity int) int
+		Object func(childComplexity int) int
+	}
+
 	F struct {
 		Count             func(childComplexity int) int
 		CreationTimestamp func(childComplexity int) int
@@ -371,6 +379,21 @@ type ComplexityRoot struct {
 		Versions          func(childComplexity int) int
 	}
 
+	FeedbackReasonCount struct {
+		Count  func(childComplexity int) int
+		Rating func(childComplexity int) int
+		Reason func(childComplexity int) int
+	}
+
+	FeedbackStat struct {
+		AppName   func(childComplexity int) int
+		Dislikes  func(childComplexity int) int
+		Likes     func(childComplexity int) int
+		Namespace func(childComplexity int) int
+		Reasons   func(childComplexity int) int
+		Total     func(childComplexity int) int
+	}
+
 	FileDetails struct {
 		EndTime   func(childComplexity int) int
 		FileName  func(childComplexity int) int
@@ -445,6 +468,7 @@ type ComplexityRoot struct {
 	KnowledgeBaseMutation struct {
 		CreateKnowledgeBase func(childComplexity int, input CreateKnowledgeBaseInput) int
 		DeleteKnowledgeBase func(childComplexity int, input *DeleteCommonInput) int
+		RetryFailedFiles    func(childComplexity int, name string, namespace string) int
 		UpdateKnowledgeBase func(childComplexity int, input *UpdateKnowledgeBaseInput) int
 	}
 
@@ -742,6 +766,16 @@ type ComplexityRoot struct {
 		Namespace   func(childComplexity int) int
 	}
 
+	Usage struct {
+		AppName          func(childComplexity int) int
+		CompletionTokens func(childComplexity int) int
+		Namespace        func(childComplexity int) int
+		PromptTokens     func(childComplexity int) int
+		Requests         func(childComplexity int) int
+		TotalTokens      func(childComplexity int) int
+		User             func(childComplexity int) int
+	}
+
 	VersionedDataset struct {
 		Annotations       func(childComplexity int) int
 		CreationTimestamp func(childComplexity int) int
@@ -813,6 +847,7 @@ type ComplexityRoot struct {
 	}
 
 	Filedetail struct {
+		Attempts        func(childComplexity int) int
 		Count           func(childComplexity int) int
 		FileType        func(childComplexity int) int
 		LatestVersion   func(childComplexity int) int
@@ -841,10 +876,13 @@ type ApplicationMutationResolver interface {
 	UpdateApplication(ctx context.Context, obj *ApplicationMutation, input UpdateApplicationMetadataInput) (*ApplicationMetadata, error)
 	DeleteApplication(ctx context.Context, obj *ApplicationMutation, input DeleteCommonInput) (*string, error)
 	UpdateApplicationConfig(ctx context.Context, obj *ApplicationMutation, input UpdateApplicationConfigInput) (*Application, error)
+	ExportFeedback(ctx context.Context, obj *ApplicationMutation, input ExportFeedbackInput) (*ExportFeedbackResult, error)
 }
 type ApplicationQueryResolver interface {
 	GetApplication(ctx context.Context, obj *ApplicationQuery, name string, namespace string) (*Application, error)
 	ListApplicationMetadata(ctx context.Context, obj *ApplicationQuery, input ListCommonInput) (*PaginatedResult, error)
+	GetUsage(ctx context.Context, obj *ApplicationQuery, input UsageInput) ([]*Usage, error)
+	GetFeedbackStats(ctx context.Context, obj *ApplicationQuery, input FeedbackStatsInput) ([]*FeedbackStat, error)
 }
 type DataProcessMutationResolver interface {
 	CreateDataProcessTask(ctx context.Context, obj *DataProcessMutation, input *AddDataProcessInput) (*DataProcessResponse, error)
@@ -901,6 +939,7 @@ type KnowledgeBaseMutationResolver interface {
 	CreateKnowledgeBase(ctx context.Context, obj *KnowledgeBaseMutation, input CreateKnowledgeBaseInput) (*KnowledgeBase, error)
 	UpdateKnowledgeBase(ctx context.Context, obj *KnowledgeBaseMutation, input *UpdateKnowledgeBaseInput) (*KnowledgeBase, error)
 	DeleteKnowledgeBase(ctx context.Context, obj *KnowledgeBaseMutation, input *DeleteCommonInput) (*string, error)
+	RetryFailedFiles(ctx context.Context, obj *KnowledgeBaseMutation, name string, namespace string) (*KnowledgeBase, error)
 }
 type KnowledgeBaseQueryResolver interface {
 	GetKnowledgeBase(ctx context.Context, obj *KnowledgeBaseQuery, name string, namespace string) (*KnowledgeBase, error)
@@ -1345,6 +1384,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.ApplicationMutation.DeleteApplication(childComplexity, args["input"].(DeleteCommonInput)), true
 
+	case "ApplicationMutation.exportFeedback":
+		if e.complexity.ApplicationMutation.ExportFeedback == nil {
+			break
+		}
+
+		args, err := ec.field_ApplicationMutation_exportFeedback_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.ApplicationMutation.ExportFeedback(childComplexity, args["input"].(ExportFeedbackInput)), true
+
 	case "ApplicationMutation.updateApplication":
 		if e.complexity.ApplicationMutation.UpdateApplication == nil {
 			break
@@ -1381,6 +1432,30 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.ApplicationQuery.GetApplication(childComplexity, args["name"].(string), args["namespace"].(string)), true
 
+	case "ApplicationQuery.getFeedbackStats":
+		if e.complexity.ApplicationQuery.GetFeedbackStats == nil {
+			break
+		}
+
+		args, err := ec.field_ApplicationQuery_getFeedbackStats_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.ApplicationQuery.GetFeedbackStats(childComplexity, args["input"].(FeedbackStatsInput)), true
+
+	case "ApplicationQuery.getUsage":
+		if e.complexity.ApplicationQuery.GetUsage == nil {
+			break
+		}
+
+		args, err := ec.field_ApplicationQuery_getUsage_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.ApplicationQuery.GetUsage(childComplexity, args["input"].(UsageInput)), true
+
 	case "ApplicationQuery.listApplicationMetadata":
 		if e.complexity.ApplicationQuery.ListApplicationMetadata == nil {
 			break
@@ -2545,6 +2620,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Endpoint.URL(childComplexity), true
 
+	case "ExportFeedbackResult.count":
+		if e.complexity.ExportFeedbackResult.Count == nil {
+			break
+		}
+
+		return e.complexity.ExportFeedbackResult.Count(childComplexity), true
+
+	case "ExportFeedbackResult.object":
+		if e.complexity.ExportFeedbackResult.Object == nil {
+			break
+		}
+
+		return e.complexity.ExportFeedbackResult.Object(childComplexity), true
+
 	case "F.count":
 		if e.complexity.F.Count == nil {
 			break
@@ -2601,6 +2690,69 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.F.Versions(childComplexity), true
 
+	case "FeedbackReasonCount.count":
+		if e.complexity.FeedbackReasonCount.Count == nil {
+			break
+		}
+
+		return e.complexity.FeedbackReasonCount.Count(childComplexity), true
+
+	case "FeedbackReasonCount.rating":
+		if e.complexity.FeedbackReasonCount.Rating == nil {
+			break
+		}
+
+		return e.complexity.FeedbackReasonCount.Rating(childComplexity), true
+
+	case "FeedbackReasonCount.reason":
+		if e.complexity.FeedbackReasonCount.Reason == nil {
+			break
+		}
+
+		return e.complexity.FeedbackReasonCount.Reason(childComplexity), true
+
+	case "FeedbackStat.appName":
+		if e.complexity.FeedbackStat.AppName == nil {
+			break
+		}
+
+		return e.complexity.FeedbackStat.AppName(childComplexity), true
+
+	case "FeedbackStat.dislikes":
+		if e.complexity.FeedbackStat.Dislikes == nil {
+			break
+		}
+
+		return e.complexity.FeedbackStat.Dislikes(childComplexity), true
+
+	case "FeedbackStat.likes":
+		if e.complexity.FeedbackStat.Likes == nil {
+			break
+		}
+
+		return e.complexity.FeedbackStat.Likes(childComplexity), true
+
+	case "FeedbackStat.namespace":
+		if e.complexity.FeedbackStat.Namespace == nil {
+			break
+		}
+
+		return e.complexity.FeedbackStat.Namespace(childComplexity), true
+
+	case "FeedbackStat.reasons":
+		if e.complexity.FeedbackStat.Reasons == nil {
+			break
+		}
+
+		return e.complexity.FeedbackStat.Reasons(childComplexity), true
+
+	case "FeedbackStat.total":
+		if e.complexity.FeedbackStat.Total == nil {
+			break
+		}
+
+		return e.complexity.FeedbackStat.Total(childComplexity), true
+
 	case "FileDetails.end_time":
 		if e.complexity.FileDetails.EndTime == nil {
 			break
@@ -2985,6 +3137,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.KnowledgeBaseMutation.DeleteKnowledgeBase(childComplexity, args["input"].(*DeleteCommonInput)), true
 
+	case "KnowledgeBaseMutation.retryFailedFiles":
+		if e.complexity.KnowledgeBaseMutation.RetryFailedFiles == nil {
+			break
+		}
+
+		args, err := ec.field_KnowledgeBaseMutation_retryFailedFiles_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.KnowledgeBaseMutation.RetryFailedFiles(childComplexity, args["name"].(string), args["namespace"].(string)), true
+
 	case "KnowledgeBaseMutation.updateKnowledgeBase":
 		if e.complexity.KnowledgeBaseMutation.UpdateKnowledgeBase == nil {
 			break
@@ -4450,6 +4614,55 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.TypedObjectReference.Namespace(childComplexity), true
 
+	case "Usage.appName":
+		if e.complexity.Usage.AppName == nil {
+			break
+		}
+
+		return e.complexity.Usage.AppName(childComplexity), true
+
+	case "Usage.completionTokens":
+		if e.complexity.Usage.CompletionTokens == nil {
+			break
+		}
+
+		return e.complexity.Usage.CompletionTokens(childComplexity), true
+
+	case "Usage.namespace":
+		if e.complexity.Usage.Namespace == nil {
+			break
+		}
+
+		return e.complexity.Usage.Namespace(childComplexity), true
+
+	case "Usage.promptTokens":
+		if e.complexity.Usage.PromptTokens == nil {
+			break
+		}
+
+		return e.complexity.Usage.PromptTokens(childComplexity), true
+
+	case "Usage.requests":
+		if e.complexity.Usage.Requests == nil {
+			break
+		}
+
+		return e.complexity.Usage.Requests(childComplexity), true
+
+	case "Usage.totalTokens":
+		if e.complexity.Usage.TotalTokens == nil {
+			break
+		}
+
+		return e.complexity.Usage.TotalTokens(childComplexity), true
+
+	case "Usage.user":
+		if e.complexity.Usage.User == nil {
+			break
+		}
+
+		return e.complexity.Usage.User(childComplexity), true
+
 	case "VersionedDataset.annotations":
 		if e.complexity.VersionedDataset.Annotations == nil {
 			break
@@ -4848,6 +5061,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.WorkerQuery.ListWorkers(childComplexity, args["input"].(ListWorkerInput)), true
 
+	case "filedetail.attempts":
+		if e.complexity.Filedetail.Attempts == nil {
+			break
+		}
+
+		return e.complexity.Filedetail.Attempts(childComplexity), true
+
 	case "filedetail.count":
 		if e.complexity.Filedetail.Count == nil {
 			break
@@ -4979,6 +5199,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputDeleteVersionedDatasetInput,
 		ec.unmarshalInputDuplicateRAGInput,
 		ec.unmarshalInputEndpointInput,
+		ec.unmarshalInputExportFeedbackInput,
+		ec.unmarshalInputFeedbackStatsInput,
 		ec.unmarshalInputFileFilter,
 		ec.unmarshalInputFileGroup,
 		ec.unmarshalInputFileItem,
@@ -5020,6 +5242,7 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputUpdateRAGInput,
 		ec.unmarshalInputUpdateVersionedDatasetInput,
 		ec.unmarshalInputUpdateWorkerInput,
+		ec.unmarshalInputUsageInput,
 		ec.unmarshalInputWebInput,
 		ec.unmarshalInputfilegroupinput,
 	)
@@ -5122,6 +5345,14 @@ var sources = []*ast.Source{
 	{Name: "../schema/application.graphqls", Input: `type ApplicationQuery {
     getApplication(name: String!, namespace: String!): Application!
     listApplicationMetadata(input: ListCommonInput!): PaginatedResult!
+    """
+    统计应用的 token 用量
+    """
+    getUsage(input: UsageInput!): [Usage!]!
+    """
+    统计应用的用户反馈
+    """
+    getFeedbackStats(input: FeedbackStatsInput!): [FeedbackStat!]!
 }
 
 type ApplicationMutation {
@@ -5129,6 +5360,10 @@ type ApplicationMutation {
     updateApplication(input: UpdateApplicationMetadataInput!): ApplicationMetadata!
     deleteApplication(input: DeleteCommonInput!): Void
     updateApplicationConfig(input: UpdateApplicationConfigInput!): Application!
+    """
+    将用户反馈导出为数据集中的QA文件，可以作为评估的标准答案
+    """
+    exportFeedback(input: ExportFeedbackInput!): ExportFeedbackResult!
 }
 extend type Mutation {
     Application: ApplicationMutation
@@ -5577,6 +5812,200 @@ input UpdateApplicationConfigInput {
     """
     batchSize: Int
 }
+
+"""
+UsageInput
+用量查询条件
+"""
+input UsageInput {
+    """
+    应用所在的命名空间
+    规则: 必填
+    """
+    namespace: String!
+
+    """
+    应用名称，为空时统计命名空间下的所有应用
+    """
+    appName: String
+
+    """
+    用户名称，为空时统计所有用户
+    """
+    user: String
+
+    """
+    开始时间，默认为今天零点
+    """
+    startTime: Time
+
+    """
+    结束时间，默认为当前时间
+    """
+    endTime: Time
+
+    """
+    分组维度，可选 user 和 application，总是按命名空间分组
+    """
+    groupBy: [String!]
+}
+
+"""
+Usage
+token 用量
+"""
+type Usage {
+    namespace: String!
+
+    """
+    应用名称，按 application 分组时有值
+    """
+    appName: String
+
+    """
+    用户名称，按 user 分组时有值
+    """
+    user: String
+
+    """
+    LLM 调用次数
+    """
+    requests: Int!
+
+    promptTokens: Int!
+    completionTokens: Int!
+    totalTokens: Int!
+}
+
+"""
+FeedbackStatsInput
+用户反馈统计条件
+"""
+input FeedbackStatsInput {
+    """
+    应用所在的命名空间
+    规则: 必填
+    """
+    namespace: String!
+
+    """
+    应用名称，为空时统计命名空间下的所有应用
+    """
+    appName: String
+
+    """
+    开始时间，为空时不限制
+    """
+    startTime: Time
+
+    """
+    结束时间，默认为当前时间
+    """
+    endTime: Time
+}
+
+"""
+FeedbackStat
+应用的用户反馈统计
+"""
+type FeedbackStat {
+    namespace: String!
+    appName: String!
+
+    """
+    反馈总数
+    """
+    total: Int!
+
+    """
+    点赞数
+    """
+    likes: Int!
+
+    """
+    点踩数
+    """
+    dislikes: Int!
+
+    """
+    按评价和原因分类的反馈数
+    """
+    reasons: [FeedbackReasonCount!]!
+}
+
+"""
+FeedbackReasonCount
+某一评价和原因分类的反馈数
+"""
+type FeedbackReasonCount {
+    """
+    评价: like 或 dislike
+    """
+    rating: String!
+
+    """
+    原因分类: inaccurate,irrelevant,incomplete,harmful,helpful,other，为空表示未填写
+    """
+    reason: String!
+
+    count: Int!
+}
+
+"""
+ExportFeedbackInput
+导出用户反馈的参数
+"""
+input ExportFeedbackInput {
+    """
+    应用所在的命名空间，数据集也需要在此命名空间
+    规则: 必填
+    """
+    namespace: String!
+
+    """
+    应用名称
+    规则: 必填
+    """
+    appName: String!
+
+    """
+    导出到的数据集版本(VersionedDataset)名称
+    规则: 必填，不能是已发布的版本
+    """
+    versionedDataset: String!
+
+    """
+    导出的文件名称，默认为 feedback-<应用名称>-<时间>.csv
+    """
+    fileName: String
+
+    """
+    开始时间，为空时不限制
+    """
+    startTime: Time
+
+    """
+    结束时间，默认为当前时间
+    """
+    endTime: Time
+}
+
+"""
+ExportFeedbackResult
+导出结果
+导出的是 q,a 两列的QA文件，a 为用户修正后的答案或者点赞的答案，没有修正的点踩答案不会导出
+"""
+type ExportFeedbackResult {
+    """
+    导出的文件在数据集版本中的完整路径，数据集版本同步完成后可以直接在 RAG 评估中使用
+    """
+    object: String!
+
+    """
+    导出的QA数量
+    """
+    count: Int!
+}
 `, BuiltIn: false},
 	{Name: "../schema/dataprocessing.graphqls", Input: `# 数据处理 Mutation
 type DataProcessMutation {
@@ -6800,6 +7229,11 @@ type filedetail{
     文件最新版本
     """
     latestVersion: String!
+
+    """
+    文件已尝试处理的次数
+    """
+    attempts: Int!
 }
 
 """
@@ -7033,6 +7467,10 @@ type KnowledgeBaseMutation {
     createKnowledgeBase(input: CreateKnowledgeBaseInput!): KnowledgeBase!
     updateKnowledgeBase(input: UpdateKnowledgeBaseInput): KnowledgeBase!
     deleteKnowledgeBase(input: DeleteCommonInput): Void
+    """
+    重新处理知识库中处理失败的文件
+    """
+    retryFailedFiles(name: String!, namespace: String!): KnowledgeBase!
 }
 
 # mutation
@@ -8176,9 +8614,10 @@ type Worker {
 
     """
     Worker类型
-    支持两种类型: 
+    支持三种类型: 
     - "fastchat" : fastchat提供的通用的推理服务模式
     - "fastchat-vllm" : fastchat提供的采用VLLM推理加速的推理服务模式
+    - "llamacpp" : llama.cpp提供的CPU推理服务模式,仅支持GGUF格式的模型
     规则: 如果为空，则默认为 "fastchat"
     """
     type: String
@@ -8249,9 +8688,10 @@ input CreateWorkerInput{
 
     """
     Worker类型
-    支持两种类型: 
+    支持三种类型: 
     - "fastchat" : fastchat提供的通用的推理服务模式
     - "fastchat-vllm" : fastchat提供的采用VLLM推理加速的推理服务模式
+    - "llamacpp" : llama.cpp提供的CPU推理服务模式,仅支持GGUF格式的模型
     规则: 如果为空，则默认为 "fastchat"
     """
     type: String
@@ -8300,9 +8740,10 @@ input UpdateWorkerInput {
 
     """
     Worker类型
-    支持两种类型: 
+    支持三种类型: 
     - "fastchat" : fastchat提供的通用的推理服务模式
     - "fastchat-vllm" : fastchat提供的采用VLLM推理加速的推理服务模式
+    - "llamacpp" : llama.cpp提供的CPU推理服务模式,仅支持GGUF格式的模型
     规则: 如果为空，则不更新；如果type类型与当前类型相同，则不更新
     """
     type: String
@@ -8411,6 +8852,21 @@ func (ec *executionContext) field_ApplicationMutation_deleteApplication_args(ctx
 	return args, nil
 }
 
+func (ec *executionContext) field_ApplicationMutation_exportFeedback_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 ExportFeedbackInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNExportFeedbackInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐExportFeedbackInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_ApplicationMutation_updateApplicationConfig_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -8465,6 +8921,36 @@ func (ec *executionContext) field_ApplicationQuery_getApplication_args(ctx conte
 	return args, nil
 }
 
+func (ec *executionContext) field_ApplicationQuery_getFeedbackStats_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 FeedbackStatsInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNFeedbackStatsInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackStatsInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ApplicationQuery_getUsage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 UsageInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNUsageInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUsageInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_ApplicationQuery_listApplicationMetadata_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -8957,244 +9443,268 @@ func (ec *executionContext) field_KnowledgeBaseMutation_deleteKnowledgeBase_args
 	return args, nil
 }
 
-func (ec *executionContext) field_KnowledgeBaseMutation_updateKnowledgeBase_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 *UpdateKnowledgeBaseInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalOUpdateKnowledgeBaseInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUpdateKnowledgeBaseInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_KnowledgeBaseQuery_getKnowledgeBase_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["name"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["name"] = arg0
-	var arg1 string
-	if tmp, ok := rawArgs["namespace"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
-		arg1, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["namespace"] = arg1
-	return args, nil
-}
-
-func (ec *executionContext) field_KnowledgeBaseQuery_listKnowledgeBases_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 ListKnowledgeBaseInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalNListKnowledgeBaseInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐListKnowledgeBaseInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_LLMQuery_getLLM_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["name"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["name"] = arg0
-	var arg1 string
-	if tmp, ok := rawArgs["namespace"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
-		arg1, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["namespace"] = arg1
-	return args, nil
-}
-
-func (ec *executionContext) field_LLMQuery_listLLMs_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 ListCommonInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalNListCommonInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐListCommonInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelMutation_createModel_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 CreateModelInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalNCreateModelInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐCreateModelInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelMutation_deleteModels_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 *DeleteCommonInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalODeleteCommonInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐDeleteCommonInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelMutation_updateModel_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 *UpdateModelInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalOUpdateModelInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUpdateModelInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelQuery_getModel_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["name"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["name"] = arg0
-	var arg1 string
-	if tmp, ok := rawArgs["namespace"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
-		arg1, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["namespace"] = arg1
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelQuery_listModels_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 ListModelInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalNListModelInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐListModelInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelServiceMutation_createModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 CreateModelServiceInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalNCreateModelServiceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐCreateModelServiceInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelServiceMutation_deleteModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 *DeleteCommonInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalODeleteCommonInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐDeleteCommonInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelServiceMutation_updateModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 *UpdateModelServiceInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalOUpdateModelServiceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUpdateModelServiceInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelServiceQuery_checkModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 CreateModelServiceInput
-	if tmp, ok := rawArgs["input"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
-		arg0, err = ec.unmarshalNCreateModelServiceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐCreateModelServiceInput(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["input"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field_ModelServiceQuery_getModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_KnowledgeBaseMutation_retryFailedFiles_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["namespace"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+		arg1, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["namespace"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_KnowledgeBaseMutation_updateKnowledgeBase_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *UpdateKnowledgeBaseInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalOUpdateKnowledgeBaseInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUpdateKnowledgeBaseInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_KnowledgeBaseQuery_getKnowledgeBase_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["namespace"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+		arg1, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["namespace"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_KnowledgeBaseQuery_listKnowledgeBases_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 ListKnowledgeBaseInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNListKnowledgeBaseInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐListKnowledgeBaseInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_LLMQuery_getLLM_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["namespace"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+		arg1, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["namespace"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_LLMQuery_listLLMs_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 ListCommonInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNListCommonInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐListCommonInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelMutation_createModel_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 CreateModelInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNCreateModelInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐCreateModelInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelMutation_deleteModels_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *DeleteCommonInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalODeleteCommonInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐDeleteCommonInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelMutation_updateModel_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *UpdateModelInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalOUpdateModelInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUpdateModelInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelQuery_getModel_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["namespace"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+		arg1, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["namespace"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelQuery_listModels_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 ListModelInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNListModelInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐListModelInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelServiceMutation_createModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 CreateModelServiceInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNCreateModelServiceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐCreateModelServiceInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelServiceMutation_deleteModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *DeleteCommonInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalODeleteCommonInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐDeleteCommonInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelServiceMutation_updateModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *UpdateModelServiceInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalOUpdateModelServiceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUpdateModelServiceInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelServiceQuery_checkModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 CreateModelServiceInput
+	if tmp, ok := rawArgs["input"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("input"))
+		arg0, err = ec.unmarshalNCreateModelServiceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐCreateModelServiceInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_ModelServiceQuery_getModelService_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
 	var arg0 string
@@ -11753,6 +12263,67 @@ func (ec *executionContext) fieldContext_ApplicationMutation_updateApplicationCo
 	return fc, nil
 }
 
+func (ec *executionContext) _ApplicationMutation_exportFeedback(ctx context.Context, field graphql.CollectedField, obj *ApplicationMutation) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ApplicationMutation_exportFeedback(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.ApplicationMutation().ExportFeedback(rctx, obj, fc.Args["input"].(ExportFeedbackInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*ExportFeedbackResult)
+	fc.Result = res
+	return ec.marshalNExportFeedbackResult2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐExportFeedbackResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ApplicationMutation_exportFeedback(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApplicationMutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "object":
+				return ec.fieldContext_ExportFeedbackResult_object(ctx, field)
+			case "count":
+				return ec.fieldContext_ExportFeedbackResult_count(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ExportFeedbackResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_ApplicationMutation_exportFeedback_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ApplicationQuery_getApplication(ctx context.Context, field graphql.CollectedField, obj *ApplicationQuery) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_ApplicationQuery_getApplication(ctx, field)
 	if err != nil {
@@ -11929,6 +12500,146 @@ func (ec *executionContext) fieldContext_ApplicationQuery_listApplicationMetadat
 	return fc, nil
 }
 
+func (ec *executionContext) _ApplicationQuery_getUsage(ctx context.Context, field graphql.CollectedField, obj *ApplicationQuery) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ApplicationQuery_getUsage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.ApplicationQuery().GetUsage(rctx, obj, fc.Args["input"].(UsageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*Usage)
+	fc.Result = res
+	return ec.marshalNUsage2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUsageᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ApplicationQuery_getUsage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApplicationQuery",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "namespace":
+				return ec.fieldContext_Usage_namespace(ctx, field)
+			case "appName":
+				return ec.fieldContext_Usage_appName(ctx, field)
+			case "user":
+				return ec.fieldContext_Usage_user(ctx, field)
+			case "requests":
+				return ec.fieldContext_Usage_requests(ctx, field)
+			case "promptTokens":
+				return ec.fieldContext_Usage_promptTokens(ctx, field)
+			case "completionTokens":
+				return ec.fieldContext_Usage_completionTokens(ctx, field)
+			case "totalTokens":
+				return ec.fieldContext_Usage_totalTokens(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Usage", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_ApplicationQuery_getUsage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApplicationQuery_getFeedbackStats(ctx context.Context, field graphql.CollectedField, obj *ApplicationQuery) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ApplicationQuery_getFeedbackStats(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.ApplicationQuery().GetFeedbackStats(rctx, obj, fc.Args["input"].(FeedbackStatsInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*FeedbackStat)
+	fc.Result = res
+	return ec.marshalNFeedbackStat2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackStatᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ApplicationQuery_getFeedbackStats(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApplicationQuery",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "namespace":
+				return ec.fieldContext_FeedbackStat_namespace(ctx, field)
+			case "appName":
+				return ec.fieldContext_FeedbackStat_appName(ctx, field)
+			case "total":
+				return ec.fieldContext_FeedbackStat_total(ctx, field)
+			case "likes":
+				return ec.fieldContext_FeedbackStat_likes(ctx, field)
+			case "dislikes":
+				return ec.fieldContext_FeedbackStat_dislikes(ctx, field)
+			case "reasons":
+				return ec.fieldContext_FeedbackStat_reasons(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FeedbackStat", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_ApplicationQuery_getFeedbackStats_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _CountDataProcessItem_status(ctx context.Context, field graphql.CollectedField, obj *CountDataProcessItem) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_CountDataProcessItem_status(ctx, field)
 	if err != nil {
@@ -19060,6 +19771,94 @@ func (ec *executionContext) fieldContext_Endpoint_insecure(ctx context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _ExportFeedbackResult_object(ctx context.Context, field graphql.CollectedField, obj *ExportFeedbackResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ExportFeedbackResult_object(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Object, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ExportFeedbackResult_object(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ExportFeedbackResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ExportFeedbackResult_count(ctx context.Context, field graphql.CollectedField, obj *ExportFeedbackResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ExportFeedbackResult_count(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Count, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ExportFeedbackResult_count(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ExportFeedbackResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _F_path(ctx context.Context, field graphql.CollectedField, obj *F) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_F_path(ctx, field)
 	if err != nil {
@@ -19394,6 +20193,410 @@ func (ec *executionContext) fieldContext_F_latestVersion(ctx context.Context, fi
 	return fc, nil
 }
 
+func (ec *executionContext) _FeedbackReasonCount_rating(ctx context.Context, field graphql.CollectedField, obj *FeedbackReasonCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackReasonCount_rating(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Rating, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackReasonCount_rating(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackReasonCount",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackReasonCount_reason(ctx context.Context, field graphql.CollectedField, obj *FeedbackReasonCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackReasonCount_reason(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Reason, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackReasonCount_reason(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackReasonCount",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackReasonCount_count(ctx context.Context, field graphql.CollectedField, obj *FeedbackReasonCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackReasonCount_count(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Count, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackReasonCount_count(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackReasonCount",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackStat_namespace(ctx context.Context, field graphql.CollectedField, obj *FeedbackStat) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackStat_namespace(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Namespace, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackStat_namespace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackStat",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackStat_appName(ctx context.Context, field graphql.CollectedField, obj *FeedbackStat) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackStat_appName(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.AppName, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackStat_appName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackStat",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackStat_total(ctx context.Context, field graphql.CollectedField, obj *FeedbackStat) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackStat_total(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Total, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackStat_total(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackStat",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackStat_likes(ctx context.Context, field graphql.CollectedField, obj *FeedbackStat) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackStat_likes(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Likes, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackStat_likes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackStat",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackStat_dislikes(ctx context.Context, field graphql.CollectedField, obj *FeedbackStat) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackStat_dislikes(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Dislikes, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackStat_dislikes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackStat",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FeedbackStat_reasons(ctx context.Context, field graphql.CollectedField, obj *FeedbackStat) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FeedbackStat_reasons(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Reasons, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*FeedbackReasonCount)
+	fc.Result = res
+	return ec.marshalNFeedbackReasonCount2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackReasonCountᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FeedbackStat_reasons(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FeedbackStat",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "rating":
+				return ec.fieldContext_FeedbackReasonCount_rating(ctx, field)
+			case "reason":
+				return ec.fieldContext_FeedbackReasonCount_reason(ctx, field)
+			case "count":
+				return ec.fieldContext_FeedbackReasonCount_count(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FeedbackReasonCount", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _FileDetails_file_name(ctx context.Context, field graphql.CollectedField, obj *FileDetails) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_FileDetails_file_name(ctx, field)
 	if err != nil {
@@ -21849,6 +23052,103 @@ func (ec *executionContext) fieldContext_KnowledgeBaseMutation_deleteKnowledgeBa
 	return fc, nil
 }
 
+func (ec *executionContext) _KnowledgeBaseMutation_retryFailedFiles(ctx context.Context, field graphql.CollectedField, obj *KnowledgeBaseMutation) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_KnowledgeBaseMutation_retryFailedFiles(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.KnowledgeBaseMutation().RetryFailedFiles(rctx, obj, fc.Args["name"].(string), fc.Args["namespace"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*KnowledgeBase)
+	fc.Result = res
+	return ec.marshalNKnowledgeBase2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐKnowledgeBase(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_KnowledgeBaseMutation_retryFailedFiles(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "KnowledgeBaseMutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_KnowledgeBase_id(ctx, field)
+			case "name":
+				return ec.fieldContext_KnowledgeBase_name(ctx, field)
+			case "namespace":
+				return ec.fieldContext_KnowledgeBase_namespace(ctx, field)
+			case "labels":
+				return ec.fieldContext_KnowledgeBase_labels(ctx, field)
+			case "annotations":
+				return ec.fieldContext_KnowledgeBase_annotations(ctx, field)
+			case "creator":
+				return ec.fieldContext_KnowledgeBase_creator(ctx, field)
+			case "displayName":
+				return ec.fieldContext_KnowledgeBase_displayName(ctx, field)
+			case "description":
+				return ec.fieldContext_KnowledgeBase_description(ctx, field)
+			case "creationTimestamp":
+				return ec.fieldContext_KnowledgeBase_creationTimestamp(ctx, field)
+			case "updateTimestamp":
+				return ec.fieldContext_KnowledgeBase_updateTimestamp(ctx, field)
+			case "embedder":
+				return ec.fieldContext_KnowledgeBase_embedder(ctx, field)
+			case "embedderType":
+				return ec.fieldContext_KnowledgeBase_embedderType(ctx, field)
+			case "vectorStore":
+				return ec.fieldContext_KnowledgeBase_vectorStore(ctx, field)
+			case "fileGroupDetails":
+				return ec.fieldContext_KnowledgeBase_fileGroupDetails(ctx, field)
+			case "chunkSize":
+				return ec.fieldContext_KnowledgeBase_chunkSize(ctx, field)
+			case "chunkOverlap":
+				return ec.fieldContext_KnowledgeBase_chunkOverlap(ctx, field)
+			case "batchSize":
+				return ec.fieldContext_KnowledgeBase_batchSize(ctx, field)
+			case "status":
+				return ec.fieldContext_KnowledgeBase_status(ctx, field)
+			case "reason":
+				return ec.fieldContext_KnowledgeBase_reason(ctx, field)
+			case "message":
+				return ec.fieldContext_KnowledgeBase_message(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type KnowledgeBase", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_KnowledgeBaseMutation_retryFailedFiles_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _KnowledgeBaseQuery_getKnowledgeBase(ctx context.Context, field graphql.CollectedField, obj *KnowledgeBaseQuery) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_KnowledgeBaseQuery_getKnowledgeBase(ctx, field)
 	if err != nil {
@@ -25840,6 +27140,8 @@ func (ec *executionContext) fieldContext_Mutation_Application(ctx context.Contex
 				return ec.fieldContext_ApplicationMutation_deleteApplication(ctx, field)
 			case "updateApplicationConfig":
 				return ec.fieldContext_ApplicationMutation_updateApplicationConfig(ctx, field)
+			case "exportFeedback":
+				return ec.fieldContext_ApplicationMutation_exportFeedback(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ApplicationMutation", field.Name)
 		},
@@ -26083,6 +27385,8 @@ func (ec *executionContext) fieldContext_Mutation_KnowledgeBase(ctx context.Cont
 				return ec.fieldContext_KnowledgeBaseMutation_updateKnowledgeBase(ctx, field)
 			case "deleteKnowledgeBase":
 				return ec.fieldContext_KnowledgeBaseMutation_deleteKnowledgeBase(ctx, field)
+			case "retryFailedFiles":
+				return ec.fieldContext_KnowledgeBaseMutation_retryFailedFiles(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type KnowledgeBaseMutation", field.Name)
 		},
@@ -27650,6 +28954,10 @@ func (ec *executionContext) fieldContext_Query_Application(ctx context.Context,
 				return ec.fieldContext_ApplicationQuery_getApplication(ctx, field)
 			case "listApplicationMetadata":
 				return ec.fieldContext_ApplicationQuery_listApplicationMetadata(ctx, field)
+			case "getUsage":
+				return ec.fieldContext_ApplicationQuery_getUsage(ctx, field)
+			case "getFeedbackStats":
+				return ec.fieldContext_ApplicationQuery_getFeedbackStats(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ApplicationQuery", field.Name)
 		},
@@ -31215,6 +32523,308 @@ func (ec *executionContext) fieldContext_TypedObjectReference_namespace(ctx cont
 	return fc, nil
 }
 
+func (ec *executionContext) _Usage_namespace(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_namespace(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Namespace, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_namespace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Usage_appName(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_appName(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.AppName, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_appName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Usage_user(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_user(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.User, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_user(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Usage_requests(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_requests(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Requests, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_requests(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Usage_promptTokens(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_promptTokens(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.PromptTokens, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_promptTokens(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Usage_completionTokens(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_completionTokens(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.CompletionTokens, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_completionTokens(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Usage_totalTokens(ctx context.Context, field graphql.CollectedField, obj *Usage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Usage_totalTokens(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.TotalTokens, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Usage_totalTokens(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Usage",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _VersionedDataset_id(ctx context.Context, field graphql.CollectedField, obj *VersionedDataset) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_VersionedDataset_id(ctx, field)
 	if err != nil {
@@ -35883,6 +37493,50 @@ func (ec *executionContext) fieldContext_filedetail_latestVersion(ctx context.Co
 	return fc, nil
 }
 
+func (ec *executionContext) _filedetail_attempts(ctx context.Context, field graphql.CollectedField, obj *Filedetail) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_filedetail_attempts(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Attempts, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_filedetail_attempts(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "filedetail",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _filegroup_source(ctx context.Context, field graphql.CollectedField, obj *Filegroup) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_filegroup_source(ctx, field)
 	if err != nil {
@@ -36131,6 +37785,8 @@ func (ec *executionContext) fieldContext_filegroupdetail_filedetails(ctx context
 				return ec.fieldContext_filedetail_version(ctx, field)
 			case "latestVersion":
 				return ec.fieldContext_filedetail_latestVersion(ctx, field)
+			case "attempts":
+				return ec.fieldContext_filedetail_attempts(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type filedetail", field.Name)
 		},
@@ -36157,6 +37813,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36164,6 +37822,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.Name = data
 		case "file_type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("file_type"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36171,6 +37831,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.FileType = data
 		case "pre_data_set_name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pre_data_set_name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36178,6 +37840,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.PreDataSetName = data
 		case "pre_data_set_version":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pre_data_set_version"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36185,6 +37849,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.PreDataSetVersion = data
 		case "file_names":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("file_names"))
 			data, err := ec.unmarshalOFileItem2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFileItemᚄ(ctx, v)
 			if err != nil {
@@ -36192,6 +37858,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.FileNames = data
 		case "post_data_set_name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("post_data_set_name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36199,6 +37867,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.PostDataSetName = data
 		case "post_data_set_version":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("post_data_set_version"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36206,6 +37876,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.PostDataSetVersion = data
 		case "data_process_config_info":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("data_process_config_info"))
 			data, err := ec.unmarshalODataProcessConfigItem2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐDataProcessConfigItemᚄ(ctx, v)
 			if err != nil {
@@ -36213,6 +37885,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.DataProcessConfigInfo = data
 		case "version_data_set_name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version_data_set_name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36220,6 +37894,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.VersionDataSetName = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36227,6 +37903,8 @@ func (ec *executionContext) unmarshalInputAddDataProcessInput(ctx context.Contex
 			}
 			it.Namespace = data
 		case "creator":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("creator"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36254,6 +37932,8 @@ func (ec *executionContext) unmarshalInputAllDataProcessListByCountInput(ctx con
 		}
 		switch k {
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36261,6 +37941,8 @@ func (ec *executionContext) unmarshalInputAllDataProcessListByCountInput(ctx con
 			}
 			it.Keyword = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36288,6 +37970,8 @@ func (ec *executionContext) unmarshalInputAllDataProcessListByPageInput(ctx cont
 		}
 		switch k {
 		case "pageIndex":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageIndex"))
 			data, err := ec.unmarshalNInt2int(ctx, v)
 			if err != nil {
@@ -36295,6 +37979,8 @@ func (ec *executionContext) unmarshalInputAllDataProcessListByPageInput(ctx cont
 			}
 			it.PageIndex = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalNInt2int(ctx, v)
 			if err != nil {
@@ -36302,6 +37988,8 @@ func (ec *executionContext) unmarshalInputAllDataProcessListByPageInput(ctx cont
 			}
 			it.PageSize = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36309,6 +37997,8 @@ func (ec *executionContext) unmarshalInputAllDataProcessListByPageInput(ctx cont
 			}
 			it.Keyword = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36336,6 +38026,8 @@ func (ec *executionContext) unmarshalInputCheckDataProcessTaskNameInput(ctx cont
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36343,6 +38035,8 @@ func (ec *executionContext) unmarshalInputCheckDataProcessTaskNameInput(ctx cont
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36370,6 +38064,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36377,6 +38073,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36384,6 +38082,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36391,6 +38091,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36398,6 +38100,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36405,6 +38109,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36412,6 +38118,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.Description = data
 		case "icon":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("icon"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36419,6 +38127,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.Icon = data
 		case "isPublic":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("isPublic"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -36426,6 +38136,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.IsPublic = data
 		case "isRecommended":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("isRecommended"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -36433,6 +38145,8 @@ func (ec *executionContext) unmarshalInputCreateApplicationMetadataInput(ctx con
 			}
 			it.IsRecommended = data
 		case "category":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("category"))
 			data, err := ec.unmarshalOString2ᚕᚖstring(ctx, v)
 			if err != nil {
@@ -36460,6 +38174,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36467,6 +38183,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36474,6 +38192,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36481,6 +38201,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36488,6 +38210,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36495,6 +38219,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36502,6 +38228,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.Description = data
 		case "contentType":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("contentType"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36509,6 +38237,8 @@ func (ec *executionContext) unmarshalInputCreateDatasetInput(ctx context.Context
 			}
 			it.ContentType = data
 		case "filed":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("filed"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36536,6 +38266,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36543,6 +38275,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36550,6 +38284,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36557,6 +38293,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36564,6 +38302,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36571,6 +38311,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36578,6 +38320,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Description = data
 		case "endpointinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpointinput"))
 			data, err := ec.unmarshalNEndpointInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -36585,6 +38329,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Endpointinput = data
 		case "ossinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("ossinput"))
 			data, err := ec.unmarshalOOssInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐOssInput(ctx, v)
 			if err != nil {
@@ -36592,6 +38338,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Ossinput = data
 		case "pginput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pginput"))
 			data, err := ec.unmarshalOPgInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐPgInput(ctx, v)
 			if err != nil {
@@ -36599,6 +38347,8 @@ func (ec *executionContext) unmarshalInputCreateDatasourceInput(ctx context.Cont
 			}
 			it.Pginput = data
 		case "webinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("webinput"))
 			data, err := ec.unmarshalOWebInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐWebInput(ctx, v)
 			if err != nil {
@@ -36626,6 +38376,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36633,6 +38385,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36640,6 +38394,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36647,6 +38403,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36654,6 +38412,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36661,6 +38421,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36668,6 +38430,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Description = data
 		case "endpointinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpointinput"))
 			data, err := ec.unmarshalNEndpointInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -36675,6 +38439,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Endpointinput = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36682,6 +38448,8 @@ func (ec *executionContext) unmarshalInputCreateEmbedderInput(ctx context.Contex
 			}
 			it.Type = data
 		case "models":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("models"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -36709,6 +38477,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36716,6 +38486,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36723,6 +38495,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36730,6 +38504,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36737,6 +38513,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36744,6 +38522,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36751,6 +38531,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.Description = data
 		case "embedder":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embedder"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36758,6 +38540,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.Embedder = data
 		case "vectorStore":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("vectorStore"))
 			data, err := ec.unmarshalOTypedObjectReferenceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -36765,6 +38549,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.VectorStore = data
 		case "fileGroups":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fileGroups"))
 			data, err := ec.unmarshalOfilegroupinput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFilegroupinputᚄ(ctx, v)
 			if err != nil {
@@ -36772,6 +38558,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.FileGroups = data
 		case "chunkSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunkSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -36779,6 +38567,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.ChunkSize = data
 		case "chunkOverlap":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunkOverlap"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -36786,6 +38576,8 @@ func (ec *executionContext) unmarshalInputCreateKnowledgeBaseInput(ctx context.C
 			}
 			it.ChunkOverlap = data
 		case "batchSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("batchSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -36813,6 +38605,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36820,6 +38614,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36827,6 +38623,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36834,6 +38632,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -36841,6 +38641,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36848,6 +38650,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36855,6 +38659,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Description = data
 		case "endpointinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpointinput"))
 			data, err := ec.unmarshalNEndpointInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -36862,6 +38668,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Endpointinput = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36869,6 +38677,8 @@ func (ec *executionContext) unmarshalInputCreateLLMInput(ctx context.Context, ob
 			}
 			it.Type = data
 		case "models":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("models"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -36896,6 +38706,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36903,6 +38715,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36910,6 +38724,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36917,6 +38733,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36924,6 +38742,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.Description = data
 		case "types":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("types"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36931,6 +38751,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.Types = data
 		case "huggingFaceRepo":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("huggingFaceRepo"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36938,6 +38760,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.HuggingFaceRepo = data
 		case "modelScopeRepo":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelScopeRepo"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36945,6 +38769,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.ModelScopeRepo = data
 		case "revision":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("revision"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36952,6 +38778,8 @@ func (ec *executionContext) unmarshalInputCreateModelInput(ctx context.Context,
 			}
 			it.Revision = data
 		case "modelSource":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelSource"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -36979,6 +38807,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36986,6 +38816,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -36993,6 +38825,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37000,6 +38834,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37007,6 +38843,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37014,6 +38852,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37021,6 +38861,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Description = data
 		case "types":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("types"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37028,6 +38870,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Types = data
 		case "apiType":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("apiType"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37035,6 +38879,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.APIType = data
 		case "endpoint":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpoint"))
 			data, err := ec.unmarshalNEndpointInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -37042,6 +38888,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.Endpoint = data
 		case "llmModels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("llmModels"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -37049,6 +38897,8 @@ func (ec *executionContext) unmarshalInputCreateModelServiceInput(ctx context.Co
 			}
 			it.LlmModels = data
 		case "embeddingModels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embeddingModels"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -37076,6 +38926,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37083,6 +38935,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37090,6 +38944,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37097,6 +38953,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37104,6 +38962,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Annotations = data
 		case "creator":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("creator"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37111,6 +38971,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Creator = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37118,6 +38980,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37125,6 +38989,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Description = data
 		case "application":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("application"))
 			data, err := ec.unmarshalNTypedObjectReferenceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -37132,6 +38998,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Application = data
 		case "datasets":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("datasets"))
 			data, err := ec.unmarshalNRAGDatasetInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐRAGDatasetInputᚄ(ctx, v)
 			if err != nil {
@@ -37139,6 +39007,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Datasets = data
 		case "judgeLLM":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("judgeLLM"))
 			data, err := ec.unmarshalNTypedObjectReferenceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -37146,6 +39016,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.JudgeLlm = data
 		case "metrics":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("metrics"))
 			data, err := ec.unmarshalNRAGMetricInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐRAGMetricInputᚄ(ctx, v)
 			if err != nil {
@@ -37153,6 +39025,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Metrics = data
 		case "storage":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("storage"))
 			data, err := ec.unmarshalOPersistentVolumeClaimSpecInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐPersistentVolumeClaimSpecInput(ctx, v)
 			if err != nil {
@@ -37160,6 +39034,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.Storage = data
 		case "serviceAccountName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("serviceAccountName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37167,6 +39043,8 @@ func (ec *executionContext) unmarshalInputCreateRAGInput(ctx context.Context, ob
 			}
 			it.ServiceAccountName = data
 		case "suspend":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("suspend"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -37194,6 +39072,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37201,6 +39081,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37208,6 +39090,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Namespace = data
 		case "datasetName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("datasetName"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37215,6 +39099,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.DatasetName = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37222,6 +39108,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37229,6 +39117,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37236,6 +39126,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37243,6 +39135,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Description = data
 		case "version":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37250,6 +39144,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Version = data
 		case "released":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("released"))
 			data, err := ec.unmarshalNInt2int(ctx, v)
 			if err != nil {
@@ -37257,6 +39153,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.Released = data
 		case "fileGrups":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fileGrups"))
 			data, err := ec.unmarshalOFileGroup2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFileGroup(ctx, v)
 			if err != nil {
@@ -37264,6 +39162,8 @@ func (ec *executionContext) unmarshalInputCreateVersionedDatasetInput(ctx contex
 			}
 			it.FileGrups = data
 		case "inheritedFrom":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("inheritedFrom"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37291,6 +39191,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37298,6 +39200,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37305,6 +39209,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37312,6 +39218,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37319,6 +39227,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.Description = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37326,6 +39236,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.Type = data
 		case "model":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("model"))
 			data, err := ec.unmarshalNTypedObjectReferenceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -37333,6 +39245,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.Model = data
 		case "resources":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("resources"))
 			data, err := ec.unmarshalNResourcesInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐResourcesInput(ctx, v)
 			if err != nil {
@@ -37340,6 +39254,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.Resources = data
 		case "matchExpressions":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("matchExpressions"))
 			data, err := ec.unmarshalONodeSelectorRequirementInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐNodeSelectorRequirementInput(ctx, v)
 			if err != nil {
@@ -37347,6 +39263,8 @@ func (ec *executionContext) unmarshalInputCreateWorkerInput(ctx context.Context,
 			}
 			it.MatchExpressions = data
 		case "additionalEnvs":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("additionalEnvs"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37374,6 +39292,8 @@ func (ec *executionContext) unmarshalInputDataProcessConfigItem(ctx context.Cont
 		}
 		switch k {
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37381,6 +39301,8 @@ func (ec *executionContext) unmarshalInputDataProcessConfigItem(ctx context.Cont
 			}
 			it.Type = data
 		case "chunk_size":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunk_size"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -37388,6 +39310,8 @@ func (ec *executionContext) unmarshalInputDataProcessConfigItem(ctx context.Cont
 			}
 			it.ChunkSize = data
 		case "chunk_overlap":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunk_overlap"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -37395,6 +39319,8 @@ func (ec *executionContext) unmarshalInputDataProcessConfigItem(ctx context.Cont
 			}
 			it.ChunkOverlap = data
 		case "llm_config":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("llm_config"))
 			data, err := ec.unmarshalOLLMConfigItem2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐLLMConfigItem(ctx, v)
 			if err != nil {
@@ -37402,6 +39328,8 @@ func (ec *executionContext) unmarshalInputDataProcessConfigItem(ctx context.Cont
 			}
 			it.LlmConfig = data
 		case "remove_duplicate_config":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("remove_duplicate_config"))
 			data, err := ec.unmarshalORemoveDuplicateConfig2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐRemoveDuplicateConfig(ctx, v)
 			if err != nil {
@@ -37429,6 +39357,8 @@ func (ec *executionContext) unmarshalInputDataProcessDetailsInput(ctx context.Co
 		}
 		switch k {
 		case "id":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("id"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37456,6 +39386,8 @@ func (ec *executionContext) unmarshalInputDataProcessFileLogInput(ctx context.Co
 		}
 		switch k {
 		case "id":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("id"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37463,6 +39395,8 @@ func (ec *executionContext) unmarshalInputDataProcessFileLogInput(ctx context.Co
 			}
 			it.ID = data
 		case "file_name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("file_name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37470,6 +39404,8 @@ func (ec *executionContext) unmarshalInputDataProcessFileLogInput(ctx context.Co
 			}
 			it.FileName = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37497,6 +39433,8 @@ func (ec *executionContext) unmarshalInputDataProcessRetryInput(ctx context.Cont
 		}
 		switch k {
 		case "id":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("id"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37504,6 +39442,8 @@ func (ec *executionContext) unmarshalInputDataProcessRetryInput(ctx context.Cont
 			}
 			it.ID = data
 		case "creator":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("creator"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37531,6 +39471,8 @@ func (ec *executionContext) unmarshalInputDeleteCommonInput(ctx context.Context,
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37538,6 +39480,8 @@ func (ec *executionContext) unmarshalInputDeleteCommonInput(ctx context.Context,
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37545,6 +39489,8 @@ func (ec *executionContext) unmarshalInputDeleteCommonInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37552,6 +39498,8 @@ func (ec *executionContext) unmarshalInputDeleteCommonInput(ctx context.Context,
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37579,6 +39527,8 @@ func (ec *executionContext) unmarshalInputDeleteDataProcessInput(ctx context.Con
 		}
 		switch k {
 		case "id":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("id"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37606,6 +39556,8 @@ func (ec *executionContext) unmarshalInputDeleteRAGInput(ctx context.Context, ob
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37613,6 +39565,8 @@ func (ec *executionContext) unmarshalInputDeleteRAGInput(ctx context.Context, ob
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37620,6 +39574,8 @@ func (ec *executionContext) unmarshalInputDeleteRAGInput(ctx context.Context, ob
 			}
 			it.Namespace = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37647,6 +39603,8 @@ func (ec *executionContext) unmarshalInputDeleteVersionedDatasetInput(ctx contex
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37654,6 +39612,8 @@ func (ec *executionContext) unmarshalInputDeleteVersionedDatasetInput(ctx contex
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37661,6 +39621,8 @@ func (ec *executionContext) unmarshalInputDeleteVersionedDatasetInput(ctx contex
 			}
 			it.Namespace = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37668,6 +39630,8 @@ func (ec *executionContext) unmarshalInputDeleteVersionedDatasetInput(ctx contex
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37695,6 +39659,8 @@ func (ec *executionContext) unmarshalInputDuplicateRAGInput(ctx context.Context,
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37702,6 +39668,8 @@ func (ec *executionContext) unmarshalInputDuplicateRAGInput(ctx context.Context,
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37709,6 +39677,8 @@ func (ec *executionContext) unmarshalInputDuplicateRAGInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37736,6 +39706,8 @@ func (ec *executionContext) unmarshalInputEndpointInput(ctx context.Context, obj
 		}
 		switch k {
 		case "url":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("url"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37743,6 +39715,8 @@ func (ec *executionContext) unmarshalInputEndpointInput(ctx context.Context, obj
 			}
 			it.URL = data
 		case "auth":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("auth"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -37750,6 +39724,8 @@ func (ec *executionContext) unmarshalInputEndpointInput(ctx context.Context, obj
 			}
 			it.Auth = data
 		case "insecure":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("insecure"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -37762,6 +39738,136 @@ func (ec *executionContext) unmarshalInputEndpointInput(ctx context.Context, obj
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputExportFeedbackInput(ctx context.Context, obj interface{}) (ExportFeedbackInput, error) {
+	var it ExportFeedbackInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"namespace", "appName", "versionedDataset", "fileName", "startTime", "endTime"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "namespace":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Namespace = data
+		case "appName":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("appName"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AppName = data
+		case "versionedDataset":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("versionedDataset"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.VersionedDataset = data
+		case "fileName":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fileName"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.FileName = data
+		case "startTime":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("startTime"))
+			data, err := ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.StartTime = data
+		case "endTime":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endTime"))
+			data, err := ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.EndTime = data
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputFeedbackStatsInput(ctx context.Context, obj interface{}) (FeedbackStatsInput, error) {
+	var it FeedbackStatsInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"namespace", "appName", "startTime", "endTime"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "namespace":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Namespace = data
+		case "appName":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("appName"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AppName = data
+		case "startTime":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("startTime"))
+			data, err := ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.StartTime = data
+		case "endTime":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endTime"))
+			data, err := ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.EndTime = data
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputFileFilter(ctx context.Context, obj interface{}) (FileFilter, error) {
 	var it FileFilter
 	asMap := map[string]interface{}{}
@@ -37777,6 +39883,8 @@ func (ec *executionContext) unmarshalInputFileFilter(ctx context.Context, obj in
 		}
 		switch k {
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37784,6 +39892,8 @@ func (ec *executionContext) unmarshalInputFileFilter(ctx context.Context, obj in
 			}
 			it.Keyword = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -37791,6 +39901,8 @@ func (ec *executionContext) unmarshalInputFileFilter(ctx context.Context, obj in
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -37798,6 +39910,8 @@ func (ec *executionContext) unmarshalInputFileFilter(ctx context.Context, obj in
 			}
 			it.PageSize = data
 		case "sortBy":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("sortBy"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37825,6 +39939,8 @@ func (ec *executionContext) unmarshalInputFileGroup(ctx context.Context, obj int
 		}
 		switch k {
 		case "source":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("source"))
 			data, err := ec.unmarshalNTypedObjectReferenceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -37832,6 +39948,8 @@ func (ec *executionContext) unmarshalInputFileGroup(ctx context.Context, obj int
 			}
 			it.Source = data
 		case "paths":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("paths"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -37839,6 +39957,8 @@ func (ec *executionContext) unmarshalInputFileGroup(ctx context.Context, obj int
 			}
 			it.Paths = data
 		case "files":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("files"))
 			data, err := ec.unmarshalOFileWithVersionInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFileWithVersionInputᚄ(ctx, v)
 			if err != nil {
@@ -37866,6 +39986,8 @@ func (ec *executionContext) unmarshalInputFileItem(ctx context.Context, obj inte
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37873,6 +39995,8 @@ func (ec *executionContext) unmarshalInputFileItem(ctx context.Context, obj inte
 			}
 			it.Name = data
 		case "size":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("size"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37900,6 +40024,8 @@ func (ec *executionContext) unmarshalInputFileWithVersionInput(ctx context.Conte
 		}
 		switch k {
 		case "path":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("path"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -37907,6 +40033,8 @@ func (ec *executionContext) unmarshalInputFileWithVersionInput(ctx context.Conte
 			}
 			it.Path = data
 		case "version":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37934,6 +40062,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37941,6 +40071,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37948,6 +40080,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.Namespace = data
 		case "model":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("model"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37955,6 +40089,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.Model = data
 		case "temperature":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("temperature"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37962,6 +40098,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.Temperature = data
 		case "top_p":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("top_p"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37969,6 +40107,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.TopP = data
 		case "max_tokens":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("max_tokens"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37976,6 +40116,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.MaxTokens = data
 		case "prompt_template":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("prompt_template"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -37983,6 +40125,8 @@ func (ec *executionContext) unmarshalInputLLMConfigItem(ctx context.Context, obj
 			}
 			it.PromptTemplate = data
 		case "provider":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("provider"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38010,6 +40154,8 @@ func (ec *executionContext) unmarshalInputLabelSelectorRequirementInput(ctx cont
 		}
 		switch k {
 		case "key":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("key"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38017,6 +40163,8 @@ func (ec *executionContext) unmarshalInputLabelSelectorRequirementInput(ctx cont
 			}
 			it.Key = data
 		case "values":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("values"))
 			data, err := ec.unmarshalOString2ᚕᚖstring(ctx, v)
 			if err != nil {
@@ -38024,6 +40172,8 @@ func (ec *executionContext) unmarshalInputLabelSelectorRequirementInput(ctx cont
 			}
 			it.Values = data
 		case "operator":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("operator"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38051,6 +40201,8 @@ func (ec *executionContext) unmarshalInputListCommonInput(ctx context.Context, o
 		}
 		switch k {
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38058,6 +40210,8 @@ func (ec *executionContext) unmarshalInputListCommonInput(ctx context.Context, o
 			}
 			it.Namespace = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38065,6 +40219,8 @@ func (ec *executionContext) unmarshalInputListCommonInput(ctx context.Context, o
 			}
 			it.Keyword = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38072,6 +40228,8 @@ func (ec *executionContext) unmarshalInputListCommonInput(ctx context.Context, o
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38079,6 +40237,8 @@ func (ec *executionContext) unmarshalInputListCommonInput(ctx context.Context, o
 			}
 			it.FieldSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38086,6 +40246,8 @@ func (ec *executionContext) unmarshalInputListCommonInput(ctx context.Context, o
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38113,6 +40275,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 		}
 		switch k {
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38120,6 +40284,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38127,6 +40293,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.Name = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38134,6 +40302,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.DisplayName = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38141,6 +40311,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38148,6 +40320,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.FieldSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38155,6 +40329,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38162,6 +40338,8 @@ func (ec *executionContext) unmarshalInputListDatasetInput(ctx context.Context,
 			}
 			it.PageSize = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38189,6 +40367,8 @@ func (ec *executionContext) unmarshalInputListGPTInput(ctx context.Context, obj
 		}
 		switch k {
 		case "category":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("category"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38196,6 +40376,8 @@ func (ec *executionContext) unmarshalInputListGPTInput(ctx context.Context, obj
 			}
 			it.Category = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38203,6 +40385,8 @@ func (ec *executionContext) unmarshalInputListGPTInput(ctx context.Context, obj
 			}
 			it.Keyword = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38210,6 +40394,8 @@ func (ec *executionContext) unmarshalInputListGPTInput(ctx context.Context, obj
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38237,6 +40423,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38244,6 +40432,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38251,6 +40441,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.Namespace = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38258,6 +40450,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.DisplayName = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38265,6 +40459,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38272,6 +40468,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.FieldSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38279,6 +40477,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38286,6 +40486,8 @@ func (ec *executionContext) unmarshalInputListKnowledgeBaseInput(ctx context.Con
 			}
 			it.PageSize = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38313,6 +40515,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 		}
 		switch k {
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38320,6 +40524,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 			}
 			it.Namespace = data
 		case "systemModel":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("systemModel"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -38327,6 +40533,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 			}
 			it.SystemModel = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38334,6 +40542,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 			}
 			it.Keyword = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38341,6 +40551,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38348,6 +40560,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 			}
 			it.FieldSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38355,6 +40569,8 @@ func (ec *executionContext) unmarshalInputListModelInput(ctx context.Context, ob
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38382,6 +40598,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 		}
 		switch k {
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38389,6 +40607,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 			}
 			it.Keyword = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38396,6 +40616,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 			}
 			it.Namespace = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38403,6 +40625,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38410,6 +40634,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 			}
 			it.PageSize = data
 		case "types":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("types"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38417,6 +40643,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 			}
 			it.Types = data
 		case "providerType":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("providerType"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38424,6 +40652,8 @@ func (ec *executionContext) unmarshalInputListModelServiceInput(ctx context.Cont
 			}
 			it.ProviderType = data
 		case "apiType":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("apiType"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38451,6 +40681,8 @@ func (ec *executionContext) unmarshalInputListNodeInput(ctx context.Context, obj
 		}
 		switch k {
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38458,6 +40690,8 @@ func (ec *executionContext) unmarshalInputListNodeInput(ctx context.Context, obj
 			}
 			it.LabelSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38465,6 +40699,8 @@ func (ec *executionContext) unmarshalInputListNodeInput(ctx context.Context, obj
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38492,6 +40728,8 @@ func (ec *executionContext) unmarshalInputListRAGInput(ctx context.Context, obj
 		}
 		switch k {
 		case "appName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("appName"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38499,6 +40737,8 @@ func (ec *executionContext) unmarshalInputListRAGInput(ctx context.Context, obj
 			}
 			it.AppName = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38506,6 +40746,8 @@ func (ec *executionContext) unmarshalInputListRAGInput(ctx context.Context, obj
 			}
 			it.Namespace = data
 		case "status":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("status"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38513,6 +40755,8 @@ func (ec *executionContext) unmarshalInputListRAGInput(ctx context.Context, obj
 			}
 			it.Status = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38520,6 +40764,8 @@ func (ec *executionContext) unmarshalInputListRAGInput(ctx context.Context, obj
 			}
 			it.Keyword = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38527,6 +40773,8 @@ func (ec *executionContext) unmarshalInputListRAGInput(ctx context.Context, obj
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38554,6 +40802,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38561,6 +40811,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38568,6 +40820,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.Namespace = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38575,6 +40829,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.DisplayName = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38582,6 +40838,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38589,6 +40847,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.FieldSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38596,6 +40856,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38603,6 +40865,8 @@ func (ec *executionContext) unmarshalInputListVersionedDatasetInput(ctx context.
 			}
 			it.PageSize = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38630,6 +40894,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 		}
 		switch k {
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38637,6 +40903,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 			}
 			it.Namespace = data
 		case "keyword":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("keyword"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38644,6 +40912,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 			}
 			it.Keyword = data
 		case "labelSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labelSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38651,6 +40921,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 			}
 			it.LabelSelector = data
 		case "fieldSelector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fieldSelector"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38658,6 +40930,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 			}
 			it.FieldSelector = data
 		case "page":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38665,6 +40939,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 			}
 			it.Page = data
 		case "pageSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pageSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38672,6 +40948,8 @@ func (ec *executionContext) unmarshalInputListWorkerInput(ctx context.Context, o
 			}
 			it.PageSize = data
 		case "modelTypes":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelTypes"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38699,6 +40977,8 @@ func (ec *executionContext) unmarshalInputNodeSelectorRequirementInput(ctx conte
 		}
 		switch k {
 		case "key":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("key"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38706,6 +40986,8 @@ func (ec *executionContext) unmarshalInputNodeSelectorRequirementInput(ctx conte
 			}
 			it.Key = data
 		case "operator":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("operator"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38713,6 +40995,8 @@ func (ec *executionContext) unmarshalInputNodeSelectorRequirementInput(ctx conte
 			}
 			it.Operator = data
 		case "values":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("values"))
 			data, err := ec.unmarshalNString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -38740,6 +41024,8 @@ func (ec *executionContext) unmarshalInputOssInput(ctx context.Context, obj inte
 		}
 		switch k {
 		case "bucket":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("bucket"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38747,6 +41033,8 @@ func (ec *executionContext) unmarshalInputOssInput(ctx context.Context, obj inte
 			}
 			it.Bucket = data
 		case "object":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("object"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38774,6 +41062,8 @@ func (ec *executionContext) unmarshalInputParameterInput(ctx context.Context, ob
 		}
 		switch k {
 		case "key":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("key"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38781,6 +41071,8 @@ func (ec *executionContext) unmarshalInputParameterInput(ctx context.Context, ob
 			}
 			it.Key = data
 		case "value":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("value"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38808,6 +41100,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 		}
 		switch k {
 		case "accessModes":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("accessModes"))
 			data, err := ec.unmarshalNString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -38815,6 +41109,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.AccessModes = data
 		case "selector":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("selector"))
 			data, err := ec.unmarshalOSelectorInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐSelectorInput(ctx, v)
 			if err != nil {
@@ -38822,6 +41118,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.Selector = data
 		case "resources":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("resources"))
 			data, err := ec.unmarshalOResourceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐResourceInput(ctx, v)
 			if err != nil {
@@ -38829,6 +41127,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.Resources = data
 		case "volumeName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("volumeName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38836,6 +41136,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.VolumeName = data
 		case "storageClassName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("storageClassName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38843,6 +41145,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.StorageClassName = data
 		case "volumeMode":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("volumeMode"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38850,6 +41154,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.VolumeMode = data
 		case "datasource":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("datasource"))
 			data, err := ec.unmarshalOTypedObjectReferenceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -38857,6 +41163,8 @@ func (ec *executionContext) unmarshalInputPersistentVolumeClaimSpecInput(ctx con
 			}
 			it.Datasource = data
 		case "dataSourceRef":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("dataSourceRef"))
 			data, err := ec.unmarshalOTypedObjectReferenceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -38884,6 +41192,8 @@ func (ec *executionContext) unmarshalInputPgInput(ctx context.Context, obj inter
 		}
 		switch k {
 		case "database":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("database"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38911,6 +41221,8 @@ func (ec *executionContext) unmarshalInputRAGDatasetInput(ctx context.Context, o
 		}
 		switch k {
 		case "source":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("source"))
 			data, err := ec.unmarshalOTypedObjectReferenceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -38918,6 +41230,8 @@ func (ec *executionContext) unmarshalInputRAGDatasetInput(ctx context.Context, o
 			}
 			it.Source = data
 		case "files":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("files"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -38945,6 +41259,8 @@ func (ec *executionContext) unmarshalInputRAGMetricInput(ctx context.Context, ob
 		}
 		switch k {
 		case "metricKind":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("metricKind"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -38952,6 +41268,8 @@ func (ec *executionContext) unmarshalInputRAGMetricInput(ctx context.Context, ob
 			}
 			it.MetricKind = data
 		case "parameters":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("parameters"))
 			data, err := ec.unmarshalOParameterInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐParameterInputᚄ(ctx, v)
 			if err != nil {
@@ -38959,6 +41277,8 @@ func (ec *executionContext) unmarshalInputRAGMetricInput(ctx context.Context, ob
 			}
 			it.Parameters = data
 		case "toleranceThreshbold":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("toleranceThreshbold"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -38986,6 +41306,8 @@ func (ec *executionContext) unmarshalInputRemoveDuplicateConfig(ctx context.Cont
 		}
 		switch k {
 		case "embedding_name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embedding_name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -38993,6 +41315,8 @@ func (ec *executionContext) unmarshalInputRemoveDuplicateConfig(ctx context.Cont
 			}
 			it.EmbeddingName = data
 		case "embedding_namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embedding_namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39000,6 +41324,8 @@ func (ec *executionContext) unmarshalInputRemoveDuplicateConfig(ctx context.Cont
 			}
 			it.EmbeddingNamespace = data
 		case "embedding_model":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embedding_model"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39007,6 +41333,8 @@ func (ec *executionContext) unmarshalInputRemoveDuplicateConfig(ctx context.Cont
 			}
 			it.EmbeddingModel = data
 		case "embedding_provider":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embedding_provider"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39014,6 +41342,8 @@ func (ec *executionContext) unmarshalInputRemoveDuplicateConfig(ctx context.Cont
 			}
 			it.EmbeddingProvider = data
 		case "similarity":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("similarity"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39041,6 +41371,8 @@ func (ec *executionContext) unmarshalInputResourceInput(ctx context.Context, obj
 		}
 		switch k {
 		case "limits":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("limits"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39048,6 +41380,8 @@ func (ec *executionContext) unmarshalInputResourceInput(ctx context.Context, obj
 			}
 			it.Limits = data
 		case "requests":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("requests"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39075,6 +41409,8 @@ func (ec *executionContext) unmarshalInputResourcesInput(ctx context.Context, ob
 		}
 		switch k {
 		case "cpu":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("cpu"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39082,6 +41418,8 @@ func (ec *executionContext) unmarshalInputResourcesInput(ctx context.Context, ob
 			}
 			it.CPU = data
 		case "memory":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("memory"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39089,6 +41427,8 @@ func (ec *executionContext) unmarshalInputResourcesInput(ctx context.Context, ob
 			}
 			it.Memory = data
 		case "nvidiaGPU":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("nvidiaGPU"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39116,6 +41456,8 @@ func (ec *executionContext) unmarshalInputSelectorInput(ctx context.Context, obj
 		}
 		switch k {
 		case "matchLabels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("matchLabels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39123,6 +41465,8 @@ func (ec *executionContext) unmarshalInputSelectorInput(ctx context.Context, obj
 			}
 			it.MatchLabels = data
 		case "matchExpressions":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("matchExpressions"))
 			data, err := ec.unmarshalOLabelSelectorRequirementInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐLabelSelectorRequirementInput(ctx, v)
 			if err != nil {
@@ -39150,6 +41494,8 @@ func (ec *executionContext) unmarshalInputToolInput(ctx context.Context, obj int
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39157,6 +41503,8 @@ func (ec *executionContext) unmarshalInputToolInput(ctx context.Context, obj int
 			}
 			it.Name = data
 		case "params":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("params"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39184,6 +41532,8 @@ func (ec *executionContext) unmarshalInputTypedObjectReferenceInput(ctx context.
 		}
 		switch k {
 		case "apiGroup":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("apiGroup"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39191,6 +41541,8 @@ func (ec *executionContext) unmarshalInputTypedObjectReferenceInput(ctx context.
 			}
 			it.APIGroup = data
 		case "kind":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("kind"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39198,6 +41550,8 @@ func (ec *executionContext) unmarshalInputTypedObjectReferenceInput(ctx context.
 			}
 			it.Kind = data
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39205,6 +41559,8 @@ func (ec *executionContext) unmarshalInputTypedObjectReferenceInput(ctx context.
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39232,6 +41588,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39239,6 +41597,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39246,6 +41606,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Namespace = data
 		case "prologue":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("prologue"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39253,6 +41615,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Prologue = data
 		case "model":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("model"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39260,6 +41624,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Model = data
 		case "llm":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("llm"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39267,6 +41633,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Llm = data
 		case "temperature":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("temperature"))
 			data, err := ec.unmarshalOFloat2ᚖfloat64(ctx, v)
 			if err != nil {
@@ -39274,6 +41642,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Temperature = data
 		case "maxLength":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("maxLength"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39281,6 +41651,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.MaxLength = data
 		case "maxTokens":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("maxTokens"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39288,6 +41660,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.MaxTokens = data
 		case "conversionWindowSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("conversionWindowSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39295,6 +41669,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ConversionWindowSize = data
 		case "knowledgebase":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("knowledgebase"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39302,6 +41678,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Knowledgebase = data
 		case "scoreThreshold":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("scoreThreshold"))
 			data, err := ec.unmarshalOFloat2ᚖfloat64(ctx, v)
 			if err != nil {
@@ -39309,6 +41687,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ScoreThreshold = data
 		case "numDocuments":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("numDocuments"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39316,6 +41696,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.NumDocuments = data
 		case "docNullReturn":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("docNullReturn"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39323,6 +41705,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.DocNullReturn = data
 		case "userPrompt":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("userPrompt"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39330,6 +41714,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.UserPrompt = data
 		case "systemPrompt":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("systemPrompt"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39337,6 +41723,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.SystemPrompt = data
 		case "showRespInfo":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("showRespInfo"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39344,6 +41732,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ShowRespInfo = data
 		case "showRetrievalInfo":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("showRetrievalInfo"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39351,6 +41741,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ShowRetrievalInfo = data
 		case "showNextGuide":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("showNextGuide"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39358,6 +41750,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ShowNextGuide = data
 		case "tools":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("tools"))
 			data, err := ec.unmarshalOToolInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐToolInput(ctx, v)
 			if err != nil {
@@ -39365,6 +41759,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.Tools = data
 		case "enableRerank":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("enableRerank"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39372,6 +41768,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.EnableRerank = data
 		case "rerankModel":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("rerankModel"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39379,6 +41777,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.RerankModel = data
 		case "enableMultiQuery":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("enableMultiQuery"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39386,6 +41786,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.EnableMultiQuery = data
 		case "chatTimeout":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chatTimeout"))
 			data, err := ec.unmarshalOFloat2ᚖfloat64(ctx, v)
 			if err != nil {
@@ -39393,6 +41795,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ChatTimeout = data
 		case "enableUploadFile":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("enableUploadFile"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39400,6 +41804,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.EnableUploadFile = data
 		case "chunkSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunkSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39407,6 +41813,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ChunkSize = data
 		case "chunkOverlap":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunkOverlap"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39414,6 +41822,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationConfigInput(ctx conte
 			}
 			it.ChunkOverlap = data
 		case "batchSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("batchSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39441,6 +41851,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39448,6 +41860,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39455,6 +41869,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39462,6 +41878,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39469,6 +41887,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39476,6 +41896,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39483,6 +41905,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.Description = data
 		case "icon":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("icon"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39490,6 +41914,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.Icon = data
 		case "isPublic":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("isPublic"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39497,6 +41923,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.IsPublic = data
 		case "isRecommended":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("isRecommended"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -39504,6 +41932,8 @@ func (ec *executionContext) unmarshalInputUpdateApplicationMetadataInput(ctx con
 			}
 			it.IsRecommended = data
 		case "category":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("category"))
 			data, err := ec.unmarshalOString2ᚕᚖstring(ctx, v)
 			if err != nil {
@@ -39531,6 +41961,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasetInput(ctx context.Context
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39538,6 +41970,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasetInput(ctx context.Context
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39545,6 +41979,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasetInput(ctx context.Context
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39552,6 +41988,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasetInput(ctx context.Context
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39559,6 +41997,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasetInput(ctx context.Context
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39566,6 +42006,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasetInput(ctx context.Context
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39593,6 +42035,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39600,6 +42044,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39607,6 +42053,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39614,6 +42062,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39621,6 +42071,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39628,6 +42080,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39635,6 +42089,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Description = data
 		case "endpointinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpointinput"))
 			data, err := ec.unmarshalOEndpointInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -39642,6 +42098,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Endpointinput = data
 		case "ossinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("ossinput"))
 			data, err := ec.unmarshalOOssInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐOssInput(ctx, v)
 			if err != nil {
@@ -39649,6 +42107,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Ossinput = data
 		case "pginput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("pginput"))
 			data, err := ec.unmarshalOPgInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐPgInput(ctx, v)
 			if err != nil {
@@ -39656,6 +42116,8 @@ func (ec *executionContext) unmarshalInputUpdateDatasourceInput(ctx context.Cont
 			}
 			it.Pginput = data
 		case "webinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("webinput"))
 			data, err := ec.unmarshalOWebInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐWebInput(ctx, v)
 			if err != nil {
@@ -39683,6 +42145,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39690,6 +42154,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39697,6 +42163,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39704,6 +42172,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39711,6 +42181,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39718,6 +42190,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39725,6 +42199,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Description = data
 		case "endpointinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpointinput"))
 			data, err := ec.unmarshalOEndpointInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -39732,6 +42208,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Endpointinput = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39739,6 +42217,8 @@ func (ec *executionContext) unmarshalInputUpdateEmbedderInput(ctx context.Contex
 			}
 			it.Type = data
 		case "models":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("models"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -39766,6 +42246,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39773,6 +42255,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39780,6 +42264,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39787,6 +42273,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39794,6 +42282,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39801,6 +42291,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39808,6 +42300,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.Description = data
 		case "fileGroups":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fileGroups"))
 			data, err := ec.unmarshalOfilegroupinput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFilegroupinputᚄ(ctx, v)
 			if err != nil {
@@ -39815,6 +42309,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.FileGroups = data
 		case "chunkSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunkSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39822,6 +42318,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.ChunkSize = data
 		case "chunkOverlap":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("chunkOverlap"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39829,6 +42327,8 @@ func (ec *executionContext) unmarshalInputUpdateKnowledgeBaseInput(ctx context.C
 			}
 			it.ChunkOverlap = data
 		case "batchSize":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("batchSize"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -39856,6 +42356,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39863,6 +42365,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39870,6 +42374,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39877,6 +42383,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39884,6 +42392,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39891,6 +42401,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39898,6 +42410,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Description = data
 		case "endpointinput":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpointinput"))
 			data, err := ec.unmarshalOEndpointInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -39905,6 +42419,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Endpointinput = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39912,6 +42428,8 @@ func (ec *executionContext) unmarshalInputUpdateLLMInput(ctx context.Context, ob
 			}
 			it.Type = data
 		case "models":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("models"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -39939,6 +42457,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39946,6 +42466,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -39953,6 +42475,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39960,6 +42484,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -39967,6 +42493,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39974,6 +42502,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39981,6 +42511,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Description = data
 		case "types":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("types"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39988,6 +42520,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Types = data
 		case "huggingFaceRepo":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("huggingFaceRepo"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -39995,6 +42529,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.HuggingFaceRepo = data
 		case "modelScopeRepo":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelScopeRepo"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40002,6 +42538,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.ModelScopeRepo = data
 		case "revision":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("revision"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40009,6 +42547,8 @@ func (ec *executionContext) unmarshalInputUpdateModelInput(ctx context.Context,
 			}
 			it.Revision = data
 		case "modelSource":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelSource"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40036,6 +42576,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40043,6 +42585,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40050,6 +42594,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40057,6 +42603,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40064,6 +42612,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40071,6 +42621,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40078,6 +42630,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Description = data
 		case "types":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("types"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40085,6 +42639,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Types = data
 		case "apiType":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("apiType"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40092,6 +42648,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.APIType = data
 		case "endpoint":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endpoint"))
 			data, err := ec.unmarshalNEndpointInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐEndpointInput(ctx, v)
 			if err != nil {
@@ -40099,6 +42657,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.Endpoint = data
 		case "llmModels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("llmModels"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -40106,6 +42666,8 @@ func (ec *executionContext) unmarshalInputUpdateModelServiceInput(ctx context.Co
 			}
 			it.LlmModels = data
 		case "embeddingModels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("embeddingModels"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -40133,6 +42695,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40140,6 +42704,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40147,6 +42713,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40154,6 +42722,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40161,6 +42731,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40168,6 +42740,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40175,6 +42749,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Description = data
 		case "application":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("application"))
 			data, err := ec.unmarshalOTypedObjectReferenceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -40182,6 +42758,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Application = data
 		case "datasets":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("datasets"))
 			data, err := ec.unmarshalORAGDatasetInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐRAGDatasetInputᚄ(ctx, v)
 			if err != nil {
@@ -40189,6 +42767,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Datasets = data
 		case "judgeLLM":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("judgeLLM"))
 			data, err := ec.unmarshalOTypedObjectReferenceInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -40196,6 +42776,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.JudgeLlm = data
 		case "metrics":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("metrics"))
 			data, err := ec.unmarshalORAGMetricInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐRAGMetricInputᚄ(ctx, v)
 			if err != nil {
@@ -40203,6 +42785,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Metrics = data
 		case "storage":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("storage"))
 			data, err := ec.unmarshalOPersistentVolumeClaimSpecInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐPersistentVolumeClaimSpecInput(ctx, v)
 			if err != nil {
@@ -40210,6 +42794,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.Storage = data
 		case "serviceAccountName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("serviceAccountName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40217,6 +42803,8 @@ func (ec *executionContext) unmarshalInputUpdateRAGInput(ctx context.Context, ob
 			}
 			it.ServiceAccountName = data
 		case "suspend":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("suspend"))
 			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
 			if err != nil {
@@ -40244,6 +42832,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40251,6 +42841,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40258,6 +42850,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40265,6 +42859,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40272,6 +42868,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40279,6 +42877,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40286,6 +42886,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.Description = data
 		case "fileGroups":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fileGroups"))
 			data, err := ec.unmarshalOFileGroup2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFileGroupᚄ(ctx, v)
 			if err != nil {
@@ -40293,6 +42895,8 @@ func (ec *executionContext) unmarshalInputUpdateVersionedDatasetInput(ctx contex
 			}
 			it.FileGroups = data
 		case "released":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("released"))
 			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
 			if err != nil {
@@ -40320,6 +42924,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 		}
 		switch k {
 		case "name":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40327,6 +42933,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Name = data
 		case "namespace":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
 			data, err := ec.unmarshalNString2string(ctx, v)
 			if err != nil {
@@ -40334,6 +42942,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Namespace = data
 		case "labels":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("labels"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40341,6 +42951,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Labels = data
 		case "annotations":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("annotations"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40348,6 +42960,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Annotations = data
 		case "displayName":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("displayName"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40355,6 +42969,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.DisplayName = data
 		case "description":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40362,6 +42978,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Description = data
 		case "type":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40369,6 +42987,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Type = data
 		case "replicas":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("replicas"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
 			if err != nil {
@@ -40376,6 +42996,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Replicas = data
 		case "resources":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("resources"))
 			data, err := ec.unmarshalOResourcesInput2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐResourcesInput(ctx, v)
 			if err != nil {
@@ -40383,6 +43005,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.Resources = data
 		case "matchExpressions":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("matchExpressions"))
 			data, err := ec.unmarshalONodeSelectorRequirementInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐNodeSelectorRequirementInput(ctx, v)
 			if err != nil {
@@ -40390,6 +43014,8 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 			}
 			it.MatchExpressions = data
 		case "additionalEnvs":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("additionalEnvs"))
 			data, err := ec.unmarshalOMap2map(ctx, v)
 			if err != nil {
@@ -40402,6 +43028,80 @@ func (ec *executionContext) unmarshalInputUpdateWorkerInput(ctx context.Context,
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputUsageInput(ctx context.Context, obj interface{}) (UsageInput, error) {
+	var it UsageInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"namespace", "appName", "user", "startTime", "endTime", "groupBy"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "namespace":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Namespace = data
+		case "appName":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("appName"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AppName = data
+		case "user":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("user"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.User = data
+		case "startTime":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("startTime"))
+			data, err := ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.StartTime = data
+		case "endTime":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("endTime"))
+			data, err := ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.EndTime = data
+		case "groupBy":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("groupBy"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.GroupBy = data
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputWebInput(ctx context.Context, obj interface{}) (WebInput, error) {
 	var it WebInput
 	asMap := map[string]interface{}{}
@@ -40417,6 +43117,8 @@ func (ec *executionContext) unmarshalInputWebInput(ctx context.Context, obj inte
 		}
 		switch k {
 		case "recommendIntervalTime":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("recommendIntervalTime"))
 			data, err := ec.unmarshalNInt2int(ctx, v)
 			if err != nil {
@@ -40444,6 +43146,8 @@ func (ec *executionContext) unmarshalInputfilegroupinput(ctx context.Context, ob
 		}
 		switch k {
 		case "source":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("source"))
 			data, err := ec.unmarshalNTypedObjectReferenceInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐTypedObjectReferenceInput(ctx, v)
 			if err != nil {
@@ -40451,6 +43155,8 @@ func (ec *executionContext) unmarshalInputfilegroupinput(ctx context.Context, ob
 			}
 			it.Source = data
 		case "path":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("path"))
 			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
 			if err != nil {
@@ -40458,6 +43164,8 @@ func (ec *executionContext) unmarshalInputfilegroupinput(ctx context.Context, ob
 			}
 			it.Path = data
 		case "files":
+			var err error
+
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("files"))
 			data, err := ec.unmarshalOFileWithVersionInput2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFileWithVersionInputᚄ(ctx, v)
 			if err != nil {
@@ -40905,41 +43613,149 @@ func (ec *executionContext) _ApplicationMutation(ctx context.Context, sel ast.Se
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
-		}
-	}
-	out.Dispatch(ctx)
-	if out.Invalids > 0 {
-		return graphql.Null
-	}
-
-	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
-
-	for label, dfs := range deferred {
-		ec.processDeferredGroup(graphql.DeferredGroup{
-			Label:    label,
-			Path:     graphql.GetPath(ctx),
-			FieldSet: dfs,
-			Context:  ctx,
-		})
-	}
+		case "exportFeedback":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._ApplicationMutation_exportFeedback(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var applicationQueryImplementors = []string{"ApplicationQuery"}
+
+func (ec *executionContext) _ApplicationQuery(ctx context.Context, sel ast.SelectionSet, obj *ApplicationQuery) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, applicationQueryImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ApplicationQuery")
+		case "getApplication":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._ApplicationQuery_getApplication(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "listApplicationMetadata":
+			field := field
 
-	return out
-}
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._ApplicationQuery_listApplicationMetadata(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
 
-var applicationQueryImplementors = []string{"ApplicationQuery"}
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
 
-func (ec *executionContext) _ApplicationQuery(ctx context.Context, sel ast.SelectionSet, obj *ApplicationQuery) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, applicationQueryImplementors)
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
 
-	out := graphql.NewFieldSet(fields)
-	deferred := make(map[string]*graphql.FieldSet)
-	for i, field := range fields {
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("ApplicationQuery")
-		case "getApplication":
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "getUsage":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -40948,7 +43764,7 @@ func (ec *executionContext) _ApplicationQuery(ctx context.Context, sel ast.Selec
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._ApplicationQuery_getApplication(ctx, field, obj)
+				res = ec._ApplicationQuery_getUsage(ctx, field, obj)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -40975,7 +43791,7 @@ func (ec *executionContext) _ApplicationQuery(ctx context.Context, sel ast.Selec
 			}
 
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
-		case "listApplicationMetadata":
+		case "getFeedbackStats":
 			field := field
 
 			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
@@ -40984,7 +43800,7 @@ func (ec *executionContext) _ApplicationQuery(ctx context.Context, sel ast.Selec
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._ApplicationQuery_listApplicationMetadata(ctx, field, obj)
+				res = ec._ApplicationQuery_getFeedbackStats(ctx, field, obj)
 				if res == graphql.Null {
 					atomic.AddUint32(&fs.Invalids, 1)
 				}
@@ -43242,6 +46058,50 @@ func (ec *executionContext) _Endpoint(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
+var exportFeedbackResultImplementors = []string{"ExportFeedbackResult"}
+
+func (ec *executionContext) _ExportFeedbackResult(ctx context.Context, sel ast.SelectionSet, obj *ExportFeedbackResult) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, exportFeedbackResultImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ExportFeedbackResult")
+		case "object":
+			out.Values[i] = ec._ExportFeedbackResult_object(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "count":
+			out.Values[i] = ec._ExportFeedbackResult_count(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var fImplementors = []string{"F", "PageNode"}
 
 func (ec *executionContext) _F(ctx context.Context, sel ast.SelectionSet, obj *F) graphql.Marshaler {
@@ -43298,6 +46158,119 @@ func (ec *executionContext) _F(ctx context.Context, sel ast.SelectionSet, obj *F
 	return out
 }
 
+var feedbackReasonCountImplementors = []string{"FeedbackReasonCount"}
+
+func (ec *executionContext) _FeedbackReasonCount(ctx context.Context, sel ast.SelectionSet, obj *FeedbackReasonCount) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, feedbackReasonCountImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("FeedbackReasonCount")
+		case "rating":
+			out.Values[i] = ec._FeedbackReasonCount_rating(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "reason":
+			out.Values[i] = ec._FeedbackReasonCount_reason(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "count":
+			out.Values[i] = ec._FeedbackReasonCount_count(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var feedbackStatImplementors = []string{"FeedbackStat"}
+
+func (ec *executionContext) _FeedbackStat(ctx context.Context, sel ast.SelectionSet, obj *FeedbackStat) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, feedbackStatImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("FeedbackStat")
+		case "namespace":
+			out.Values[i] = ec._FeedbackStat_namespace(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "appName":
+			out.Values[i] = ec._FeedbackStat_appName(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "total":
+			out.Values[i] = ec._FeedbackStat_total(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "likes":
+			out.Values[i] = ec._FeedbackStat_likes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "dislikes":
+			out.Values[i] = ec._FeedbackStat_dislikes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "reasons":
+			out.Values[i] = ec._FeedbackStat_reasons(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var fileDetailsImplementors = []string{"FileDetails"}
 
 func (ec *executionContext) _FileDetails(ctx context.Context, sel ast.SelectionSet, obj *FileDetails) graphql.Marshaler {
@@ -43926,6 +46899,42 @@ func (ec *executionContext) _KnowledgeBaseMutation(ctx context.Context, sel ast.
 				continue
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "retryFailedFiles":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._KnowledgeBaseMutation_retryFailedFiles(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
@@ -46720,23 +49729,61 @@ func (ec *executionContext) _Resource(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var resourcesImplementors = []string{"Resources"}
+var resourcesImplementors = []string{"Resources"}
+
+func (ec *executionContext) _Resources(ctx context.Context, sel ast.SelectionSet, obj *Resources) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, resourcesImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Resources")
+		case "cpu":
+			out.Values[i] = ec._Resources_cpu(ctx, field, obj)
+		case "memory":
+			out.Values[i] = ec._Resources_memory(ctx, field, obj)
+		case "nvidiaGPU":
+			out.Values[i] = ec._Resources_nvidiaGPU(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var selectorImplementors = []string{"Selector"}
 
-func (ec *executionContext) _Resources(ctx context.Context, sel ast.SelectionSet, obj *Resources) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, resourcesImplementors)
+func (ec *executionContext) _Selector(ctx context.Context, sel ast.SelectionSet, obj *Selector) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, selectorImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Resources")
-		case "cpu":
-			out.Values[i] = ec._Resources_cpu(ctx, field, obj)
-		case "memory":
-			out.Values[i] = ec._Resources_memory(ctx, field, obj)
-		case "nvidiaGPU":
-			out.Values[i] = ec._Resources_nvidiaGPU(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Selector")
+		case "matchLabels":
+			out.Values[i] = ec._Selector_matchLabels(ctx, field, obj)
+		case "matchExpressions":
+			out.Values[i] = ec._Selector_matchExpressions(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -46760,21 +49807,21 @@ func (ec *executionContext) _Resources(ctx context.Context, sel ast.SelectionSet
 	return out
 }
 
-var selectorImplementors = []string{"Selector"}
+var toolImplementors = []string{"Tool"}
 
-func (ec *executionContext) _Selector(ctx context.Context, sel ast.SelectionSet, obj *Selector) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, selectorImplementors)
+func (ec *executionContext) _Tool(ctx context.Context, sel ast.SelectionSet, obj *Tool) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, toolImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Selector")
-		case "matchLabels":
-			out.Values[i] = ec._Selector_matchLabels(ctx, field, obj)
-		case "matchExpressions":
-			out.Values[i] = ec._Selector_matchExpressions(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Tool")
+		case "name":
+			out.Values[i] = ec._Tool_name(ctx, field, obj)
+		case "params":
+			out.Values[i] = ec._Tool_params(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -46798,21 +49845,33 @@ func (ec *executionContext) _Selector(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var toolImplementors = []string{"Tool"}
+var typedObjectReferenceImplementors = []string{"TypedObjectReference"}
 
-func (ec *executionContext) _Tool(ctx context.Context, sel ast.SelectionSet, obj *Tool) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, toolImplementors)
+func (ec *executionContext) _TypedObjectReference(ctx context.Context, sel ast.SelectionSet, obj *TypedObjectReference) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, typedObjectReferenceImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Tool")
+			out.Values[i] = graphql.MarshalString("TypedObjectReference")
+		case "apiGroup":
+			out.Values[i] = ec._TypedObjectReference_apiGroup(ctx, field, obj)
+		case "kind":
+			out.Values[i] = ec._TypedObjectReference_kind(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "name":
-			out.Values[i] = ec._Tool_name(ctx, field, obj)
-		case "params":
-			out.Values[i] = ec._Tool_params(ctx, field, obj)
+			out.Values[i] = ec._TypedObjectReference_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "displayName":
+			out.Values[i] = ec._TypedObjectReference_displayName(ctx, field, obj)
+		case "namespace":
+			out.Values[i] = ec._TypedObjectReference_namespace(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -46836,33 +49895,46 @@ func (ec *executionContext) _Tool(ctx context.Context, sel ast.SelectionSet, obj
 	return out
 }
 
-var typedObjectReferenceImplementors = []string{"TypedObjectReference"}
+var usageImplementors = []string{"Usage"}
 
-func (ec *executionContext) _TypedObjectReference(ctx context.Context, sel ast.SelectionSet, obj *TypedObjectReference) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, typedObjectReferenceImplementors)
+func (ec *executionContext) _Usage(ctx context.Context, sel ast.SelectionSet, obj *Usage) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, usageImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("TypedObjectReference")
-		case "apiGroup":
-			out.Values[i] = ec._TypedObjectReference_apiGroup(ctx, field, obj)
-		case "kind":
-			out.Values[i] = ec._TypedObjectReference_kind(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Usage")
+		case "namespace":
+			out.Values[i] = ec._Usage_namespace(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "name":
-			out.Values[i] = ec._TypedObjectReference_name(ctx, field, obj)
+		case "appName":
+			out.Values[i] = ec._Usage_appName(ctx, field, obj)
+		case "user":
+			out.Values[i] = ec._Usage_user(ctx, field, obj)
+		case "requests":
+			out.Values[i] = ec._Usage_requests(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "promptTokens":
+			out.Values[i] = ec._Usage_promptTokens(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "completionTokens":
+			out.Values[i] = ec._Usage_completionTokens(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "totalTokens":
+			out.Values[i] = ec._Usage_totalTokens(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "displayName":
-			out.Values[i] = ec._TypedObjectReference_displayName(ctx, field, obj)
-		case "namespace":
-			out.Values[i] = ec._TypedObjectReference_namespace(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -47998,6 +51070,11 @@ func (ec *executionContext) _filedetail(ctx context.Context, sel ast.SelectionSe
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "attempts":
+			out.Values[i] = ec._filedetail_attempts(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -48317,6 +51394,25 @@ func (ec *executionContext) unmarshalNEndpointInput2githubᚗcomᚋkubeagiᚋarc
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalNExportFeedbackInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐExportFeedbackInput(ctx context.Context, v interface{}) (ExportFeedbackInput, error) {
+	res, err := ec.unmarshalInputExportFeedbackInput(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNExportFeedbackResult2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐExportFeedbackResult(ctx context.Context, sel ast.SelectionSet, v ExportFeedbackResult) graphql.Marshaler {
+	return ec._ExportFeedbackResult(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNExportFeedbackResult2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐExportFeedbackResult(ctx context.Context, sel ast.SelectionSet, v *ExportFeedbackResult) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._ExportFeedbackResult(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNF2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐF(ctx context.Context, sel ast.SelectionSet, v *F) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
@@ -48327,6 +51423,119 @@ func (ec *executionContext) marshalNF2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapis
 	return ec._F(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNFeedbackReasonCount2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackReasonCountᚄ(ctx context.Context, sel ast.SelectionSet, v []*FeedbackReasonCount) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNFeedbackReasonCount2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackReasonCount(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNFeedbackReasonCount2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackReasonCount(ctx context.Context, sel ast.SelectionSet, v *FeedbackReasonCount) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._FeedbackReasonCount(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNFeedbackStat2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackStatᚄ(ctx context.Context, sel ast.SelectionSet, v []*FeedbackStat) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNFeedbackStat2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackStat(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNFeedbackStat2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackStat(ctx context.Context, sel ast.SelectionSet, v *FeedbackStat) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._FeedbackStat(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNFeedbackStatsInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFeedbackStatsInput(ctx context.Context, v interface{}) (FeedbackStatsInput, error) {
+	res, err := ec.unmarshalInputFeedbackStatsInput(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) marshalNFileDetails2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐFileDetails(ctx context.Context, sel ast.SelectionSet, v *FileDetails) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
@@ -48843,6 +52052,65 @@ func (ec *executionContext) unmarshalNUpdateVersionedDatasetInput2githubᚗcom
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) marshalNUsage2ᚕᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUsageᚄ(ctx context.Context, sel ast.SelectionSet, v []*Usage) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNUsage2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUsage(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNUsage2ᚖgithubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUsage(ctx context.Context, sel ast.SelectionSet, v *Usage) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._Usage(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNUsageInput2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐUsageInput(ctx context.Context, v interface{}) (UsageInput, error) {
+	res, err := ec.unmarshalInputUsageInput(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) marshalNVersionedDataset2githubᚗcomᚋkubeagiᚋarcadiaᚋapiserverᚋgraphᚋgeneratedᚐVersionedDataset(ctx context.Context, sel ast.SelectionSet, v VersionedDataset) graphql.Marshaler {
 	return ec._VersionedDataset(ctx, sel, &v)
 }
@@ -146,11 +146,17 @@ type ApplicationMutation struct {
 	UpdateApplication       ApplicationMetadata `json:"updateApplication"`
 	DeleteApplication       *string             `json:"deleteApplication,omitempty"`
 	UpdateApplicationConfig Application         `json:"updateApplicationConfig"`
+	// 将用户反馈导出为数据集中的QA文件，可以作为评估的标准答案
+	ExportFeedback ExportFeedbackResult `json:"exportFeedback"`
 }
 
 type ApplicationQuery struct {
 	GetApplication          Application     `json:"getApplication"`
 	ListApplicationMetadata PaginatedResult `json:"listApplicationMetadata"`
+	// 统计应用的 token 用量
+	GetUsage []*Usage `json:"getUsage"`
+	// 统计应用的用户反馈
+	GetFeedbackStats []*FeedbackStat `json:"getFeedbackStats"`
 }
 
 type CheckDataProcessTaskNameInput struct {
@@ -419,9 +425,10 @@ type CreateWorkerInput struct {
 	// 模型资源描述
 	Description *string `json:"description,omitempty"`
 	// Worker类型
-	// 支持两种类型:
+	// 支持三种类型:
 	// - "fastchat" : fastchat提供的通用的推理服务模式
 	// - "fastchat-vllm" : fastchat提供的采用VLLM推理加速的推理服务模式
+	// - "llamacpp" : llama.cpp提供的CPU推理服务模式,仅支持GGUF格式的模型
 	// 规则: 如果为空，则默认为 "fastchat"
 	Type *string `json:"type,omitempty"`
 	// worker对应的模型
@@ -803,6 +810,36 @@ type EndpointInput struct {
 	Insecure *bool `json:"insecure,omitempty"`
 }
 
+// ExportFeedbackInput
+// 导出用户反馈的参数
+type ExportFeedbackInput struct {
+	// 应用所在的命名空间，数据集也需要在此命名空间
+	// 规则: 必填
+	Namespace string `json:"namespace"`
+	// 应用名称
+	// 规则: 必填
+	AppName string `json:"appName"`
+	// 导出到的数据集版本(VersionedDataset)名称
+	// 规则: 必填，不能是已发布的版本
+	VersionedDataset string `json:"versionedDataset"`
+	// 导出的文件名称，默认为 feedback-<应用名称>-<时间>.csv
+	FileName *string `json:"fileName,omitempty"`
+	// 开始时间，为空时不限制
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// 结束时间，默认为当前时间
+	EndTime *time.Time `json:"endTime,omitempty"`
+}
+
+// ExportFeedbackResult
+// 导出结果
+// 导出的是 q,a 两列的QA文件，a 为用户修正后的答案或者点赞的答案，没有修正的点踩答案不会导出
+type ExportFeedbackResult struct {
+	// 导出的文件在数据集版本中的完整路径，数据集版本同步完成后可以直接在 RAG 评估中使用
+	Object string `json:"object"`
+	// 导出的QA数量
+	Count int `json:"count"`
+}
+
 // File
 // 展示某个版本的所有文件。
 type F struct {
@@ -826,6 +863,45 @@ type F struct {
 
 func (F) IsPageNode() {}
 
+// FeedbackReasonCount
+// 某一评价和原因分类的反馈数
+type FeedbackReasonCount struct {
+	// 评价: like 或 dislike
+	Rating string `json:"rating"`
+	// 原因分类: inaccurate,irrelevant,incomplete,harmful,helpful,other，为空表示未填写
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// FeedbackStat
+// 应用的用户反馈统计
+type FeedbackStat struct {
+	Namespace string `json:"namespace"`
+	AppName   string `json:"appName"`
+	// 反馈总数
+	Total int `json:"total"`
+	// 点赞数
+	Likes int `json:"likes"`
+	// 点踩数
+	Dislikes int `json:"dislikes"`
+	// 按评价和原因分类的反馈数
+	Reasons []*FeedbackReasonCount `json:"reasons"`
+}
+
+// FeedbackStatsInput
+// 用户反馈统计条件
+type FeedbackStatsInput struct {
+	// 应用所在的命名空间
+	// 规则: 必填
+	Namespace string `json:"namespace"`
+	// 应用名称，为空时统计命名空间下的所有应用
+	AppName *string `json:"appName,omitempty"`
+	// 开始时间，为空时不限制
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// 结束时间，默认为当前时间
+	EndTime *time.Time `json:"endTime,omitempty"`
+}
+
 type FileDetails struct {
 	FileName  string `json:"file_name"`
 	Status    string `json:"status"`
@@ -986,6 +1062,8 @@ type KnowledgeBaseMutation struct {
 	CreateKnowledgeBase KnowledgeBase `json:"createKnowledgeBase"`
 	UpdateKnowledgeBase KnowledgeBase `json:"updateKnowledgeBase"`
 	DeleteKnowledgeBase *string       `json:"deleteKnowledgeBase,omitempty"`
+	// 重新处理知识库中处理失败的文件
+	RetryFailedFiles KnowledgeBase `json:"retryFailedFiles"`
 }
 
 type KnowledgeBaseQuery struct {
@@ -1991,9 +2069,10 @@ type UpdateWorkerInput struct {
 	// 模型资源描述
 	Description *string `json:"description,omitempty"`
 	// Worker类型
-	// 支持两种类型:
+	// 支持三种类型:
 	// - "fastchat" : fastchat提供的通用的推理服务模式
 	// - "fastchat-vllm" : fastchat提供的采用VLLM推理加速的推理服务模式
+	// - "llamacpp" : llama.cpp提供的CPU推理服务模式,仅支持GGUF格式的模型
 	// 规则: 如果为空，则不更新；如果type类型与当前类型相同，则不更新
 	Type     *string `json:"type,omitempty"`
 	Replicas *string `json:"replicas,omitempty"`
@@ -2005,6 +2084,39 @@ type UpdateWorkerInput struct {
 	AdditionalEnvs map[string]interface{} `json:"additionalEnvs,omitempty"`
 }
 
+// Usage
+// token 用量
+type Usage struct {
+	Namespace string `json:"namespace"`
+	// 应用名称，按 application 分组时有值
+	AppName *string `json:"appName,omitempty"`
+	// 用户名称，按 user 分组时有值
+	User *string `json:"user,omitempty"`
+	// LLM 调用次数
+	Requests         int `json:"requests"`
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// UsageInput
+// 用量查询条件
+type UsageInput struct {
+	// 应用所在的命名空间
+	// 规则: 必填
+	Namespace string `json:"namespace"`
+	// 应用名称，为空时统计命名空间下的所有应用
+	AppName *string `json:"appName,omitempty"`
+	// 用户名称，为空时统计所有用户
+	User *string `json:"user,omitempty"`
+	// 开始时间，默认为今天零点
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// 结束时间，默认为当前时间
+	EndTime *time.Time `json:"endTime,omitempty"`
+	// 分组维度，可选 user 和 application，总是按命名空间分组
+	GroupBy []string `json:"groupBy,omitempty"`
+}
+
 // VersionedDataset
 // 数据集的版本信息。
 // 主要记录版本名字，数据的来源，以及文件的同步状态
@@ -2094,9 +2206,10 @@ type Worker struct {
 	// 更新时间
 	UpdateTimestamp *time.Time `json:"updateTimestamp,omitempty"`
 	// Worker类型
-	// 支持两种类型:
+	// 支持三种类型:
 	// - "fastchat" : fastchat提供的通用的推理服务模式
 	// - "fastchat-vllm" : fastchat提供的采用VLLM推理加速的推理服务模式
+	// - "llamacpp" : llama.cpp提供的CPU推理服务模式,仅支持GGUF格式的模型
 	// 规则: 如果为空，则默认为 "fastchat"
 	Type *string `json:"type,omitempty"`
 	// worker对应的模型
@@ -2165,6 +2278,8 @@ type Filedetail struct {
 	Version string `json:"version"`
 	// 文件最新版本
 	LatestVersion string `json:"latestVersion"`
+	// 文件已尝试处理的次数
+	Attempts int `json:"attempts"`
 }
 
 // 文件组
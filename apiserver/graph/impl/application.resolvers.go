@@ -47,6 +47,15 @@ func (r *applicationMutationResolver) UpdateApplicationConfig(ctx context.Contex
 	return application.UpdateApplicationConfig(ctx, c, input)
 }
 
+// ExportFeedback is the resolver for the exportFeedback field.
+func (r *applicationMutationResolver) ExportFeedback(ctx context.Context, obj *generated.ApplicationMutation, input generated.ExportFeedbackInput) (*generated.ExportFeedbackResult, error) {
+	c, err := getClientFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return application.ExportFeedback(ctx, c, input)
+}
+
 // GetApplication is the resolver for the getApplication field.
 func (r *applicationQueryResolver) GetApplication(ctx context.Context, obj *generated.ApplicationQuery, name string, namespace string) (*generated.Application, error) {
 	c, err := getClientFromCtx(ctx)
@@ -65,6 +74,24 @@ func (r *applicationQueryResolver) ListApplicationMetadata(ctx context.Context,
 	return application.ListApplicationMeatadatas(ctx, c, input)
 }
 
+// GetUsage is the resolver for the getUsage field.
+func (r *applicationQueryResolver) GetUsage(ctx context.Context, obj *generated.ApplicationQuery, input generated.UsageInput) ([]*generated.Usage, error) {
+	c, err := getClientFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return application.GetUsage(ctx, c, input)
+}
+
+// GetFeedbackStats is the resolver for the getFeedbackStats field.
+func (r *applicationQueryResolver) GetFeedbackStats(ctx context.Context, obj *generated.ApplicationQuery, input generated.FeedbackStatsInput) ([]*generated.FeedbackStat, error) {
+	c, err := getClientFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return application.GetFeedbackStats(ctx, c, input)
+}
+
 // Application is the resolver for the Application field.
 func (r *mutationResolver) Application(ctx context.Context) (*generated.ApplicationMutation, error) {
 	return &generated.ApplicationMutation{}, nil
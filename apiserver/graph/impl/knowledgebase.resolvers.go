@@ -50,6 +50,15 @@ func (r *knowledgeBaseMutationResolver) DeleteKnowledgeBase(ctx context.Context,
 	return knowledgebase.DeleteKnowledgeBase(ctx, c, name, input.Namespace, labelSelector, fieldSelector)
 }
 
+// RetryFailedFiles is the resolver for the retryFailedFiles field.
+func (r *knowledgeBaseMutationResolver) RetryFailedFiles(ctx context.Context, obj *generated.KnowledgeBaseMutation, name string, namespace string) (*generated.KnowledgeBase, error) {
+	c, err := getClientFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return knowledgebase.RetryFailedFiles(ctx, c, name, namespace)
+}
+
 // GetKnowledgeBase is the resolver for the getKnowledgeBase field.
 func (r *knowledgeBaseQueryResolver) GetKnowledgeBase(ctx context.Context, obj *generated.KnowledgeBaseQuery, name string, namespace string) (*generated.KnowledgeBase, error) {
 	c, err := getClientFromCtx(ctx)
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/graph/generated"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgclient "github.com/kubeagi/arcadia/apiserver/pkg/client"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/documentloaders"
+)
+
+func feedbackStorage() (storage.Storage, error) {
+	systemCli, err := pkgclient.GetClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	return chat.GetStorage(systemCli), nil
+}
+
+// feedbackTimeRange returns the time range of feedbacks, which is not limited by default
+func feedbackTimeRange(startTime, endTime *time.Time) (since, until time.Time) {
+	until = time.Now()
+	if startTime != nil {
+		since = *startTime
+	}
+	if endTime != nil {
+		until = *endTime
+	}
+	return since, until
+}
+
+// GetFeedbackStats counts the feedbacks of applications in a namespace
+func GetFeedbackStats(ctx context.Context, c client.Client, input generated.FeedbackStatsInput) ([]*generated.FeedbackStat, error) {
+	// the feedbacks are not kubernetes resources, so make sure the user can list applications in the namespace
+	if err := c.List(ctx, &v1alpha1.ApplicationList{}, client.InNamespace(input.Namespace), client.Limit(1)); err != nil {
+		return nil, err
+	}
+	s, err := feedbackStorage()
+	if err != nil {
+		return nil, err
+	}
+	opts := []storage.SearchOption{storage.WithAppNamespace(input.Namespace)}
+	if input.AppName != nil {
+		opts = append(opts, storage.WithAppName(*input.AppName))
+	}
+	since, until := feedbackTimeRange(input.StartTime, input.EndTime)
+	counts, err := s.AggregateFeedback(since, until, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedbackStats(counts), nil
+}
+
+// feedbackStats sums the feedback counts of each application
+func feedbackStats(counts []storage.FeedbackCount) []*generated.FeedbackStat {
+	res := make([]*generated.FeedbackStat, 0)
+	index := make(map[string]*generated.FeedbackStat)
+	for _, count := range counts {
+		item, ok := index[count.AppName]
+		if !ok {
+			item = &generated.FeedbackStat{Namespace: count.AppNamespace, AppName: count.AppName, Reasons: make([]*generated.FeedbackReasonCount, 0)}
+			index[count.AppName] = item
+			res = append(res, item)
+		}
+		item.Total += int(count.Count)
+		switch count.Rating {
+		case storage.FeedbackRatingLike:
+			item.Likes += int(count.Count)
+		case storage.FeedbackRatingDislike:
+			item.Dislikes += int(count.Count)
+		}
+		item.Reasons = append(item.Reasons, &generated.FeedbackReasonCount{Rating: string(count.Rating), Reason: string(count.Reason), Count: int(count.Count)})
+	}
+	return res
+}
+
+// WriteFeedbackQA writes the feedbacks with ground truth as a QA csv,
+// which can be used by the evaluation as the test dataset
+func WriteFeedbackQA(w io.Writer, feedbacks []storage.Feedback) (int, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{documentloaders.QuestionCol, documentloaders.AnswerCol}); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, feedback := range feedbacks {
+		groundTruth := feedback.GroundTruth()
+		if feedback.Query == "" || groundTruth == "" {
+			continue
+		}
+		if err := writer.Write([]string{feedback.Query, groundTruth}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	writer.Flush()
+	return count, writer.Error()
+}
+
+// ExportFeedback exports the rated answers of an application into a versioned dataset as QA csv.
+// The csv is uploaded to the system datasource and added to the files of the versioned dataset,
+// then the versioned dataset controller copies it into the version.
+func ExportFeedback(ctx context.Context, c client.Client, input generated.ExportFeedbackInput) (*generated.ExportFeedbackResult, error) {
+	app := &v1alpha1.Application{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: input.Namespace, Name: input.AppName}, app); err != nil {
+		return nil, err
+	}
+	vds := &v1alpha1.VersionedDataset{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: input.Namespace, Name: input.VersionedDataset}, vds); err != nil {
+		return nil, err
+	}
+	if vds.Spec.Released == 1 {
+		return nil, fmt.Errorf("versioned dataset %s is released, files can not be added", input.VersionedDataset)
+	}
+	if vds.Spec.Dataset == nil {
+		return nil, fmt.Errorf("versioned dataset %s has no dataset", input.VersionedDataset)
+	}
+	fileName := ""
+	if input.FileName != nil && *input.FileName != "" {
+		// the file is put right under the feedback directory of the application
+		fileName = *input.FileName
+		if base := path.Base(fileName); base != fileName || base == "." || base == ".." {
+			return nil, fmt.Errorf("invalid file name %s", fileName)
+		}
+	}
+	s, err := feedbackStorage()
+	if err != nil {
+		return nil, err
+	}
+	since, until := feedbackTimeRange(input.StartTime, input.EndTime)
+	feedbacks, err := s.ListFeedbacks(since, until, storage.WithAppNamespace(input.Namespace), storage.WithAppName(input.AppName))
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.Buffer{}
+	count, err := WriteFeedbackQA(&buf, feedbacks)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("no feedback with ground truth found in application %s", input.AppName)
+	}
+
+	if fileName == "" {
+		fileName = fmt.Sprintf("feedback-%s-%s.csv", input.AppName, until.Format("20060102150405"))
+	}
+	systemDatasource, err := pkgconfig.GetSystemDatasource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oss, err := pkgconfig.GetSystemDatasourceOSS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filePath := v1alpha1.FeedbackFilePath(input.AppName, fileName)
+	if _, err = oss.Client.PutObject(ctx, datasourceBucket(systemDatasource), filePath, &buf, int64(buf.Len()), minio.PutObjectOptions{
+		ContentType: "text/csv",
+		UserTags:    feedbackQATags(count),
+	}); err != nil {
+		return nil, err
+	}
+	addVersionedDatasetFile(vds, systemDatasource, filePath)
+	if err = c.Update(ctx, vds); err != nil {
+		return nil, err
+	}
+	object := fmt.Sprintf("dataset/%s/%s/%s", vds.Spec.Dataset.Name, vds.Spec.Version, filePath)
+	return &generated.ExportFeedbackResult{Object: object, Count: count}, nil
+}
+
+// feedbackQATags are the object tags of the exported csv, which mark it as a QA file with count rows
+func feedbackQATags(count int) map[string]string {
+	return map[string]string{
+		v1alpha1.ObjectTypeTag:  v1alpha1.ObjectTypeQA,
+		v1alpha1.ObjectCountTag: strconv.Itoa(count),
+	}
+}
+
+// datasourceBucket is the bucket where the versioned dataset controller copies files of the datasource from
+func datasourceBucket(ds *v1alpha1.Datasource) string {
+	if ds.Spec.OSS != nil && ds.Spec.OSS.Bucket != "" {
+		return ds.Spec.OSS.Bucket
+	}
+	return ds.Namespace
+}
+
+// addVersionedDatasetFile adds the file of the datasource to the file groups of the versioned dataset if not added yet
+func addVersionedDatasetFile(vds *v1alpha1.VersionedDataset, ds *v1alpha1.Datasource, path string) {
+	for i, fg := range vds.Spec.FileGroups {
+		if fg.Source == nil || fg.Source.Kind != "Datasource" || fg.Source.Name != ds.Name || fg.Source.GetNamespace(vds.Namespace) != ds.Namespace {
+			continue
+		}
+		for _, f := range fg.Files {
+			if f.Path == path {
+				return
+			}
+		}
+		vds.Spec.FileGroups[i].Files = append(vds.Spec.FileGroups[i].Files, v1alpha1.FileWithVersion{Path: path})
+		return
+	}
+	vds.Spec.FileGroups = append(vds.Spec.FileGroups, v1alpha1.FileGroup{
+		Source: &v1alpha1.TypedObjectReference{
+			APIGroup:  &v1alpha1.GroupVersion.Group,
+			Kind:      "Datasource",
+			Name:      ds.Name,
+			Namespace: &ds.Namespace,
+		},
+		Files: []v1alpha1.FileWithVersion{{Path: path}},
+	})
+}
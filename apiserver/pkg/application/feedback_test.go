@@ -0,0 +1,149 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/graph/generated"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+func TestWriteFeedbackQA(t *testing.T) {
+	feedbacks := []storage.Feedback{
+		{Query: "q1", Answer: "a1", Rating: storage.FeedbackRatingLike},
+		{Query: "q2", Answer: "a2", Rating: storage.FeedbackRatingDislike},
+		{Query: "q3", Answer: "a3", Rating: storage.FeedbackRatingDislike, CorrectedAnswer: "a3, corrected"},
+	}
+	buf := bytes.Buffer{}
+	count, err := WriteFeedbackQA(&buf, feedbacks)
+	if err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("expect 2 QA, got %d", count)
+	}
+	if want := "q,a\nq1,a1\nq3,\"a3, corrected\"\n"; buf.String() != want {
+		t.Errorf("unexpected csv %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFeedbackStats(t *testing.T) {
+	counts := []storage.FeedbackCount{
+		{AppName: "a", AppNamespace: "ns", Rating: storage.FeedbackRatingLike, Reason: storage.FeedbackReasonHelpful, Count: 3},
+		{AppName: "b", AppNamespace: "ns", Rating: storage.FeedbackRatingDislike, Reason: storage.FeedbackReasonInaccurate, Count: 1},
+		{AppName: "a", AppNamespace: "ns", Rating: storage.FeedbackRatingDislike, Reason: storage.FeedbackReasonIncomplete, Count: 2},
+	}
+	stats := feedbackStats(counts)
+	if len(stats) != 2 {
+		t.Fatalf("expect stats of 2 apps, got %d", len(stats))
+	}
+	if a := stats[0]; a.AppName != "a" || a.Total != 5 || a.Likes != 3 || a.Dislikes != 2 || len(a.Reasons) != 2 {
+		t.Errorf("unexpected stat of app a %+v", a)
+	}
+	if b := stats[1]; b.AppName != "b" || b.Total != 1 || b.Likes != 0 || b.Dislikes != 1 || b.Reasons[0].Reason != string(storage.FeedbackReasonInaccurate) {
+		t.Errorf("unexpected stat of app b %+v", b)
+	}
+	if stats := feedbackStats(nil); len(stats) != 0 {
+		t.Errorf("expect no stats without feedback, got %+v", stats)
+	}
+}
+
+func TestAddVersionedDatasetFile(t *testing.T) {
+	ds := &v1alpha1.Datasource{ObjectMeta: metav1.ObjectMeta{Namespace: "arcadia", Name: "datasource-sample"}}
+	vds := &v1alpha1.VersionedDataset{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dataset-v1"}}
+	vds.Spec.FileGroups = []v1alpha1.FileGroup{{
+		Source: &v1alpha1.TypedObjectReference{Kind: "Datasource", Name: "other"},
+		Files:  []v1alpha1.FileWithVersion{{Path: "a.csv"}},
+	}}
+
+	addVersionedDatasetFile(vds, ds, "application/app/feedback/a.csv")
+	if len(vds.Spec.FileGroups) != 2 {
+		t.Fatalf("expect a new file group of the system datasource, got %+v", vds.Spec.FileGroups)
+	}
+	fg := vds.Spec.FileGroups[1]
+	if fg.Source.Name != ds.Name || fg.Source.GetNamespace("") != ds.Namespace || len(fg.Files) != 1 {
+		t.Errorf("unexpected file group %+v", fg)
+	}
+	addVersionedDatasetFile(vds, ds, "application/app/feedback/a.csv")
+	addVersionedDatasetFile(vds, ds, "application/app/feedback/b.csv")
+	if files := vds.Spec.FileGroups[1].Files; len(files) != 2 || files[1].Path != "application/app/feedback/b.csv" {
+		t.Errorf("expect the files added once to the file group, got %+v", files)
+	}
+	if files := vds.Spec.FileGroups[0].Files; len(files) != 1 {
+		t.Errorf("expect other file groups unchanged, got %+v", files)
+	}
+
+	tags := feedbackQATags(2)
+	if tags[v1alpha1.ObjectTypeTag] != v1alpha1.ObjectTypeQA || tags[v1alpha1.ObjectCountTag] != "2" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
+
+func TestExportFeedbackReleased(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	vds := &v1alpha1.VersionedDataset{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dataset-v1"}}
+	vds.Spec.Released = 1
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app, vds).Build()
+
+	_, err := ExportFeedback(context.Background(), c, generated.ExportFeedbackInput{Namespace: "default", AppName: "app", VersionedDataset: "dataset-v1"})
+	if err == nil || !strings.Contains(err.Error(), "released") {
+		t.Errorf("expect export to a released version refused, got %v", err)
+	}
+}
+
+func TestExportFeedbackInvalid(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	noDataset := &v1alpha1.VersionedDataset{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "no-dataset"}}
+	vds := &v1alpha1.VersionedDataset{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dataset-v1"}}
+	vds.Spec.Dataset = &v1alpha1.TypedObjectReference{Name: "dataset"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app, noDataset, vds).Build()
+
+	testCases := []struct {
+		name     string
+		vds      string
+		fileName string
+		err      string
+	}{
+		{name: "no dataset", vds: "no-dataset", err: "no dataset"},
+		{name: "directory", vds: "dataset-v1", fileName: "../qa.csv", err: "invalid file name"},
+		{name: "parent", vds: "dataset-v1", fileName: "..", err: "invalid file name"},
+		{name: "absolute", vds: "dataset-v1", fileName: "/qa.csv", err: "invalid file name"},
+	}
+	for _, tc := range testCases {
+		input := generated.ExportFeedbackInput{Namespace: "default", AppName: "app", VersionedDataset: tc.vds}
+		if tc.fileName != "" {
+			input.FileName = &tc.fileName
+		}
+		_, err := ExportFeedback(context.Background(), c, input)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expect error %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
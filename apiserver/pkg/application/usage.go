@@ -0,0 +1,108 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/graph/generated"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgclient "github.com/kubeagi/arcadia/apiserver/pkg/client"
+)
+
+const (
+	UsageGroupByUser        = "user"
+	UsageGroupByApplication = "application"
+)
+
+// GetUsage sums the token usage of applications in a namespace
+func GetUsage(ctx context.Context, c client.Client, input generated.UsageInput) ([]*generated.Usage, error) {
+	// the usage records are not kubernetes resources, so make sure the user can list applications in the namespace
+	if err := c.List(ctx, &v1alpha1.ApplicationList{}, client.InNamespace(input.Namespace), client.Limit(1)); err != nil {
+		return nil, err
+	}
+	var byUser, byApp bool
+	for _, g := range input.GroupBy {
+		switch g {
+		case UsageGroupByUser:
+			byUser = true
+		case UsageGroupByApplication:
+			byApp = true
+		default:
+			return nil, fmt.Errorf("unsupported group by: %s", g)
+		}
+	}
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if input.StartTime != nil {
+		since = *input.StartTime
+	}
+	until := now
+	if input.EndTime != nil {
+		until = *input.EndTime
+	}
+
+	systemCli, err := pkgclient.GetClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	opts := []storage.SearchOption{storage.WithAppNamespace(input.Namespace)}
+	if input.AppName != nil {
+		opts = append(opts, storage.WithAppName(*input.AppName))
+	}
+	if input.User != nil {
+		opts = append(opts, storage.WithUser(*input.User))
+	}
+	usages, err := chat.GetStorage(systemCli).AggregateUsage(since, until, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*generated.Usage, 0)
+	index := make(map[string]*generated.Usage)
+	for _, u := range usages {
+		key := ""
+		item := &generated.Usage{Namespace: u.AppNamespace}
+		if byApp {
+			key += u.AppName
+			item.AppName = pointer.String(u.AppName)
+		}
+		key += "/"
+		if byUser {
+			key += u.User
+			item.User = pointer.String(u.User)
+		}
+		if existing, ok := index[key]; ok {
+			item = existing
+		} else {
+			index[key] = item
+			res = append(res, item)
+		}
+		item.Requests += int(u.Requests)
+		item.PromptTokens += int(u.PromptTokens)
+		item.CompletionTokens += int(u.CompletionTokens)
+		item.TotalTokens += int(u.TotalTokens)
+	}
+	return res, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-gonic/gin"
@@ -37,10 +38,18 @@ var NeedAuth bool
 type contextKey string
 
 const (
-	idTokenContextKey  contextKey = "idToken"
-	UserNameContextKey contextKey = "userName"
+	idTokenContextKey     contextKey = "idToken"
+	tokenExpiryContextKey contextKey = "tokenExpiry"
+	UserNameContextKey    contextKey = "userName"
 )
 
+// TokenExpiryFromContext returns the expiry of the token verified by AuthInterceptor,
+// the long-lived connections like websocket should stop when the token expires
+func TokenExpiryFromContext(ctx context.Context) (time.Time, bool) {
+	expiry, ok := ctx.Value(tokenExpiryContextKey).(time.Time)
+	return expiry, ok && !expiry.IsZero()
+}
+
 type User struct {
 	Name        string            `json:"name"`
 	Password    string            `json:"password,omitempty"`
@@ -150,6 +159,21 @@ func AuthInterceptor(needAuth bool, oidcVerifier *oidc.IDTokenVerifier, groupVer
 
 		// for graphql query
 		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), idTokenContextKey, rawToken))
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), tokenExpiryContextKey, oidcIDtoken.Expiry))
+		ctx.Next()
+	}
+}
+
+// WebSocketCredentials moves the token and namespace in the query into the headers,
+// as browsers can't set headers on the websocket handshake, so that AuthInterceptor works the same way for websocket
+func WebSocketCredentials() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token := ctx.Query("token"); token != "" && ctx.GetHeader("Authorization") == "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		if namespace := ctx.Query("namespace"); namespace != "" && ctx.GetHeader("namespace") == "" {
+			ctx.Request.Header.Set("namespace", namespace)
+		}
 		ctx.Next()
 	}
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+// continueInstruction is the question sent to the llm when continuing an answer, it is not stored in the conversation
+const continueInstruction = "Continue from where you stopped, do not repeat what you already said."
+
+// branchParent returns the message which the new message follows.
+// When regenerating, the query and files of the regenerated message are reused.
+// When continuing, the continued message itself is returned, so that its partial answer is in the history.
+func branchParent(conversation *storage.Conversation, req *ChatReqBody) (string, error) {
+	switch {
+	case req.ContinueMessageID != "":
+		m := conversation.Message(req.ContinueMessageID)
+		if m == nil {
+			return "", errors.New("the message to continue is not found")
+		}
+		if m.Action == "UPLOAD" {
+			return "", errors.New("the answer of uploading files can not be continued")
+		}
+		return m.ID, nil
+	case req.RegenerateMessageID != "":
+		m := conversation.Message(req.RegenerateMessageID)
+		if m == nil {
+			return "", errors.New("the message to regenerate is not found")
+		}
+		if m.Action == "UPLOAD" {
+			return "", errors.New("the answer of uploading files can not be regenerated")
+		}
+		req.Query = m.Query
+		if len(req.Files) == 0 {
+			if m.RawFiles != "" {
+				req.Files = append(req.Files, strings.Split(m.RawFiles, ",")...)
+			}
+			req.Files = append(req.Files, m.Images...)
+		}
+		return m.ParentID, nil
+	case req.ParentMessageID != "":
+		if req.ParentMessageID != conversation.ID && conversation.Message(req.ParentMessageID) == nil {
+			return "", errors.New("the parent message is not found")
+		}
+		if req.Query == "" {
+			return "", errors.New("query is required")
+		}
+		return req.ParentMessageID, nil
+	default:
+		if req.Query == "" {
+			return "", errors.New("query is required")
+		}
+		return conversation.ActiveLeaf(), nil
+	}
+}
+
+// SelectMessage selects the branch of the message, the latest messages following it are selected as well.
+// It returns the messages of the selected branch.
+func (cs *ChatServer) SelectMessage(ctx context.Context, req MessageReqBody) (storage.Conversation, error) {
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	c, err := cs.Storage().FindExistingConversation(req.ConversationID, storage.WithAppName(req.APPName), storage.WithAppNamespace(req.AppNamespace), storage.WithUser(currentUser))
+	if err != nil {
+		return storage.Conversation{}, err
+	}
+	if c.Message(req.MessageID) == nil {
+		return storage.Conversation{}, errors.New("conversation or message is not found")
+	}
+	c.FillParents()
+	c.ActiveMessageID = c.LatestLeaf(req.MessageID)
+	c.UpdatedAt = time.Now()
+	if err := cs.Storage().UpdateConversation(c); err != nil {
+		return storage.Conversation{}, err
+	}
+	c.Messages = c.ActivePath()
+	return *c, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"testing"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+func TestBranchParent(t *testing.T) {
+	conversation := &storage.Conversation{ID: "c1", ActiveMessageID: "m2", Messages: []storage.Message{
+		{ID: "m1", ParentID: "c1", Action: "UPLOAD", Query: "UPLOAD", Answer: "a.pdf"},
+		{ID: "m2", ParentID: "m1", Action: "CHAT", Query: "hi", Answer: "hello, I am"},
+	}}
+	testCases := []struct {
+		name   string
+		req    ChatReqBody
+		parent string
+		query  string
+		err    string
+	}{
+		{name: "default", req: ChatReqBody{Query: "q"}, parent: "m2", query: "q"},
+		{name: "no query", req: ChatReqBody{}, err: "query is required"},
+		{name: "edit", req: ChatReqBody{Query: "q", ParentMessageID: "m1"}, parent: "m1", query: "q"},
+		{name: "regenerate", req: ChatReqBody{RegenerateMessageID: "m2"}, parent: "m1", query: "hi"},
+		{name: "continue", req: ChatReqBody{ContinueMessageID: "m2"}, parent: "m2"},
+		{name: "continue missing", req: ChatReqBody{ContinueMessageID: "m3"}, err: "the message to continue is not found"},
+		{name: "continue upload", req: ChatReqBody{ContinueMessageID: "m1"}, err: "the answer of uploading files can not be continued"},
+	}
+	for _, tc := range testCases {
+		req := tc.req
+		parent, err := branchParent(conversation, &req)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: expected error %q, but got %v", tc.name, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || parent != tc.parent || req.Query != tc.query {
+			t.Errorf("%s: expected parent %q with query %q, but got %q with query %q, err %v", tc.name, tc.parent, tc.query, parent, req.Query, err)
+		}
+	}
+}
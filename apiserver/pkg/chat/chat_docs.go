@@ -38,6 +38,7 @@ import (
 	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
 	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
 	pkgclient "github.com/kubeagi/arcadia/apiserver/pkg/client"
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
 	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
 )
 
@@ -121,11 +122,13 @@ func (cs *ChatServer) ReceiveConversationFile(ctx context.Context, messageID str
 		Object:         objectPath,
 	}
 
-	// build/update conversation knowledgebase
-	err = cs.BuildConversationKnowledgeBase(ctx, req, document)
-	if err != nil {
-		// only log error
-		klog.Errorf("failed to build conversation knowledgebase %s with error %s", req.ConversationID, err.Error())
+	// build/update conversation knowledgebase, images are skipped because they are sent to the llm directly in chat
+	if !base.IsImage(file.Filename) {
+		err = cs.BuildConversationKnowledgeBase(ctx, req, document)
+		if err != nil {
+			// only log error
+			klog.Errorf("failed to build conversation knowledgebase %s with error %s", req.ConversationID, err.Error())
+		}
 	}
 
 	// process document with map-reduce
@@ -139,7 +142,10 @@ func (cs *ChatServer) ReceiveConversationFile(ctx context.Context, messageID str
 	}
 
 	// update conversat ion
+	conversation.FillParents()
+	message.ParentID = conversation.ActiveLeaf()
 	conversation.Messages = append(conversation.Messages, message)
+	conversation.ActiveMessageID = messageID
 	conversation.UpdatedAt = time.Now()
 	// update the conversation with new message
 	if err := cs.Storage().UpdateConversation(conversation); err != nil {
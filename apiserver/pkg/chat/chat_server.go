@@ -32,6 +32,7 @@ import (
 	langchainschema "github.com/tmc/langchaingo/schema"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog/v2"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -54,8 +55,6 @@ import (
 
 type ChatServer struct {
 	systemCli runtimeclient.Client
-	storage   storage.Storage
-	once      sync.Once
 	isGpts    bool
 }
 
@@ -66,43 +65,83 @@ func NewChatServer(cli runtimeclient.Client, isGpts bool) *ChatServer {
 	}
 }
 
-func (cs *ChatServer) Storage() storage.Storage {
-	if cs.storage == nil {
-		cs.once.Do(func() {
-			ctx := context.TODO()
-			ds, err := pkgconfig.GetRelationalDatasource(ctx)
-			if err != nil || ds == nil {
-				if err != nil {
-					klog.Infof("get relational datasource failed: %s, use memory storage for chat", err.Error())
-				} else if ds == nil {
-					klog.Infoln("no relational datasource found, use memory storage for chat")
-				}
-				cs.storage = storage.NewMemoryStorage()
-				return
-			}
-			pg, err := datasource.GetPostgreSQLPool(ctx, cs.systemCli, ds)
-			if err != nil {
-				klog.Errorf("get postgresql pool failed : %s", err.Error())
-				cs.storage = storage.NewMemoryStorage()
-				return
-			}
-			conn, err := pg.Pool.Acquire(ctx)
-			if err != nil {
-				klog.Errorf("postgresql pool acquire failed : %s", err.Error())
-				cs.storage = storage.NewMemoryStorage()
-				return
-			}
-			db, err := storage.NewPostgreSQLStorage(conn.Conn())
+var (
+	chatStorage     storage.Storage
+	chatStorageOnce sync.Once
+)
+
+// GetStorage returns the storage of chat, which is shared by all chat servers
+func GetStorage(cli runtimeclient.Client) storage.Storage {
+	chatStorageOnce.Do(func() {
+		ctx := context.TODO()
+		ds, err := pkgconfig.GetRelationalDatasource(ctx)
+		if err != nil || ds == nil {
 			if err != nil {
-				klog.Errorf("storage.NewPostgreSQLStorage failed : %s", err.Error())
-				cs.storage = storage.NewMemoryStorage()
-				return
+				klog.Infof("get relational datasource failed: %s, use embedded storage for chat", err.Error())
+			} else if ds == nil {
+				klog.Infoln("no relational datasource found, use embedded storage for chat")
 			}
-			klog.Infoln("use pg as chat storage.")
-			cs.storage = db
-		})
+			chatStorage = fallbackStorage()
+			return
+		}
+		pg, err := datasource.GetPostgreSQLPool(ctx, cli, ds)
+		if err != nil {
+			klog.Errorf("get postgresql pool failed : %s", err.Error())
+			chatStorage = fallbackStorage()
+			return
+		}
+		conn, err := pg.Pool.Acquire(ctx)
+		if err != nil {
+			klog.Errorf("postgresql pool acquire failed : %s", err.Error())
+			chatStorage = fallbackStorage()
+			return
+		}
+		db, err := storage.NewPostgreSQLStorage(conn.Conn(), config.GetConfig().ChatSearchConfig)
+		if err != nil {
+			klog.Errorf("storage.NewPostgreSQLStorage failed : %s", err.Error())
+			chatStorage = fallbackStorage()
+			return
+		}
+		klog.Infoln("use pg as chat storage.")
+		chatStorage = db
+	})
+	return chatStorage
+}
+
+// fallbackStorage is used when the relational datasource is not available,
+// which is the sqlite storage if the path is configured, otherwise the chats are kept in memory
+func fallbackStorage() storage.Storage {
+	path := config.GetConfig().ChatStoragePath
+	if path == "" {
+		klog.Infoln("no chat storage path configured, use memory storage for chat")
+		return storage.NewMemoryStorage()
+	}
+	db, err := storage.NewSQLiteStorage(path)
+	if err != nil {
+		klog.Errorf("storage.NewSQLiteStorage failed : %s, use memory storage for chat", err.Error())
+		return storage.NewMemoryStorage()
+	}
+	klog.Infof("use sqlite %s as chat storage.", path)
+	return db
+}
+
+func (cs *ChatServer) Storage() storage.Storage {
+	return GetStorage(cs.systemCli)
+}
+
+// recordUsage saves the token usage of llm calls, the error is only logged as the chat is already done
+func (cs *ChatServer) recordUsage(ctx context.Context, record storage.UsageRecord, usage llm.Usage) {
+	if usage.Requests == 0 {
+		return
+	}
+	record.ID = string(uuid.NewUUID())
+	record.Requests = usage.Requests
+	record.PromptTokens = usage.PromptTokens
+	record.CompletionTokens = usage.CompletionTokens
+	record.TotalTokens = usage.TotalTokens
+	if err := cs.Storage().AddUsage(&record); err != nil {
+		klog.FromContext(ctx).Error(err, "failed to record token usage", "appName", record.AppName, "appNamespace", record.AppNamespace)
 	}
-	return cs.storage
 }
 
 func (cs *ChatServer) AppRun(ctx context.Context, req ChatReqBody, respStream chan string, messageID string, timeout *float64) (*ChatRespBody, error) {
@@ -112,7 +151,7 @@ func (cs *ChatServer) AppRun(ctx context.Context, req ChatReqBody, respStream ch
 	}
 	*timeout = app.Spec.ChatTimeoutSecond
 	var conversation *storage.Conversation
-	history := memory.NewChatMessageHistory()
+	history := base.NewConversationHistory(memory.NewChatMessageHistory())
 	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
 	if !req.NewChat {
 		search := []storage.SearchOption{
@@ -127,10 +166,6 @@ func (cs *ChatServer) AppRun(ctx context.Context, req ChatReqBody, respStream ch
 		if err != nil {
 			return nil, err
 		}
-		for _, v := range conversation.Messages {
-			_ = history.AddUserMessage(ctx, v.Query)
-			_ = history.AddAIMessage(ctx, v.Answer)
-		}
 	} else {
 		conversation = &storage.Conversation{
 			ID:           req.ConversationID,
@@ -141,39 +176,146 @@ func (cs *ChatServer) AppRun(ctx context.Context, req ChatReqBody, respStream ch
 			User:         currentUser,
 			Debug:        req.Debug,
 		}
+		if token := ShareTokenFromContext(ctx); token != nil {
+			conversation.ShareTokenID = token.ID
+		}
 		// create before do AppRun
 		if err := cs.Storage().UpdateConversation(conversation); err != nil {
 			return nil, err
 		}
 	}
-	conversation.Messages = append(conversation.Messages, storage.Message{
-		ID:     messageID,
-		Action: "CHAT",
-		Query:  req.Query,
-		Answer: "",
-	})
+	// the new message follows the selected branch, or starts a new branch when regenerating or editing
+	conversation.FillParents()
+	parentID, err := branchParent(conversation, &req)
+	if err != nil {
+		return nil, err
+	}
+	// the turns merged into the running summary are replaced by the summary
+	var path []storage.Message
+	if req.History == nil {
+		path = conversation.PathTo(parentID)
+		for i, v := range path {
+			if v.ID == conversation.SummaryMessageID {
+				path = path[i+1:]
+				history.Summary = conversation.Summary
+				break
+			}
+		}
+		for _, v := range path {
+			if len(v.Images) > 0 {
+				_ = history.AddMessage(ctx, base.MultimodalHumanMessage{Content: v.Query, Images: v.Images})
+			} else {
+				_ = history.AddUserMessage(ctx, v.Query)
+			}
+			_ = history.AddAIMessage(ctx, v.Answer)
+		}
+	}
+	for _, v := range req.History {
+		_ = history.AddUserMessage(ctx, v.Query)
+		_ = history.AddAIMessage(ctx, v.Answer)
+	}
+	// the answer of the continued message is extended in place, otherwise a new message is added
+	var message *storage.Message
+	query := req.Query
+	if req.ContinueMessageID != "" {
+		message = conversation.Message(req.ContinueMessageID)
+		messageID = message.ID
+		query = continueInstruction
+	} else {
+		conversation.Messages = append(conversation.Messages, storage.Message{
+			ID:       messageID,
+			ParentID: parentID,
+			Action:   "CHAT",
+			Query:    req.Query,
+			Answer:   "",
+		})
+		conversation.ActiveMessageID = messageID
+		message = &conversation.Messages[len(conversation.Messages)-1]
+	}
+	// the facts about the user are recalled once when the conversation starts
+	if longTermMemoryEnabled(ctx, app, currentUser) {
+		if conversation.Facts == nil {
+			facts, err := cs.RecallFacts(ctx, app, currentUser, req.Query)
+			if err != nil {
+				klog.FromContext(ctx).Error(err, "failed to recall facts about the user, continue without them")
+			}
+			conversation.Facts = facts
+		}
+		history.Facts = conversation.Facts
+	}
 	// since authenticattion already passed by http handler,we should use chatserver's client which is also the system client to new/ini appruntime
 	appRun, err := appruntime.NewAppOrGetFromCache(ctx, cs.systemCli, app)
 	if err != nil {
 		return nil, err
 	}
 	klog.FromContext(ctx).Info("begin to run application", "appName", req.APPName, "appNamespace", req.AppNamespace)
-	out, err := appRun.Run(ctx, cs.systemCli, respStream, appruntime.Input{Question: req.Query, Files: req.Files, NeedStream: req.ResponseMode.IsStreaming(), History: history, ConversationID: req.ConversationID})
+	runCtx, usageCollector := llm.WithUsageCollector(ctx)
+	// images are sent to the llm directly, the other files are loaded as documents
+	var files, images []string
+	for _, file := range req.Files {
+		if base.IsImage(file) {
+			images = append(images, file)
+		} else {
+			files = append(files, file)
+		}
+	}
+	out, err := appRun.Run(runCtx, cs.systemCli, respStream, appruntime.Input{Question: query, Files: files, Images: images, NeedStream: req.ResponseMode.IsStreaming(), History: history, ConversationID: req.ConversationID})
+	// the tokens are consumed even if the run failed
+	usage := usageCollector.Usage()
+	cs.recordUsage(ctx, storage.UsageRecord{
+		User:           currentUser,
+		AppName:        req.APPName,
+		AppNamespace:   req.AppNamespace,
+		ConversationID: conversation.ID,
+		MessageID:      messageID,
+		Action:         "CHAT",
+	}, usage)
 	if err != nil {
 		return nil, err
 	}
 
 	conversation.UpdatedAt = req.StartTime
-	conversation.Messages[len(conversation.Messages)-1].Answer = out.Answer
-	conversation.Messages[len(conversation.Messages)-1].References = out.References
-	conversation.Messages[len(conversation.Messages)-1].Latency = time.Since(req.StartTime).Milliseconds()
-	if req.Files != nil && len(req.Files) > 0 {
-		conversation.Messages[len(conversation.Messages)-1].RawFiles = strings.Join(req.Files, ",")
+	answer := out.Answer
+	// the personal information is masked before stored, the answer in response is not changed
+	rules, redact := redactionRules(ctx)
+	if redact {
+		answer = Redact(answer, rules)
+	}
+	if req.ContinueMessageID != "" {
+		message.Answer += answer
+		message.References = append(message.References, out.References...)
+		message.Latency += time.Since(req.StartTime).Milliseconds()
+		message.PromptTokens += usage.PromptTokens
+		message.CompletionTokens += usage.CompletionTokens
+		message.TotalTokens += usage.TotalTokens
+	} else {
+		message.Query = req.Query
+		if redact {
+			message.Query = Redact(req.Query, rules)
+		}
+		message.Answer = answer
+		message.References = out.References
+		message.Latency = time.Since(req.StartTime).Milliseconds()
+		message.PromptTokens = usage.PromptTokens
+		message.CompletionTokens = usage.CompletionTokens
+		message.TotalTokens = usage.TotalTokens
+		if len(files) > 0 {
+			message.RawFiles = strings.Join(files, ",")
+		}
+		message.Images = images
+	}
+	if history.SummarizedTurns > 0 && history.SummarizedTurns <= len(path) {
+		conversation.Summary = history.Summary
+		conversation.SummaryMessageID = path[history.SummarizedTurns-1].ID
 	}
 
 	if err := cs.Storage().UpdateConversation(conversation); err != nil {
 		return nil, err
 	}
+	// the facts are extracted by the llm of the app
+	if model, ok := appRun.LLM(); ok && longTermMemoryEnabled(ctx, app, currentUser) {
+		cs.rememberFactsInBackground(ctx, model, app, currentUser, conversation.ID, messageID, message.Query, message.Answer)
+	}
 	return &ChatRespBody{
 		ConversationID: conversation.ID,
 		MessageID:      messageID,
@@ -181,6 +323,7 @@ func (cs *ChatServer) AppRun(ctx context.Context, req ChatReqBody, respStream ch
 		Message:        out.Answer,
 		CreatedAt:      time.Now(),
 		References:     out.References,
+		Usage:          usage,
 	}, nil
 }
 
@@ -189,6 +332,41 @@ func (cs *ChatServer) ListConversations(ctx context.Context, req APPMetadata) ([
 	return cs.Storage().ListConversations(storage.WithAppNamespace(req.AppNamespace), storage.WithAppName(req.APPName), storage.WithUser(currentUser))
 }
 
+const (
+	defaultSearchPageSize = 10
+	maxSearchPageSize     = 100
+)
+
+// SearchConversations searches the queries and answers in current user's conversations
+func (cs *ChatServer) SearchConversations(ctx context.Context, req SearchReqBody) (SearchRespBody, error) {
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = defaultSearchPageSize
+	}
+	if req.PageSize > maxSearchPageSize {
+		req.PageSize = maxSearchPageSize
+	}
+	search := storage.MessageSearch{
+		Keyword: req.Keyword,
+		Offset:  (req.Page - 1) * req.PageSize,
+		Limit:   req.PageSize,
+	}
+	if req.StartTime != nil {
+		search.Since = *req.StartTime
+	}
+	if req.EndTime != nil {
+		search.Until = *req.EndTime
+	}
+	hits, total, err := cs.Storage().SearchMessages(search, storage.WithAppNamespace(req.AppNamespace), storage.WithAppName(req.APPName), storage.WithUser(currentUser))
+	if err != nil {
+		return SearchRespBody{}, err
+	}
+	return SearchRespBody{Total: total, Page: req.Page, PageSize: req.PageSize, Hits: hits}, nil
+}
+
 func (cs *ChatServer) DeleteConversation(ctx context.Context, conversationID string) error {
 	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
 	// Note: in pg table, this data is marked as deleted, deleted_at column is not null. the pdf in minio is not deleted. we only delete the conversation knowledgebase.
@@ -198,14 +376,19 @@ func (cs *ChatServer) DeleteConversation(ctx context.Context, conversationID str
 	if err != nil {
 		return err
 	}
+	return cs.deleteConversationKnowledgeBase(ctx, conversationID)
+}
+
+// deleteConversationKnowledgeBase deletes the conversation knowledgebase if it exists
+func (cs *ChatServer) deleteConversationKnowledgeBase(ctx context.Context, conversationID string) error {
 	kbList := &v1alpha1.KnowledgeBaseList{}
-	if err = runtimeclient.IgnoreNotFound(cs.systemCli.List(ctx, kbList, runtimeclient.MatchingFields(map[string]string{"metadata.name": conversationID}))); err != nil {
+	if err := runtimeclient.IgnoreNotFound(cs.systemCli.List(ctx, kbList, runtimeclient.MatchingFields(map[string]string{"metadata.name": conversationID}))); err != nil {
 		return err
 	}
 	// delete when conversation knowledgebase found(only one conversation knowledgebase at most)
 	if len(kbList.Items) == 1 {
 		kb := &kbList.Items[0]
-		if err = cs.systemCli.Delete(ctx, kb); err != nil {
+		if err := cs.systemCli.Delete(ctx, kb); err != nil {
 			klog.Errorf("conversation %s deleted but knowledgebase for this conversation failed to delete: %s", conversationID, err.Error())
 			return fmt.Errorf("failed to delete conversation knowledgebase:%s", err.Error())
 		}
@@ -213,13 +396,20 @@ func (cs *ChatServer) DeleteConversation(ctx context.Context, conversationID str
 	return nil
 }
 
-func (cs *ChatServer) ListMessages(ctx context.Context, req ConversationReqBody) (storage.Conversation, error) {
+// ListMessages returns the messages of the selected branch with the siblings of each message,
+// or all messages of the conversation as a tree linked by the parent
+func (cs *ChatServer) ListMessages(ctx context.Context, req ConversationReqBody, tree bool) (storage.Conversation, error) {
 	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
 	c, err := cs.Storage().FindExistingConversation(req.ConversationID, storage.WithAppNamespace(req.AppNamespace), storage.WithAppName(req.APPName), storage.WithAppNamespace(req.AppNamespace), storage.WithUser(currentUser))
 	if err != nil {
 		return storage.Conversation{}, err
 	}
 	if c != nil {
+		c.FillParents()
+		c.ActiveMessageID = c.ActiveLeaf()
+		if !tree {
+			c.Messages = c.ActivePath()
+		}
 		return *c, nil
 	}
 	return storage.Conversation{}, errors.New("conversation is not found")
@@ -362,7 +552,15 @@ func (cs *ChatServer) ListPromptStarters(ctx context.Context, req APPMetadata, l
 	} else {
 		llmchain = chains.NewLLMChain(model, p)
 	}
-	result, err := chains.Predict(ctx, llmchain, predictArg)
+	predictCtx, usageCollector := llm.WithUsageCollector(ctx)
+	result, err := chains.Predict(predictCtx, llmchain, predictArg)
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	cs.recordUsage(ctx, storage.UsageRecord{
+		User:         currentUser,
+		AppName:      req.APPName,
+		AppNamespace: req.AppNamespace,
+		Action:       "PROMPT_STARTER",
+	}, usageCollector.Usage())
 	if err != nil {
 		return nil, err
 	}
@@ -405,6 +603,21 @@ func (cs *ChatServer) GetApp(ctx context.Context, appName, appNamespace string)
 	return app, nil
 }
 
+// ListApps lists the ready applications in the namespace which current user can chat with
+func (cs *ChatServer) ListApps(ctx context.Context, appNamespace string) ([]v1alpha1.Application, error) {
+	apps := &v1alpha1.ApplicationList{}
+	if err := cs.systemCli.List(ctx, apps, runtimeclient.InNamespace(appNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	res := make([]v1alpha1.Application, 0, len(apps.Items))
+	for _, app := range apps.Items {
+		if app.Status.IsReady() && cs.IsGPTUserHasPermissionForApp(ctx, &app) {
+			res = append(res, app)
+		}
+	}
+	return res, nil
+}
+
 // todo Reuse the flow without having to rebuild req same, not finish, Flow doesn't start with/contain nodes that depend on incomingInput.question
 
 func (cs *ChatServer) FillAppIconToConversations(ctx context.Context, conversations *[]storage.Conversation) error {
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+const (
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "markdown"
+)
+
+// ExportConversations returns current user's conversations with all messages to export
+func (cs *ChatServer) ExportConversations(ctx context.Context, req ExportReqBody) ([]storage.Conversation, error) {
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	conversations, err := cs.Storage().ListConversations(storage.WithAppNamespace(req.AppNamespace), storage.WithAppName(req.APPName), storage.WithConversationID(req.ConversationID), storage.WithUser(currentUser))
+	if err != nil {
+		return nil, err
+	}
+	if conversations == nil {
+		conversations = make([]storage.Conversation, 0)
+	}
+	for i := range conversations {
+		conversations[i].FillParents()
+		conversations[i].ActiveMessageID = conversations[i].ActiveLeaf()
+	}
+	return conversations, nil
+}
+
+// WriteMarkdown writes the conversations as markdown, the messages are in the order of creation
+// and the regenerated or edited ones are marked as another version of the original message
+func WriteMarkdown(w io.Writer, conversations []storage.Conversation) error {
+	b := &strings.Builder{}
+	for _, c := range conversations {
+		fmt.Fprintf(b, "# %s/%s %s\n\n", c.AppNamespace, c.AppName, c.ID)
+		fmt.Fprintf(b, "- Started at: %s\n- Updated at: %s\n\n", c.StartedAt.Format(time.RFC3339), c.UpdatedAt.Format(time.RFC3339))
+		for i, m := range c.Messages {
+			if m.Action == "UPLOAD" {
+				names := make([]string, 0, len(m.Documents))
+				for _, d := range m.Documents {
+					names = append(names, d.Name)
+				}
+				fmt.Fprintf(b, "## %d. Upload\n\n%s\n\n", i+1, strings.Join(names, ", "))
+				continue
+			}
+			fmt.Fprintf(b, "## %d. User\n\n", i+1)
+			if siblings := c.Children(m.ParentID); len(siblings) > 1 && siblings[0].ID != m.ID {
+				fmt.Fprintf(b, "> Another version of message %s\n\n", siblings[0].ID)
+			}
+			fmt.Fprintf(b, "%s\n\n## %d. Assistant\n\n%s\n\n", m.Query, i+1, m.Answer)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+// UpdateFeedback rates the answer of a message in current user's conversation
+func (cs *ChatServer) UpdateFeedback(ctx context.Context, req FeedbackReqBody) (*storage.Feedback, error) {
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	m, err := cs.Storage().FindExistingMessage(req.ConversationID, req.MessageID, storage.WithAppName(req.APPName), storage.WithAppNamespace(req.AppNamespace), storage.WithUser(currentUser))
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, errors.New("conversation or message is not found")
+	}
+	feedback := &storage.Feedback{
+		MessageID:       m.ID,
+		ConversationID:  req.ConversationID,
+		AppName:         req.APPName,
+		AppNamespace:    req.AppNamespace,
+		User:            currentUser,
+		Rating:          req.Rating,
+		Reason:          req.Reason,
+		Comment:         req.Comment,
+		CorrectedAnswer: req.CorrectedAnswer,
+		Query:           m.Query,
+		Answer:          m.Answer,
+	}
+	if rules, ok := redactionRules(ctx); ok {
+		feedback.Comment = Redact(feedback.Comment, rules)
+		feedback.CorrectedAnswer = Redact(feedback.CorrectedAnswer, rules)
+	}
+	if err := cs.Storage().UpdateFeedback(feedback); err != nil {
+		return nil, err
+	}
+	return feedback, nil
+}
+
+// DeleteFeedback deletes current user's feedback on a message
+func (cs *ChatServer) DeleteFeedback(ctx context.Context, req MessageReqBody) error {
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	m, err := cs.Storage().FindExistingMessage(req.ConversationID, req.MessageID, storage.WithAppName(req.APPName), storage.WithAppNamespace(req.AppNamespace), storage.WithUser(currentUser))
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return errors.New("conversation or message is not found")
+	}
+	return cs.Storage().DeleteFeedback(m.ID, storage.WithUser(currentUser))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+// useMemoryStorage makes the chat servers in tests use the memory storage
+func useMemoryStorage(t *testing.T) storage.Storage {
+	chatStorageOnce.Do(func() {
+		chatStorage = storage.NewMemoryStorage()
+	})
+	if _, ok := chatStorage.(*storage.MemoryStorage); !ok {
+		t.Fatalf("expected the memory storage, but got %T", chatStorage)
+	}
+	return chatStorage
+}
+
+func TestFeedback(t *testing.T) {
+	s := useMemoryStorage(t)
+	conversation := storage.Conversation{
+		ID: "feedback-conversation", AppName: "app", AppNamespace: "default", User: "alice", StartedAt: time.Now(), UpdatedAt: time.Now(),
+		Messages: []storage.Message{{ID: "feedback-message", ConversationID: "feedback-conversation", Query: "q", Answer: "a"}},
+	}
+	if err := s.UpdateConversation(&conversation); err != nil {
+		t.Fatal(err)
+	}
+	cs := NewChatServer(nil, false)
+	req := MessageReqBody{
+		ConversationReqBody: ConversationReqBody{APPMetadata: APPMetadata{APPName: "app", AppNamespace: "default"}, ConversationID: "feedback-conversation"},
+		MessageID:           "feedback-message",
+	}
+	alice := context.WithValue(context.Background(), auth.UserNameContextKey, "alice")
+	bob := context.WithValue(context.Background(), auth.UserNameContextKey, "bob")
+
+	if _, err := cs.UpdateFeedback(bob, FeedbackReqBody{MessageReqBody: req, Rating: storage.FeedbackRatingDislike}); err == nil {
+		t.Error("expected the message of other user not to be rated")
+	}
+	feedback, err := cs.UpdateFeedback(alice, FeedbackReqBody{MessageReqBody: req, Rating: storage.FeedbackRatingDislike, Reason: storage.FeedbackReasonInaccurate, CorrectedAnswer: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feedback.Query != "q" || feedback.Answer != "a" || feedback.User != "alice" || feedback.GroundTruth() != "b" {
+		t.Errorf("expected the query and answer copied from the message, but got %+v", feedback)
+	}
+	if _, err = cs.UpdateFeedback(alice, FeedbackReqBody{MessageReqBody: req, Rating: storage.FeedbackRatingLike}); err != nil {
+		t.Fatal(err)
+	}
+	feedbacks, err := s.ListFeedbacks(time.Time{}, time.Now().Add(time.Minute), storage.WithAppName("app"), storage.WithAppNamespace("default"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feedbacks) != 1 || feedbacks[0].Rating != storage.FeedbackRatingLike || feedbacks[0].CorrectedAnswer != "" {
+		t.Errorf("expected the feedback to be replaced, but got %+v", feedbacks)
+	}
+
+	if err = cs.DeleteFeedback(bob, req); err == nil {
+		t.Error("expected the feedback not to be deleted by other user")
+	}
+	if err = cs.DeleteFeedback(alice, req); err != nil {
+		t.Fatal(err)
+	}
+	if feedbacks, _ = s.ListFeedbacks(time.Time{}, time.Now().Add(time.Minute), storage.WithAppName("app")); len(feedbacks) != 0 {
+		t.Errorf("expected the feedback to be deleted, but got %+v", feedbacks)
+	}
+	req.MessageID = "not-found"
+	if err = cs.DeleteFeedback(alice, req); err == nil {
+		t.Error("expected an error for the message not found")
+	}
+}
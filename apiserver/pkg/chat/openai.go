@@ -0,0 +1,219 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/utils/pointer"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/retriever"
+)
+
+// The types below are compatible with OpenAI's chat completions api(https://platform.openai.com/docs/api-reference/chat),
+// so that the clients based on OpenAI SDK can chat with applications.
+
+const (
+	OpenAIRoleSystem    = "system"
+	OpenAIRoleUser      = "user"
+	OpenAIRoleAssistant = "assistant"
+
+	OpenAIObjectChatCompletion      = "chat.completion"
+	OpenAIObjectChatCompletionChunk = "chat.completion.chunk"
+	OpenAIObjectModel               = "model"
+	OpenAIObjectList                = "list"
+
+	OpenAIFinishReasonStop = "stop"
+)
+
+// OpenAIMessage is a message in the chat completion request
+type OpenAIMessage struct {
+	Role string `json:"role" example:"user"`
+	// Content is a string, or an array of content parts of which only the text parts are used
+	Content OpenAIMessageContent `json:"content" swaggertype:"string" example:"旷工最小计算单位为多少天？"`
+}
+
+// OpenAIMessageContent is the text content of a message
+type OpenAIMessageContent string
+
+func (content *OpenAIMessageContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*content = OpenAIMessageContent(text)
+		return nil
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("content must be a string or an array of content parts: %w", err)
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	*content = OpenAIMessageContent(strings.Join(texts, "\n"))
+	return nil
+}
+
+// OpenAIChatCompletionReqBody is the request body of chat completions
+type OpenAIChatCompletionReqBody struct {
+	// Model is the application in `namespace/name` format, or the application name in the namespace of request header
+	Model string `json:"model" binding:"required" example:"arcadia/chat-with-llm"`
+	// Messages are the history of this chat, the last one must be from user.
+	// System messages are ignored as the prompt is defined by the application.
+	Messages []OpenAIMessage `json:"messages" binding:"required"`
+	Stream   bool            `json:"stream,omitempty"`
+	// ConversationID is an extension to store this chat into an existing conversation, a new conversation will be created if it is empty
+	ConversationID string `json:"conversation_id,omitempty" example:"5a41f3ca-763b-41ec-91c3-4bbbb00736d0"`
+}
+
+// ParseOpenAIModel gets the namespace and name of the application from the model
+func ParseOpenAIModel(model, defaultNamespace string) (namespace, name string) {
+	if namespace, name, ok := strings.Cut(model, "/"); ok {
+		return namespace, name
+	}
+	return defaultNamespace, model
+}
+
+// OpenAIModelOfApp is the model of an application in chat completions
+func OpenAIModelOfApp(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// ToChatReqBody converts the chat completion request to a chat request of the application
+func (req OpenAIChatCompletionReqBody) ToChatReqBody(defaultNamespace string) (ChatReqBody, error) {
+	chatReq := ChatReqBody{
+		StartTime: time.Now(),
+		History:   make([]HistoryMessage, 0),
+	}
+	chatReq.AppNamespace, chatReq.APPName = ParseOpenAIModel(req.Model, defaultNamespace)
+	if chatReq.AppNamespace == "" || chatReq.APPName == "" {
+		return chatReq, fmt.Errorf("invalid model %s, must be the application in namespace/name format", req.Model)
+	}
+	chatReq.ConversationID = req.ConversationID
+	chatReq.ResponseMode = Blocking
+	if req.Stream {
+		chatReq.ResponseMode = Streaming
+	}
+
+	// pair the user messages with the assistant's answers
+	var queries []string
+	for _, message := range req.Messages {
+		switch message.Role {
+		case OpenAIRoleUser:
+			queries = append(queries, string(message.Content))
+		case OpenAIRoleAssistant:
+			chatReq.History = append(chatReq.History, HistoryMessage{Query: strings.Join(queries, "\n"), Answer: string(message.Content)})
+			queries = nil
+		}
+	}
+	if len(queries) == 0 {
+		return chatReq, errors.New("the last message must be from user")
+	}
+	chatReq.Query = strings.Join(queries, "\n")
+	return chatReq, nil
+}
+
+// OpenAIUsage is the token usage of a chat completion
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type OpenAIChoice struct {
+	Index        int            `json:"index"`
+	Message      *OpenAIMessage `json:"message,omitempty"`
+	Delta        *OpenAIMessage `json:"delta,omitempty"`
+	FinishReason *string        `json:"finish_reason"`
+}
+
+// OpenAIChatCompletionRespBody is the response body of chat completions,
+// and is also the chunk in streaming mode with the delta in choices
+type OpenAIChatCompletionRespBody struct {
+	ID      string         `json:"id" example:"chatcmpl-4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
+	Object  string         `json:"object" example:"chat.completion"`
+	Created int64          `json:"created" example:"1703125266"`
+	Model   string         `json:"model" example:"arcadia/chat-with-llm"`
+	Choices []OpenAIChoice `json:"choices"`
+	Usage   *OpenAIUsage   `json:"usage,omitempty"`
+	// ConversationID is an extension for the conversation this chat is stored in
+	ConversationID string `json:"conversation_id,omitempty" example:"5a41f3ca-763b-41ec-91c3-4bbbb00736d0"`
+	// References is an extension for the references of the answer,only in the last chunk of streaming mode
+	References []retriever.Reference `json:"references,omitempty"`
+}
+
+// NewOpenAIChatCompletion returns the chat completion of the application's response
+func NewOpenAIChatCompletion(model string, resp *ChatRespBody) OpenAIChatCompletionRespBody {
+	completion := NewOpenAIChatCompletionChunk(model, resp.ConversationID, resp.MessageID, "")
+	completion.Object = OpenAIObjectChatCompletion
+	completion.Choices[0].Delta = nil
+	completion.Choices[0].Message = &OpenAIMessage{Role: OpenAIRoleAssistant, Content: OpenAIMessageContent(resp.Message)}
+	completion.Choices[0].FinishReason = pointer.String(OpenAIFinishReasonStop)
+	completion.Usage = &OpenAIUsage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	}
+	completion.References = resp.References
+	return completion
+}
+
+// NewOpenAIChatCompletionChunk returns a chunk with the delta content in streaming mode
+func NewOpenAIChatCompletionChunk(model, conversationID, messageID, content string) OpenAIChatCompletionRespBody {
+	return OpenAIChatCompletionRespBody{
+		ID:      "chatcmpl-" + messageID,
+		Object:  OpenAIObjectChatCompletionChunk,
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []OpenAIChoice{
+			{Index: 0, Delta: &OpenAIMessage{Role: OpenAIRoleAssistant, Content: OpenAIMessageContent(content)}},
+		},
+		ConversationID: conversationID,
+	}
+}
+
+// OpenAIModel is an application which can be used as the model of chat completions
+type OpenAIModel struct {
+	ID      string `json:"id" example:"arcadia/chat-with-llm"`
+	Object  string `json:"object" example:"model"`
+	Created int64  `json:"created" example:"1703125266"`
+	OwnedBy string `json:"owned_by" example:"arcadia"`
+}
+
+type OpenAIModelList struct {
+	Object string        `json:"object" example:"list"`
+	Data   []OpenAIModel `json:"data"`
+}
+
+// OpenAIErrorResp is the error response compatible with OpenAI
+type OpenAIErrorResp struct {
+	Error OpenAIError `json:"error"`
+}
+
+type OpenAIError struct {
+	Message string `json:"message" example:"application not ready"`
+	Type    string `json:"type" example:"invalid_request_error"`
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAIChatCompletionToChatReqBody(t *testing.T) {
+	body := `{
+		"model": "arcadia/chat-with-llm",
+		"stream": true,
+		"messages": [
+			{"role": "system", "content": "You are a helpful assistant."},
+			{"role": "user", "content": "hi"},
+			{"role": "assistant", "content": "hello"},
+			{"role": "user", "content": [{"type": "text", "text": "who are you?"}, {"type": "image_url", "image_url": {"url": "https://example.com/a.png"}}]}
+		]
+	}`
+	req := OpenAIChatCompletionReqBody{}
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("unmarshal failed: %s", err)
+	}
+	chatReq, err := req.ToChatReqBody("default")
+	if err != nil {
+		t.Fatalf("convert failed: %s", err)
+	}
+	if chatReq.AppNamespace != "arcadia" || chatReq.APPName != "chat-with-llm" {
+		t.Errorf("unexpected app %s/%s", chatReq.AppNamespace, chatReq.APPName)
+	}
+	if !chatReq.ResponseMode.IsStreaming() {
+		t.Errorf("expect streaming mode")
+	}
+	if chatReq.Query != "who are you?" {
+		t.Errorf("unexpected query %q", chatReq.Query)
+	}
+	if want := []HistoryMessage{{Query: "hi", Answer: "hello"}}; !reflect.DeepEqual(chatReq.History, want) {
+		t.Errorf("unexpected history %v, want %v", chatReq.History, want)
+	}
+
+	// the app in header namespace
+	req.Model = "chat-with-llm"
+	if chatReq, _ = req.ToChatReqBody("default"); chatReq.AppNamespace != "default" {
+		t.Errorf("unexpected namespace %s", chatReq.AppNamespace)
+	}
+
+	// the last message is not from user
+	req.Messages = req.Messages[:3]
+	if _, err = req.ToChatReqBody("default"); err == nil {
+		t.Errorf("expect error when the last message is not from user")
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+)
+
+// QuotaExceededError is returned when a chat request exceeds the quota
+type QuotaExceededError struct {
+	Quota   pkgconfig.Quota
+	Subject string
+	Reason  string
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded for %s %s: %s", e.Quota.Scope, e.Subject, e.Reason)
+}
+
+// requestWindow counts the requests in the last minute of each subject
+type requestWindow struct {
+	mu       sync.Mutex
+	requests map[string][]time.Time
+}
+
+var requestsInLastMinute = newRequestWindow()
+
+func newRequestWindow() *requestWindow {
+	return &requestWindow{requests: make(map[string][]time.Time)}
+}
+
+// rateLimit is the limit of requests per minute of a quota
+type rateLimit struct {
+	quota   pkgconfig.Quota
+	subject string
+}
+
+func (l rateLimit) key() string {
+	return string(l.quota.Scope) + "/" + l.subject
+}
+
+// allow records the request only if the requests in the last minute don't exceed any limit.
+// It returns the index of the first exceeded limit, or -1 if the request is allowed.
+func (w *requestWindow) allow(limits []rateLimit, now time.Time) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	start := now.Add(-time.Minute)
+	for i, limit := range limits {
+		requests := w.requests[limit.key()]
+		n := 0
+		for n < len(requests) && !requests[n].After(start) {
+			n++
+		}
+		w.requests[limit.key()] = requests[n:]
+		if len(requests)-n >= limit.quota.RequestsPerMinute {
+			return i
+		}
+	}
+	for _, limit := range limits {
+		w.requests[limit.key()] = append(w.requests[limit.key()], now)
+	}
+	return -1
+}
+
+// matchQuota returns the subject of the quota and the search options to aggregate its usage,
+// returns false if the quota doesn't apply to this request
+func matchQuota(quota pkgconfig.Quota, user, appName, appNamespace string) (string, []storage.SearchOption, bool) {
+	switch quota.Scope {
+	case pkgconfig.QuotaScopeUser:
+		if quota.Name != "" && quota.Name != user {
+			return "", nil, false
+		}
+		return user, []storage.SearchOption{storage.WithUser(user)}, true
+	case pkgconfig.QuotaScopeApplication:
+		if (quota.Name != "" && quota.Name != appName) || (quota.Namespace != "" && quota.Namespace != appNamespace) {
+			return "", nil, false
+		}
+		return appNamespace + "/" + appName, []storage.SearchOption{storage.WithAppName(appName), storage.WithAppNamespace(appNamespace)}, true
+	case pkgconfig.QuotaScopeNamespace:
+		if quota.Name != "" && quota.Name != appNamespace {
+			return "", nil, false
+		}
+		return appNamespace, []storage.SearchOption{storage.WithAppNamespace(appNamespace)}, true
+	}
+	return "", nil, false
+}
+
+// CheckQuota checks the configured quotas for the current user and the application,
+// returns a QuotaExceededError if any quota is exceeded
+func (cs *ChatServer) CheckQuota(ctx context.Context, appName, appNamespace string) error {
+	quotas, err := pkgconfig.GetQuotas(ctx)
+	if err != nil {
+		klog.FromContext(ctx).V(3).Info("failed to get quotas, skip quota check", "error", err.Error())
+		// the rate limit of the share token applies even if no quota is configured
+		quotas = nil
+	}
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	return checkQuotas(requestsInLastMinute, quotas, ShareTokenFromContext(ctx), currentUser, appName, appNamespace, time.Now(),
+		func(start, end time.Time, opts ...storage.SearchOption) (int64, error) {
+			usages, err := cs.Storage().AggregateUsage(start, end, opts...)
+			if err != nil {
+				return 0, err
+			}
+			var tokens int64
+			for _, u := range usages {
+				tokens += u.TotalTokens
+			}
+			return tokens, nil
+		})
+}
+
+// tokensUsage returns the tokens used in the period
+type tokensUsage func(start, end time.Time, opts ...storage.SearchOption) (int64, error)
+
+// checkQuotas checks all quotas before recording the request in the request window,
+// so that a rejected request is not counted by the quotas it passed.
+func checkQuotas(window *requestWindow, quotas []pkgconfig.Quota, token *storage.ShareToken, user, appName, appNamespace string, now time.Time, usage tokensUsage) error {
+	limits := make([]rateLimit, 0, len(quotas)+1)
+	if token != nil {
+		limits = append(limits, rateLimit{quota: shareTokenQuota(token), subject: token.ID})
+	}
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, quota := range quotas {
+		subject, opts, ok := matchQuota(quota, user, appName, appNamespace)
+		if !ok {
+			continue
+		}
+		if quota.RequestsPerMinute > 0 {
+			limits = append(limits, rateLimit{quota: quota, subject: subject})
+		}
+		if quota.TokensPerDay > 0 {
+			tokens, err := usage(startOfDay, now, opts...)
+			if err != nil {
+				return err
+			}
+			if tokens >= quota.TokensPerDay {
+				return &QuotaExceededError{Quota: quota, Subject: subject, Reason: fmt.Sprintf("%d tokens used today, the limit is %d", tokens, quota.TokensPerDay)}
+			}
+		}
+	}
+	if i := window.allow(limits, now); i >= 0 {
+		return &QuotaExceededError{Quota: limits[i].quota, Subject: limits[i].subject, Reason: fmt.Sprintf("more than %d requests per minute", limits[i].quota.RequestsPerMinute)}
+	}
+	return nil
+}
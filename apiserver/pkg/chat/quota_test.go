@@ -0,0 +1,105 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+)
+
+func TestMatchQuota(t *testing.T) {
+	testCases := []struct {
+		name    string
+		quota   pkgconfig.Quota
+		subject string
+		match   bool
+	}{
+		{name: "all users", quota: pkgconfig.Quota{Scope: pkgconfig.QuotaScopeUser}, subject: "alice", match: true},
+		{name: "the user", quota: pkgconfig.Quota{Scope: pkgconfig.QuotaScopeUser, Name: "alice"}, subject: "alice", match: true},
+		{name: "another user", quota: pkgconfig.Quota{Scope: pkgconfig.QuotaScopeUser, Name: "bob"}},
+		{name: "the application", quota: pkgconfig.Quota{Scope: pkgconfig.QuotaScopeApplication, Name: "app", Namespace: "default"}, subject: "default/app", match: true},
+		{name: "application in another namespace", quota: pkgconfig.Quota{Scope: pkgconfig.QuotaScopeApplication, Name: "app", Namespace: "other"}},
+		{name: "the namespace", quota: pkgconfig.Quota{Scope: pkgconfig.QuotaScopeNamespace, Name: "default"}, subject: "default", match: true},
+		{name: "unknown scope", quota: pkgconfig.Quota{Scope: "unknown"}},
+	}
+	for _, tc := range testCases {
+		subject, _, ok := matchQuota(tc.quota, "alice", "app", "default")
+		if ok != tc.match || subject != tc.subject {
+			t.Errorf("%s: want %q(%v), got %q(%v)", tc.name, tc.subject, tc.match, subject, ok)
+		}
+	}
+}
+
+func TestCheckQuotas(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	quotas := []pkgconfig.Quota{
+		{Scope: pkgconfig.QuotaScopeUser, RequestsPerMinute: 2},
+		{Scope: pkgconfig.QuotaScopeApplication, Name: "app", RequestsPerMinute: 3, TokensPerDay: 100},
+	}
+	tokens := map[string]int64{"app": 0}
+	usage := func(start, end time.Time, opts ...storage.SearchOption) (int64, error) {
+		if !start.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || end.Before(now) {
+			t.Errorf("want the usage of today, got %s - %s", start, end)
+		}
+		return tokens["app"], nil
+	}
+	window := newRequestWindow()
+	check := func(user string, at time.Time) error {
+		return checkQuotas(window, quotas, nil, user, "app", "default", at, usage)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := check("alice", now); err != nil {
+			t.Fatalf("request %d should be allowed, got %v", i, err)
+		}
+	}
+	quotaErr := &QuotaExceededError{}
+	if err := check("alice", now); !errors.As(err, &quotaErr) || quotaErr.Quota.Scope != pkgconfig.QuotaScopeUser || quotaErr.Subject != "alice" {
+		t.Fatalf("want the user quota exceeded, got %v", err)
+	}
+	// the request rejected by the user quota is not counted by the application quota
+	if err := check("bob", now); err != nil {
+		t.Fatalf("want the request of another user allowed, got %v", err)
+	}
+	if err := check("carol", now); !errors.As(err, &quotaErr) || quotaErr.Quota.Scope != pkgconfig.QuotaScopeApplication {
+		t.Fatalf("want the application quota exceeded, got %v", err)
+	}
+
+	// a request rejected by the tokens is not counted by any window
+	later := now.Add(time.Minute)
+	tokens["app"] = 100
+	for i := 0; i < 3; i++ {
+		if err := check("alice", later); !errors.As(err, &quotaErr) || quotaErr.Quota.TokensPerDay == 0 {
+			t.Fatalf("want the tokens per day exceeded, got %v", err)
+		}
+	}
+	tokens["app"] = 99
+	if err := check("alice", later); err != nil {
+		t.Errorf("want the request allowed after the tokens are available, got %v", err)
+	}
+
+	failed := errors.New("storage is down")
+	if err := checkQuotas(newRequestWindow(), quotas, nil, "alice", "app", "default", now, func(time.Time, time.Time, ...storage.SearchOption) (int64, error) {
+		return 0, failed
+	}); !errors.Is(err, failed) {
+		t.Errorf("want the storage error, got %v", err)
+	}
+}
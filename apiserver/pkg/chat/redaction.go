@@ -0,0 +1,92 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+)
+
+var (
+	emailRegexp = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// the resident identity card number of China
+	idCardRegexp = regexp.MustCompile(`\b[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`)
+	// the mobile phone numbers of China with optional country code, and the landline numbers with area code
+	phoneRegexp = regexp.MustCompile(`(?:\+86[- ]?|\b86[- ]?|\b)1[3-9]\d{9}\b|\b0\d{2,3}-\d{7,8}\b`)
+
+	// the rules are applied in order, so the numbers in emails and id cards are not masked as phones
+	allRedactionRules = []pkgconfig.RedactionRule{pkgconfig.RedactionRuleEmail, pkgconfig.RedactionRuleIDCard, pkgconfig.RedactionRulePhone}
+)
+
+// maskMiddle replaces the runes between the head and tail with *
+func maskMiddle(s string, head, tail int) string {
+	runes := []rune(s)
+	if head < 0 {
+		head = 0
+	}
+	if head+tail >= len(runes) {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:head]) + strings.Repeat("*", len(runes)-head-tail) + string(runes[len(runes)-tail:])
+}
+
+// Redact masks the personal information in the text by the rules, all rules are applied if rules is empty
+func Redact(text string, rules []pkgconfig.RedactionRule) string {
+	enabled := make(map[pkgconfig.RedactionRule]bool, len(rules))
+	for _, rule := range rules {
+		enabled[rule] = true
+	}
+	for _, rule := range allRedactionRules {
+		if len(rules) > 0 && !enabled[rule] {
+			continue
+		}
+		switch rule {
+		case pkgconfig.RedactionRuleEmail:
+			text = emailRegexp.ReplaceAllStringFunc(text, func(email string) string {
+				local, domain, _ := strings.Cut(email, "@")
+				return maskMiddle(local, 1, 0) + "@" + domain
+			})
+		case pkgconfig.RedactionRuleIDCard:
+			text = idCardRegexp.ReplaceAllStringFunc(text, func(id string) string {
+				return maskMiddle(id, 3, 4)
+			})
+		case pkgconfig.RedactionRulePhone:
+			text = phoneRegexp.ReplaceAllStringFunc(text, func(phone string) string {
+				return maskMiddle(phone, len(phone)-8, 4)
+			})
+		}
+	}
+	return text
+}
+
+// redactionRules returns the rules to redact the stored queries and answers, false if the redaction is disabled
+func redactionRules(ctx context.Context) ([]pkgconfig.RedactionRule, bool) {
+	redaction, err := pkgconfig.GetRedaction(ctx)
+	if err != nil {
+		klog.FromContext(ctx).V(3).Info("failed to get redaction, skip redaction", "error", err.Error())
+		return nil, false
+	}
+	if redaction == nil || !redaction.Enabled {
+		return nil, false
+	}
+	return redaction.Rules, true
+}
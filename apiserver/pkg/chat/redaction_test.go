@@ -0,0 +1,43 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"testing"
+
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+)
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		text  string
+		rules []pkgconfig.RedactionRule
+		want  string
+	}{
+		{text: "我的邮箱是zhangsan@example.com，请联系我", want: "我的邮箱是z*******@example.com，请联系我"},
+		{text: "手机13812345678，座机010-12345678", want: "手机138****5678，座机010-****5678"},
+		{text: "call +86 13812345678 now", want: "call +86 138****5678 now"},
+		{text: "身份证号11010519491231002X", want: "身份证号110***********002X"},
+		{text: "订单号 123456789012345678901 不是手机号", want: "订单号 123456789012345678901 不是手机号"},
+		{text: "a@example.com 13812345678", rules: []pkgconfig.RedactionRule{pkgconfig.RedactionRulePhone}, want: "a@example.com 138****5678"},
+	}
+	for _, c := range cases {
+		if got := Redact(c.text, c.rules); got != c.want {
+			t.Errorf("Redact(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
@@ -19,6 +19,8 @@ package chat
 import (
 	"time"
 
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	"github.com/kubeagi/arcadia/pkg/appruntime/llm"
 	"github.com/kubeagi/arcadia/pkg/appruntime/retriever"
 )
 
@@ -61,19 +63,109 @@ type MessageReqBody struct {
 	MessageID string `json:"message_id" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
 }
 
+type FeedbackReqBody struct {
+	MessageReqBody `json:",inline"`
+	// Rating of the answer, like or dislike
+	Rating storage.FeedbackRating `json:"rating" binding:"required,oneof=like dislike" example:"dislike"`
+	// Reason category of the rating, one of inaccurate,irrelevant,incomplete,harmful,helpful and other
+	Reason storage.FeedbackReason `json:"reason" binding:"omitempty,oneof=inaccurate irrelevant incomplete harmful helpful other" example:"inaccurate"`
+	// Comment is the free text comment on the answer
+	Comment string `json:"comment" example:"旷工最小计算单位应为1天"`
+	// CorrectedAnswer is the correct answer given by user, which is used as ground truth when exported
+	CorrectedAnswer string `json:"corrected_answer" example:"旷工最小计算单位为1天。"`
+}
+
+type SearchReqBody struct {
+	// AppName, search in the conversations of the application, all applications in the namespace if it is empty
+	APPName string `json:"app_name" example:"chat-with-llm"`
+	// AppNamespace, will be forced to use the value of the namespace in the request header
+	AppNamespace string `json:"-"`
+	// Keyword, the words to search in the queries and answers, the messages containing all words are matched
+	Keyword string `json:"keyword" binding:"required" example:"旷工"`
+	// StartTime and EndTime limit the update time of conversations
+	StartTime *time.Time `json:"start_time,omitempty" example:"2023-12-21T00:00:00+08:00"`
+	EndTime   *time.Time `json:"end_time,omitempty" example:"2023-12-22T00:00:00+08:00"`
+	// Page starts from 1
+	Page     int `json:"page" example:"1"`
+	PageSize int `json:"page_size" example:"10"`
+}
+
+type SearchRespBody struct {
+	Total    int64                `json:"total" example:"1"`
+	Page     int                  `json:"page" example:"1"`
+	PageSize int                  `json:"page_size" example:"10"`
+	Hits     []storage.MessageHit `json:"hits"`
+}
+
+type ExportReqBody struct {
+	// AppName, export the conversations of the application, all applications in the namespace if it is empty
+	APPName string `json:"app_name" example:"chat-with-llm"`
+	// AppNamespace, will be forced to use the value of the namespace in the request header
+	AppNamespace string `json:"-"`
+	// ConversationID, export this conversation only if it is not empty
+	ConversationID string `json:"conversation_id" example:"5a41f3ca-763b-41ec-91c3-4bbbb00736d0"`
+	// Format, json or markdown, json by default
+	Format string `json:"format" binding:"omitempty,oneof=json markdown" example:"markdown"`
+}
+
+type ShareTokenReqBody struct {
+	APPMetadata `json:",inline"`
+	// Name of the share token, such as the page where the widget is embedded
+	Name string `json:"name" example:"intranet hr bot"`
+	// ExpiresAt is the time the token expires at, the token never expires if it is empty
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-12-21T00:00:00+08:00"`
+	// RequestsPerMinute limits the chat requests of all anonymous users with this token, 10 by default
+	RequestsPerMinute int `json:"requests_per_minute" binding:"omitempty,min=1" example:"10"`
+}
+
+type ShareTokenRespBody struct {
+	storage.ShareToken `json:",inline"`
+	// Token is used by the anonymous users to chat, which is only returned when the token is created
+	Token string `json:"token,omitempty" example:"st_Jm9zZ3Vlc3MtdGhpcy1pcy1ub3QtYS1yZWFsLXRva2Vu"`
+}
+
+// SharedAppRespBody is the information of the application shown to the anonymous users
+type SharedAppRespBody struct {
+	APPName      string `json:"app_name" example:"chat-with-llm"`
+	AppNamespace string `json:"app_namespace" example:"arcadia"`
+	DisplayName  string `json:"display_name" example:"HR Assistant"`
+	Description  string `json:"description,omitempty" example:"answer the questions about HR policies"`
+	Icon         string `json:"icon,omitempty"`
+	Prologue     string `json:"prologue,omitempty" example:"Hi, how can I help you?"`
+}
+
 type ChatReqBody struct {
 	// Query user query string
-	Query string `json:"query" form:"query" binding:"required" example:"旷工最小计算单位为多少天？"`
-	// Files this conversation will use in the context
+	// It is required unless regenerating an answer
+	Query string `json:"query" form:"query" example:"旷工最小计算单位为多少天？"`
+	// Files this conversation will use in the context.
+	// Images are sent to the llm with the query, which requires a vision capable llm
 	Files []string `json:"files" form:"files" example:"test.pdf,song.mp3"`
 	// ResponseMode:
 	// * Blocking - means the response is returned in a blocking manner
 	// * Streaming - means the response will use Server-Sent Events
 	ResponseMode        ResponseMode `json:"response_mode" form:"response_mode" binding:"required" example:"blocking"`
 	ConversationReqBody `json:",inline"`
+	// ParentMessageID is the message this query follows, which is the last message of the selected branch by default.
+	// Set it to the parent of a message to edit that message's query in a new branch,
+	// the conversation id is the parent of the first round.
+	ParentMessageID string `json:"parent_message_id,omitempty" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
+	// RegenerateMessageID is the message to regenerate the answer in a new branch, its query and files are reused
+	RegenerateMessageID string    `json:"regenerate_message_id,omitempty" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
 	Debug               bool      `json:"-"`
 	NewChat             bool      `json:"-"`
 	StartTime           time.Time `json:"-"`
+	// History is used as the chat history instead of the messages stored in conversation if not nil,
+	// for the clients which maintain the history by themselves
+	History []HistoryMessage `json:"-"`
+	// ContinueMessageID is the message whose answer is extended by the llm instead of adding a new message
+	ContinueMessageID string `json:"-"`
+}
+
+// HistoryMessage is a round of chat in history
+type HistoryMessage struct {
+	Query  string
+	Answer string
 }
 
 type ChatRespBody struct {
@@ -91,6 +183,8 @@ type ChatRespBody struct {
 	Latency int64 `json:"latency,omitempty" example:"1000"`
 	// Documents in this chat
 	Document DocumentRespBody `json:"document,omitempty"`
+	// Usage is the token usage of this chat
+	Usage llm.Usage `json:"-"`
 }
 
 type DocumentRespBody struct {
@@ -125,3 +219,47 @@ type ConversatioSingleDocRespBody struct {
 	Summary                  string  `json:"summary,omitempty"`
 	TimecostForSummarization float64 `json:"timecost_for_summarization,omitempty"`
 }
+
+// WebSocketFrameType is the type of the frames in the chat websocket
+type WebSocketFrameType string
+
+const (
+	// FrameChat asks a question with the chat request, the same as the body of chat api
+	FrameChat WebSocketFrameType = "chat"
+	// FrameContinue asks the llm to continue the answer of the parent message in chat request
+	FrameContinue WebSocketFrameType = "continue"
+	// FrameCancel stops answering the message with the same id
+	FrameCancel WebSocketFrameType = "cancel"
+
+	// EventMessage is a piece of the answer in streaming mode
+	EventMessage WebSocketFrameType = "message"
+	// EventDone is the whole answer, the message is finished
+	EventDone WebSocketFrameType = "done"
+	// EventError means the message is failed or the frame is invalid
+	EventError WebSocketFrameType = "error"
+	// EventCancelled means the message is stopped by a cancel frame
+	EventCancelled WebSocketFrameType = "cancelled"
+)
+
+// WebSocketRequest is a frame sent by the client
+type WebSocketRequest struct {
+	// Type is one of chat, continue and cancel
+	Type WebSocketFrameType `json:"type" example:"chat"`
+	// ID is chosen by the client to identify the message in this socket, the events of the message have the same id
+	ID string `json:"id" example:"1"`
+	// Chat is the chat request for chat and continue frames.
+	// For continue frames, the query is ignored and parent_message_id is the message to continue
+	Chat *ChatReqBody `json:"chat,omitempty"`
+}
+
+// WebSocketEvent is a frame sent by the server
+type WebSocketEvent struct {
+	// Type is one of message, done, error and cancelled
+	Type WebSocketFrameType `json:"type" example:"message"`
+	// ID is the id in the request frame
+	ID string `json:"id" example:"1"`
+	// Data is the answer, only the piece in the message event
+	Data *ChatRespBody `json:"data,omitempty"`
+	// Error is the reason of the error event
+	Error string `json:"error,omitempty" example:"conversation is not found"`
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+)
+
+// RetentionInterval is how often the expired conversations are purged
+const RetentionInterval = time.Hour
+
+// RunRetention purges the expired conversations periodically until the context is done
+func (cs *ChatServer) RunRetention(ctx context.Context, interval time.Duration) {
+	wait.UntilWithContext(ctx, cs.PurgeExpiredConversations, interval)
+}
+
+// retentionOptions returns the search options of the conversations the retention applies to.
+// The names are required, as the empty ones match all conversations.
+func retentionOptions(retention pkgconfig.Retention) ([]storage.SearchOption, bool) {
+	switch retention.Scope {
+	case pkgconfig.RetentionScopeApplication:
+		if retention.Name == "" || retention.Namespace == "" {
+			return nil, false
+		}
+		return []storage.SearchOption{storage.WithAppName(retention.Name), storage.WithAppNamespace(retention.Namespace)}, true
+	case pkgconfig.RetentionScopeNamespace:
+		if retention.Name == "" {
+			return nil, false
+		}
+		return []storage.SearchOption{storage.WithAppNamespace(retention.Name)}, true
+	}
+	return nil, false
+}
+
+// PurgeExpiredConversations hard deletes the conversations by the configured retentions,
+// together with their conversation knowledgebases and the facts about the users remembered from them
+func (cs *ChatServer) PurgeExpiredConversations(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	retentions, err := pkgconfig.GetRetentions(ctx)
+	if err != nil {
+		logger.V(3).Info("failed to get retentions, skip purging conversations", "error", err.Error())
+		return
+	}
+	now := time.Now()
+	for _, retention := range retentions {
+		opts, ok := retentionOptions(retention)
+		if !ok || retention.Days <= 0 {
+			logger.Info("invalid retention, skip it", "retention", retention)
+			continue
+		}
+		purged, err := cs.Storage().PurgeConversations(now.AddDate(0, 0, -retention.Days), opts...)
+		if err != nil {
+			logger.Error(err, "failed to purge conversations", "retention", retention)
+			continue
+		}
+		for _, c := range purged {
+			if err := cs.deleteConversationKnowledgeBase(ctx, c.ID); err != nil {
+				logger.Error(err, "conversation purged but failed to delete its knowledgebase", "conversationID", c.ID)
+			}
+			if err := cs.ForgetFacts(ctx, c); err != nil {
+				logger.Error(err, "conversation purged but failed to forget the facts remembered from it", "conversationID", c.ID)
+			}
+		}
+		if len(purged) > 0 {
+			logger.Info("expired conversations purged", "retention", retention, "count", len(purged))
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+// newConfigClient returns a fake client with the arcadia config, which is used as the system client of the config
+func newConfigClient(t *testing.T, config string, objs ...client.Object) client.Client {
+	t.Setenv(utils.EnvNamespaceKey, "arcadia")
+	t.Setenv(pkgconfig.EnvConfigKey, pkgconfig.EnvConfigDefaultValue)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "arcadia", Name: pkgconfig.EnvConfigDefaultValue},
+		Data:       map[string]string{"config": config},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, cm)...).Build()
+	pkgconfig.InitSystemClient(c)
+	t.Cleanup(func() { pkgconfig.InitSystemClient(nil) })
+	return c
+}
+
+func TestRetentionOptions(t *testing.T) {
+	testCases := []struct {
+		retention pkgconfig.Retention
+		valid     bool
+	}{
+		{pkgconfig.Retention{Scope: pkgconfig.RetentionScopeApplication, Name: "app", Namespace: "default", Days: 30}, true},
+		{pkgconfig.Retention{Scope: pkgconfig.RetentionScopeApplication, Name: "app", Days: 30}, false},
+		{pkgconfig.Retention{Scope: pkgconfig.RetentionScopeApplication, Namespace: "default", Days: 30}, false},
+		{pkgconfig.Retention{Scope: pkgconfig.RetentionScopeNamespace, Name: "default", Days: 30}, true},
+		{pkgconfig.Retention{Scope: pkgconfig.RetentionScopeNamespace, Days: 30}, false},
+		{pkgconfig.Retention{Scope: "user", Name: "alice", Days: 30}, false},
+	}
+	for _, tc := range testCases {
+		if _, valid := retentionOptions(tc.retention); valid != tc.valid {
+			t.Errorf("%+v: expected valid %v, but got %v", tc.retention, tc.valid, valid)
+		}
+	}
+}
+
+func TestPurgeExpiredConversations(t *testing.T) {
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "retention", Name: "app"}}
+	c := newConfigClient(t, "retentions:\n- scope: namespace\n  days: 1\n- scope: application\n  name: app\n  namespace: retention\n  days: 30\n", app)
+	s := useMemoryStorage(t)
+	now := time.Now()
+	for id, updatedAt := range map[string]time.Time{"retention-old": now.AddDate(0, 0, -31), "retention-new": now.AddDate(0, 0, -2)} {
+		if err := s.UpdateConversation(&storage.Conversation{ID: id, AppName: "app", AppNamespace: "retention", User: "alice", UpdatedAt: updatedAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the retention of namespace without the name is skipped, instead of purging the conversations of all namespaces
+	NewChatServer(c, false).PurgeExpiredConversations(context.Background())
+	if conversation, _ := s.FindExistingConversation("retention-old"); conversation != nil {
+		t.Error("expected the expired conversation purged")
+	}
+	if conversation, _ := s.FindExistingConversation("retention-new"); conversation == nil {
+		t.Error("expected the conversation kept by the retention of application")
+	}
+}
+
+func TestForgetFacts(t *testing.T) {
+	app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	memoryApp := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "memory-app"}}
+	memoryApp.Spec.LongTermMemory = &v1alpha1.LongTermMemory{Enabled: true}
+	cs := NewChatServer(newConfigClient(t, "gateway:\n  controller: http://fastchat-controller:21001\n", app, memoryApp), false)
+	ctx := context.Background()
+
+	if err := cs.ForgetFacts(ctx, storage.Conversation{ID: "c1", AppName: "app", AppNamespace: "default"}); err != nil {
+		t.Errorf("expected nothing to forget for the anonymous user, but got %v", err)
+	}
+	if err := cs.ForgetFacts(ctx, storage.Conversation{ID: "c1", AppName: "app", AppNamespace: "default", User: "alice"}); err != nil {
+		t.Errorf("expected nothing to forget without long-term memory, but got %v", err)
+	}
+	// the facts are removed from the system vectorstore, which is not configured here
+	if err := cs.ForgetFacts(ctx, storage.Conversation{ID: "c1", AppName: "memory-app", AppNamespace: "default", User: "alice"}); err == nil {
+		t.Error("expected the facts removed from the system vectorstore")
+	}
+	if err := cs.ForgetFacts(ctx, storage.Conversation{ID: "c1", AppName: "deleted-app", AppNamespace: "default", User: "alice"}); err == nil {
+		t.Error("expected the facts of a deleted app removed from the system vectorstore")
+	}
+}
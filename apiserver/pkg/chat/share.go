@@ -0,0 +1,198 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/kubeagi/arcadia/apiserver/config"
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	"github.com/kubeagi/arcadia/apiserver/pkg/common"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+)
+
+const (
+	// DefaultShareTokenRequestsPerMinute is the rate limit of a share token if not set
+	DefaultShareTokenRequestsPerMinute = 10
+	// shareTokenPrefix makes the share tokens distinguishable from the oidc tokens
+	shareTokenPrefix = "st_"
+	// shareUserPrefix is the prefix of the user of the conversations started with share tokens
+	shareUserPrefix = "share:"
+	// MinShareVisitorLength is the minimum length of the visitor secret, which is random and kept by the anonymous user
+	MinShareVisitorLength = 16
+
+	quotaScopeShareToken pkgconfig.QuotaScope = "shareToken"
+)
+
+// ErrInvalidShareToken is returned if the share token is not found, expired or revoked
+var ErrInvalidShareToken = errors.New("share token is invalid, expired or revoked")
+
+// ErrInvalidShareVisitor is returned if the visitor secret is missing or too short
+var ErrInvalidShareVisitor = fmt.Errorf("visitor is required and should be at least %d characters", MinShareVisitorLength)
+
+type shareTokenContextKey struct{}
+
+// WithShareToken returns a context of an anonymous user chatting with the share token.
+// The user is identified by the visitor secret, so that the anonymous users of the same token can't find the conversations of each other.
+func WithShareToken(ctx context.Context, token *storage.ShareToken, visitor string) (context.Context, error) {
+	if len(visitor) < MinShareVisitorLength {
+		return nil, ErrInvalidShareVisitor
+	}
+	ctx = context.WithValue(ctx, auth.UserNameContextKey, shareUser(token, visitor))
+	return context.WithValue(ctx, shareTokenContextKey{}, token), nil
+}
+
+// shareUser is the user of the visitor, the secret is hashed as the user is stored and shown in the conversations
+func shareUser(token *storage.ShareToken, visitor string) string {
+	sum := sha256.Sum256([]byte(visitor))
+	return shareUserPrefix + token.ID + ":" + hex.EncodeToString(sum[:16])
+}
+
+// ShareTokenFromContext returns the share token of the anonymous user, nil for the signed-in users
+func ShareTokenFromContext(ctx context.Context) *storage.ShareToken {
+	token, _ := ctx.Value(shareTokenContextKey{}).(*storage.ShareToken)
+	return token
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return shareTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CreateShareToken creates a share token of the application, the token is only returned here
+func (cs *ChatServer) CreateShareToken(ctx context.Context, req ShareTokenReqBody) (*ShareTokenRespBody, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at should be in the future")
+	}
+	if _, err := cs.GetApp(ctx, req.APPName, req.AppNamespace); err != nil {
+		return nil, err
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	currentUser, _ := ctx.Value(auth.UserNameContextKey).(string)
+	shareToken := storage.ShareToken{
+		ID:                string(uuid.NewUUID()),
+		Name:              req.Name,
+		AppName:           req.APPName,
+		AppNamespace:      req.AppNamespace,
+		TokenHash:         hashShareToken(token),
+		Creator:           currentUser,
+		RequestsPerMinute: req.RequestsPerMinute,
+		ExpiresAt:         req.ExpiresAt,
+	}
+	if shareToken.RequestsPerMinute <= 0 {
+		shareToken.RequestsPerMinute = DefaultShareTokenRequestsPerMinute
+	}
+	if err := cs.Storage().CreateShareToken(&shareToken); err != nil {
+		return nil, err
+	}
+	return &ShareTokenRespBody{ShareToken: shareToken, Token: token}, nil
+}
+
+// ListShareTokens lists the share tokens of the application, including the expired and revoked ones
+func (cs *ChatServer) ListShareTokens(ctx context.Context, req APPMetadata) ([]storage.ShareToken, error) {
+	return cs.Storage().ListShareTokens(storage.WithAppName(req.APPName), storage.WithAppNamespace(req.AppNamespace))
+}
+
+// RevokeShareToken revokes a share token of the application, the anonymous users can't chat with it any more
+func (cs *ChatServer) RevokeShareToken(ctx context.Context, req APPMetadata, id string) error {
+	return cs.Storage().RevokeShareToken(id, time.Now(), storage.WithAppName(req.APPName), storage.WithAppNamespace(req.AppNamespace))
+}
+
+// VerifyShareToken returns the share token if it is valid now, otherwise ErrInvalidShareToken
+func (cs *ChatServer) VerifyShareToken(ctx context.Context, token string) (*storage.ShareToken, error) {
+	if !strings.HasPrefix(token, shareTokenPrefix) {
+		return nil, ErrInvalidShareToken
+	}
+	shareToken, err := cs.Storage().FindShareToken(hashShareToken(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrShareTokenNotFound) {
+			return nil, ErrInvalidShareToken
+		}
+		return nil, err
+	}
+	if !shareToken.Valid(time.Now()) {
+		return nil, ErrInvalidShareToken
+	}
+	return shareToken, nil
+}
+
+// ReverifyShareToken checks the share token verified before is still valid now, as it may expire or be revoked
+// in a long-lived connection. It returns ErrInvalidShareToken if not.
+func (cs *ChatServer) ReverifyShareToken(ctx context.Context, token *storage.ShareToken) error {
+	shareToken, err := cs.Storage().FindShareToken(token.TokenHash)
+	if err != nil {
+		if errors.Is(err, storage.ErrShareTokenNotFound) {
+			return ErrInvalidShareToken
+		}
+		return err
+	}
+	if !shareToken.Valid(time.Now()) {
+		return ErrInvalidShareToken
+	}
+	return nil
+}
+
+// shareTokenQuota limits the requests of all anonymous users with the share token
+func shareTokenQuota(token *storage.ShareToken) pkgconfig.Quota {
+	limit := token.RequestsPerMinute
+	if limit <= 0 {
+		limit = DefaultShareTokenRequestsPerMinute
+	}
+	return pkgconfig.Quota{Scope: quotaScopeShareToken, RequestsPerMinute: limit}
+}
+
+// GetSharedApp returns the information of the application shared by the token
+func (cs *ChatServer) GetSharedApp(ctx context.Context, token *storage.ShareToken) (*SharedAppRespBody, error) {
+	app, err := cs.GetApp(ctx, token.AppName, token.AppNamespace)
+	if err != nil {
+		return nil, err
+	}
+	resp := &SharedAppRespBody{
+		APPName:      app.Name,
+		AppNamespace: app.Namespace,
+		DisplayName:  app.Spec.DisplayName,
+		Description:  app.Spec.Description,
+		Icon:         common.AppIconLink(app, config.GetConfig().PlaygroundEndpointPrefix),
+		Prologue:     app.Spec.Prologue,
+	}
+	if resp.DisplayName == "" {
+		resp.DisplayName = app.Name
+	}
+	return resp, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+func TestShareTokenContext(t *testing.T) {
+	ctx := context.Background()
+	if ShareTokenFromContext(ctx) != nil {
+		t.Fatal("want no share token in the context of signed-in users")
+	}
+	token := &storage.ShareToken{ID: "9b2c3a4e", AppName: "app", AppNamespace: "ns"}
+	if _, err := WithShareToken(ctx, token, "short"); !errors.Is(err, ErrInvalidShareVisitor) {
+		t.Errorf("want ErrInvalidShareVisitor for a short visitor, got %v", err)
+	}
+	alice, err := WithShareToken(ctx, token, "alice-0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ShareTokenFromContext(alice); got != token {
+		t.Errorf("ShareTokenFromContext() = %v, want %v", got, token)
+	}
+	aliceUser, _ := alice.Value(auth.UserNameContextKey).(string)
+	if !strings.HasPrefix(aliceUser, "share:9b2c3a4e:") || strings.Contains(aliceUser, "alice") {
+		t.Errorf("want the anonymous user of the token with the hashed visitor, got %q", aliceUser)
+	}
+	bob, err := WithShareToken(ctx, token, "bob-0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bobUser, _ := bob.Value(auth.UserNameContextKey).(string); bobUser == aliceUser {
+		t.Errorf("want different users for different visitors, got %q", bobUser)
+	}
+	if again, _ := WithShareToken(ctx, token, "alice-0123456789abcdef"); again.Value(auth.UserNameContextKey) != aliceUser {
+		t.Errorf("want the same user for the same visitor")
+	}
+}
+
+func TestShareTokenLongTermMemory(t *testing.T) {
+	app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{LongTermMemory: &v1alpha1.LongTermMemory{Enabled: true}}}
+	if !longTermMemoryEnabled(context.Background(), app, "alice") {
+		t.Errorf("want long-term memory enabled for the signed-in users")
+	}
+	ctx, err := WithShareToken(context.Background(), &storage.ShareToken{ID: "9b2c3a4e"}, "alice-0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if longTermMemoryEnabled(ctx, app, "share:9b2c3a4e:alice") {
+		t.Errorf("want long-term memory disabled for the anonymous users")
+	}
+}
+
+func TestReverifyShareToken(t *testing.T) {
+	useMemoryStorage(t)
+	cs := &ChatServer{}
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+	token := &storage.ShareToken{ID: "t1", AppName: "app", AppNamespace: "ns", TokenHash: hashShareToken("st_t1"), ExpiresAt: &expiresAt}
+	if err := cs.Storage().CreateShareToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.ReverifyShareToken(ctx, token); err != nil {
+		t.Errorf("want the share token valid, got %v", err)
+	}
+	if err := cs.RevokeShareToken(ctx, APPMetadata{APPName: "app", AppNamespace: "ns"}, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.ReverifyShareToken(ctx, token); !errors.Is(err, ErrInvalidShareToken) {
+		t.Errorf("want ErrInvalidShareToken after revoked, got %v", err)
+	}
+	if err := cs.ReverifyShareToken(ctx, &storage.ShareToken{TokenHash: hashShareToken("st_unknown")}); !errors.Is(err, ErrInvalidShareToken) {
+		t.Errorf("want ErrInvalidShareToken for an unknown token, got %v", err)
+	}
+}
+
+func TestNewShareToken(t *testing.T) {
+	a, err := newShareToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newShareToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b || !strings.HasPrefix(a, shareTokenPrefix) {
+		t.Errorf("want unique tokens with prefix %s, got %s and %s", shareTokenPrefix, a, b)
+	}
+	if hashShareToken(a) == a || hashShareToken(a) != hashShareToken(a) {
+		t.Errorf("want a stable hash of the token")
+	}
+}
+
+func TestShareTokenRate(t *testing.T) {
+	window := newRequestWindow()
+	token := &storage.ShareToken{ID: "rate", RequestsPerMinute: 2}
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := checkQuotas(window, nil, token, "share:rate", "app", "default", now, nil); err != nil {
+			t.Fatalf("request %d should be allowed, got %v", i, err)
+		}
+	}
+	quotaErr := &QuotaExceededError{}
+	if err := checkQuotas(window, nil, token, "share:rate", "app", "default", now, nil); !errors.As(err, &quotaErr) || quotaErr.Quota.Scope != quotaScopeShareToken {
+		t.Errorf("want QuotaExceededError of the share token, got %v", err)
+	}
+	if err := checkQuotas(window, nil, token, "share:rate", "app", "default", now.Add(time.Minute), nil); err != nil {
+		t.Errorf("request should be allowed in the next minute, got %v", err)
+	}
+	if quota := shareTokenQuota(&storage.ShareToken{ID: "default"}); quota.RequestsPerMinute != DefaultShareTokenRequestsPerMinute {
+		t.Errorf("want the default rate %d, got %d", DefaultShareTokenRequestsPerMinute, quota.RequestsPerMinute)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// The messages of a conversation are a tree. Each message points to its parent,
+// which is the conversation id for the first round, so that the regenerated answers
+// and edited queries are kept as the siblings of the original message.
+
+// parentOf returns the parent of the message at index i,
+// the messages stored before branching was supported have no parent and follow the previous message.
+func (c *Conversation) parentOf(i int) string {
+	if parent := c.Messages[i].ParentID; parent != "" {
+		return parent
+	}
+	if i == 0 {
+		return c.ID
+	}
+	return c.Messages[i-1].ID
+}
+
+// FillParents sets the parent of the messages stored before branching was supported
+func (c *Conversation) FillParents() {
+	for i := range c.Messages {
+		c.Messages[i].ParentID = c.parentOf(i)
+	}
+}
+
+// Message returns the message with the id, nil if not found
+func (c *Conversation) Message(id string) *Message {
+	for i := range c.Messages {
+		if c.Messages[i].ID == id {
+			return &c.Messages[i]
+		}
+	}
+	return nil
+}
+
+// Children returns the messages following the parent, which is a message id or the conversation id
+func (c *Conversation) Children(parentID string) []Message {
+	var children []Message
+	for i := range c.Messages {
+		if c.parentOf(i) == parentID {
+			children = append(children, c.Messages[i])
+		}
+	}
+	return children
+}
+
+// LatestLeaf follows the latest children from the message to the end of the branch
+func (c *Conversation) LatestLeaf(id string) string {
+	for range c.Messages {
+		children := c.Children(id)
+		if len(children) == 0 {
+			break
+		}
+		id = children[len(children)-1].ID
+	}
+	return id
+}
+
+// ActiveLeaf returns the last message of the selected branch,
+// which is the conversation id if there is no message
+func (c *Conversation) ActiveLeaf() string {
+	if c.ActiveMessageID != "" && c.Message(c.ActiveMessageID) != nil {
+		return c.ActiveMessageID
+	}
+	if len(c.Messages) == 0 {
+		return c.ID
+	}
+	return c.Messages[len(c.Messages)-1].ID
+}
+
+// PathTo returns the messages from the first round to the message
+func (c *Conversation) PathTo(id string) []Message {
+	index := make(map[string]int, len(c.Messages))
+	for i := range c.Messages {
+		index[c.Messages[i].ID] = i
+	}
+	var path []Message
+	for len(path) < len(c.Messages) {
+		i, ok := index[id]
+		if !ok {
+			break
+		}
+		m := c.Messages[i]
+		m.ParentID = c.parentOf(i)
+		path = append(path, m)
+		id = m.ParentID
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// ActivePath returns the messages of the selected branch, with the siblings of each message
+func (c *Conversation) ActivePath() []Message {
+	path := c.PathTo(c.ActiveLeaf())
+	for i := range path {
+		siblings := c.Children(path[i].ParentID)
+		path[i].SiblingIDs = make([]string, len(siblings))
+		for j := range siblings {
+			path[i].SiblingIDs[j] = siblings[j].ID
+		}
+	}
+	return path
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func messageIDs(messages []Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestConversationBranch(t *testing.T) {
+	// m1 and m2 are stored before branching is supported, m3 regenerates m2 and m4 follows m3
+	c := Conversation{
+		ID: "c",
+		Messages: []Message{
+			{ID: "m1"},
+			{ID: "m2"},
+			{ID: "m3", ParentID: "m1"},
+			{ID: "m4", ParentID: "m3"},
+		},
+	}
+	if got := c.ActiveLeaf(); got != "m4" {
+		t.Errorf("unexpected active leaf %s", got)
+	}
+	path := c.ActivePath()
+	if got, want := messageIDs(path), []string{"m1", "m3", "m4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected active path %v, want %v", got, want)
+	}
+	if got, want := path[1].SiblingIDs, []string{"m2", "m3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected siblings %v, want %v", got, want)
+	}
+	if got := path[0].ParentID; got != "c" {
+		t.Errorf("unexpected parent of the first message %s", got)
+	}
+
+	// select the original answer
+	c.ActiveMessageID = c.LatestLeaf("m2")
+	if got, want := messageIDs(c.ActivePath()), []string{"m1", "m2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected active path %v, want %v", got, want)
+	}
+	// select the first round, the latest branch is followed
+	if got := c.LatestLeaf("m1"); got != "m4" {
+		t.Errorf("unexpected latest leaf %s", got)
+	}
+}
@@ -16,6 +16,12 @@ limitations under the License.
 
 package storage
 
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
 type Search struct {
 	ConversationID *string
 	MessageID      *string
@@ -95,3 +101,98 @@ func WithDebug(debug bool) SearchOption {
 		o.Debug = &debug
 	}
 }
+
+const (
+	HighlightStart = "<em>"
+	HighlightStop  = "</em>"
+
+	// the runes kept before the first match and in total of a snippet
+	snippetContext = 20
+	snippetLength  = 120
+)
+
+// SearchTerms splits the keyword into the words to search
+func SearchTerms(keyword string) []string {
+	return strings.Fields(keyword)
+}
+
+// lowerRunes lowers the runes one by one, so the indexes are the same as the original text
+func lowerRunes(text string) []rune {
+	runes := []rune(text)
+	for i := range runes {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return runes
+}
+
+// indexRunes returns the indexes where the term is in the text
+func indexRunes(text, term []rune) []int {
+	var res []int
+	if len(term) == 0 {
+		return res
+	}
+	for i := 0; i+len(term) <= len(text); i++ {
+		if string(text[i:i+len(term)]) == string(term) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// MatchTerms reports whether all terms are in the text, ignoring case
+func MatchTerms(text string, terms []string) bool {
+	lower := lowerRunes(text)
+	for _, term := range terms {
+		if len(indexRunes(lower, lowerRunes(term))) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Snippet returns the part of the text around the first matched term, with the matched terms highlighted.
+// The beginning of the text is returned if no term is matched.
+// The text is html escaped, so only the highlight markers are html tags.
+func Snippet(text string, terms []string) string {
+	runes := []rune(text)
+	lower := lowerRunes(text)
+	matched := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		t := lowerRunes(term)
+		for _, i := range indexRunes(lower, t) {
+			for j := i; j < i+len(t); j++ {
+				matched[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	start := 0
+	if first > snippetContext {
+		start = first - snippetContext
+	}
+	end := start + snippetLength
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	for i := start; i < end; i++ {
+		if matched[i] && (i == start || !matched[i-1]) {
+			b.WriteString(HighlightStart)
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		if matched[i] && (i == end-1 || !matched[i+1]) {
+			b.WriteString(HighlightStop)
+		}
+	}
+	if end < len(runes) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnippet(t *testing.T) {
+	terms := SearchTerms("旷工 Day")
+	if got, want := Snippet("旷工最小计算单位为0.5 day", terms), "<em>旷工</em>最小计算单位为0.5 <em>day</em>"; got != want {
+		t.Errorf("unexpected snippet %q, want %q", got, want)
+	}
+	long := strings.Repeat("a", 100) + "旷工" + strings.Repeat("b", 200)
+	got := Snippet(long, terms)
+	if !strings.HasPrefix(got, "..."+strings.Repeat("a", snippetContext)+"<em>旷工</em>") || !strings.HasSuffix(got, "b...") {
+		t.Errorf("unexpected snippet %q", got)
+	}
+	if got, want := Snippet(`<script>alert("旷工")</script>`, terms), `&lt;script&gt;alert(&#34;<em>旷工</em>&#34;)&lt;/script&gt;`; got != want {
+		t.Errorf("unexpected escaped snippet %q, want %q", got, want)
+	}
+	if !MatchTerms("旷工最小计算单位为0.5 DAY", terms) || MatchTerms("旷工最小计算单位", terms) {
+		t.Errorf("unexpected match result")
+	}
+}
+
+func TestMemoryStorageSearchMessages(t *testing.T) {
+	m := NewMemoryStorage()
+	now := time.Now()
+	_ = m.UpdateConversation(&Conversation{ID: "c1", AppName: "app", AppNamespace: "ns", User: "alice", UpdatedAt: now, Messages: []Message{
+		{ID: "m1", Action: "CHAT", Query: "旷工最小计算单位为多少天？", Answer: "0.5天"},
+		{ID: "m2", Action: "UPLOAD", Query: "UPLOAD", Answer: "旷工.pdf"},
+	}})
+	_ = m.UpdateConversation(&Conversation{ID: "c2", AppName: "app", AppNamespace: "ns", User: "alice", UpdatedAt: now.Add(-48 * time.Hour), Messages: []Message{
+		{ID: "m3", Action: "CHAT", Query: "年假", Answer: "旷工不影响年假"},
+	}})
+	_ = m.UpdateConversation(&Conversation{ID: "c3", AppName: "app", AppNamespace: "ns", User: "bob", UpdatedAt: now, Messages: []Message{
+		{ID: "m4", Action: "CHAT", Query: "旷工", Answer: "..."},
+	}})
+
+	hits, total, err := m.SearchMessages(MessageSearch{Keyword: "旷工"}, WithUser("alice"))
+	if err != nil || total != 2 || len(hits) != 2 || hits[0].MessageID != "m1" {
+		t.Fatalf("unexpected hits %v, total %d, err %v", hits, total, err)
+	}
+	if hits[0].Query != "<em>旷工</em>最小计算单位为多少天？" {
+		t.Errorf("unexpected snippet %q", hits[0].Query)
+	}
+	hits, total, _ = m.SearchMessages(MessageSearch{Keyword: "旷工", Since: now.Add(-time.Hour)}, WithUser("alice"))
+	if total != 1 || hits[0].MessageID != "m1" {
+		t.Errorf("unexpected hits %v in time range", hits)
+	}
+	hits, total, _ = m.SearchMessages(MessageSearch{Keyword: "旷工", Offset: 1, Limit: 1}, WithUser("alice"))
+	if total != 2 || len(hits) != 1 || hits[0].MessageID != "m3" {
+		t.Errorf("unexpected hits %v in page 2", hits)
+	}
+}
@@ -28,6 +28,8 @@ import (
 
 var (
 	ErrConversationNotFound = errors.New("conversation is not found")
+	ErrDocumentNotFound     = errors.New("document is not found")
+	ErrShareTokenNotFound   = errors.New("share token is not found")
 )
 
 // Conversation represent a conversation in storage
@@ -43,6 +45,16 @@ type Conversation struct {
 	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;type:time;comment:the time the conversation deleted at" json:"-"`
 	// icon only valid in conversation list api
 	Icon string `gorm:"-" json:"icon"`
+	// ActiveMessageID is the last message of the selected branch
+	ActiveMessageID string `gorm:"column:active_message_id;type:string;comment:the last message of the selected branch" json:"active_message_id,omitempty" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
+	// Summary is the running summary of the turns until SummaryMessageID, which are not sent to the llm any more
+	Summary string `gorm:"column:summary;type:string;comment:the running summary of the earlier turns" json:"summary,omitempty"`
+	// SummaryMessageID is the last message merged into the summary
+	SummaryMessageID string `gorm:"column:summary_message_id;type:string;comment:the last message merged into the summary" json:"-"`
+	// Facts are the long-term memories about the user recalled when the conversation started
+	Facts []string `gorm:"column:facts;type:json;serializer:json;comment:the facts recalled about the user" json:"-"`
+	// ShareTokenID is the share token the conversation is started with, which is empty for the signed-in users
+	ShareTokenID string `gorm:"column:share_token_id;type:string;index;comment:the share token the conversation started with" json:"share_token_id,omitempty" example:"9b2c3a4e-1f7d-4c55-8a1e-2f4d5c6b7a80"`
 }
 
 // Message represent a message in storage
@@ -50,6 +62,11 @@ type Message struct {
 	ID             string `gorm:"column:id;primaryKey;type:uuid;comment:message id" json:"id" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
 	ConversationID string `gorm:"column:conversation_id;type:uuid;comment:conversation id" json:"-"`
 	Latency        int64  `gorm:"column:latency;type:int;comment:request latency, in ms" json:"latency" example:"1000"`
+	// ParentID is the message this one follows, which is the conversation id for the first round
+	ParentID string `gorm:"column:parent_id;type:string;comment:parent message id" json:"parent_id,omitempty" example:"5a41f3ca-763b-41ec-91c3-4bbbb00736d0"`
+	// SiblingIDs are the messages with the same parent including this one,
+	// which are the regenerated or edited versions. Only valid in messages history api
+	SiblingIDs []string `gorm:"-" json:"sibling_ids,omitempty"`
 
 	// Action indicates what is this message for
 	// Chat(by default),UPLOAD,etc...
@@ -58,13 +75,25 @@ type Message struct {
 	// For Action Chat
 	Query string `gorm:"column:query;type:string;comment:user input" json:"query" example:"旷工最小计算单位为多少天？"`
 	// Files that shall be used in this Chat
-	Files      []string   `gorm:"-" json:"files"`
-	RawFiles   string     `gorm:"column:files;type:string;comment:input files" json:"-"`
+	Files    []string `gorm:"-" json:"files"`
+	RawFiles string   `gorm:"column:files;type:string;comment:input files" json:"-"`
+	// Images that are sent to the llm in this Chat, which are the object names in the system datasource
+	Images     []string   `gorm:"column:images;type:json;serializer:json;comment:input images" json:"images,omitempty"`
 	Answer     string     `gorm:"column:answer;type:string;comment:ai response" json:"answer" example:"旷工最小计算单位为0.5天。"`
 	References References `gorm:"column:references;type:json;comment:references" json:"references,omitempty"`
+	// Token usage of all llm calls to answer this message
+	PromptTokens     int `gorm:"column:prompt_tokens;type:int;comment:prompt tokens used by llm calls" json:"prompt_tokens" example:"100"`
+	CompletionTokens int `gorm:"column:completion_tokens;type:int;comment:completion tokens generated by llm calls" json:"completion_tokens" example:"20"`
+	TotalTokens      int `gorm:"column:total_tokens;type:int;comment:total tokens of llm calls" json:"total_tokens" example:"120"`
 
 	// For Action Upload
 	Documents []Document `gorm:"foreignKey:MessageID" json:"documents"`
+
+	// CreatedAt keeps the messages of a conversation in the order they are sent
+	CreatedAt time.Time `gorm:"column:created_at;type:time;autoCreateTime;index;comment:the time the message created at" json:"-"`
+
+	// Feedback of current user on the answer, only valid in messages history api
+	Feedback *Feedback `gorm:"-" json:"feedback,omitempty"`
 }
 
 func (m *Message) AfterFind(tx *gorm.DB) error {
@@ -83,6 +112,137 @@ type Document struct {
 
 type References []retriever.Reference
 
+// UsageRecord records the token usage of llm calls for a user action.
+// It is kept even if the conversation is deleted, so the costs can be charged back.
+type UsageRecord struct {
+	ID               string    `gorm:"column:id;primaryKey;type:uuid;comment:usage record id" json:"id"`
+	User             string    `gorm:"column:user;type:string;comment:the chat user" json:"user"`
+	AppName          string    `gorm:"column:app_name;type:string;comment:app name" json:"app_name"`
+	AppNamespace     string    `gorm:"column:app_namespace;type:string;comment:app namespace" json:"app_namespace"`
+	ConversationID   string    `gorm:"column:conversation_id;type:string;comment:conversation id" json:"conversation_id"`
+	MessageID        string    `gorm:"column:message_id;type:string;comment:message id" json:"message_id"`
+	Action           string    `gorm:"column:action;type:string;comment:user action" json:"action"`
+	Requests         int       `gorm:"column:requests;type:int;comment:count of llm calls" json:"requests"`
+	PromptTokens     int       `gorm:"column:prompt_tokens;type:int;comment:prompt tokens" json:"prompt_tokens"`
+	CompletionTokens int       `gorm:"column:completion_tokens;type:int;comment:completion tokens" json:"completion_tokens"`
+	TotalTokens      int       `gorm:"column:total_tokens;type:int;comment:total tokens" json:"total_tokens"`
+	CreatedAt        time.Time `gorm:"column:created_at;type:time;autoCreateTime;index;comment:the time the usage recorded at" json:"created_at"`
+}
+
+// ShareToken allows the anonymous users to chat with one application, until it expires or is revoked.
+// Only the sha256 hash of the token is stored, the token itself is returned once when created.
+type ShareToken struct {
+	ID           string `gorm:"column:id;primaryKey;type:uuid;comment:share token id" json:"id" example:"9b2c3a4e-1f7d-4c55-8a1e-2f4d5c6b7a80"`
+	Name         string `gorm:"column:name;type:string;comment:share token name" json:"name" example:"intranet hr bot"`
+	AppName      string `gorm:"column:app_name;type:string;index;comment:app name" json:"app_name" example:"chat-with-llm"`
+	AppNamespace string `gorm:"column:app_namespace;type:string;index;comment:app namespace" json:"app_namespace" example:"arcadia"`
+	TokenHash    string `gorm:"column:token_hash;type:string;uniqueIndex;comment:sha256 of the token" json:"-"`
+	Creator      string `gorm:"column:creator;type:string;comment:the user who creates the token" json:"creator" example:"admin"`
+	// RequestsPerMinute limits the chat requests of all anonymous users with this token
+	RequestsPerMinute int        `gorm:"column:requests_per_minute;type:int;comment:rate limit of chat requests" json:"requests_per_minute" example:"10"`
+	ExpiresAt         *time.Time `gorm:"column:expires_at;type:time;comment:the time the token expires at" json:"expires_at,omitempty" example:"2024-12-21T10:21:06.389359092+08:00"`
+	RevokedAt         *time.Time `gorm:"column:revoked_at;type:time;comment:the time the token revoked at" json:"revoked_at,omitempty" example:"2024-06-21T10:21:06.389359092+08:00"`
+	CreatedAt         time.Time  `gorm:"column:created_at;type:time;autoCreateTime;comment:the time the token created at" json:"created_at" example:"2023-12-21T10:21:06.389359092+08:00"`
+}
+
+// Valid returns whether the token can be used to chat at the time
+func (t ShareToken) Valid(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// FeedbackRating is the rating of an answer
+type FeedbackRating string
+
+const (
+	FeedbackRatingLike    FeedbackRating = "like"
+	FeedbackRatingDislike FeedbackRating = "dislike"
+)
+
+// FeedbackReason is the category of the reason why the answer is rated
+type FeedbackReason string
+
+const (
+	FeedbackReasonInaccurate FeedbackReason = "inaccurate"
+	FeedbackReasonIrrelevant FeedbackReason = "irrelevant"
+	FeedbackReasonIncomplete FeedbackReason = "incomplete"
+	FeedbackReasonHarmful    FeedbackReason = "harmful"
+	FeedbackReasonHelpful    FeedbackReason = "helpful"
+	FeedbackReasonOther      FeedbackReason = "other"
+)
+
+// Feedback is a user's rating on the answer of a message.
+// The query and answer are copied from the message, so the feedback can be exported even if the conversation is deleted.
+type Feedback struct {
+	MessageID       string         `gorm:"column:message_id;primaryKey;type:uuid;comment:message id" json:"message_id" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
+	ConversationID  string         `gorm:"column:conversation_id;type:uuid;comment:conversation id" json:"conversation_id" example:"5a41f3ca-763b-41ec-91c3-4bbbb00736d0"`
+	AppName         string         `gorm:"column:app_name;type:string;index;comment:app name" json:"app_name" example:"chat-with-llm"`
+	AppNamespace    string         `gorm:"column:app_namespace;type:string;index;comment:app namespace" json:"app_namespace" example:"arcadia"`
+	User            string         `gorm:"column:user;type:string;comment:the user who gives the feedback" json:"-"`
+	Rating          FeedbackRating `gorm:"column:rating;type:string;comment:like or dislike" json:"rating" example:"dislike"`
+	Reason          FeedbackReason `gorm:"column:reason;type:string;comment:reason category" json:"reason,omitempty" example:"inaccurate"`
+	Comment         string         `gorm:"column:comment;type:string;comment:free text comment" json:"comment,omitempty" example:"旷工最小计算单位应为1天"`
+	CorrectedAnswer string         `gorm:"column:corrected_answer;type:string;comment:the answer corrected by user" json:"corrected_answer,omitempty" example:"旷工最小计算单位为1天。"`
+	Query           string         `gorm:"column:query;type:string;comment:user input" json:"query" example:"旷工最小计算单位为多少天？"`
+	Answer          string         `gorm:"column:answer;type:string;comment:ai response" json:"answer" example:"旷工最小计算单位为0.5天。"`
+	CreatedAt       time.Time      `gorm:"column:created_at;type:time;autoCreateTime;comment:the time the feedback created at" json:"created_at" example:"2023-12-21T10:21:06.389359092+08:00"`
+	UpdatedAt       time.Time      `gorm:"column:updated_at;type:time;autoUpdateTime;index;comment:the time the feedback updated at" json:"updated_at" example:"2023-12-21T10:21:06.389359092+08:00"`
+}
+
+// GroundTruth returns the correct answer to the query,which is empty if the answer is disliked without correction
+func (f Feedback) GroundTruth() string {
+	if f.CorrectedAnswer != "" {
+		return f.CorrectedAnswer
+	}
+	if f.Rating == FeedbackRatingLike {
+		return f.Answer
+	}
+	return ""
+}
+
+// FeedbackCount is the count of feedbacks aggregated by app, namespace, rating and reason
+type FeedbackCount struct {
+	AppName      string         `json:"app_name"`
+	AppNamespace string         `json:"app_namespace"`
+	Rating       FeedbackRating `json:"rating"`
+	Reason       FeedbackReason `json:"reason"`
+	Count        int64          `json:"count"`
+}
+
+// Usage is the token usage aggregated by user, app and namespace
+type Usage struct {
+	User             string `json:"user"`
+	AppName          string `json:"app_name"`
+	AppNamespace     string `json:"app_namespace"`
+	Requests         int64  `json:"requests"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+}
+
+// MessageSearch is the full-text search on the queries and answers of messages
+type MessageSearch struct {
+	// Keyword is the words to search, the messages containing all words are matched
+	Keyword string
+	// Since and Until limit the update time of conversations, which are not limited if zero
+	Since, Until time.Time
+	Offset       int
+	Limit        int
+}
+
+// MessageHit is a message matching the search, with the highlighted snippets of the query and answer
+type MessageHit struct {
+	ConversationID string    `json:"conversation_id" example:"5a41f3ca-763b-41ec-91c3-4bbbb00736d0"`
+	MessageID      string    `json:"message_id" example:"4f3546dd-5404-4bf8-a3bc-4fa3f9a7ba24"`
+	AppName        string    `json:"app_name" example:"chat-with-llm"`
+	AppNamespace   string    `json:"app_namespace" example:"arcadia"`
+	UpdatedAt      time.Time `json:"updated_at" example:"2023-12-22T10:21:06.389359092+08:00"`
+	Query          string    `json:"query" example:"<em>旷工</em>最小计算单位为多少天？"`
+	Answer         string    `json:"answer" example:"<em>旷工</em>最小计算单位为0.5天。"`
+}
+
 func (Conversation) TableName() string {
 	return "app_chat_conversation"
 }
@@ -95,10 +255,25 @@ func (Document) TableName() string {
 	return "app_chat_document"
 }
 
+func (UsageRecord) TableName() string {
+	return "app_chat_usage"
+}
+
+func (Feedback) TableName() string {
+	return "app_chat_feedback"
+}
+
+func (ShareToken) TableName() string {
+	return "app_chat_share_token"
+}
+
 type Storage interface {
 	ConversationStorage
 	MessageStorage
 	DocumentStorage
+	UsageStorage
+	FeedbackStorage
+	ShareTokenStorage
 }
 
 // ConversationStorage interface
@@ -121,6 +296,12 @@ type ConversationStorage interface {
 	//
 	// It accepts SearchOption(s) and returns a slice of Conversation and an error.
 	ListConversations(opts ...SearchOption) ([]Conversation, error)
+	// PurgeConversations hard deletes the conversations not updated since the time, including the soft-deleted ones,
+	// together with their messages, documents and feedbacks.
+	//
+	// The app name and app namespace in SearchOption(s) are used to filter the conversations.
+	// It returns the deleted conversations with the id, app name, app namespace and user only.
+	PurgeConversations(before time.Time, opts ...SearchOption) ([]Conversation, error)
 }
 
 type MessageStorage interface {
@@ -130,8 +311,76 @@ type MessageStorage interface {
 	FindExistingMessage(conversationID, messageID string, opts ...SearchOption) (*Message, error)
 	// CountMessages count how many messages is about this app
 	CountMessages(appName, appNamespace string) (int64, error)
+	// SearchMessages searches the queries and answers of messages, ordered by relevance and the update time of conversations.
+	//
+	// The user, app name and app namespace in SearchOption(s) are used to filter the conversations.
+	// It returns the messages in the page and the total count of matched messages.
+	SearchMessages(search MessageSearch, opts ...SearchOption) ([]MessageHit, int64, error)
+}
+
+type UsageStorage interface {
+	// AddUsage records the token usage of llm calls.
+	AddUsage(*UsageRecord) error
+	// AggregateUsage sums the token usage recorded in [since, until), grouped by user, app name and app namespace.
+	//
+	// The user, app name and app namespace in SearchOption(s) are used to filter the records.
+	AggregateUsage(since, until time.Time, opts ...SearchOption) ([]Usage, error)
 }
 
+type FeedbackStorage interface {
+	// UpdateFeedback creates or updates the feedback of a message.
+	UpdateFeedback(*Feedback) error
+	// DeleteFeedback deletes the feedback of a message.
+	//
+	// The user in SearchOption(s) is used to make sure the feedback is given by this user.
+	// **not** return error if the feedback is not found
+	DeleteFeedback(messageID string, opts ...SearchOption) error
+	// ListFeedbacks returns the feedbacks updated in [since, until), ordered by update time.
+	//
+	// The user, app name and app namespace in SearchOption(s) are used to filter the feedbacks.
+	ListFeedbacks(since, until time.Time, opts ...SearchOption) ([]Feedback, error)
+	// AggregateFeedback counts the feedbacks updated in [since, until), grouped by app name, app namespace, rating and reason.
+	//
+	// The user, app name and app namespace in SearchOption(s) are used to filter the feedbacks.
+	AggregateFeedback(since, until time.Time, opts ...SearchOption) ([]FeedbackCount, error)
+}
+
+// DocumentStorage manages the documents uploaded in conversations.
+//
+// The user, app name and app namespace in SearchOption(s) are used to make sure the conversation is accessible,
+// ErrConversationNotFound is returned if not.
 type DocumentStorage interface {
-	// TO BE DEFINED
+	// ListDocuments returns the documents uploaded in the conversation, ordered by name.
+	ListDocuments(conversationID string, opts ...SearchOption) ([]Document, error)
+	// FindExistingDocument finds a document in the conversation.
+	//
+	// It returns ErrDocumentNotFound if the document is not found.
+	FindExistingDocument(conversationID, documentID string, opts ...SearchOption) (*Document, error)
+	// DeleteDocument deletes a document from the conversation.
+	//
+	// **not** return error if the document is not found
+	DeleteDocument(conversationID, documentID string, opts ...SearchOption) error
+	// UpdateDocumentSummary updates the summary of a document in the conversation.
+	//
+	// It returns ErrDocumentNotFound if the document is not found.
+	UpdateDocumentSummary(conversationID, documentID, summary string, opts ...SearchOption) error
+}
+
+// ShareTokenStorage manages the share tokens of applications.
+type ShareTokenStorage interface {
+	// CreateShareToken saves a new share token.
+	CreateShareToken(*ShareToken) error
+	// FindShareToken finds the share token by the sha256 hash of the token, including the expired and revoked ones.
+	//
+	// It returns ErrShareTokenNotFound if the token is not found.
+	FindShareToken(tokenHash string) (*ShareToken, error)
+	// ListShareTokens returns the share tokens ordered by the create time.
+	//
+	// The app name and app namespace in SearchOption(s) are used to filter the tokens.
+	ListShareTokens(opts ...SearchOption) ([]ShareToken, error)
+	// RevokeShareToken revokes the share token at the time, a revoked token is not revoked again.
+	//
+	// The app name and app namespace in SearchOption(s) are used to make sure the token belongs to the app,
+	// ErrShareTokenNotFound is returned if not.
+	RevokeShareToken(id string, revokedAt time.Time, opts ...SearchOption) error
 }
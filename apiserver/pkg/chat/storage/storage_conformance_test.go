@@ -0,0 +1,405 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// testPostgreSQLDSNEnv is the env of the database to run the conformance tests against PostgreSQL, which are skipped if not set
+const testPostgreSQLDSNEnv = "ARCADIA_TEST_POSTGRESQL_DSN"
+
+func TestMemoryStorageConformance(t *testing.T) {
+	testStorage(t, NewMemoryStorage())
+}
+
+func TestSQLiteStorageConformance(t *testing.T) {
+	s, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "chat", "chat.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
+
+func TestPostgreSQLStorageConformance(t *testing.T) {
+	dsn := os.Getenv(testPostgreSQLDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testPostgreSQLDSNEnv)
+	}
+	conn, err := pgx.Connect(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close(context.Background())
+	s, err := NewPostgreSQLStorage(conn, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
+
+func newID() string {
+	return string(uuid.NewUUID())
+}
+
+// testStorage is the conformance test suite which all the implementations of Storage should pass.
+// The ids and namespace are generated, so it can run against a database with existing data.
+func testStorage(t *testing.T, s Storage) {
+	now := time.Now()
+	namespace := "ns-" + newID()
+	aliceConversation, bobConversation := newID(), newID()
+	upload, chat, bobChat := newID(), newID(), newID()
+	docA, docB := newID(), newID()
+	conversations := []Conversation{
+		{
+			ID: aliceConversation, AppName: "app", AppNamespace: namespace, User: "alice", StartedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-time.Hour),
+			Messages: []Message{
+				{
+					ID: upload, ConversationID: aliceConversation, ParentID: aliceConversation, Action: "UPLOAD", Query: "UPLOAD", Answer: "DONE",
+					Documents: []Document{
+						{ID: docB, Name: "b.pdf", Object: "objects/b.pdf", ConversationID: aliceConversation, MessageID: upload},
+						{ID: docA, Name: "a.pdf", Object: "objects/a.pdf", ConversationID: aliceConversation, MessageID: upload},
+					},
+				},
+				{ID: chat, ConversationID: aliceConversation, ParentID: upload, Action: "CHAT", Query: "How many days of Annual Leave?", Answer: "Five days."},
+			},
+			ActiveMessageID: chat,
+		},
+		{
+			ID: bobConversation, AppName: "app", AppNamespace: namespace, User: "bob", StartedAt: now.Add(-time.Minute), UpdatedAt: now,
+			Messages: []Message{
+				{ID: bobChat, ConversationID: bobConversation, ParentID: bobConversation, Action: "CHAT", Query: "annual leave of bob", Answer: "Ten days, 年假有十天。"},
+			},
+		},
+	}
+	for i := range conversations {
+		if err := s.UpdateConversation(&conversations[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("conversations", func(t *testing.T) {
+		c, err := s.FindExistingConversation(aliceConversation, WithUser("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.AppName != "app" || c.ActiveMessageID != chat || len(c.Messages) != 2 {
+			t.Errorf("unexpected conversation %+v", c)
+		}
+		for _, m := range c.Messages {
+			if m.ID == upload && len(m.Documents) != 2 {
+				t.Errorf("want 2 documents in the upload message, got %d", len(m.Documents))
+			}
+		}
+		if _, err := s.FindExistingConversation(aliceConversation, WithUser("bob")); err == nil {
+			t.Errorf("conversation of alice should not be found by bob")
+		}
+		list, err := s.ListConversations(WithAppName("app"), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 2 || list[0].ID != bobConversation || list[1].ID != aliceConversation {
+			t.Errorf("want the conversations ordered by update time, got %d conversations", len(list))
+		}
+		list, err = s.ListConversations(WithAppNamespace(namespace), WithUser("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || list[0].ID != aliceConversation {
+			t.Errorf("want the conversation of alice only, got %d conversations", len(list))
+		}
+	})
+
+	t.Run("messages", func(t *testing.T) {
+		m, err := s.FindExistingMessage(aliceConversation, chat, WithUser("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m == nil || m.Answer != "Five days." || m.ParentID != upload {
+			t.Errorf("unexpected message %+v", m)
+		}
+		count, err := s.CountMessages("app", namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("want 3 messages, got %d", count)
+		}
+	})
+
+	t.Run("documents", func(t *testing.T) {
+		documents, err := s.ListDocuments(aliceConversation, WithUser("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(documents) != 2 || documents[0].Name != "a.pdf" || documents[1].Name != "b.pdf" {
+			t.Errorf("want the documents ordered by name, got %+v", documents)
+		}
+		if _, err := s.ListDocuments(aliceConversation, WithUser("bob")); !errors.Is(err, ErrConversationNotFound) {
+			t.Errorf("want ErrConversationNotFound for other user, got %v", err)
+		}
+		if _, err := s.FindExistingDocument(aliceConversation, newID(), WithUser("alice")); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("want ErrDocumentNotFound, got %v", err)
+		}
+		if err := s.UpdateDocumentSummary(aliceConversation, docB, "the summary of b", WithUser("alice")); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateDocumentSummary(aliceConversation, newID(), "summary", WithUser("alice")); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("want ErrDocumentNotFound, got %v", err)
+		}
+		if err := s.UpdateDocumentSummary(aliceConversation, docB, "summary", WithUser("bob")); !errors.Is(err, ErrConversationNotFound) {
+			t.Errorf("want ErrConversationNotFound for other user, got %v", err)
+		}
+		document, err := s.FindExistingDocument(aliceConversation, docB, WithUser("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if document.Name != "b.pdf" || document.Object != "objects/b.pdf" || document.MessageID != upload || document.Summary != "the summary of b" {
+			t.Errorf("unexpected document %+v", document)
+		}
+		if err := s.DeleteDocument(aliceConversation, docA, WithUser("alice")); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DeleteDocument(aliceConversation, docA, WithUser("alice")); err != nil {
+			t.Errorf("deleting a deleted document should not fail, got %v", err)
+		}
+		documents, err = s.ListDocuments(aliceConversation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(documents) != 1 || documents[0].ID != docB {
+			t.Errorf("want document b only, got %+v", documents)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		hits, total, err := s.SearchMessages(MessageSearch{Keyword: "ANNUAL leave", Limit: 1}, WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 2 || len(hits) != 1 {
+			t.Errorf("want 1 of 2 hits, got %d of %d", len(hits), total)
+		}
+		hits, total, err = s.SearchMessages(MessageSearch{Keyword: "annual", Limit: 10}, WithAppNamespace(namespace), WithUser("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(hits) != 1 || hits[0].MessageID != chat || hits[0].ConversationID != aliceConversation || !strings.Contains(hits[0].Query, HighlightStart+"Annual"+HighlightStop) {
+			t.Errorf("unexpected hits %+v", hits)
+		}
+		_, total, err = s.SearchMessages(MessageSearch{Keyword: "annual", Until: now.Add(-30 * time.Minute)}, WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 {
+			t.Errorf("want 1 hit updated before the time, got %d", total)
+		}
+		// a chinese word in a sentence
+		hits, total, err = s.SearchMessages(MessageSearch{Keyword: "年假", Limit: 10}, WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(hits) != 1 || hits[0].MessageID != bobChat {
+			t.Errorf("unexpected hits %+v", hits)
+		}
+	})
+
+	t.Run("feedbacks", func(t *testing.T) {
+		feedback := Feedback{MessageID: chat, ConversationID: aliceConversation, AppName: "app", AppNamespace: namespace, User: "alice", Rating: FeedbackRatingLike, Query: "q", Answer: "a"}
+		if err := s.UpdateFeedback(&feedback); err != nil {
+			t.Fatal(err)
+		}
+		feedback.Rating, feedback.Reason = FeedbackRatingDislike, FeedbackReasonInaccurate
+		if err := s.UpdateFeedback(&feedback); err != nil {
+			t.Fatal(err)
+		}
+		feedbacks, err := s.ListFeedbacks(now.Add(-time.Hour), time.Now().Add(time.Hour), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(feedbacks) != 1 || feedbacks[0].Rating != FeedbackRatingDislike || feedbacks[0].Reason != FeedbackReasonInaccurate {
+			t.Errorf("want the updated feedback, got %+v", feedbacks)
+		}
+		if feedbacks, _ := s.ListFeedbacks(now.Add(-time.Hour), time.Now().Add(time.Hour), WithAppName("other"), WithAppNamespace(namespace)); len(feedbacks) != 0 {
+			t.Errorf("want no feedback of other app, got %+v", feedbacks)
+		}
+		if feedbacks, _ := s.ListFeedbacks(now.Add(-time.Hour), now.Add(-time.Minute), WithAppNamespace(namespace)); len(feedbacks) != 0 {
+			t.Errorf("want no feedback updated before the time range, got %+v", feedbacks)
+		}
+		counts, err := s.AggregateFeedback(now.Add(-time.Hour), time.Now().Add(time.Hour), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(counts) != 1 || counts[0].Count != 1 || counts[0].Rating != FeedbackRatingDislike {
+			t.Errorf("unexpected feedback counts %+v", counts)
+		}
+		if err := s.DeleteFeedback(chat, WithUser("bob")); err != nil {
+			t.Fatal(err)
+		}
+		if c, _ := s.FindExistingConversation(aliceConversation); c == nil || findMessage(c.Messages, chat).Feedback == nil {
+			t.Errorf("feedback should not be deleted by other user")
+		}
+		if err := s.DeleteFeedback(chat, WithUser("alice")); err != nil {
+			t.Fatal(err)
+		}
+		if feedbacks, _ := s.ListFeedbacks(now.Add(-time.Hour), time.Now().Add(time.Hour), WithAppNamespace(namespace)); len(feedbacks) != 0 {
+			t.Errorf("feedback should be deleted, got %+v", feedbacks)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		for _, tokens := range []int{10, 20} {
+			record := UsageRecord{ID: newID(), User: "alice", AppName: "app", AppNamespace: namespace, ConversationID: aliceConversation, MessageID: chat, Action: "CHAT", Requests: 1, PromptTokens: tokens, CompletionTokens: 1, TotalTokens: tokens + 1}
+			if err := s.AddUsage(&record); err != nil {
+				t.Fatal(err)
+			}
+		}
+		usages, err := s.AggregateUsage(now.Add(-time.Hour), time.Now().Add(time.Hour), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(usages) != 1 || usages[0].User != "alice" || usages[0].Requests != 2 || usages[0].PromptTokens != 30 || usages[0].TotalTokens != 32 {
+			t.Errorf("unexpected usages %+v", usages)
+		}
+	})
+
+	t.Run("share tokens", func(t *testing.T) {
+		expiresAt := now.Add(time.Hour).Truncate(time.Second)
+		tokens := []ShareToken{
+			{ID: newID(), Name: "intranet", AppName: "app", AppNamespace: namespace, TokenHash: newID(), RequestsPerMinute: 10, ExpiresAt: &expiresAt},
+			{ID: newID(), Name: "other app", AppName: "other", AppNamespace: namespace, TokenHash: newID()},
+		}
+		for i := range tokens {
+			if err := s.CreateShareToken(&tokens[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		token, err := s.FindShareToken(tokens[0].TokenHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.ID != tokens[0].ID || token.RequestsPerMinute != 10 || token.ExpiresAt == nil || !token.ExpiresAt.Equal(expiresAt) || !token.Valid(now) {
+			t.Errorf("unexpected share token %+v", token)
+		}
+		if _, err := s.FindShareToken(newID()); !errors.Is(err, ErrShareTokenNotFound) {
+			t.Errorf("want ErrShareTokenNotFound, got %v", err)
+		}
+		list, err := s.ListShareTokens(WithAppName("app"), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || list[0].ID != tokens[0].ID {
+			t.Errorf("want the share token of app only, got %+v", list)
+		}
+		if err := s.RevokeShareToken(tokens[0].ID, now, WithAppName("other"), WithAppNamespace(namespace)); !errors.Is(err, ErrShareTokenNotFound) {
+			t.Errorf("share token should not be revoked by other app, got %v", err)
+		}
+		if err := s.RevokeShareToken(tokens[0].ID, now, WithAppName("app"), WithAppNamespace(namespace)); err != nil {
+			t.Fatal(err)
+		}
+		token, err = s.FindShareToken(tokens[0].TokenHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.RevokedAt == nil || token.Valid(now) {
+			t.Errorf("share token should be revoked, got %+v", token)
+		}
+	})
+
+	t.Run("purge and delete", func(t *testing.T) {
+		purged, err := s.PurgeConversations(now.Add(-30*time.Minute), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(purged) != 1 || purged[0].ID != aliceConversation || purged[0].AppName != "app" || purged[0].AppNamespace != namespace || purged[0].User != "alice" {
+			t.Errorf("want the conversation of alice purged, got %+v", purged)
+		}
+		if _, err := s.ListDocuments(aliceConversation); !errors.Is(err, ErrConversationNotFound) {
+			t.Errorf("want ErrConversationNotFound after purged, got %v", err)
+		}
+		if err := s.Delete(WithConversationID(bobConversation), WithUser("alice")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.FindExistingConversation(bobConversation); err != nil {
+			t.Errorf("conversation should not be deleted by other user, got %v", err)
+		}
+		if err := s.Delete(WithConversationID(bobConversation), WithUser("bob")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.FindExistingConversation(bobConversation); err == nil {
+			t.Errorf("conversation should be deleted")
+		}
+	})
+	t.Run("message order", func(t *testing.T) {
+		conversation := Conversation{ID: newID(), AppName: "order", AppNamespace: namespace, User: "alice", StartedAt: now, UpdatedAt: now}
+		want := make([]string, 0)
+		for i := 0; i < 5; i++ {
+			id := newID()
+			want = append(want, id)
+			conversation.Messages = append(conversation.Messages, Message{ID: id, ConversationID: conversation.ID, Action: "CHAT", Query: "q", Answer: "a"})
+		}
+		if err := s.UpdateConversation(&conversation); err != nil {
+			t.Fatal(err)
+		}
+		c, err := s.FindExistingConversation(conversation.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the answer of the first message is updated, and more messages are sent
+		c.Messages[0].Answer = "a, updated"
+		for i := 0; i < 3; i++ {
+			id := newID()
+			want = append(want, id)
+			c.Messages = append(c.Messages, Message{ID: id, ConversationID: conversation.ID, Action: "CHAT", Query: "q", Answer: "a"})
+			if err := s.UpdateConversation(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+		c, err = s.FindExistingConversation(conversation.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := messageIDs(c.Messages); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("want the messages in the order they are sent %v, got %v", want, got)
+		}
+		list, err := s.ListConversations(WithAppName("order"), WithAppNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || strings.Join(messageIDs(list[0].Messages), ",") != strings.Join(want, ",") {
+			t.Errorf("want the listed messages in the order they are sent, got %+v", list)
+		}
+	})
+}
+
+func findMessage(messages []Message, id string) Message {
+	for _, m := range messages {
+		if m.ID == id {
+			return m
+		}
+	}
+	return Message{}
+}
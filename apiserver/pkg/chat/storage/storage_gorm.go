@@ -0,0 +1,657 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/retriever"
+)
+
+func (r *References) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		// sqlite returns json as text
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal JSONB value:%#v", value)
+	}
+
+	result := make([]retriever.Reference, 0)
+	err := json.Unmarshal(bytes, &result)
+	if err != nil {
+		return err
+	}
+	*r = result
+	return nil
+}
+
+func (r References) Value() (driver.Value, error) {
+	if r == nil || len([]retriever.Reference(r)) == 0 {
+		return nil, nil
+	}
+	// return nil, nil
+	return json.Marshal(r)
+}
+
+// gormStorage is the storage shared by the relational databases, the dialect specific parts are implemented by the embedding storage
+type gormStorage struct {
+	db *gorm.DB
+}
+
+// likeEscaper escapes the wildcards in the pattern of LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// termsLikeCondition matches the messages containing all the terms in the query or answer
+func termsLikeCondition(terms []string) (string, []any) {
+	conditions := make([]string, 0, len(terms))
+	vars := make([]any, 0, 2*len(terms))
+	for _, term := range terms {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(term)) + "%"
+		conditions = append(conditions, `(lower(m.query) LIKE ? ESCAPE '\' OR lower(m.answer) LIKE ? ESCAPE '\')`)
+		vars = append(vars, pattern, pattern)
+	}
+	return strings.Join(conditions, " AND "), vars
+}
+
+func newGormStorage(dialector gorm.Dialector) (*gormStorage, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&Conversation{}, &Message{}, &Document{}, &UsageRecord{}, &Feedback{}, &ShareToken{}); err != nil {
+		return nil, err
+	}
+	if err := backfillMessageCreatedAt(db); err != nil {
+		return nil, err
+	}
+	customLogger := logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold:             100 * time.Millisecond,
+		LogLevel:                  logger.Info,
+		IgnoreRecordNotFoundError: false,
+		Colorful:                  false,
+	})
+	db.Logger = customLogger
+	return &gormStorage{db: db}, nil
+}
+
+// backfillMessageCreatedAt sets the creation time of the messages saved before the column is added.
+// They are stamped from the start of their conversation in the order they are stored, which is how they are listed before.
+func backfillMessageCreatedAt(db *gorm.DB) error {
+	messages := make([]Message, 0)
+	if err := db.Select("id", "conversation_id").Where("created_at IS NULL").Find(&messages).Error; err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	conversationIDs := make([]string, 0)
+	byConversation := make(map[string][]string)
+	for _, m := range messages {
+		if _, ok := byConversation[m.ConversationID]; !ok {
+			conversationIDs = append(conversationIDs, m.ConversationID)
+		}
+		byConversation[m.ConversationID] = append(byConversation[m.ConversationID], m.ID)
+	}
+	conversations := make([]Conversation, 0)
+	if err := db.Unscoped().Select("id", "started_at").Where("id IN ?", conversationIDs).Find(&conversations).Error; err != nil {
+		return err
+	}
+	startedAt := make(map[string]time.Time, len(conversations))
+	for _, c := range conversations {
+		startedAt[c.ID] = c.StartedAt
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, conversationID := range conversationIDs {
+			for i, id := range byConversation[conversationID] {
+				createdAt := startedAt[conversationID].Add(time.Duration(i) * time.Microsecond)
+				if err := tx.Model(&Message{}).Where("id = ?", id).Update("created_at", createdAt).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// orderMessages preloads the messages of conversations in the order they are sent
+func orderMessages(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at")
+}
+
+func (g *gormStorage) CountMessages(appName, appNamespace string) (int64, error) {
+	conversationQuery := Conversation{AppNamespace: appNamespace, AppName: appName}
+	conversation := make([]Conversation, 0)
+	tx := g.db.Select("id").Find(&conversation, conversationQuery)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	conversationIDs := make([]string, len(conversation))
+	for i := range conversation {
+		conversationIDs[i] = conversation[i].ID
+	}
+	var count int64
+	tx = g.db.Model(&Message{}).Where("conversation_id IN ?", conversationIDs).Count(&count)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return count, nil
+}
+
+func (g *gormStorage) ListConversations(opts ...SearchOption) ([]Conversation, error) {
+	searchOpt := applyOptions(nil, opts...)
+	conversationQuery := Conversation{}
+	if searchOpt.ConversationID != nil {
+		conversationQuery.ID = *searchOpt.ConversationID
+	}
+	if searchOpt.Debug != nil {
+		conversationQuery.Debug = *searchOpt.Debug
+	}
+	if searchOpt.User != nil {
+		conversationQuery.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		conversationQuery.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		conversationQuery.AppNamespace = *searchOpt.AppNamespace
+	}
+	conversationQuery.Debug = false
+	conversationQuery.DeletedAt.Valid = false
+	res := make([]Conversation, 0)
+	tx := g.db.Preload("Messages", orderMessages).Preload("Messages.Documents").Order("updated_at DESC").Find(&res, conversationQuery)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return res, nil
+}
+
+func (g *gormStorage) UpdateConversation(conversation *Conversation) error {
+	// the new messages saved in the same statement would get the same creation time, keep their order
+	now := time.Now()
+	for i := range conversation.Messages {
+		if conversation.Messages[i].CreatedAt.IsZero() {
+			conversation.Messages[i].CreatedAt = now.Add(time.Duration(i) * time.Microsecond)
+		}
+	}
+	tx := g.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(conversation)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+func (g *gormStorage) FindExistingConversation(conversationID string, opts ...SearchOption) (*Conversation, error) {
+	searchOpt := applyOptions(&conversationID, opts...)
+	conversationQuery := Conversation{ID: conversationID}
+	if searchOpt.Debug != nil {
+		conversationQuery.Debug = *searchOpt.Debug
+	}
+	if searchOpt.User != nil {
+		conversationQuery.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		conversationQuery.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		conversationQuery.AppNamespace = *searchOpt.AppNamespace
+	}
+	conversationQuery.Debug = false
+	conversationQuery.DeletedAt.Valid = false
+	res := &Conversation{}
+	tx := g.db.Preload("Messages", orderMessages).Preload("Messages.Documents").First(res, conversationQuery)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	if err := g.fillFeedbacks(res.Messages); err != nil {
+		return nil, err
+	}
+
+	for index, message := range res.Messages {
+		// search document info based on object which is also a primary key in Document
+		if message.Action != "UPLOAD" && message.Files != nil && len(message.Files) > 0 {
+			documents, err := g.findMessageRelevantDocuments(message)
+			if err == nil {
+				message.Documents = documents
+				res.Messages[index] = message
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// fillFeedbacks fills the feedback of messages
+func (g *gormStorage) fillFeedbacks(messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	messageIDs := make([]string, len(messages))
+	for i := range messages {
+		messageIDs[i] = messages[i].ID
+	}
+	feedbacks := make([]Feedback, 0)
+	if err := g.db.Where("message_id IN ?", messageIDs).Find(&feedbacks).Error; err != nil {
+		return err
+	}
+	index := make(map[string]*Feedback, len(feedbacks))
+	for i := range feedbacks {
+		index[feedbacks[i].MessageID] = &feedbacks[i]
+	}
+	for i := range messages {
+		messages[i].Feedback = index[messages[i].ID]
+	}
+	return nil
+}
+
+func (g *gormStorage) findMessageRelevantDocuments(message Message) ([]Document, error) {
+	var documents []Document
+	err := g.db.Where("object IN ?", message.Files).Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (g *gormStorage) Delete(opts ...SearchOption) error {
+	searchOpt := applyOptions(nil, opts...)
+	c := &Conversation{}
+	if searchOpt.ConversationID != nil {
+		c.ID = *searchOpt.ConversationID
+	}
+	if searchOpt.User != nil {
+		c.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		c.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		c.AppNamespace = *searchOpt.AppNamespace
+	}
+	if searchOpt.Debug != nil {
+		c.Debug = *searchOpt.Debug
+	}
+	// the conditions must be in where, gorm only uses the primary key if it is set in the deleted value
+	tx := g.db.Where(c).Delete(&Conversation{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+func (g *gormStorage) PurgeConversations(before time.Time, opts ...SearchOption) ([]Conversation, error) {
+	searchOpt := applyOptions(nil, opts...)
+	query := Conversation{}
+	if searchOpt.User != nil {
+		query.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		query.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		query.AppNamespace = *searchOpt.AppNamespace
+	}
+	purged := make([]Conversation, 0)
+	if err := g.db.Unscoped().Select("id", "app_name", "app_namespace", "user").Where(query).Where("updated_at < ?", before).Find(&purged).Error; err != nil {
+		return nil, err
+	}
+	if len(purged) == 0 {
+		return purged, nil
+	}
+	ids := make([]string, len(purged))
+	for i := range purged {
+		ids[i] = purged[i].ID
+	}
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		messages := tx.Model(&Message{}).Select("id").Where("conversation_id IN ?", ids)
+		if err := tx.Where("message_id IN (?)", messages).Delete(&Feedback{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("conversation_id IN ?", ids).Delete(&Document{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("conversation_id IN ?", ids).Delete(&Message{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&Conversation{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
+
+func (g *gormStorage) FindExistingMessage(conversationID string, messageID string, opts ...SearchOption) (*Message, error) {
+	searchOpt := applyOptions(&conversationID, opts...)
+	conversationQuery := Conversation{ID: conversationID}
+	if searchOpt.Debug != nil {
+		conversationQuery.Debug = *searchOpt.Debug
+	}
+	if searchOpt.User != nil {
+		conversationQuery.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		conversationQuery.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		conversationQuery.AppNamespace = *searchOpt.AppNamespace
+	}
+	conversationQuery.Debug = false
+	conversationQuery.DeletedAt.Valid = false
+	conversation := &Conversation{}
+	message := &Message{}
+	tx := g.db.Preload("Documents").First(message, Message{ID: messageID})
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	tx = g.db.First(conversation, conversationQuery)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	association := g.db.Model(conversation).Association("Messages")
+	if association.Error != nil {
+		return nil, association.Error
+	}
+	if err := association.Find(message, Message{ID: messageID}); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (g *gormStorage) AddUsage(record *UsageRecord) error {
+	return g.db.Create(record).Error
+}
+
+func (g *gormStorage) AggregateUsage(since, until time.Time, opts ...SearchOption) ([]Usage, error) {
+	searchOpt := applyOptions(nil, opts...)
+	query := UsageRecord{}
+	if searchOpt.User != nil {
+		query.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		query.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		query.AppNamespace = *searchOpt.AppNamespace
+	}
+	res := make([]Usage, 0)
+	tx := g.db.Model(&UsageRecord{}).
+		Select(`"user", app_name, app_namespace, sum(requests) AS requests, sum(prompt_tokens) AS prompt_tokens, sum(completion_tokens) AS completion_tokens, sum(total_tokens) AS total_tokens`).
+		Where(query).
+		Where("created_at >= ? AND created_at < ?", since, until).
+		Group(`"user", app_name, app_namespace`).
+		Scan(&res)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return res, nil
+}
+
+func (g *gormStorage) UpdateFeedback(feedback *Feedback) error {
+	return g.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "reason", "comment", "corrected_answer", "updated_at"}),
+	}).Create(feedback).Error
+}
+
+func (g *gormStorage) DeleteFeedback(messageID string, opts ...SearchOption) error {
+	searchOpt := applyOptions(nil, opts...)
+	query := Feedback{MessageID: messageID}
+	if searchOpt.User != nil {
+		query.User = *searchOpt.User
+	}
+	return g.db.Where(query).Delete(&Feedback{}).Error
+}
+
+func (g *gormStorage) ListFeedbacks(since, until time.Time, opts ...SearchOption) ([]Feedback, error) {
+	res := make([]Feedback, 0)
+	tx := g.db.Where(feedbackQuery(opts...)).
+		Where("updated_at >= ? AND updated_at < ?", since, until).
+		Order("updated_at").
+		Find(&res)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return res, nil
+}
+
+func (g *gormStorage) AggregateFeedback(since, until time.Time, opts ...SearchOption) ([]FeedbackCount, error) {
+	res := make([]FeedbackCount, 0)
+	tx := g.db.Model(&Feedback{}).
+		Select("app_name, app_namespace, rating, reason, count(*) AS count").
+		Where(feedbackQuery(opts...)).
+		Where("updated_at >= ? AND updated_at < ?", since, until).
+		Group("app_name, app_namespace, rating, reason").
+		Scan(&res)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return res, nil
+}
+
+func feedbackQuery(opts ...SearchOption) Feedback {
+	searchOpt := applyOptions(nil, opts...)
+	query := Feedback{}
+	if searchOpt.User != nil {
+		query.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		query.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		query.AppNamespace = *searchOpt.AppNamespace
+	}
+	return query
+}
+
+// searchQuery selects the messages in the conversations filtered by the options and the search time range
+func (g *gormStorage) searchQuery(search MessageSearch, opts ...SearchOption) *gorm.DB {
+	searchOpt := applyOptions(nil, opts...)
+	tx := g.db.Table("app_chat_message AS m").
+		Joins("JOIN app_chat_conversation AS c ON c.id = m.conversation_id").
+		Where("c.deleted_at IS NULL AND c.debug = ? AND m.action <> ?", false, "UPLOAD")
+	if searchOpt.User != nil {
+		tx = tx.Where(`c."user" = ?`, *searchOpt.User)
+	}
+	if searchOpt.AppName != nil {
+		tx = tx.Where("c.app_name = ?", *searchOpt.AppName)
+	}
+	if searchOpt.AppNamespace != nil {
+		tx = tx.Where("c.app_namespace = ?", *searchOpt.AppNamespace)
+	}
+	if !search.Since.IsZero() {
+		tx = tx.Where("c.updated_at >= ?", search.Since)
+	}
+	if !search.Until.IsZero() {
+		tx = tx.Where("c.updated_at < ?", search.Until)
+	}
+	return tx
+}
+
+// pageHits counts the matched messages and returns the page of them with snippets
+func (g *gormStorage) pageHits(tx *gorm.DB, order clause.Expression, search MessageSearch, terms []string) ([]MessageHit, int64, error) {
+	tx = tx.Session(&gorm.Session{})
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	hits := make([]MessageHit, 0)
+	if total == 0 {
+		return hits, 0, nil
+	}
+	query := tx.Select("m.id AS message_id, m.conversation_id, c.app_name, c.app_namespace, c.updated_at, m.query, m.answer").
+		Order(order).
+		Offset(search.Offset)
+	if search.Limit > 0 {
+		query = query.Limit(search.Limit)
+	}
+	if err := query.Scan(&hits).Error; err != nil {
+		return nil, 0, err
+	}
+	for i := range hits {
+		hits[i].Query = Snippet(hits[i].Query, terms)
+		hits[i].Answer = Snippet(hits[i].Answer, terms)
+	}
+	return hits, total, nil
+}
+
+// SearchMessages matches each word as a substring, ordered by the update time of conversations
+func (g *gormStorage) SearchMessages(search MessageSearch, opts ...SearchOption) ([]MessageHit, int64, error) {
+	terms := SearchTerms(search.Keyword)
+	if len(terms) == 0 {
+		return make([]MessageHit, 0), 0, nil
+	}
+	tx := g.searchQuery(search, opts...)
+	condition, vars := termsLikeCondition(terms)
+	tx = tx.Where(condition, vars...)
+	return g.pageHits(tx, clause.Expr{SQL: "c.updated_at DESC, m.id", WithoutParentheses: true}, search, terms)
+}
+
+// findConversation finds the conversation without its messages, to make sure it is accessible with the options
+func (g *gormStorage) findConversation(conversationID string, opts ...SearchOption) (*Conversation, error) {
+	searchOpt := applyOptions(&conversationID, opts...)
+	conversationQuery := Conversation{ID: conversationID}
+	if searchOpt.User != nil {
+		conversationQuery.User = *searchOpt.User
+	}
+	if searchOpt.AppName != nil {
+		conversationQuery.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		conversationQuery.AppNamespace = *searchOpt.AppNamespace
+	}
+	conversation := &Conversation{}
+	if err := g.db.First(conversation, conversationQuery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (g *gormStorage) ListDocuments(conversationID string, opts ...SearchOption) ([]Document, error) {
+	if _, err := g.findConversation(conversationID, opts...); err != nil {
+		return nil, err
+	}
+	documents := make([]Document, 0)
+	if err := g.db.Where(Document{ConversationID: conversationID}).Order("name, id").Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (g *gormStorage) FindExistingDocument(conversationID, documentID string, opts ...SearchOption) (*Document, error) {
+	if _, err := g.findConversation(conversationID, opts...); err != nil {
+		return nil, err
+	}
+	document := &Document{}
+	if err := g.db.First(document, Document{ConversationID: conversationID, ID: documentID}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return document, nil
+}
+
+func (g *gormStorage) DeleteDocument(conversationID, documentID string, opts ...SearchOption) error {
+	if _, err := g.findConversation(conversationID, opts...); err != nil {
+		return err
+	}
+	return g.db.Where(Document{ConversationID: conversationID, ID: documentID}).Delete(&Document{}).Error
+}
+
+func (g *gormStorage) UpdateDocumentSummary(conversationID, documentID, summary string, opts ...SearchOption) error {
+	if _, err := g.findConversation(conversationID, opts...); err != nil {
+		return err
+	}
+	tx := g.db.Model(&Document{}).Where(Document{ConversationID: conversationID, ID: documentID}).Update("summary", summary)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrDocumentNotFound
+	}
+	return nil
+}
+
+func (g *gormStorage) CreateShareToken(token *ShareToken) error {
+	return g.db.Create(token).Error
+}
+
+func (g *gormStorage) FindShareToken(tokenHash string) (*ShareToken, error) {
+	token := &ShareToken{}
+	if err := g.db.First(token, ShareToken{TokenHash: tokenHash}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShareTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (g *gormStorage) ListShareTokens(opts ...SearchOption) ([]ShareToken, error) {
+	res := make([]ShareToken, 0)
+	if err := g.db.Where(shareTokenQuery(opts...)).Order("created_at, id").Find(&res).Error; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (g *gormStorage) RevokeShareToken(id string, revokedAt time.Time, opts ...SearchOption) error {
+	query := shareTokenQuery(opts...)
+	query.ID = id
+	token := &ShareToken{}
+	if err := g.db.First(token, query).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrShareTokenNotFound
+		}
+		return err
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+	return g.db.Model(token).Update("revoked_at", revokedAt).Error
+}
+
+func shareTokenQuery(opts ...SearchOption) ShareToken {
+	searchOpt := applyOptions(nil, opts...)
+	query := ShareToken{}
+	if searchOpt.AppName != nil {
+		query.AppName = *searchOpt.AppName
+	}
+	if searchOpt.AppNamespace != nil {
+		query.AppNamespace = *searchOpt.AppNamespace
+	}
+	return query
+}
@@ -19,6 +19,7 @@ package storage
 import (
 	"sort"
 	"sync"
+	"time"
 )
 
 var _ Storage = (*MemoryStorage)(nil)
@@ -26,6 +27,9 @@ var _ Storage = (*MemoryStorage)(nil)
 type MemoryStorage struct {
 	mu            sync.Mutex
 	conversations map[string]Conversation
+	usages        []UsageRecord
+	feedbacks     map[string]Feedback
+	shareTokens   []ShareToken
 }
 
 func (m *MemoryStorage) CountMessages(appName, appNamespace string) (res int64, err error) {
@@ -44,6 +48,7 @@ func (m *MemoryStorage) CountMessages(appName, appNamespace string) (res int64,
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
 		conversations: make(map[string]Conversation),
+		feedbacks:     make(map[string]Feedback),
 	}
 }
 
@@ -141,6 +146,33 @@ func (m *MemoryStorage) Delete(opts ...SearchOption) (err error) {
 	return nil
 }
 
+func (m *MemoryStorage) PurgeConversations(before time.Time, opts ...SearchOption) ([]Conversation, error) {
+	searchOpt := applyOptions(nil, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	purged := make([]Conversation, 0)
+	for id, c := range m.conversations {
+		if !c.UpdatedAt.Before(before) {
+			continue
+		}
+		if searchOpt.User != nil && c.User != *searchOpt.User {
+			continue
+		}
+		if searchOpt.AppName != nil && c.AppName != *searchOpt.AppName {
+			continue
+		}
+		if searchOpt.AppNamespace != nil && c.AppNamespace != *searchOpt.AppNamespace {
+			continue
+		}
+		for _, message := range c.Messages {
+			delete(m.feedbacks, message.ID)
+		}
+		delete(m.conversations, id)
+		purged = append(purged, Conversation{ID: c.ID, AppName: c.AppName, AppNamespace: c.AppNamespace, User: c.User})
+	}
+	return purged, nil
+}
+
 // FindExistingConversation searches for an existing conversation in MemoryStorage.
 //
 // ConversationID string, opt ...SearchOption. Returns *Conversation, error.
@@ -164,19 +196,304 @@ func (m *MemoryStorage) FindExistingConversation(conversationID string, opt ...S
 	if searchOpt.User != nil && v.User != *searchOpt.User {
 		return nil, ErrConversationNotFound
 	}
+	// copy the messages to fill the feedbacks without changing the stored conversation
+	v.Messages = append([]Message(nil), v.Messages...)
+	m.mu.Lock()
+	for i := range v.Messages {
+		v.Messages[i].Feedback = nil
+		if feedback, ok := m.feedbacks[v.Messages[i].ID]; ok {
+			v.Messages[i].Feedback = &feedback
+		}
+	}
+	m.mu.Unlock()
 	return &v, nil
 }
 
-func (m *MemoryStorage) FindExistingDocument(conversationID string, messageID string, documentID string, opts ...SearchOption) (*Document, error) {
-	message, err := m.FindExistingMessage(conversationID, messageID, opts...)
+func (m *MemoryStorage) ListDocuments(conversationID string, opts ...SearchOption) ([]Document, error) {
+	conversation, err := m.FindExistingConversation(conversationID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]Document, 0)
+	for _, message := range conversation.Messages {
+		documents = append(documents, message.Documents...)
+	}
+	sort.Slice(documents, func(i, j int) bool {
+		if documents[i].Name != documents[j].Name {
+			return documents[i].Name < documents[j].Name
+		}
+		return documents[i].ID < documents[j].ID
+	})
+	return documents, nil
+}
+
+func (m *MemoryStorage) FindExistingDocument(conversationID string, documentID string, opts ...SearchOption) (*Document, error) {
+	documents, err := m.ListDocuments(conversationID, opts...)
 	if err != nil {
 		return nil, err
 	}
-	for _, v := range message.Documents {
+	for _, v := range documents {
 		v := v
 		if v.ID == documentID {
 			return &v, nil
 		}
 	}
-	return nil, nil
+	return nil, ErrDocumentNotFound
+}
+
+func (m *MemoryStorage) DeleteDocument(conversationID string, documentID string, opts ...SearchOption) error {
+	return m.updateDocuments(conversationID, documentID, func(documents []Document, i int) []Document {
+		return append(documents[:i:i], documents[i+1:]...)
+	}, opts...)
+}
+
+func (m *MemoryStorage) UpdateDocumentSummary(conversationID string, documentID string, summary string, opts ...SearchOption) error {
+	found := false
+	err := m.updateDocuments(conversationID, documentID, func(documents []Document, i int) []Document {
+		found = true
+		documents = append([]Document(nil), documents...)
+		documents[i].Summary = summary
+		return documents
+	}, opts...)
+	if err == nil && !found {
+		return ErrDocumentNotFound
+	}
+	return err
+}
+
+// updateDocuments replaces the documents of the message which has the document,
+// the messages are copied so the conversations returned before are not changed
+func (m *MemoryStorage) updateDocuments(conversationID string, documentID string, update func(documents []Document, i int) []Document, opts ...SearchOption) error {
+	if _, err := m.FindExistingConversation(conversationID, opts...); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	conversation, ok := m.conversations[conversationID]
+	if !ok {
+		return ErrConversationNotFound
+	}
+	for i, message := range conversation.Messages {
+		for j, document := range message.Documents {
+			if document.ID != documentID {
+				continue
+			}
+			conversation.Messages = append([]Message(nil), conversation.Messages...)
+			conversation.Messages[i].Documents = update(message.Documents, j)
+			m.conversations[conversationID] = conversation
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) AddUsage(record *UsageRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	m.mu.Lock()
+	m.usages = append(m.usages, *record)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStorage) AggregateUsage(since, until time.Time, opts ...SearchOption) ([]Usage, error) {
+	searchOpt := applyOptions(nil, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	index := make(map[[3]string]int)
+	res := make([]Usage, 0)
+	for _, r := range m.usages {
+		if r.CreatedAt.Before(since) || !r.CreatedAt.Before(until) {
+			continue
+		}
+		if searchOpt.User != nil && r.User != *searchOpt.User {
+			continue
+		}
+		if searchOpt.AppName != nil && r.AppName != *searchOpt.AppName {
+			continue
+		}
+		if searchOpt.AppNamespace != nil && r.AppNamespace != *searchOpt.AppNamespace {
+			continue
+		}
+		key := [3]string{r.User, r.AppName, r.AppNamespace}
+		i, ok := index[key]
+		if !ok {
+			i = len(res)
+			index[key] = i
+			res = append(res, Usage{User: r.User, AppName: r.AppName, AppNamespace: r.AppNamespace})
+		}
+		res[i].Requests += int64(r.Requests)
+		res[i].PromptTokens += int64(r.PromptTokens)
+		res[i].CompletionTokens += int64(r.CompletionTokens)
+		res[i].TotalTokens += int64(r.TotalTokens)
+	}
+	return res, nil
+}
+
+func (m *MemoryStorage) UpdateFeedback(feedback *Feedback) error {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.feedbacks[feedback.MessageID]; ok {
+		feedback.CreatedAt = existing.CreatedAt
+	} else if feedback.CreatedAt.IsZero() {
+		feedback.CreatedAt = now
+	}
+	feedback.UpdatedAt = now
+	m.feedbacks[feedback.MessageID] = *feedback
+	return nil
+}
+
+func (m *MemoryStorage) DeleteFeedback(messageID string, opts ...SearchOption) error {
+	searchOpt := applyOptions(nil, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	feedback, ok := m.feedbacks[messageID]
+	if !ok || (searchOpt.User != nil && feedback.User != *searchOpt.User) {
+		return nil
+	}
+	delete(m.feedbacks, messageID)
+	return nil
+}
+
+func (m *MemoryStorage) ListFeedbacks(since, until time.Time, opts ...SearchOption) ([]Feedback, error) {
+	searchOpt := applyOptions(nil, opts...)
+	m.mu.Lock()
+	res := make([]Feedback, 0)
+	for _, f := range m.feedbacks {
+		if f.UpdatedAt.Before(since) || !f.UpdatedAt.Before(until) {
+			continue
+		}
+		if searchOpt.User != nil && f.User != *searchOpt.User {
+			continue
+		}
+		if searchOpt.AppName != nil && f.AppName != *searchOpt.AppName {
+			continue
+		}
+		if searchOpt.AppNamespace != nil && f.AppNamespace != *searchOpt.AppNamespace {
+			continue
+		}
+		res = append(res, f)
+	}
+	m.mu.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].UpdatedAt.Before(res[j].UpdatedAt)
+	})
+	return res, nil
+}
+
+func (m *MemoryStorage) AggregateFeedback(since, until time.Time, opts ...SearchOption) ([]FeedbackCount, error) {
+	feedbacks, err := m.ListFeedbacks(since, until, opts...)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[FeedbackCount]int)
+	res := make([]FeedbackCount, 0)
+	for _, f := range feedbacks {
+		key := FeedbackCount{AppName: f.AppName, AppNamespace: f.AppNamespace, Rating: f.Rating, Reason: f.Reason}
+		i, ok := index[key]
+		if !ok {
+			i = len(res)
+			index[key] = i
+			res = append(res, key)
+		}
+		res[i].Count++
+	}
+	return res, nil
+}
+
+func (m *MemoryStorage) SearchMessages(search MessageSearch, opts ...SearchOption) ([]MessageHit, int64, error) {
+	conversations, err := m.ListConversations(append(opts, WithDebug(false))...)
+	if err != nil {
+		return nil, 0, err
+	}
+	terms := SearchTerms(search.Keyword)
+	hits := make([]MessageHit, 0)
+	for _, c := range conversations {
+		if c.UpdatedAt.Before(search.Since) || (!search.Until.IsZero() && !c.UpdatedAt.Before(search.Until)) {
+			continue
+		}
+		for _, message := range c.Messages {
+			if message.Action == "UPLOAD" || !MatchTerms(message.Query+" "+message.Answer, terms) {
+				continue
+			}
+			hits = append(hits, MessageHit{
+				ConversationID: c.ID,
+				MessageID:      message.ID,
+				AppName:        c.AppName,
+				AppNamespace:   c.AppNamespace,
+				UpdatedAt:      c.UpdatedAt,
+				Query:          Snippet(message.Query, terms),
+				Answer:         Snippet(message.Answer, terms),
+			})
+		}
+	}
+	total := int64(len(hits))
+	if search.Offset >= len(hits) {
+		return make([]MessageHit, 0), total, nil
+	}
+	hits = hits[search.Offset:]
+	if search.Limit > 0 && search.Limit < len(hits) {
+		hits = hits[:search.Limit]
+	}
+	return hits, total, nil
+}
+
+func (m *MemoryStorage) CreateShareToken(token *ShareToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	m.shareTokens = append(m.shareTokens, *token)
+	return nil
+}
+
+func (m *MemoryStorage) FindShareToken(tokenHash string) (*ShareToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.shareTokens {
+		if t.TokenHash == tokenHash {
+			t := t
+			return &t, nil
+		}
+	}
+	return nil, ErrShareTokenNotFound
+}
+
+func (m *MemoryStorage) ListShareTokens(opts ...SearchOption) ([]ShareToken, error) {
+	searchOpt := applyOptions(nil, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make([]ShareToken, 0)
+	for _, t := range m.shareTokens {
+		if searchOpt.AppName != nil && t.AppName != *searchOpt.AppName {
+			continue
+		}
+		if searchOpt.AppNamespace != nil && t.AppNamespace != *searchOpt.AppNamespace {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func (m *MemoryStorage) RevokeShareToken(id string, revokedAt time.Time, opts ...SearchOption) error {
+	searchOpt := applyOptions(nil, opts...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, t := range m.shareTokens {
+		if t.ID != id {
+			continue
+		}
+		if (searchOpt.AppName != nil && t.AppName != *searchOpt.AppName) || (searchOpt.AppNamespace != nil && t.AppNamespace != *searchOpt.AppNamespace) {
+			break
+		}
+		if t.RevokedAt == nil {
+			m.shareTokens[i].RevokedAt = &revokedAt
+		}
+		return nil
+	}
+	return ErrShareTokenNotFound
 }
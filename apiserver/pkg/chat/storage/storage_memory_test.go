@@ -0,0 +1,43 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoragePurgeConversations(t *testing.T) {
+	m := NewMemoryStorage()
+	now := time.Now()
+	_ = m.UpdateConversation(&Conversation{ID: "old", AppName: "app", AppNamespace: "ns", UpdatedAt: now.AddDate(0, 0, -31), Messages: []Message{{ID: "m1"}}})
+	_ = m.UpdateConversation(&Conversation{ID: "new", AppName: "app", AppNamespace: "ns", UpdatedAt: now})
+	_ = m.UpdateConversation(&Conversation{ID: "other", AppName: "app", AppNamespace: "other", UpdatedAt: now.AddDate(0, 0, -31)})
+	_ = m.UpdateFeedback(&Feedback{MessageID: "m1", AppName: "app", AppNamespace: "ns"})
+
+	purged, err := m.PurgeConversations(now.AddDate(0, 0, -30), WithAppNamespace("ns"))
+	if err != nil || !reflect.DeepEqual(purged, []Conversation{{ID: "old", AppName: "app", AppNamespace: "ns"}}) {
+		t.Fatalf("unexpected purged conversations %v, err %v", purged, err)
+	}
+	if _, ok := m.conversations["other"]; !ok {
+		t.Errorf("conversation in other namespace should be kept")
+	}
+	if _, ok := m.feedbacks["m1"]; ok {
+		t.Errorf("feedback of purged conversation should be deleted")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+var _ Storage = (*SQLiteStorage)(nil)
+
+// SQLiteStorage stores the chats in an embedded sqlite database file, which is for single-node installs without a relational datasource
+type SQLiteStorage struct {
+	gormStorage
+}
+
+// NewSQLiteStorage opens or creates the sqlite database in the path
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	// wait for the lock instead of failing when the database is being written
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)", path)
+	g, err := newGormStorage(sqliteDialector{Dialector: &sqlite.Dialector{DSN: dsn}})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// sqlite allows only one writer at a time
+	sqlDB.SetMaxOpenConns(1)
+	return &SQLiteStorage{gormStorage: *g}, nil
+}
+
+// sqliteDialector creates the time columns as datetime, which are parsed into time by the sqlite driver,
+// while the columns are tagged with `type:time` for PostgreSQL
+type sqliteDialector struct {
+	*sqlite.Dialector
+}
+
+func (d sqliteDialector) DataTypeOf(field *schema.Field) string {
+	if field.DataType == schema.Time {
+		return "datetime"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+func (d sqliteDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return &sqlite.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
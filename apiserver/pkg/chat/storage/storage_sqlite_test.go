@@ -0,0 +1,68 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStorageBackfillMessageCreatedAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chat.db")
+	s, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	startedAt := time.Now().Add(-time.Hour)
+	conversation := Conversation{ID: newID(), AppName: "app", AppNamespace: "ns", StartedAt: startedAt, UpdatedAt: startedAt}
+	want := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		id := newID()
+		want = append(want, id)
+		conversation.Messages = append(conversation.Messages, Message{ID: id, ConversationID: conversation.ID, Query: "q", Answer: "a"})
+	}
+	if err := s.UpdateConversation(&conversation); err != nil {
+		t.Fatal(err)
+	}
+	// the messages saved before the column is added
+	if err := s.db.Model(&Message{}).Where("1 = 1").Update("created_at", nil).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = NewSQLiteStorage(path); err != nil {
+		t.Fatal(err)
+	}
+	var missing int64
+	if err := s.db.Model(&Message{}).Where("created_at IS NULL").Count(&missing).Error; err != nil {
+		t.Fatal(err)
+	}
+	if missing != 0 {
+		t.Errorf("expected the creation time of all messages backfilled, but got %d missing", missing)
+	}
+	c, err := s.FindExistingConversation(conversation.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(c.Messages); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the messages kept in the stored order %v, but got %v", want, got)
+	}
+	if first := c.Messages[0].CreatedAt; first.Before(startedAt.Add(-time.Second)) || first.After(startedAt.Add(time.Second)) {
+		t.Errorf("expected the messages stamped from the start of the conversation %s, but got %s", startedAt, first)
+	}
+}
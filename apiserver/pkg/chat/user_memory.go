@@ -0,0 +1,219 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	langchainschema "github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/langchainwrap"
+	pkgvectorstore "github.com/kubeagi/arcadia/pkg/vectorstore"
+)
+
+const (
+	defaultNumFacts = 5
+	// noFacts is the reply of the llm when there is nothing to remember
+	noFacts = "NONE"
+
+	// maxRememberingFacts limits the turns remembered in background at the same time
+	maxRememberingFacts = 8
+	// rememberFactsTimeout limits the time to remember the facts of a turn
+	rememberFactsTimeout = 2 * time.Minute
+)
+
+// rememberingFacts holds a slot for each turn being remembered in background
+var rememberingFacts = make(chan struct{}, maxRememberingFacts)
+
+const promptTemplateForFacts = `Extract the facts about the user from the conversation below which are worth remembering in the later conversations,
+such as the name, job, preferences, plans and the things the user owns. Ignore the general knowledge and the facts about the assistant.
+Write one short fact per line in the language of the conversation, without numbering. If there is nothing to remember, reply with {{.none}} only.
+
+User: {{.query}}
+Assistant: {{.answer}}
+
+Facts:`
+
+// longTermMemoryEnabled returns whether the facts about the user should be remembered in this app,
+// the anonymous users with share tokens have no long-term memory
+func longTermMemoryEnabled(ctx context.Context, app *v1alpha1.Application, user string) bool {
+	return app.Spec.LongTermMemory != nil && app.Spec.LongTermMemory.Enabled && user != "" && ShareTokenFromContext(ctx) == nil
+}
+
+// userMemoryCollection is the vector collection of the facts about the user in this app,
+// the names are hashed as the collection name only allows limited characters.
+func userMemoryCollection(appNamespace, appName, user string) string {
+	sum := sha256.Sum256([]byte(appNamespace + "/" + appName + "/" + user))
+	return "memory-" + hex.EncodeToString(sum[:16])
+}
+
+func (cs *ChatServer) userMemoryStore(ctx context.Context, app *v1alpha1.Application, user string) (vectorstores.VectorStore, func(), error) {
+	embedder, vectorStore, err := pkgconfig.GetSystemEmbeddingSuite(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	em, err := langchainwrap.GetLangchainEmbedder(ctx, embedder, cs.systemCli, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkgvectorstore.NewVectorStore(ctx, vectorStore, em, userMemoryCollection(app.Namespace, app.Name, user), cs.systemCli)
+}
+
+// RecallFacts returns the facts about the user which are most relevant to the query
+func (cs *ChatServer) RecallFacts(ctx context.Context, app *v1alpha1.Application, user, query string) ([]string, error) {
+	store, finish, err := cs.userMemoryStore(ctx, app, user)
+	if finish != nil {
+		defer finish()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return recallFacts(ctx, store, query, app.Spec.LongTermMemory.NumFacts)
+}
+
+func recallFacts(ctx context.Context, store vectorstores.VectorStore, query string, num int) ([]string, error) {
+	if num <= 0 {
+		num = defaultNumFacts
+	}
+	docs, err := store.SimilaritySearch(ctx, query, num)
+	if err != nil {
+		return nil, err
+	}
+	facts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		facts = append(facts, doc.PageContent)
+	}
+	return facts, nil
+}
+
+// RememberFacts extracts the facts about the user from one turn by the llm of the app, and saves them to the user memory
+func (cs *ChatServer) RememberFacts(ctx context.Context, model langchainllms.Model, app *v1alpha1.Application, user, conversationID, messageID, query, answer string) error {
+	facts, err := ExtractFacts(ctx, model, query, answer)
+	if err != nil || len(facts) == 0 {
+		return err
+	}
+	store, finish, err := cs.userMemoryStore(ctx, app, user)
+	if finish != nil {
+		defer finish()
+	}
+	if err != nil {
+		return err
+	}
+	return saveFacts(ctx, store, facts, conversationID, messageID)
+}
+
+// ExtractFacts asks the llm for the facts about the user in one turn
+func ExtractFacts(ctx context.Context, model langchainllms.Model, query, answer string) ([]string, error) {
+	prompt, err := prompts.NewPromptTemplate(promptTemplateForFacts, []string{"none", "query", "answer"}).Format(map[string]any{
+		"none":   noFacts,
+		"query":  query,
+		"answer": answer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out, err := langchainllms.GenerateFromSinglePrompt(ctx, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFacts(out), nil
+}
+
+func saveFacts(ctx context.Context, store vectorstores.VectorStore, facts []string, conversationID, messageID string) error {
+	docs := make([]langchainschema.Document, 0, len(facts))
+	for _, fact := range facts {
+		docs = append(docs, langchainschema.Document{
+			PageContent: fact,
+			Metadata: map[string]any{
+				// the facts are removed together with the conversation by the retention
+				pkgvectorstore.SourceMetadataKey: conversationID,
+				"conversation_id":                conversationID,
+				"message_id":                     messageID,
+			},
+		})
+	}
+	_, err := store.AddDocuments(ctx, docs)
+	return err
+}
+
+// ForgetFacts removes the facts about the user remembered from the conversation
+func (cs *ChatServer) ForgetFacts(ctx context.Context, c storage.Conversation) error {
+	// nothing is remembered for the anonymous users with share tokens
+	if c.User == "" || strings.HasPrefix(c.User, shareUserPrefix) {
+		return nil
+	}
+	app := &v1alpha1.Application{}
+	err := cs.systemCli.Get(ctx, runtimeclient.ObjectKey{Namespace: c.AppNamespace, Name: c.AppName}, app)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	// nothing is remembered if the long-term memory is never configured in the app
+	if err == nil && app.Spec.LongTermMemory == nil {
+		return nil
+	}
+	_, vectorStore, err := pkgconfig.GetSystemEmbeddingSuite(ctx)
+	if err != nil {
+		return err
+	}
+	return pkgvectorstore.RemoveDocumentsBySource(ctx, klog.FromContext(ctx), vectorStore, userMemoryCollection(c.AppNamespace, c.AppName, c.User), cs.systemCli, c.ID)
+}
+
+// rememberFactsInBackground does not block the chat response, the error is only logged.
+// At most maxRememberingFacts turns are handled at the same time, the others are skipped.
+func (cs *ChatServer) rememberFactsInBackground(ctx context.Context, model langchainllms.Model, app *v1alpha1.Application, user, conversationID, messageID, query, answer string) {
+	logger := klog.FromContext(ctx).WithValues("appName", app.Name, "appNamespace", app.Namespace, "conversationID", conversationID)
+	select {
+	case rememberingFacts <- struct{}{}:
+	default:
+		logger.Info("too many turns being remembered, skip remembering facts about the user in this one")
+		return
+	}
+	go func() {
+		defer func() { <-rememberingFacts }()
+		ctx, cancel := context.WithTimeout(context.Background(), rememberFactsTimeout)
+		defer cancel()
+		if err := cs.RememberFacts(ctx, model, app, user, conversationID, messageID, query, answer); err != nil {
+			logger.Error(err, "failed to remember facts about the user")
+		}
+	}()
+}
+
+// ParseFacts splits the reply of the llm into facts, one per line
+func ParseFacts(out string) []string {
+	facts := make([]string, 0)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if line == "" || strings.EqualFold(line, noFacts) {
+			continue
+		}
+		facts = append(facts, line)
+	}
+	return facts
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	langchainschema "github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	pkgvectorstore "github.com/kubeagi/arcadia/pkg/vectorstore"
+)
+
+// fakeModel replies with the text and records the prompts
+type fakeModel struct {
+	reply   string
+	prompts []string
+}
+
+func (f *fakeModel) Call(ctx context.Context, prompt string, options ...langchainllms.CallOption) (string, error) {
+	return langchainllms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func (f *fakeModel) GenerateContent(_ context.Context, messages []langchainllms.MessageContent, _ ...langchainllms.CallOption) (*langchainllms.ContentResponse, error) {
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			if text, ok := part.(langchainllms.TextContent); ok {
+				f.prompts = append(f.prompts, text.Text)
+			}
+		}
+	}
+	return &langchainllms.ContentResponse{Choices: []*langchainllms.ContentChoice{{Content: f.reply}}}, nil
+}
+
+// fakeStore keeps the documents in memory, the search returns the documents containing the query first
+type fakeStore struct {
+	docs []langchainschema.Document
+}
+
+func (f *fakeStore) AddDocuments(_ context.Context, docs []langchainschema.Document, _ ...vectorstores.Option) ([]string, error) {
+	f.docs = append(f.docs, docs...)
+	return nil, nil
+}
+
+func (f *fakeStore) SimilaritySearch(_ context.Context, query string, num int, _ ...vectorstores.Option) ([]langchainschema.Document, error) {
+	res := make([]langchainschema.Document, 0)
+	for _, doc := range f.docs {
+		if strings.Contains(doc.PageContent, query) {
+			res = append(res, doc)
+		}
+	}
+	for _, doc := range f.docs {
+		if !strings.Contains(doc.PageContent, query) {
+			res = append(res, doc)
+		}
+	}
+	if len(res) > num {
+		res = res[:num]
+	}
+	return res, nil
+}
+
+func TestParseFacts(t *testing.T) {
+	testCases := map[string][]string{
+		"NONE":     {},
+		" none \n": {},
+		"The user is Alice.\nThe user likes tea.":                           {"The user is Alice.", "The user likes tea."},
+		"- The user is Alice.\n\n* The user has a cat\n•  lives in Beijing": {"The user is Alice.", "The user has a cat", "lives in Beijing"},
+	}
+	for out, want := range testCases {
+		if got := ParseFacts(out); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: expected facts %q, but got %q", out, want, got)
+		}
+	}
+}
+
+func TestExtractFacts(t *testing.T) {
+	model := &fakeModel{reply: "- The user is Alice.\n- The user likes tea."}
+	facts, err := ExtractFacts(context.Background(), model, "I am Alice, and I like tea", "Hello Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(facts) != 2 || facts[1] != "The user likes tea." {
+		t.Errorf("unexpected facts %q", facts)
+	}
+	if len(model.prompts) != 1 || !strings.Contains(model.prompts[0], "User: I am Alice, and I like tea") || !strings.Contains(model.prompts[0], "reply with NONE only") {
+		t.Errorf("unexpected prompt %q", model.prompts)
+	}
+
+	model.reply = "NONE"
+	if facts, _ = ExtractFacts(context.Background(), model, "What's the weather?", "Sunny"); len(facts) != 0 {
+		t.Errorf("expected nothing to remember, but got %q", facts)
+	}
+}
+
+func TestSaveAndRecallFacts(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	if err := saveFacts(ctx, store, []string{"The user is Alice.", "The user likes tea."}, "c1", "m1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.docs) != 2 || store.docs[0].Metadata[pkgvectorstore.SourceMetadataKey] != "c1" || store.docs[0].Metadata["message_id"] != "m1" {
+		t.Errorf("expected the facts saved with the conversation as the source, but got %+v", store.docs)
+	}
+	if err := saveFacts(ctx, store, []string{"The user has a cat."}, "c2", "m2"); err != nil {
+		t.Fatal(err)
+	}
+
+	facts, err := recallFacts(ctx, store, "tea", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(facts, []string{"The user likes tea."}) {
+		t.Errorf("expected the most relevant fact, but got %q", facts)
+	}
+	if facts, _ = recallFacts(ctx, store, "tea", 0); len(facts) != 3 {
+		t.Errorf("expected at most %d facts by default, but got %q", defaultNumFacts, facts)
+	}
+}
+
+func TestRememberFactsInBackgroundLimit(t *testing.T) {
+	for i := 0; i < maxRememberingFacts; i++ {
+		rememberingFacts <- struct{}{}
+	}
+	defer func() {
+		for i := 0; i < maxRememberingFacts; i++ {
+			<-rememberingFacts
+		}
+	}()
+	model := &fakeModel{reply: "The user is Alice."}
+	app := &v1alpha1.Application{}
+	app.Namespace, app.Name = "default", "app"
+	NewChatServer(nil, false).rememberFactsInBackground(context.Background(), model, app, "alice", "c1", "m1", "I am Alice", "Hello Alice")
+	if len(rememberingFacts) != maxRememberingFacts || len(model.prompts) != 0 {
+		t.Error("expected the turn skipped when too many turns are being remembered")
+	}
+}
+
+func TestUserMemoryCollection(t *testing.T) {
+	alice := userMemoryCollection("default", "app", "alice")
+	if alice == userMemoryCollection("default", "app", "bob") || alice == userMemoryCollection("default", "other", "alice") {
+		t.Error("expected the facts of each user in each app kept in its own collection")
+	}
+	if !strings.HasPrefix(alice, "memory-") || alice != userMemoryCollection("default", "app", "alice") {
+		t.Errorf("unexpected collection %s", alice)
+	}
+}
@@ -195,9 +195,11 @@ func UpdateDatasource(ctx context.Context, c client.Client, input *generated.Upd
 
 	// Update postgresql
 	if input.Pginput != nil {
-		datasource.Spec.PostgreSQL = &v1alpha1.PostgreSQL{
-			Database: input.Pginput.Database,
+		// keep the other fields like sources which can't be updated by apiserver
+		if datasource.Spec.PostgreSQL == nil {
+			datasource.Spec.PostgreSQL = &v1alpha1.PostgreSQL{}
 		}
+		datasource.Spec.PostgreSQL.Database = input.Pginput.Database
 	}
 
 	// Update webinput
@@ -139,6 +139,7 @@ func knowledgebase2model(ctx context.Context, c client.Client, knowledgebase *v1
 						UpdateTimestamp: new(time.Time),
 						Version:         detail.Version,
 						LatestVersion:   detailStat.VersionID,
+						Attempts:        detail.Attempts,
 					}
 					*filegroupdetails[v[0]].Filedetails[v[1]].UpdateTimestamp = detail.LastUpdateTime.Time
 				}
@@ -395,6 +396,22 @@ func DeleteKnowledgeBase(ctx context.Context, c client.Client, name, namespace,
 	return nil, err
 }
 
+// RetryFailedFiles asks the controller to process the failed files of a knowledgebase again
+func RetryFailedFiles(ctx context.Context, c client.Client, name, namespace string) (*generated.KnowledgeBase, error) {
+	kb := &v1alpha1.KnowledgeBase{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, kb); err != nil {
+		return nil, err
+	}
+	if kb.Annotations == nil {
+		kb.Annotations = make(map[string]string)
+	}
+	kb.Annotations[v1alpha1.UpdateSourceFileAnnotationKey] = v1alpha1.UpdateSourceFileForFailed
+	if err := c.Update(ctx, kb); err != nil {
+		return nil, err
+	}
+	return knowledgebase2model(ctx, c, kb)
+}
+
 func ReadKnowledgeBase(ctx context.Context, c client.Client, name, namespace string) (*generated.KnowledgeBase, error) {
 	kb := &v1alpha1.KnowledgeBase{}
 	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, kb)
@@ -34,6 +34,8 @@ import (
 	"github.com/kubeagi/arcadia/apiserver/pkg/llm"
 	"github.com/kubeagi/arcadia/apiserver/pkg/worker"
 	llmspkg "github.com/kubeagi/arcadia/pkg/llms"
+	"github.com/kubeagi/arcadia/pkg/llms/anthropic"
+	"github.com/kubeagi/arcadia/pkg/llms/dashscope"
 	"github.com/kubeagi/arcadia/pkg/llms/openai"
 	"github.com/kubeagi/arcadia/pkg/llms/zhipuai"
 )
@@ -318,6 +320,12 @@ func CheckModelService(ctx context.Context, c client.Client, input generated.Cre
 			info, err = checkOpenAI(ctx, input)
 		case "zhipuai":
 			info, err = checkZhipuAI(ctx, input)
+		case "dashscope":
+			info, err = checkDashScope(ctx, input)
+		case "anthropic":
+			info, err = checkAnthropic(ctx, input)
+		case "openai-compatible":
+			info, err = checkOpenAICompatible(ctx, input)
 		default:
 			err = fmt.Errorf("not support api type %s", *input.APIType)
 		}
@@ -369,3 +377,47 @@ func checkZhipuAI(ctx context.Context, input generated.CreateModelServiceInput)
 	}
 	return res.String(), nil
 }
+
+func checkDashScope(ctx context.Context, input generated.CreateModelServiceInput) (string, error) {
+	apiKey := input.Endpoint.Auth["apiKey"].(string)
+	client := dashscope.NewDashScope(apiKey, false, dashscope.WithBaseURL(input.Endpoint.URL))
+
+	// use the first model if specified by user
+	var options []llms.CallOption
+	if input.LlmModels != nil && len(input.LlmModels) > 0 {
+		options = append(options, llms.WithModel(input.LlmModels[0]))
+	}
+	res, err := client.Validate(ctx, options...)
+	if err != nil {
+		return "", err
+	}
+	return res.String(), nil
+}
+
+func checkAnthropic(ctx context.Context, input generated.CreateModelServiceInput) (string, error) {
+	apiKey := input.Endpoint.Auth["apiKey"].(string)
+	client := anthropic.NewAnthropic(apiKey, input.Endpoint.URL)
+
+	// use the first model if specified by user
+	var options []llms.CallOption
+	if input.LlmModels != nil && len(input.LlmModels) > 0 {
+		options = append(options, llms.WithModel(input.LlmModels[0]))
+	}
+	res, err := client.Validate(ctx, options...)
+	if err != nil {
+		return "", err
+	}
+	return res.String(), nil
+}
+
+func checkOpenAICompatible(ctx context.Context, input generated.CreateModelServiceInput) (string, error) {
+	apiKey := input.Endpoint.Auth["apiKey"].(string)
+	client := openai.NewOpenAICompatible(apiKey, input.Endpoint.URL)
+
+	// list models to check the service is reachable
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("models: %s", strings.Join(models, ",")), nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -66,6 +67,7 @@ func NewChatService(cli runtimeclient.Client, isGpts bool) (*ChatService, error)
 // @Param			request		body		chat.ChatReqBody	true	"query params"
 // @Success		200			{object}	chat.ChatRespBody	"blocking mode, will return all field; streaming mode, only conversation_id, message and created_at will be returned"
 // @Failure		400			{object}	chat.ErrorResp
+// @Failure		429			{object}	chat.ErrorResp	"quota exceeded"
 // @Failure		500			{object}	chat.ErrorResp
 // @Router			/chat [post]
 func (cs *ChatService) ChatHandler() gin.HandlerFunc {
@@ -75,8 +77,22 @@ func (cs *ChatService) ChatHandler() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
 			return
 		}
+		if req.Query == "" && req.RegenerateMessageID == "" {
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: "query is required"})
+			return
+		}
 		req.AppNamespace = NamespaceInHeader(c)
 		req.Debug = c.Query("debug") == "true"
+		sharedApp(c, &req.APPName, &req.Debug)
+		if err := cs.server.CheckQuota(c.Request.Context(), req.APPName, req.AppNamespace); err != nil {
+			quotaErr := &chat.QuotaExceededError{}
+			if errors.As(err, &quotaErr) {
+				c.JSON(http.StatusTooManyRequests, chat.ErrorResp{Err: err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
 		req.NewChat = len(req.ConversationID) == 0
 		if req.NewChat {
 			req.ConversationID = string(uuid.NewUUID())
@@ -320,13 +336,90 @@ func (cs *ChatService) DeleteConversationHandler() gin.HandlerFunc {
 	}
 }
 
+// @Summary	search conversations
+// @Schemes
+// @Description	search the queries and answers in current user's conversations, the matched words are highlighted with <em> in the snippets
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Param			namespace	header		string				true	"namespace this request is in"
+// @Param			request		body		chat.SearchReqBody	true	"query params"
+// @Success		200			{object}	chat.SearchRespBody
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/conversations/search [post]
+func (cs *ChatService) SearchConversationHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.SearchReqBody{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "searchConversationHandler: error binding json")
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.AppNamespace = NamespaceInHeader(c)
+		resp, err := cs.server.SearchConversations(c.Request.Context(), req)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error search conversations")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		klog.FromContext(c.Request.Context()).V(3).Info("search conversations done", "req", req, "total", resp.Total)
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary	export conversations
+// @Schemes
+// @Description	export current user's conversations with all messages as a json or markdown file
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Produce		text/markdown
+// @Param			namespace	header		string					true	"namespace this request is in"
+// @Param			request		body		chat.ExportReqBody		true	"query params"
+// @Success		200			{object}	[]storage.Conversation	"json format returns the conversations, markdown format returns a markdown file"
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/conversations/export [post]
+func (cs *ChatService) ExportConversationHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.ExportReqBody{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "exportConversationHandler: error binding json")
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.AppNamespace = NamespaceInHeader(c)
+		conversations, err := cs.server.ExportConversations(c.Request.Context(), req)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error export conversations")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		fileName := "conversations-" + time.Now().Format("20060102150405")
+		if req.Format == chat.ExportFormatMarkdown {
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.md", fileName))
+			c.Header("Content-Type", "text/markdown; charset=utf-8")
+			c.Status(http.StatusOK)
+			if err := chat.WriteMarkdown(c.Writer, conversations); err != nil {
+				klog.FromContext(c.Request.Context()).Error(err, "error write markdown")
+			}
+		} else {
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", fileName))
+			c.JSON(http.StatusOK, conversations)
+		}
+		klog.FromContext(c.Request.Context()).V(3).Info("export conversations done", "req", req, "count", len(conversations))
+	}
+}
+
 // @Summary	get all messages history for one conversation
 // @Schemes
-// @Description	get all messages history for one conversation
+// @Description	get the messages of the selected branch with the sibling ids of each message, or all messages as a tree linked by parent_id when tree is true
 // @Tags			application
 // @Accept			json
 // @Produce		json
 // @Param			namespace	header		string						true	"namespace this request is in"
+// @Param			tree		query		bool						false	"return all messages of the conversation as a tree"
 // @Param			request		body		chat.ConversationReqBody	true	"query params"
 // @Success		200			{object}	storage.Conversation
 // @Failure		400			{object}	chat.ErrorResp
@@ -341,7 +434,7 @@ func (cs *ChatService) HistoryHandler() gin.HandlerFunc {
 			return
 		}
 		req.AppNamespace = NamespaceInHeader(c)
-		resp, err := cs.server.ListMessages(c.Request.Context(), req)
+		resp, err := cs.server.ListMessages(c.Request.Context(), req, c.Query("tree") == "true")
 		if err != nil {
 			klog.FromContext(c.Request.Context()).Error(err, "error list messages")
 			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
@@ -394,6 +487,107 @@ func (cs *ChatService) ReferenceHandler() gin.HandlerFunc {
 	}
 }
 
+// @Summary	rate the answer of a message
+// @Schemes
+// @Description	rate the answer of a message with like or dislike, the reason, comment and corrected answer are optional
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Param			namespace	header		string					true	"namespace this request is in"
+// @Param			messageID	path		string					true	"messageID"
+// @Param			request		body		chat.FeedbackReqBody	true	"query params"
+// @Success		200			{object}	storage.Feedback
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/messages/:messageID/feedback [post]
+func (cs *ChatService) FeedbackHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.FeedbackReqBody{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "feedbackHandler: error binding json")
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.MessageID = c.Param("messageID")
+		req.AppNamespace = NamespaceInHeader(c)
+		resp, err := cs.server.UpdateFeedback(c.Request.Context(), req)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error update message feedback")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		klog.FromContext(c.Request.Context()).V(3).Info("update message feedback done", "req", req)
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary	delete the feedback of a message
+// @Schemes
+// @Description	delete current user's feedback on a message
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Param			namespace	header		string				true	"namespace this request is in"
+// @Param			messageID	path		string				true	"messageID"
+// @Param			request		body		chat.MessageReqBody	true	"query params"
+// @Success		200			{object}	chat.SimpleResp
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/messages/:messageID/feedback [delete]
+func (cs *ChatService) DeleteFeedbackHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.MessageReqBody{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "deleteFeedbackHandler: error binding json")
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.MessageID = c.Param("messageID")
+		req.AppNamespace = NamespaceInHeader(c)
+		if err := cs.server.DeleteFeedback(c.Request.Context(), req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error delete message feedback")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		klog.FromContext(c.Request.Context()).V(3).Info("delete message feedback done", "req", req)
+		c.JSON(http.StatusOK, chat.SimpleResp{Message: "ok"})
+	}
+}
+
+// @Summary	select the branch of a message
+// @Schemes
+// @Description	select the branch of a message to continue the conversation from, the latest messages following it are selected as well
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Param			namespace	header		string				true	"namespace this request is in"
+// @Param			messageID	path		string				true	"messageID"
+// @Param			request		body		chat.MessageReqBody	true	"query params"
+// @Success		200			{object}	storage.Conversation
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/messages/:messageID/active [post]
+func (cs *ChatService) SelectMessageHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.MessageReqBody{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "selectMessageHandler: error binding json")
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.MessageID = c.Param("messageID")
+		req.AppNamespace = NamespaceInHeader(c)
+		resp, err := cs.server.SelectMessage(c.Request.Context(), req)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error select message")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		klog.FromContext(c.Request.Context()).V(3).Info("select message done", "req", req)
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
 // @Summary	get app's prompt starters
 // @Schemes
 // @Description	get app's prompt starters
@@ -454,15 +648,33 @@ func registerChat(g *gin.RouterGroup, conf config.ServerConfig) {
 	if err != nil {
 		panic(err)
 	}
+	// purge the expired conversations by the retentions in config
+	go chatService.server.RunRetention(context.Background(), chat.RetentionInterval)
 
-	g.POST("", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ChatHandler()) // chat with bot
+	g.POST("", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ChatHandler())                                         // chat with bot
+	g.GET("/ws", auth.WebSocketCredentials(), auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ChatWebSocketHandler()) // chat with bot in websocket
 
 	g.POST("/conversations/file", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ChatFile())                               // upload fles for conversation
 	g.POST("/conversations", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ListConversationHandler())                     // list conversations
 	g.DELETE("/conversations/:conversationID", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.DeleteConversationHandler()) // delete conversation
+	g.POST("/conversations/search", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.SearchConversationHandler())            // search conversations
+	g.POST("/conversations/export", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ExportConversationHandler())            // export conversations
 
-	g.POST("/messages", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.HistoryHandler())                         // messages history
-	g.POST("/messages/:messageID/references", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ReferenceHandler()) // messages reference
+	g.POST("/messages", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.HistoryHandler())                              // messages history
+	g.POST("/messages/:messageID/references", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ReferenceHandler())      // messages reference
+	g.POST("/messages/:messageID/feedback", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.FeedbackHandler())         // rate the answer
+	g.DELETE("/messages/:messageID/feedback", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.DeleteFeedbackHandler()) // delete the rating
+	g.POST("/messages/:messageID/active", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.SelectMessageHandler())      // select the branch
 
 	g.POST("/prompt-starter", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.PromptStartersHandler())
+
+	g.POST("/share-tokens", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "update", "applications"), requestid.RequestIDInterceptor(), chatService.CreateShareTokenHandler())            // create a share token
+	g.GET("/share-tokens", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "update", "applications"), requestid.RequestIDInterceptor(), chatService.ListShareTokensHandler())              // list share tokens
+	g.DELETE("/share-tokens/:tokenID", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "update", "applications"), requestid.RequestIDInterceptor(), chatService.RevokeShareTokenHandler()) // revoke a share token
+
+	// for the anonymous users with share tokens
+	g.GET("/public/widget.js", chatService.WidgetHandler())                                                                                                     // the embeddable chat widget
+	g.GET("/public/app", chatService.ShareTokenInterceptor(), requestid.RequestIDInterceptor(), chatService.SharedAppHandler())                                 // the shared application
+	g.POST("/public", chatService.ShareTokenInterceptor(), requestid.RequestIDInterceptor(), chatService.ChatHandler())                                         // chat with the shared application
+	g.GET("/public/ws", auth.WebSocketCredentials(), chatService.ShareTokenInterceptor(), requestid.RequestIDInterceptor(), chatService.ChatWebSocketHandler()) // chat with the shared application in websocket
 }
@@ -0,0 +1,393 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+const (
+	// time allowed to write a frame to the client
+	wsWriteWait = 10 * time.Second
+	// maximum size of a frame from the client
+	wsMaxFrameSize = 1 << 20
+	// maximum number of messages answering at the same time in a socket
+	wsMaxConcurrentMessages = 5
+)
+
+var (
+	// the client is dead if no pong or frame is received in this time
+	wsPongWait = 60 * time.Second
+	// ping the client in this period, must be less than wsPongWait
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// any origin is allowed the same as Cors, the token is checked by AuthInterceptor
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// chatRunner answers the messages in a websocket, which is the chat server
+type chatRunner interface {
+	CheckQuota(ctx context.Context, appName, appNamespace string) error
+	AppRun(ctx context.Context, req chat.ChatReqBody, respStream chan string, messageID string, timeout *float64) (*chat.ChatRespBody, error)
+	ReverifyShareToken(ctx context.Context, token *storage.ShareToken) error
+}
+
+// wsSession is a chat websocket, which answers several messages at the same time
+type wsSession struct {
+	runner    chatRunner
+	conn      *websocket.Conn
+	namespace string
+	debug     bool
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// @Summary	chat with application in websocket
+// @Schemes
+// @Description	chat with application in websocket. The client sends chat.WebSocketRequest frames to ask, continue or cancel a message,
+// @Description	and the server sends chat.WebSocketEvent frames with the same id. Browsers can pass the token and namespace in query.
+// @Tags			application
+// @Param			namespace	header		string					true	"namespace this request is in"
+// @Param			token		query		string					false	"the bearer token, for the clients can't set the header"
+// @Param			debug		query		bool					false	"Should the chat request be treated as debugging?"
+// @Param			request		body		chat.WebSocketRequest	false	"frames sent by the client"
+// @Success		101			{object}	chat.WebSocketEvent		"frames sent by the server"
+// @Failure		400			{object}	chat.ErrorResp
+// @Router			/chat/ws [get]
+func (cs *ChatService) ChatWebSocketHandler() gin.HandlerFunc {
+	return chatWebSocketHandler(cs.server)
+}
+
+func chatWebSocketHandler(runner chatRunner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := klog.FromContext(c.Request.Context())
+		conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// the upgrader already replied the error
+			logger.Error(err, "failed to upgrade to websocket")
+			return
+		}
+		s := &wsSession{
+			runner:    runner,
+			conn:      conn,
+			namespace: NamespaceInHeader(c),
+			debug:     c.Query("debug") == "true",
+			running:   make(map[string]context.CancelFunc),
+		}
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer func() {
+			// stop the running messages and wait them to finish before closing the connection
+			cancel()
+			s.wg.Wait()
+			_ = conn.Close()
+			logger.Info("chat websocket closed")
+		}()
+		logger.Info("chat websocket connected")
+		expiry, _ := auth.TokenExpiryFromContext(c.Request.Context())
+		if token := chat.ShareTokenFromContext(ctx); token != nil && token.ExpiresAt != nil {
+			expiry = *token.ExpiresAt
+		}
+		go s.heartbeat(ctx, expiry)
+		s.readLoop(ctx)
+	}
+}
+
+// heartbeat pings the client periodically, the read deadline is exceeded if the client is dead.
+// The token is verified only in the handshake, so the websocket is closed when the token expires.
+func (s *wsSession) heartbeat(ctx context.Context, expiry time.Time) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	var expired <-chan time.Time
+	if !expiry.IsZero() {
+		timer := time.NewTimer(time.Until(expiry))
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			klog.FromContext(ctx).Info("the token is expired, close the websocket")
+			s.close(websocket.ClosePolicyViolation, "token expired")
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				klog.FromContext(ctx).V(3).Info("failed to ping the client", "err", err)
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) readLoop(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	s.conn.SetReadLimit(wsMaxFrameSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Info("chat websocket is broken", "err", err)
+			}
+			return
+		}
+		// any frame from the client proves it is alive
+		_ = s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		frame := chat.WebSocketRequest{}
+		if err := json.Unmarshal(data, &frame); err != nil {
+			s.send(ctx, chat.WebSocketEvent{Type: chat.EventError, Error: "invalid frame: " + err.Error()})
+			continue
+		}
+		switch frame.Type {
+		case chat.FrameChat, chat.FrameContinue:
+			// the share token may be revoked after the handshake
+			if token := chat.ShareTokenFromContext(ctx); token != nil {
+				if err := s.runner.ReverifyShareToken(ctx, token); err != nil {
+					s.send(ctx, chat.WebSocketEvent{Type: chat.EventError, ID: frame.ID, Error: err.Error()})
+					if errors.Is(err, chat.ErrInvalidShareToken) {
+						s.close(websocket.ClosePolicyViolation, err.Error())
+						return
+					}
+					continue
+				}
+			}
+			if err := s.start(ctx, frame); err != nil {
+				s.send(ctx, chat.WebSocketEvent{Type: chat.EventError, ID: frame.ID, Error: err.Error()})
+			}
+		case chat.FrameCancel:
+			s.cancel(frame.ID)
+		default:
+			s.send(ctx, chat.WebSocketEvent{Type: chat.EventError, ID: frame.ID, Error: fmt.Sprintf("unknown frame type %q", frame.Type)})
+		}
+	}
+}
+
+// start validates the chat request and answers it in the background
+func (s *wsSession) start(ctx context.Context, frame chat.WebSocketRequest) error {
+	if frame.ID == "" {
+		return errors.New("id is required")
+	}
+	if frame.Chat == nil {
+		return errors.New("chat is required")
+	}
+	req := *frame.Chat
+	if frame.Type == chat.FrameContinue {
+		if req.ConversationID == "" || req.ParentMessageID == "" {
+			return errors.New("conversation_id and parent_message_id are required to continue")
+		}
+		req.ContinueMessageID = req.ParentMessageID
+		req.Query = ""
+		req.Files = nil
+		req.RegenerateMessageID = ""
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return err
+	}
+	if req.Query == "" && req.RegenerateMessageID == "" && req.ContinueMessageID == "" {
+		return errors.New("query is required")
+	}
+	req.StartTime = time.Now()
+	req.AppNamespace = s.namespace
+	req.Debug = s.debug
+	if token := chat.ShareTokenFromContext(ctx); token != nil {
+		req.APPName = token.AppName
+		req.Debug = false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.running[frame.ID]; ok {
+		return fmt.Errorf("message %s is running", frame.ID)
+	}
+	if len(s.running) >= wsMaxConcurrentMessages {
+		return fmt.Errorf("too many messages running, at most %d", wsMaxConcurrentMessages)
+	}
+	runCtx, cancel := context.WithCancel(klog.NewContext(ctx, klog.FromContext(ctx).WithValues("wsMessageID", frame.ID)))
+	s.running[frame.ID] = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, frame.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+		s.run(runCtx, cancel, frame.ID, req)
+	}()
+	return nil
+}
+
+func (s *wsSession) cancel(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+}
+
+type wsRunResult struct {
+	response *chat.ChatRespBody
+	err      error
+}
+
+// run answers a message, sends the pieces of the answer as message events in streaming mode,
+// and finishes with one of done, error and cancelled events
+func (s *wsSession) run(ctx context.Context, cancel context.CancelFunc, id string, req chat.ChatReqBody) {
+	logger := klog.FromContext(ctx)
+	if err := s.runner.CheckQuota(ctx, req.APPName, req.AppNamespace); err != nil {
+		s.send(ctx, chat.WebSocketEvent{Type: chat.EventError, ID: id, Error: err.Error()})
+		return
+	}
+	req.NewChat = len(req.ConversationID) == 0
+	if req.NewChat {
+		req.ConversationID = string(uuid.NewUUID())
+	}
+	// the answer of the continued message is extended, so the events have its id
+	messageID := req.ContinueMessageID
+	if messageID == "" {
+		messageID = string(uuid.NewUUID())
+	}
+	chatTimeoutSecond := pointer.Float64(WaitTimeoutForChatStreaming)
+	var respStream chan string
+	// idle is the timer to stop the stream if no data from llm for a long time
+	var idleTimer *time.Timer
+	var idle <-chan time.Time
+	if req.ResponseMode.IsStreaming() {
+		respStream = make(chan string, 1)
+		idleTimer = time.NewTimer(time.Second * WaitTimeoutForChatStreaming)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
+	done := make(chan wsRunResult, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				logger.Error(fmt.Errorf("get err:%#v", e), "A panic occurred when run chat.AppRun")
+				done <- wsRunResult{err: fmt.Errorf("internal error: %v", e)}
+			}
+		}()
+		response, err := s.runner.AppRun(ctx, req, respStream, messageID, chatTimeoutSecond)
+		done <- wsRunResult{response: response, err: err}
+	}()
+
+	timedOut := false
+	for {
+		select {
+		case msg := <-respStream:
+			s.sendPiece(ctx, id, req, messageID, msg)
+			// the timeout of the app is set before any piece is sent
+			idleTimer.Reset(time.Duration(*chatTimeoutSecond * float64(time.Second)))
+		case <-idle:
+			logger.Info("no data from LLM for a long time, stop the message")
+			timedOut = true
+			cancel()
+		case result := <-done:
+			// the last piece may be still in the channel
+			select {
+			case msg := <-respStream:
+				s.sendPiece(ctx, id, req, messageID, msg)
+			default:
+			}
+			event := chat.WebSocketEvent{ID: id}
+			switch {
+			case timedOut:
+				event.Type = chat.EventError
+				event.Error = "no data from LLM for a long time"
+			case result.err != nil && ctx.Err() != nil:
+				event.Type = chat.EventCancelled
+			case result.err != nil:
+				event.Type = chat.EventError
+				event.Error = result.err.Error()
+			default:
+				event.Type = chat.EventDone
+				event.Data = result.response
+			}
+			if event.Data == nil {
+				event.Data = &chat.ChatRespBody{
+					ConversationID: req.ConversationID,
+					MessageID:      messageID,
+					CreatedAt:      time.Now(),
+					Latency:        time.Since(req.StartTime).Milliseconds(),
+				}
+			}
+			s.send(ctx, event)
+			logger.Info("chat done", "event", event.Type)
+			return
+		}
+	}
+}
+
+func (s *wsSession) sendPiece(ctx context.Context, id string, req chat.ChatReqBody, messageID, msg string) {
+	s.send(ctx, chat.WebSocketEvent{
+		Type: chat.EventMessage,
+		ID:   id,
+		Data: &chat.ChatRespBody{
+			ConversationID: req.ConversationID,
+			MessageID:      messageID,
+			Message:        msg,
+			CreatedAt:      time.Now(),
+			Latency:        time.Since(req.StartTime).Milliseconds(),
+		},
+	})
+}
+
+// send writes an event to the client, the connection is closed if failed, which stops the read loop
+func (s *wsSession) send(ctx context.Context, event chat.WebSocketEvent) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := s.conn.WriteJSON(event); err != nil {
+		klog.FromContext(ctx).V(3).Info("failed to send the event, close the websocket", "err", err)
+		_ = s.conn.Close()
+	}
+}
+
+// close sends a close frame with the reason and closes the connection, which stops the read loop
+func (s *wsSession) close(code int, reason string) {
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteWait))
+	_ = s.conn.Close()
+}
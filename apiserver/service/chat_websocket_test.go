@@ -0,0 +1,304 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+func TestChatWebSocketInvalidFrames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/chat/ws", (&ChatService{}).ChatWebSocketHandler())
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/chat/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	testCases := []struct {
+		frame string
+		id    string
+		err   string
+	}{
+		{frame: `not json`, err: "invalid frame"},
+		{frame: `{"type":"unknown","id":"1"}`, id: "1", err: `unknown frame type "unknown"`},
+		{frame: `{"type":"chat"}`, err: "id is required"},
+		{frame: `{"type":"chat","id":"2"}`, id: "2", err: "chat is required"},
+		{frame: `{"type":"chat","id":"3","chat":{"app_name":"app","response_mode":"streaming"}}`, id: "3", err: "query is required"},
+		{frame: `{"type":"continue","id":"4","chat":{"app_name":"app","response_mode":"streaming"}}`, id: "4", err: "parent_message_id are required"},
+	}
+	for _, tc := range testCases {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(tc.frame)); err != nil {
+			t.Fatal(err)
+		}
+		event := chat.WebSocketEvent{}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != chat.EventError || event.ID != tc.id || !strings.Contains(event.Error, tc.err) {
+			t.Errorf("frame %s: want error %q of %q, got %+v", tc.frame, tc.err, tc.id, event)
+		}
+	}
+}
+
+// fakeRunner answers with the query, and blocks until the message is cancelled if block is set
+type fakeRunner struct {
+	block   bool
+	revoked bool
+
+	mu   sync.Mutex
+	reqs []chat.ChatReqBody
+}
+
+func (r *fakeRunner) CheckQuota(ctx context.Context, appName, appNamespace string) error {
+	return nil
+}
+
+func (r *fakeRunner) AppRun(ctx context.Context, req chat.ChatReqBody, respStream chan string, messageID string, timeout *float64) (*chat.ChatRespBody, error) {
+	r.mu.Lock()
+	r.reqs = append(r.reqs, req)
+	r.mu.Unlock()
+	if respStream != nil {
+		respStream <- "piece"
+	}
+	if r.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &chat.ChatRespBody{ConversationID: req.ConversationID, MessageID: messageID, Message: req.Query}, nil
+}
+
+func (r *fakeRunner) ReverifyShareToken(ctx context.Context, token *storage.ShareToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.revoked {
+		return chat.ErrInvalidShareToken
+	}
+	return nil
+}
+
+func dialChatWebSocket(t *testing.T, runner chatRunner, handlers ...gin.HandlerFunc) *websocket.Conn {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/chat/ws", append(handlers, chatWebSocketHandler(runner))...)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/chat/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func chatRequest(query string) *chat.ChatReqBody {
+	req := &chat.ChatReqBody{Query: query, ResponseMode: chat.Streaming}
+	req.APPName = "app"
+	return req
+}
+
+// readEvent reads the events until the one of the type
+func readEvent(t *testing.T, conn *websocket.Conn, eventType chat.WebSocketFrameType) chat.WebSocketEvent {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for {
+		event := chat.WebSocketEvent{}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("expected %s event, but got error %v", eventType, err)
+		}
+		if event.Type == eventType {
+			return event
+		}
+		if event.Type != chat.EventMessage {
+			t.Fatalf("expected %s event, but got %+v", eventType, event)
+		}
+	}
+}
+
+func TestChatWebSocketCancel(t *testing.T) {
+	conn := dialChatWebSocket(t, &fakeRunner{block: true})
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: "1", Chat: chatRequest("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if event := readEvent(t, conn, chat.EventMessage); event.ID != "1" || event.Data.Message != "piece" {
+		t.Fatalf("expected the piece of message 1, but got %+v", event)
+	}
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameCancel, ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if event := readEvent(t, conn, chat.EventCancelled); event.ID != "1" || event.Data == nil || event.Data.MessageID == "" {
+		t.Errorf("expected message 1 is cancelled, but got %+v", event)
+	}
+}
+
+func TestChatWebSocketConcurrencyLimit(t *testing.T) {
+	conn := dialChatWebSocket(t, &fakeRunner{block: true})
+	for i := 0; i < wsMaxConcurrentMessages; i++ {
+		if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: fmt.Sprint(i), Chat: chatRequest("hi")}); err != nil {
+			t.Fatal(err)
+		}
+		readEvent(t, conn, chat.EventMessage)
+	}
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: "0", Chat: chatRequest("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if event := readEvent(t, conn, chat.EventError); event.ID != "0" || !strings.Contains(event.Error, "message 0 is running") {
+		t.Errorf("expected message 0 is running, but got %+v", event)
+	}
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: "extra", Chat: chatRequest("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if event := readEvent(t, conn, chat.EventError); event.ID != "extra" || !strings.Contains(event.Error, "too many messages running") {
+		t.Errorf("expected too many messages running, but got %+v", event)
+	}
+	// the limit is released when a message is finished
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameCancel, ID: "0"}); err != nil {
+		t.Fatal(err)
+	}
+	readEvent(t, conn, chat.EventCancelled)
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: "extra", Chat: chatRequest("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if event := readEvent(t, conn, chat.EventMessage); event.ID != "extra" {
+		t.Errorf("expected the piece of message extra, but got %+v", event)
+	}
+}
+
+func TestChatWebSocketContinue(t *testing.T) {
+	runner := &fakeRunner{}
+	conn := dialChatWebSocket(t, runner)
+	req := chatRequest("ignored")
+	req.ConversationID = "c1"
+	req.ParentMessageID = "m1"
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameContinue, ID: "1", Chat: req}); err != nil {
+		t.Fatal(err)
+	}
+	event := readEvent(t, conn, chat.EventDone)
+	if event.ID != "1" || event.Data.ConversationID != "c1" || event.Data.MessageID != "m1" {
+		t.Errorf("expected the answer of message m1 is extended, but got %+v", event)
+	}
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	if len(runner.reqs) != 1 || runner.reqs[0].ContinueMessageID != "m1" || runner.reqs[0].Query != "" || runner.reqs[0].NewChat {
+		t.Errorf("expected the request to continue m1 without query, but got %+v", runner.reqs)
+	}
+}
+
+func TestChatWebSocketPongTimeout(t *testing.T) {
+	pongWait, pingPeriod := wsPongWait, wsPingPeriod
+	wsPongWait, wsPingPeriod = 300*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() { wsPongWait, wsPingPeriod = pongWait, pingPeriod })
+
+	// the client replies pong automatically when reading, it is alive after several pong waits
+	alive := dialChatWebSocket(t, &fakeRunner{})
+	_ = alive.SetReadDeadline(time.Now().Add(3 * wsPongWait))
+	if _, _, err := alive.ReadMessage(); !isTimeout(err) {
+		t.Errorf("expected the alive client is not closed, but got %v", err)
+	}
+
+	// the client without pong is closed after the pong wait
+	dead := dialChatWebSocket(t, &fakeRunner{})
+	dead.SetPingHandler(func(string) error { return nil })
+	_ = dead.SetReadDeadline(time.Now().Add(3 * wsPongWait))
+	if _, _, err := dead.ReadMessage(); err == nil || isTimeout(err) {
+		t.Errorf("expected the dead client is closed, but got %v", err)
+	}
+}
+
+// withShareToken chats as an anonymous user with the share token
+func withShareToken(token *storage.ShareToken) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, err := chat.WithShareToken(c.Request.Context(), token, "0123456789abcdef")
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Request = c.Request.WithContext(ctx)
+	}
+}
+
+func TestChatWebSocketRevokedShareToken(t *testing.T) {
+	runner := &fakeRunner{}
+	conn := dialChatWebSocket(t, runner, withShareToken(&storage.ShareToken{ID: "t1", AppName: "app"}))
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: "1", Chat: chatRequest("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	readEvent(t, conn, chat.EventDone)
+
+	runner.mu.Lock()
+	runner.revoked = true
+	runner.mu.Unlock()
+	if err := conn.WriteJSON(chat.WebSocketRequest{Type: chat.FrameChat, ID: "2", Chat: chatRequest("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if event := readEvent(t, conn, chat.EventError); event.ID != "2" || event.Error != chat.ErrInvalidShareToken.Error() {
+		t.Errorf("expected the share token is invalid, but got %+v", event)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("expected the websocket is closed for policy violation, but got %v", err)
+	}
+}
+
+func TestChatWebSocketExpiredShareToken(t *testing.T) {
+	expiresAt := time.Now().Add(200 * time.Millisecond)
+	conn := dialChatWebSocket(t, &fakeRunner{}, withShareToken(&storage.ShareToken{ID: "t1", AppName: "app", ExpiresAt: &expiresAt}))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	closeErr := &websocket.CloseError{}
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "token expired" {
+		t.Errorf("expected the websocket is closed as the token expired, but got %v", err)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	testCases := map[string]string{
+		"Bearer st_abc":  "st_abc",
+		"bearer st_abc":  "st_abc",
+		"BEARER  st_abc": "st_abc",
+		"Basic st_abc":   "",
+		"Bearer ":        "",
+		"st_abc":         "",
+	}
+	for header, want := range testCases {
+		if got := bearerToken(header); got != want {
+			t.Errorf("expected token %q in %q, but got %q", want, header, got)
+		}
+	}
+}
+
+func isTimeout(err error) bool {
+	e, ok := err.(interface{ Timeout() bool })
+	return ok && e.Timeout()
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/config"
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	apiserverclient "github.com/kubeagi/arcadia/apiserver/pkg/client"
+	"github.com/kubeagi/arcadia/apiserver/pkg/oidc"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/worker"
+)
+
+// GatewayService forwards the requests to the llm gateway(the fastchat api server),
+// the requests to the autoscaling workers are counted to drive the autoscaling, and the workers scaled to zero are woken up.
+type GatewayService struct {
+	c client.Client
+}
+
+// ProxyHandler forwards the request to the same path under the gateway api server
+func (gs *GatewayService) ProxyHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		gateway, err := pkgconfig.GetGateway(ctx)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		target, err := url.Parse(gateway.APIServer)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("invalid gateway api server: %s", err)})
+			return
+		}
+
+		if model := requestModel(c.Request); model != "" {
+			w, err := worker.WorkerOfModel(ctx, gs.c, model)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+				return
+			}
+			if w != nil {
+				done, err := worker.TrackRequest(ctx, gs.c, w)
+				if err != nil {
+					c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"message": err.Error()})
+					return
+				}
+				defer done()
+			}
+		}
+
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetURL(target)
+				r.Out.URL.Path = strings.TrimSuffix(target.Path, "/") + c.Param("path")
+				r.Out.URL.RawPath = ""
+				r.Out.Host = target.Host
+			},
+			// stream the responses without buffering
+			FlushInterval: -1,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				klog.FromContext(r.Context()).Error(err, "failed to forward the request to the gateway")
+				w.WriteHeader(http.StatusBadGateway)
+			},
+		}
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// requestModel returns the model in the json body of the request, the body is kept for forwarding
+func requestModel(r *http.Request) string {
+	if r.Body == nil || r.Method != http.MethodPost {
+		return ""
+	}
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	req := struct {
+		Model string `json:"model"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return req.Model
+}
+
+func registerGateway(g *gin.RouterGroup, conf config.ServerConfig) {
+	c, err := apiserverclient.GetClient(nil)
+	if err != nil {
+		panic(err)
+	}
+	gs := &GatewayService{c: c}
+	g.Any("/*path", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "workers"), gs.ProxyHandler()) // llm apis of the workers
+}
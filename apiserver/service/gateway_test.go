@@ -0,0 +1,99 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	pkgconfig "github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+func TestGatewayProxy(t *testing.T) {
+	var path, body string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path + "?" + r.URL.RawQuery
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_, _ = w.Write([]byte(`{"object":"chat.completion"}`))
+	}))
+	defer upstream.Close()
+
+	t.Setenv(utils.EnvNamespaceKey, "arcadia")
+	t.Setenv(pkgconfig.EnvConfigKey, pkgconfig.EnvConfigDefaultValue)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "arcadia", Name: pkgconfig.EnvConfigDefaultValue},
+		Data:       map[string]string{"config": "gateway:\n  apiServer: " + upstream.URL + "/v1\n"},
+	}
+	worker := &v1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker", UID: "uid-a"},
+		Spec:       v1alpha1.WorkerSpec{Autoscaling: &v1alpha1.WorkerAutoscaling{MaxReplicas: 2}},
+		Status:     v1alpha1.WorkerStatus{Autoscaling: &v1alpha1.WorkerAutoscalingStatus{Replicas: 1, ReadyReplicas: 1}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm, worker).Build()
+	pkgconfig.InitSystemClient(c)
+	defer pkgconfig.InitSystemClient(nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Any("/gateway/*path", (&GatewayService{c: c}).ProxyHandler())
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	for _, model := range []string{"uid-a", "unknown"} {
+		request := `{"model":"` + model + `","messages":[{"role":"user","content":"hi"}]}`
+		resp, err := http.Post(server.URL+"/gateway/chat/completions?stream=false", "application/json", strings.NewReader(request))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(data) != `{"object":"chat.completion"}` {
+			t.Errorf("%s: unexpected response %d %s", model, resp.StatusCode, data)
+		}
+		if path != "/v1/chat/completions?stream=false" || body != request {
+			t.Errorf("%s: expected the request forwarded, but got %s %s", model, path, body)
+		}
+	}
+}
+
+func TestRequestModel(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/gateway/embeddings", strings.NewReader(`{"model":"uid-a","input":"hi"}`))
+	if model := requestModel(req); model != "uid-a" {
+		t.Errorf("expected model uid-a, but got %q", model)
+	}
+	if data, _ := io.ReadAll(req.Body); string(data) != `{"model":"uid-a","input":"hi"}` {
+		t.Errorf("expected the body kept, but got %s", data)
+	}
+	if model := requestModel(httptest.NewRequest(http.MethodGet, "/gateway/models", nil)); model != "" {
+		t.Errorf("expected no model in get requests, but got %q", model)
+	}
+}
@@ -37,14 +37,20 @@ func registerGptsChat(g *gin.RouterGroup, conf config.ServerConfig) {
 		panic(err)
 	}
 
-	g.POST("", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ChatHandler()) // chat with bot
+	g.POST("", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ChatHandler())                                         // chat with bot
+	g.GET("/ws", auth.WebSocketCredentials(), auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ChatWebSocketHandler()) // chat with bot in websocket
 
 	g.POST("/conversations/file", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ChatFile())                               // upload fles for conversation
 	g.POST("/conversations", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ListConversationHandler())                     // list conversations
 	g.DELETE("/conversations/:conversationID", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.DeleteConversationHandler()) // delete conversation
-
-	g.POST("/messages", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.HistoryHandler())                         // messages history
-	g.POST("/messages/:messageID/references", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ReferenceHandler()) // messages reference
+	g.POST("/conversations/search", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.SearchConversationHandler())            // search conversations
+	g.POST("/conversations/export", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ExportConversationHandler())            // export conversations
+
+	g.POST("/messages", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.HistoryHandler())                              // messages history
+	g.POST("/messages/:messageID/references", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.ReferenceHandler())      // messages reference
+	g.POST("/messages/:messageID/feedback", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.FeedbackHandler())         // rate the answer
+	g.DELETE("/messages/:messageID/feedback", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.DeleteFeedbackHandler()) // delete the rating
+	g.POST("/messages/:messageID/active", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.SelectMessageHandler())      // select the branch
 
 	g.POST("/prompt-starter", auth.AuthTokenIsValid(conf.EnableOIDC, oidc.Verifier), requestid.RequestIDInterceptor(), chatService.PromptStartersHandler())
 }
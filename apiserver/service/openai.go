@@ -0,0 +1,238 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/apiserver/config"
+	"github.com/kubeagi/arcadia/apiserver/pkg/auth"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat"
+	"github.com/kubeagi/arcadia/apiserver/pkg/client"
+	"github.com/kubeagi/arcadia/apiserver/pkg/oidc"
+	"github.com/kubeagi/arcadia/apiserver/pkg/requestid"
+)
+
+const (
+	openAIErrorTypeInvalidRequest = "invalid_request_error"
+	openAIErrorTypeRateLimit      = "rate_limit_exceeded"
+	openAIErrorTypeServer         = "server_error"
+)
+
+func openAIError(c *gin.Context, code int, errType string, err error) {
+	c.AbortWithStatusJSON(code, chat.OpenAIErrorResp{Error: chat.OpenAIError{Message: err.Error(), Type: errType}})
+}
+
+// OpenAIModelNamespace sets the namespace in header from the model of chat completions request,
+// so the permission is checked in the namespace of the application
+func OpenAIModelNamespace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.OpenAIChatCompletionReqBody{}
+		if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+			openAIError(c, http.StatusBadRequest, openAIErrorTypeInvalidRequest, err)
+			return
+		}
+		namespace, _ := chat.ParseOpenAIModel(req.Model, NamespaceInHeader(c))
+		if headerNamespace := NamespaceInHeader(c); headerNamespace != "" && headerNamespace != namespace {
+			openAIError(c, http.StatusBadRequest, openAIErrorTypeInvalidRequest, fmt.Errorf("model %s is not in namespace %s", req.Model, headerNamespace))
+			return
+		}
+		c.Request.Header.Set(namespaceHeader, namespace)
+		c.Next()
+	}
+}
+
+// @Summary	chat with application in OpenAI's chat completions format
+// @Schemes
+// @Description	chat with application in OpenAI's chat completions format, the model is the application in `namespace/name` format
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Param			namespace	header		string								false	"namespace this request is in, can be omitted if the model contains namespace"
+// @Param			request		body		chat.OpenAIChatCompletionReqBody	true	"query params"
+// @Success		200			{object}	chat.OpenAIChatCompletionRespBody	"blocking mode returns a chat.completion object; streaming mode returns chat.completion.chunk objects as Server-Sent Events"
+// @Failure		400			{object}	chat.OpenAIErrorResp
+// @Failure		429			{object}	chat.OpenAIErrorResp	"quota exceeded"
+// @Failure		500			{object}	chat.OpenAIErrorResp
+// @Router			/v1/chat/completions [post]
+func (cs *ChatService) ChatCompletionsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		openAIReq := chat.OpenAIChatCompletionReqBody{}
+		if err := c.ShouldBindBodyWith(&openAIReq, binding.JSON); err != nil {
+			openAIError(c, http.StatusBadRequest, openAIErrorTypeInvalidRequest, err)
+			return
+		}
+		req, err := openAIReq.ToChatReqBody(NamespaceInHeader(c))
+		if err != nil {
+			openAIError(c, http.StatusBadRequest, openAIErrorTypeInvalidRequest, err)
+			return
+		}
+		if err := cs.server.CheckQuota(c.Request.Context(), req.APPName, req.AppNamespace); err != nil {
+			quotaErr := &chat.QuotaExceededError{}
+			if errors.As(err, &quotaErr) {
+				openAIError(c, http.StatusTooManyRequests, openAIErrorTypeRateLimit, err)
+				return
+			}
+			openAIError(c, http.StatusInternalServerError, openAIErrorTypeServer, err)
+			return
+		}
+		req.NewChat = len(req.ConversationID) == 0
+		if req.NewChat {
+			req.ConversationID = string(uuid.NewUUID())
+		}
+		model := chat.OpenAIModelOfApp(req.AppNamespace, req.APPName)
+		messageID := string(uuid.NewUUID())
+		logger := klog.FromContext(c.Request.Context())
+		chatTimeoutSecond := pointer.Float64(WaitTimeoutForChatStreaming)
+
+		if !req.ResponseMode.IsStreaming() {
+			response, err := cs.server.AppRun(c.Request.Context(), req, nil, messageID, chatTimeoutSecond)
+			if err != nil {
+				logger.Error(err, "error resp")
+				openAIError(c, http.StatusInternalServerError, openAIErrorTypeServer, err)
+				return
+			}
+			c.JSON(http.StatusOK, chat.NewOpenAIChatCompletion(model, response))
+			logger.Info("chat completion done", "model", model)
+			return
+		}
+
+		var response *chat.ChatRespBody
+		respStream := make(chan string, 1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() {
+				if e := recover(); e != nil {
+					err = fmt.Errorf("a panic occurred when run chat.AppRun: %v", e)
+				}
+			}()
+			response, err = cs.server.AppRun(c.Request.Context(), req, respStream, messageID, chatTimeoutSecond)
+		}()
+
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+		c.Writer.Header().Set("Cache-Control", "no-cache")
+		c.Writer.Header().Set("Connection", "keep-alive")
+		streamed := false
+		writeData := func(w io.Writer, data any) {
+			raw, _ := json.Marshal(data)
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", raw)
+		}
+		clientDisconnected := c.Stream(func(w io.Writer) bool {
+			select {
+			case msg := <-respStream:
+				writeData(w, chat.NewOpenAIChatCompletionChunk(model, req.ConversationID, messageID, msg))
+				streamed = true
+				return true
+			case <-done:
+				// the messages sent before the run is done
+				for len(respStream) > 0 {
+					writeData(w, chat.NewOpenAIChatCompletionChunk(model, req.ConversationID, messageID, <-respStream))
+					streamed = true
+				}
+				if err != nil {
+					logger.Error(err, "error resp, stop the stream")
+					writeData(w, chat.OpenAIErrorResp{Error: chat.OpenAIError{Message: err.Error(), Type: openAIErrorTypeServer}})
+					return false
+				}
+				// the answer is not streamed when the application does not support streaming
+				if !streamed && response.Message != "" {
+					writeData(w, chat.NewOpenAIChatCompletionChunk(model, req.ConversationID, messageID, response.Message))
+				}
+				last := chat.NewOpenAIChatCompletionChunk(model, req.ConversationID, messageID, "")
+				last.Choices[0].Delta = &chat.OpenAIMessage{}
+				last.Choices[0].FinishReason = pointer.String(chat.OpenAIFinishReasonStop)
+				last.References = response.References
+				writeData(w, last)
+				_, _ = io.WriteString(w, "data: [DONE]\n\n")
+				return false
+			}
+		})
+		if clientDisconnected {
+			logger.Info("chatCompletionsHandler: the client is disconnected")
+			// drain the stream so the application run would not be blocked
+			go func() {
+				for {
+					select {
+					case <-respStream:
+					case <-done:
+						return
+					}
+				}
+			}()
+			return
+		}
+		logger.Info("chat completion done", "model", model)
+	}
+}
+
+// @Summary	list applications as the models of chat completions
+// @Schemes
+// @Description	list the ready applications in the namespace as the models of chat completions
+// @Tags			application
+// @Produce		json
+// @Param			namespace	header		string	true	"namespace this request is in"
+// @Success		200			{object}	chat.OpenAIModelList
+// @Failure		500			{object}	chat.OpenAIErrorResp
+// @Router			/v1/models [get]
+func (cs *ChatService) ListModelsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace := NamespaceInHeader(c)
+		apps, err := cs.server.ListApps(c.Request.Context(), namespace)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error list applications")
+			openAIError(c, http.StatusInternalServerError, openAIErrorTypeServer, err)
+			return
+		}
+		models := chat.OpenAIModelList{Object: chat.OpenAIObjectList, Data: make([]chat.OpenAIModel, 0, len(apps))}
+		for _, app := range apps {
+			models.Data = append(models.Data, chat.OpenAIModel{
+				ID:      chat.OpenAIModelOfApp(app.Namespace, app.Name),
+				Object:  chat.OpenAIObjectModel,
+				Created: app.CreationTimestamp.Unix(),
+				OwnedBy: app.Namespace,
+			})
+		}
+		c.JSON(http.StatusOK, models)
+	}
+}
+
+func registerOpenAI(g *gin.RouterGroup, conf config.ServerConfig) {
+	c, err := client.GetClient(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	chatService, err := NewChatService(c, false)
+	if err != nil {
+		panic(err)
+	}
+
+	g.POST("/chat/completions", OpenAIModelNamespace(), auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "get", "applications"), requestid.RequestIDInterceptor(), chatService.ChatCompletionsHandler()) // chat with application in OpenAI's format
+	g.GET("/models", auth.AuthInterceptor(conf.EnableOIDC, oidc.Verifier, v1alpha1.GroupVersion, "list", "applications"), requestid.RequestIDInterceptor(), chatService.ListModelsHandler())                                        // list applications as models
+}
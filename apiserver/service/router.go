@@ -36,7 +36,7 @@ func Cors() gin.HandlerFunc {
 		origin := c.GetHeader("origin")
 		c.Header("Access-Control-Allow-Origin", origin)
 		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, UPDATE")
-		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Authorization, namespace, Referer, User-Agent")
+		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Authorization, namespace, visitor, Referer, User-Agent")
 		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		if method == "OPTIONS" {
@@ -80,8 +80,16 @@ func NewServerAndRun(conf config.ServerConfig) {
 		gptsGroup := r.Group("/gpts/chat")
 		registerGptsChat(gptsGroup, conf)
 
+		// for OpenAI compatible chat completions with applications
+		openAIGroup := r.Group("/v1")
+		registerOpenAI(openAIGroup, conf)
+
 		fg := r.Group("/forward")
 		registerForward(fg, conf)
+
+		// for the llm apis of the workers, which are counted for autoscaling
+		gatewayGroup := r.Group("/gateway")
+		registerGateway(gatewayGroup, conf)
 	}
 
 	//  for swagger
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	_ "embed"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat"
+	"github.com/kubeagi/arcadia/apiserver/pkg/chat/storage"
+)
+
+// widgetScript is the embeddable chat widget, which chats with the application by a share token
+//
+//go:embed static/widget.js
+var widgetScript []byte
+
+// shareVisitorHeader is the random secret kept by the anonymous user, which identifies the conversations of the user
+const shareVisitorHeader = "visitor"
+
+// bearerToken returns the token in the Authorization header, the scheme is case-insensitive
+func bearerToken(header string) string {
+	if len(header) < 6 || !strings.EqualFold(header[:6], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(header[6:])
+}
+
+// ShareTokenInterceptor authenticates the anonymous users by the share token in the bearer header,
+// the namespace of the request is the namespace of the shared application.
+// The visitor secret in the header, or in the query for websocket, separates the conversations of the anonymous users.
+func (cs *ChatService) ShareTokenInterceptor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c.GetHeader("Authorization"))
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, chat.ErrorResp{Err: "share token is required"})
+			return
+		}
+		visitor := c.GetHeader(shareVisitorHeader)
+		if visitor == "" {
+			visitor = c.Query(shareVisitorHeader)
+		}
+		shareToken, err := cs.server.VerifyShareToken(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, chat.ErrInvalidShareToken) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, chat.ErrorResp{Err: err.Error()})
+				return
+			}
+			klog.FromContext(c.Request.Context()).Error(err, "failed to verify share token")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		ctx, err := chat.WithShareToken(c.Request.Context(), shareToken, visitor)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		c.Request.Header.Set(namespaceHeader, shareToken.AppNamespace)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// sharedApp forces the request to the application of the share token, and disables debugging for anonymous users
+func sharedApp(c *gin.Context, appName *string, debug *bool) {
+	if token := chat.ShareTokenFromContext(c.Request.Context()); token != nil {
+		*appName = token.AppName
+		*debug = false
+	}
+}
+
+// @Summary	create a share token of application
+// @Schemes
+// @Description	create a share token, which allows the anonymous users to chat with the application until it expires or is revoked.
+// @Description	The token is only returned in this response.
+// @Tags			application
+// @Accept			json
+// @Produce		json
+// @Param			namespace	header		string					true	"namespace this request is in"
+// @Param			request		body		chat.ShareTokenReqBody	true	"query params"
+// @Success		200			{object}	chat.ShareTokenRespBody
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/share-tokens [post]
+func (cs *ChatService) CreateShareTokenHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.ShareTokenReqBody{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "createShareTokenHandler: error binding json")
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.AppNamespace = NamespaceInHeader(c)
+		resp, err := cs.server.CreateShareToken(c.Request.Context(), req)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error create share token")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		klog.FromContext(c.Request.Context()).Info("create share token done", "appName", req.APPName, "appNamespace", req.AppNamespace, "tokenID", resp.ID)
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary	list the share tokens of application
+// @Schemes
+// @Description	list the share tokens of application, including the expired and revoked ones
+// @Tags			application
+// @Produce		json
+// @Param			namespace	header		string	true	"namespace this request is in"
+// @Param			app_name	query		string	true	"the name of the application"
+// @Success		200			{object}	[]storage.ShareToken
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/share-tokens [get]
+func (cs *ChatService) ListShareTokensHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.APPMetadata{}
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.AppNamespace = NamespaceInHeader(c)
+		resp, err := cs.server.ListShareTokens(c.Request.Context(), req)
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error list share tokens")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary	revoke a share token of application
+// @Schemes
+// @Description	revoke a share token, the anonymous users can't chat with it any more
+// @Tags			application
+// @Produce		json
+// @Param			namespace	header		string	true	"namespace this request is in"
+// @Param			tokenID		path		string	true	"the id of the share token"
+// @Param			app_name	query		string	true	"the name of the application"
+// @Success		200			{object}	chat.SimpleResp
+// @Failure		400			{object}	chat.ErrorResp
+// @Failure		404			{object}	chat.ErrorResp
+// @Failure		500			{object}	chat.ErrorResp
+// @Router			/chat/share-tokens/{tokenID} [delete]
+func (cs *ChatService) RevokeShareTokenHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := chat.APPMetadata{}
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		req.AppNamespace = NamespaceInHeader(c)
+		tokenID := c.Param("tokenID")
+		if err := cs.server.RevokeShareToken(c.Request.Context(), req, tokenID); err != nil {
+			if errors.Is(err, storage.ErrShareTokenNotFound) {
+				c.JSON(http.StatusNotFound, chat.ErrorResp{Err: err.Error()})
+				return
+			}
+			klog.FromContext(c.Request.Context()).Error(err, "error revoke share token")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		klog.FromContext(c.Request.Context()).Info("revoke share token done", "appName", req.APPName, "appNamespace", req.AppNamespace, "tokenID", tokenID)
+		c.JSON(http.StatusOK, chat.SimpleResp{Message: "ok"})
+	}
+}
+
+// @Summary	get the shared application
+// @Schemes
+// @Description	get the information of the application shared by the token, for the anonymous users
+// @Tags			application
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer share token"
+// @Param			visitor			header		string	true	"the random secret of the anonymous user, at least 16 characters"
+// @Success		200				{object}	chat.SharedAppRespBody
+// @Failure		401				{object}	chat.ErrorResp
+// @Failure		500				{object}	chat.ErrorResp
+// @Router			/chat/public/app [get]
+func (cs *ChatService) SharedAppHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := cs.server.GetSharedApp(c.Request.Context(), chat.ShareTokenFromContext(c.Request.Context()))
+		if err != nil {
+			klog.FromContext(c.Request.Context()).Error(err, "error get shared app")
+			c.JSON(http.StatusInternalServerError, chat.ErrorResp{Err: err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// @Summary	the embeddable chat widget
+// @Schemes
+// @Description	the script of the chat widget, which can be embedded in any page with a share token:
+// @Description	`<script src="<apiserver>/chat/public/widget.js" data-token="<share token>" async></script>`
+// @Tags			application
+// @Produce		application/javascript
+// @Success		200
+// @Router			/chat/public/widget.js [get]
+func (cs *ChatService) WidgetHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.Data(http.StatusOK, "application/javascript; charset=utf-8", widgetScript)
+	}
+}
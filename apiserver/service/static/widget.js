@@ -0,0 +1,170 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The embeddable chat widget of arcadia applications. Embed it in any page with a share token:
+//
+//   <script src="https://<apiserver>/chat/public/widget.js" data-token="<share token>" async></script>
+//
+// Optional attributes: data-title overrides the name of the application, data-color is the theme color.
+(function () {
+  "use strict";
+
+  var script = document.currentScript;
+  if (!script || !script.dataset.token) {
+    console.error("arcadia widget: data-token is required");
+    return;
+  }
+  var token = script.dataset.token;
+  var color = script.dataset.color || "#1677ff";
+  var base = script.src.replace(/\/widget\.js(\?.*)?$/, "");
+  var storageKey = "arcadia-widget-" + token.slice(-8);
+  var visitor = localStorage.getItem(storageKey + "-visitor");
+  if (!visitor) {
+    // the random secret of this browser, only the conversations started with it are found by it
+    var bytes = new Uint8Array(24);
+    crypto.getRandomValues(bytes);
+    visitor = Array.prototype.map.call(bytes, function (b) { return ("0" + b.toString(16)).slice(-2); }).join("");
+    localStorage.setItem(storageKey + "-visitor", visitor);
+  }
+
+  var app = null;
+  var conversationID = sessionStorage.getItem(storageKey) || "";
+  var socket = null;
+  var pending = [];
+  var seq = 0;
+  var answers = {};
+
+  function el(tag, style, text) {
+    var e = document.createElement(tag);
+    if (style) e.style.cssText = style;
+    if (text) e.textContent = text;
+    return e;
+  }
+
+  var button = el("button", "position:fixed;right:24px;bottom:24px;width:56px;height:56px;border:none;border-radius:50%;" +
+    "background:" + color + ";color:#fff;font-size:24px;cursor:pointer;box-shadow:0 4px 12px rgba(0,0,0,.2);z-index:2147483646", "✉");
+  var panel = el("div", "position:fixed;right:24px;bottom:92px;width:360px;max-width:calc(100vw - 48px);height:520px;" +
+    "max-height:calc(100vh - 120px);display:none;flex-direction:column;background:#fff;border-radius:12px;overflow:hidden;" +
+    "box-shadow:0 8px 24px rgba(0,0,0,.2);font:14px/1.5 sans-serif;color:#222;z-index:2147483647");
+  var header = el("div", "padding:12px 16px;background:" + color + ";color:#fff;font-weight:bold", script.dataset.title || "");
+  var list = el("div", "flex:1;overflow-y:auto;padding:12px 16px");
+  var form = el("form", "display:flex;border-top:1px solid #eee");
+  var input = el("input", "flex:1;border:none;padding:12px 16px;outline:none;font:inherit");
+  var send = el("button", "border:none;background:none;color:" + color + ";padding:0 16px;cursor:pointer;font:inherit", "Send");
+  input.placeholder = "Ask a question...";
+  send.type = "submit";
+  form.appendChild(input);
+  form.appendChild(send);
+  panel.appendChild(header);
+  panel.appendChild(list);
+  panel.appendChild(form);
+
+  function bubble(text, mine) {
+    var row = el("div", "display:flex;margin:6px 0;justify-content:" + (mine ? "flex-end" : "flex-start"));
+    var b = el("div", "max-width:80%;padding:8px 12px;border-radius:12px;white-space:pre-wrap;word-break:break-word;" +
+      (mine ? "background:" + color + ";color:#fff" : "background:#f2f3f5"), text);
+    row.appendChild(b);
+    list.appendChild(row);
+    list.scrollTop = list.scrollHeight;
+    return b;
+  }
+
+  function request(path) {
+    return fetch(base + path, { headers: { Authorization: "Bearer " + token, visitor: visitor } }).then(function (resp) {
+      return resp.json().then(function (body) {
+        if (!resp.ok) throw new Error(body.error || resp.statusText);
+        return body;
+      });
+    });
+  }
+
+  function connect() {
+    var url = base.replace(/^http/, "ws") + "/ws?token=" + encodeURIComponent(token) + "&visitor=" + visitor;
+    socket = new WebSocket(url);
+    socket.onopen = function () {
+      while (pending.length) socket.send(pending.shift());
+    };
+    socket.onmessage = function (e) {
+      var event = JSON.parse(e.data);
+      var answer = answers[event.id];
+      if (!answer) return;
+      if (event.data && event.data.conversation_id) {
+        conversationID = event.data.conversation_id;
+        sessionStorage.setItem(storageKey, conversationID);
+      }
+      switch (event.type) {
+        case "message":
+          answer.textContent += event.data.message;
+          break;
+        case "done":
+          if (event.data.message) answer.textContent = event.data.message;
+          delete answers[event.id];
+          break;
+        case "error":
+          answer.textContent = event.error || "Something went wrong, please try again later.";
+          delete answers[event.id];
+          break;
+        default:
+          delete answers[event.id];
+      }
+      list.scrollTop = list.scrollHeight;
+    };
+    socket.onclose = function () {
+      socket = null;
+      Object.keys(answers).forEach(function (id) {
+        if (!answers[id].textContent) answers[id].textContent = "The connection is closed, please try again.";
+        delete answers[id];
+      });
+    };
+  }
+
+  form.addEventListener("submit", function (e) {
+    e.preventDefault();
+    var query = input.value.trim();
+    if (!query || !app) return;
+    input.value = "";
+    bubble(query, true);
+    var id = String(++seq);
+    answers[id] = bubble("", false);
+    var frame = JSON.stringify({
+      type: "chat",
+      id: id,
+      chat: { query: query, response_mode: "streaming", app_name: app.app_name, conversation_id: conversationID }
+    });
+    if (socket && socket.readyState === WebSocket.OPEN) {
+      socket.send(frame);
+      return;
+    }
+    pending.push(frame);
+    if (!socket) connect();
+  });
+
+  button.addEventListener("click", function () {
+    var open = panel.style.display === "none";
+    panel.style.display = open ? "flex" : "none";
+    if (open) input.focus();
+  });
+
+  request("/app").then(function (resp) {
+    app = resp;
+    if (!header.textContent) header.textContent = app.display_name;
+    if (app.prologue) bubble(app.prologue, false);
+    document.body.appendChild(panel);
+    document.body.appendChild(button);
+  }).catch(function (err) {
+    console.error("arcadia widget: failed to load the application", err);
+  });
+})();
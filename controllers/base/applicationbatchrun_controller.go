@@ -0,0 +1,480 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/minio/minio-go/v7"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/batchrun"
+	"github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/datasource"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+// batchRunReportInterval is the min interval to update the run progress into status
+const batchRunReportInterval = 5 * time.Second
+
+// batchRuns are the running batch runs by uid
+var batchRuns sync.Map
+
+// batchRunning is a batch run running in background
+type batchRunning struct {
+	// cancel stops the run with the reason
+	cancel context.CancelCauseFunc
+	// generation of the spec the run started with
+	generation int64
+}
+
+var (
+	errBatchRunDeleted     = errors.New("the ApplicationBatchRun is deleted")
+	errBatchRunSuspended   = errors.New("cancelled as the ApplicationBatchRun is suspended")
+	errBatchRunSpecChanged = errors.New("cancelled as the spec of the ApplicationBatchRun is changed")
+)
+
+// ApplicationBatchRunReconciler reconciles an ApplicationBatchRun object
+type ApplicationBatchRunReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=applicationbatchruns,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=applicationbatchruns/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=applicationbatchruns/finalizers,verbs=update
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=applications,verbs=get;list;watch
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=datasources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=versioneddatasets,verbs=get;list;watch
+
+// Reconcile starts a run when it is due, the run answers the questions in background and reports the progress in status.
+func (r *ApplicationBatchRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(5).Info("Starting application batch run reconcile")
+
+	instance := &arcadiav1alpha1.ApplicationBatchRun{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		// There's no need to requeue if the resource no longer exists.
+		// Otherwise, we'll be requeued implicitly because we return an error.
+		logger.V(1).Info("Failed to get ApplicationBatchRun")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Add a finalizer to stop the running run before the ApplicationBatchRun is deleted
+	if newAdded := controllerutil.AddFinalizer(instance, arcadiav1alpha1.Finalizer); newAdded {
+		logger.Info("Try to add Finalizer for ApplicationBatchRun")
+		if err := r.Update(ctx, instance); err != nil {
+			logger.Error(err, "Failed to update ApplicationBatchRun to add finalizer, will try again later")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Adding Finalizer for ApplicationBatchRun done")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if instance.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(instance, arcadiav1alpha1.Finalizer) {
+		logger.Info("Performing Finalizer Operations for ApplicationBatchRun before delete CR")
+		// the answer files are kept as reports
+		r.CancelRun(instance)
+		controllerutil.RemoveFinalizer(instance, arcadiav1alpha1.Finalizer)
+		if err := r.Update(ctx, instance); err != nil {
+			logger.Error(err, "Failed to remove finalizer for ApplicationBatchRun")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Remove ApplicationBatchRun done")
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileRun(ctx, logger, instance)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ApplicationBatchRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arcadiav1alpha1.ApplicationBatchRun{}, builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(ue event.UpdateEvent) bool {
+				oldRun := ue.ObjectOld.(*arcadiav1alpha1.ApplicationBatchRun)
+				newRun := ue.ObjectNew.(*arcadiav1alpha1.ApplicationBatchRun)
+				return !reflect.DeepEqual(oldRun.Spec, newRun.Spec) || newRun.DeletionTimestamp != nil
+			},
+		})).
+		Complete(r)
+}
+
+// CancelRun stops the running run of the ApplicationBatchRun, which is deleted
+func (r *ApplicationBatchRunReconciler) CancelRun(instance *arcadiav1alpha1.ApplicationBatchRun) {
+	if running, ok := batchRuns.LoadAndDelete(instance.UID); ok {
+		running.(*batchRunning).cancel(errBatchRunDeleted)
+	}
+}
+
+func (r *ApplicationBatchRunReconciler) reconcileRun(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.ApplicationBatchRun) (ctrl.Result, error) {
+	if v, ok := batchRuns.Load(instance.UID); ok {
+		// the running run is cancelled and records the reason itself, a new run starts after it stops if due
+		running := v.(*batchRunning)
+		switch {
+		case instance.Spec.Suspend:
+			logger.Info("Cancel the running run as suspended")
+			running.cancel(errBatchRunSuspended)
+			return ctrl.Result{RequeueAfter: waitSmaller}, nil
+		case running.generation != instance.Generation:
+			logger.Info("Cancel the running run as the spec is changed")
+			running.cancel(errBatchRunSpecChanged)
+			return ctrl.Result{RequeueAfter: waitSmaller}, nil
+		}
+		return ctrl.Result{RequeueAfter: waitMedium}, nil
+	}
+	key := client.ObjectKeyFromObject(instance)
+	// the run is not in this process, it was interrupted when the controller restarted
+	if last := instance.LastRun(); last != nil && last.Phase == arcadiav1alpha1.BatchRunPhaseRunning {
+		record := last.DeepCopy()
+		record.Phase = arcadiav1alpha1.BatchRunPhaseFailed
+		record.Message = "interrupted as the controller restarted"
+		now := metav1.Now()
+		record.CompletionTime = &now
+		logger.Info("Mark the interrupted run failed", "run", record.ID)
+		return ctrl.Result{Requeue: true}, r.patchRun(ctx, key, record, instance.ErrorCondition(record.Message), nil)
+	}
+
+	due, next, err := r.nextRun(instance, time.Now())
+	if err != nil {
+		// wait for the spec to be fixed
+		instance.Status.SetConditions(instance.ErrorCondition(err.Error()))
+		return ctrl.Result{}, r.Client.Status().Update(ctx, instance)
+	}
+	if !due {
+		if !reflect.DeepEqual(instance.Status.NextRunTime, next) {
+			instance.Status.NextRunTime = next
+			if err := r.Client.Status().Update(ctx, instance); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if next == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Until(next.Time)}, nil
+	}
+
+	start := metav1.Now()
+	runID := start.Format(arcadiav1alpha1.BatchRunIDLayout)
+	record := &arcadiav1alpha1.BatchRunRecord{ID: runID, Phase: arcadiav1alpha1.BatchRunPhaseRunning, StartTime: &start}
+	instance.Status.Runs = append([]arcadiav1alpha1.BatchRunRecord{*record}, instance.Status.Runs...)
+	if limit := instance.Spec.HistoryLimit; limit > 0 && len(instance.Status.Runs) > limit {
+		instance.Status.Runs = instance.Status.Runs[:limit]
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.NextRunTime = nil
+	instance.Status.SetConditions(instance.RunningCondition())
+	if err := r.Client.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the run takes a long time, it runs in background until done, or is cancelled when the ApplicationBatchRun is
+	// deleted, suspended or its spec is changed
+	runCtx, cancel := context.WithCancelCause(context.Background())
+	batchRuns.Store(instance.UID, &batchRunning{cancel: cancel, generation: instance.Generation})
+	logger = logger.WithValues("run", runID)
+	logger.Info("Start to run the questions")
+	go func() {
+		defer cancel(nil)
+		r.run(runCtx, logger, instance.DeepCopy(), record)
+	}()
+	return ctrl.Result{RequeueAfter: waitMedium}, nil
+}
+
+// nextRun returns whether a run is due now, and the time of the next run if not
+func (r *ApplicationBatchRunReconciler) nextRun(instance *arcadiav1alpha1.ApplicationBatchRun, now time.Time) (bool, *metav1.Time, error) {
+	if instance.Spec.Suspend {
+		return false, nil, nil
+	}
+	last := instance.LastRun()
+	if instance.Spec.Schedule == "" {
+		// run once, and run again when the spec changes
+		return last == nil || instance.Status.ObservedGeneration != instance.Generation, nil, nil
+	}
+	cron, err := utils.ParseCron(instance.Spec.Schedule)
+	if err != nil {
+		return false, nil, err
+	}
+	from := instance.CreationTimestamp.Time
+	if last != nil && last.StartTime != nil {
+		from = last.StartTime.Time
+	}
+	next := cron.Next(from)
+	if next.IsZero() {
+		return false, nil, nil
+	}
+	if !next.After(now) {
+		return true, nil, nil
+	}
+	return false, &metav1.Time{Time: next}, nil
+}
+
+// run answers the questions and writes the answers, the status is updated with the progress
+func (r *ApplicationBatchRunReconciler) run(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.ApplicationBatchRun, record *arcadiav1alpha1.BatchRunRecord) {
+	key := client.ObjectKeyFromObject(instance)
+	err := r.runQuestions(ctx, logger, instance, record)
+	cause := context.Cause(ctx)
+	// nothing to record as the ApplicationBatchRun is deleted
+	if errors.Is(cause, errBatchRunDeleted) {
+		return
+	}
+	// the run is removed after the status is updated, so that the next run starts after this one is recorded
+	defer batchRuns.Delete(instance.UID)
+	now := metav1.Now()
+	record.CompletionTime = &now
+	condition := instance.ReadyCondition()
+	if cause != nil {
+		logger.Info("Run the questions cancelled", "reason", cause.Error())
+		record.Phase = arcadiav1alpha1.BatchRunPhaseCancelled
+		record.Message = cause.Error()
+	} else if err != nil {
+		logger.Error(err, "Failed to run the questions")
+		record.Phase = arcadiav1alpha1.BatchRunPhaseFailed
+		record.Message = err.Error()
+		condition = instance.ErrorCondition(fmt.Sprintf("run %s failed: %s", record.ID, err))
+	} else {
+		record.Phase = arcadiav1alpha1.BatchRunPhaseSucceeded
+		logger.Info("Run the questions done", "total", record.Total, "succeeded", record.Succeeded, "failed", record.Failed, "output", record.Output)
+	}
+	var next *metav1.Time
+	if _, n, err := r.nextRun(instance, time.Now()); err == nil {
+		next = n
+	}
+	// the status is updated even if the controller is stopping
+	if err := r.patchRun(context.Background(), key, record, condition, next); err != nil {
+		logger.Error(err, "Failed to update the run status")
+	}
+}
+
+func (r *ApplicationBatchRunReconciler) runQuestions(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.ApplicationBatchRun, record *arcadiav1alpha1.BatchRunRecord) error {
+	app := &arcadiav1alpha1.Application{}
+	appRef := instance.Spec.Application
+	if err := r.Get(ctx, types.NamespacedName{Namespace: appRef.GetNamespace(instance.Namespace), Name: appRef.Name}, app); err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	if !app.Status.IsReady() {
+		return fmt.Errorf("application %s is not ready", app.Name)
+	}
+	answer, err := batchrun.NewAppAnswerFunc(ctx, r.Client, app)
+	if err != nil {
+		return fmt.Errorf("failed to init application: %w", err)
+	}
+	columns, rows, err := r.readQuestions(ctx, instance)
+	if err != nil {
+		return fmt.Errorf("failed to read questions: %w", err)
+	}
+	record.Total = len(rows)
+	logger.Info("Read the questions done", "total", record.Total)
+
+	// the progress is reported periodically, as the questions may be answered quickly in parallel
+	var mu sync.Mutex
+	var latest *batchrun.Progress
+	done := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		ticker := time.NewTicker(batchRunReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				p := latest
+				latest = nil
+				mu.Unlock()
+				if p == nil {
+					continue
+				}
+				running := record.DeepCopy()
+				setRunProgress(running, *p)
+				if err := r.patchRun(ctx, client.ObjectKeyFromObject(instance), running, instance.RunningCondition(), nil); err != nil {
+					logger.Error(err, "Failed to update the run progress")
+				}
+			}
+		}
+	}()
+	results, progress := batchrun.Run(ctx, rows, answer, batchrun.Options{
+		Concurrency: instance.Spec.Concurrency,
+		Timeout:     time.Duration(instance.Spec.TimeoutSeconds) * time.Second,
+		OnProgress: func(p batchrun.Progress) {
+			mu.Lock()
+			latest = &p
+			mu.Unlock()
+		},
+	})
+	close(done)
+	<-reported
+	setRunProgress(record, progress)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	format := instance.OutputFormat()
+	if err := batchrun.WriteResults(buf, format, columns, results); err != nil {
+		return fmt.Errorf("failed to write answers: %w", err)
+	}
+	object := instance.OutputObject(record.ID)
+	if err := r.writeAnswers(ctx, instance.Namespace, object, format, buf); err != nil {
+		return fmt.Errorf("failed to write answers: %w", err)
+	}
+	record.Output = instance.Namespace + "/" + object
+	return nil
+}
+
+func setRunProgress(record *arcadiav1alpha1.BatchRunRecord, p batchrun.Progress) {
+	record.Total = p.Total
+	record.Succeeded = p.Succeeded
+	record.Failed = p.Failed
+	record.Progress = p.Percentage()
+	record.Failures = p.Failures
+}
+
+// readQuestions reads the question file from the Datasource or VersionedDataset
+func (r *ApplicationBatchRunReconciler) readQuestions(ctx context.Context, instance *arcadiav1alpha1.ApplicationBatchRun) ([]string, []batchrun.Row, error) {
+	format, err := instance.InputFormat()
+	if err != nil {
+		return nil, nil, err
+	}
+	source := instance.Spec.Input.Source
+	if source == nil {
+		return nil, nil, errors.New("input.source is required")
+	}
+	ns := source.GetNamespace(instance.Namespace)
+	info := &arcadiav1alpha1.OSS{Bucket: ns, Object: instance.Spec.Input.File}
+	var ds datasource.Datasource
+	switch strings.ToLower(source.Kind) {
+	case "versioneddataset":
+		versionedDataset := &arcadiav1alpha1.VersionedDataset{}
+		if err := r.Get(ctx, types.NamespacedName{Name: source.Name, Namespace: ns}, versionedDataset); err != nil {
+			return nil, nil, err
+		}
+		if versionedDataset.Spec.Dataset == nil {
+			return nil, nil, errors.New("versionedDataset.Spec.Dataset is nil")
+		}
+		if !versionedDataset.Status.IsReady() {
+			return nil, nil, errDataSourceNotReady
+		}
+		oss, err := config.GetSystemDatasourceOSS(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		ds = oss
+		info.Object = filepath.Join("dataset", versionedDataset.Spec.Dataset.Name, versionedDataset.Spec.Version, instance.Spec.Input.File)
+	case "datasource", "":
+		dsObj := &arcadiav1alpha1.Datasource{}
+		if err := r.Get(ctx, types.NamespacedName{Name: source.Name, Namespace: ns}, dsObj); err != nil {
+			return nil, nil, err
+		}
+		if dsObj.Spec.Type() != arcadiav1alpha1.DatasourceTypeOSS {
+			return nil, nil, fmt.Errorf("datasource type %s is not supported, only oss", dsObj.Spec.Type())
+		}
+		if !dsObj.Status.IsReady() {
+			return nil, nil, errDataSourceNotReady
+		}
+		// set endpoint's auth secret namespace to current datasource if not set
+		endpoint := dsObj.Spec.Endpoint.DeepCopy()
+		if endpoint != nil && endpoint.AuthSecret != nil {
+			endpoint.AuthSecret.WithNameSpace(dsObj.Namespace)
+		}
+		oss, err := datasource.NewOSS(ctx, r.Client, endpoint)
+		if err != nil {
+			return nil, nil, err
+		}
+		ds = oss
+		info.Bucket = dsObj.Spec.OSS.Bucket
+	default:
+		return nil, nil, fmt.Errorf("source type %s not supported yet", source.Kind)
+	}
+	file, err := ds.ReadFile(ctx, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return batchrun.ReadRows(file, format, instance.QuestionColumn())
+}
+
+// writeAnswers writes the answer file into the bucket of the namespace in the system datasource
+func (r *ApplicationBatchRunReconciler) writeAnswers(ctx context.Context, bucket, object string, format arcadiav1alpha1.BatchRunFileFormat, data *bytes.Buffer) error {
+	oss, err := config.GetSystemDatasourceOSS(ctx)
+	if err != nil {
+		return err
+	}
+	exists, err := oss.Client.BucketExists(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err = oss.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return err
+		}
+	}
+	contentType := "application/jsonl"
+	if format == arcadiav1alpha1.BatchRunFileFormatCSV {
+		contentType = "text/csv"
+	}
+	size := int64(data.Len())
+	_, err = oss.Client.PutObject(ctx, bucket, object, io.Reader(data), size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// patchRun replaces the run with the same id in status
+func (r *ApplicationBatchRunReconciler) patchRun(ctx context.Context, key types.NamespacedName, record *arcadiav1alpha1.BatchRunRecord, condition arcadiav1alpha1.Condition, next *metav1.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &arcadiav1alpha1.ApplicationBatchRun{}
+		if err := r.Client.Get(ctx, key, latest); err != nil {
+			return err
+		}
+		found := false
+		for i := range latest.Status.Runs {
+			if latest.Status.Runs[i].ID == record.ID {
+				latest.Status.Runs[i] = *record
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+		latest.Status.NextRunTime = next
+		latest.Status.SetConditions(condition)
+		return r.Client.Status().Update(ctx, latest)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func newBatchRunReconciler(t *testing.T, objs ...client.Object) *ApplicationBatchRunReconciler {
+	scheme := runtime.NewScheme()
+	if err := arcadiav1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return &ApplicationBatchRunReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), Scheme: scheme}
+}
+
+func TestReconcileRunCancel(t *testing.T) {
+	testCases := []struct {
+		name       string
+		suspend    bool
+		generation int64
+		cause      error
+	}{
+		{name: "running", generation: 1},
+		{name: "suspended", suspend: true, generation: 1, cause: errBatchRunSuspended},
+		{name: "spec changed", generation: 2, cause: errBatchRunSpecChanged},
+	}
+	for _, tc := range testCases {
+		instance := &arcadiav1alpha1.ApplicationBatchRun{ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "default", UID: "uid", Generation: tc.generation}}
+		instance.Spec.Suspend = tc.suspend
+		ctx, cancel := context.WithCancelCause(context.Background())
+		batchRuns.Store(instance.UID, &batchRunning{cancel: cancel, generation: 1})
+		r := newBatchRunReconciler(t, instance)
+		if _, err := r.reconcileRun(context.Background(), logr.Discard(), instance); err != nil {
+			t.Errorf("%s: expected no error, but got %v", tc.name, err)
+		}
+		if cause := context.Cause(ctx); !errors.Is(cause, tc.cause) {
+			t.Errorf("%s: expected the run cancelled by %v, but got %v", tc.name, tc.cause, cause)
+		}
+		batchRuns.Delete(instance.UID)
+		cancel(nil)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	start := metav1.Now()
+	record := arcadiav1alpha1.BatchRunRecord{ID: "20240101-020000", Phase: arcadiav1alpha1.BatchRunPhaseRunning, StartTime: &start}
+	instance := &arcadiav1alpha1.ApplicationBatchRun{ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "default", UID: "uid"}}
+	instance.Spec.Application = &arcadiav1alpha1.TypedObjectReference{Name: "app"}
+	instance.Status.Runs = []arcadiav1alpha1.BatchRunRecord{record}
+
+	for _, cause := range []error{errBatchRunDeleted, errBatchRunSuspended} {
+		r := newBatchRunReconciler(t, instance.DeepCopy())
+		ctx, cancel := context.WithCancelCause(context.Background())
+		batchRuns.Store(instance.UID, &batchRunning{cancel: cancel})
+		cancel(cause)
+		r.run(ctx, logr.Discard(), instance.DeepCopy(), record.DeepCopy())
+
+		latest := &arcadiav1alpha1.ApplicationBatchRun{}
+		if err := r.Get(context.Background(), client.ObjectKeyFromObject(instance), latest); err != nil {
+			t.Fatal(err)
+		}
+		got := latest.Status.Runs[0]
+		if cause == errBatchRunDeleted {
+			// the run is removed by CancelRun, and the status is not updated
+			if got.Phase != arcadiav1alpha1.BatchRunPhaseRunning {
+				t.Errorf("expected the run of deleted ApplicationBatchRun not recorded, but got %+v", got)
+			}
+			batchRuns.Delete(instance.UID)
+			continue
+		}
+		if got.Phase != arcadiav1alpha1.BatchRunPhaseCancelled || got.Message != cause.Error() || got.CompletionTime == nil {
+			t.Errorf("expected the run cancelled by %v, but got %+v", cause, got)
+		}
+		if _, ok := batchRuns.Load(instance.UID); ok {
+			t.Errorf("expected the cancelled run removed")
+		}
+	}
+}
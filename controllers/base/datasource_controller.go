@@ -21,9 +21,15 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/minio/minio-go/v7"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,13 +40,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/config"
 	"github.com/kubeagi/arcadia/pkg/datasource"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+const (
+	// crawlRetryDelay is the delay to retry a failed crawl which has no schedule
+	crawlRetryDelay = 10 * time.Minute
+	// gitRefreshInterval is the interval to check the head of the branch a git datasource tracks
+	gitRefreshInterval = 10 * time.Minute
 )
 
 // DatasourceReconciler reconciles a Datasource object
 type DatasourceReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	crawlingMu sync.Mutex
+	// crawling records the web datasources which are being crawled
+	crawling map[string]bool
 }
 
 //+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=datasources,verbs=get;list;watch;create;update;patch;delete
@@ -48,6 +67,7 @@ type DatasourceReconciler struct {
 //+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=datasources/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=knowledgebases,verbs=get;list;watch;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -87,7 +107,7 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// indicated by the deletion timestamp being set.
 	if instance.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(instance, arcadiav1alpha1.Finalizer) {
 		logger.Info("Performing Finalizer Operations for Datasource before delete CR")
-		r.RemoveDatasource(logger, instance)
+		r.RemoveDatasource(ctx, logger, instance)
 		logger.Info("Removing Finalizer for Datasource after successfully performing the operations")
 		controllerutil.RemoveFinalizer(instance, arcadiav1alpha1.Finalizer)
 		if err := r.Update(ctx, instance); err != nil {
@@ -112,6 +132,15 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// Update conditioned status
 		return reconcile.Result{RequeueAfter: waitMedium}, err
 	}
+
+	// crawl website
+	if instance.Spec.IsWebCrawler() {
+		return r.reconcileWebCrawler(ctx, logger, instance), nil
+	}
+	// check the head of the tracked branch periodically
+	if instance.Spec.Type() == arcadiav1alpha1.DatasourceTypeGit && instance.Spec.Git.TracksBranch() {
+		return ctrl.Result{RequeueAfter: gitRefreshInterval}, nil
+	}
 	return ctrl.Result{RequeueAfter: waitLonger}, nil
 }
 
@@ -158,6 +187,10 @@ func (r *DatasourceReconciler) Checkdatasource(ctx context.Context, logger logr.
 	if endpoint.AuthSecret != nil {
 		endpoint.AuthSecret.WithNameSpace(instance.Namespace)
 	}
+	// the cached clone is stale once the datasource is not a git repository any more
+	if instance.Spec.Type() != arcadiav1alpha1.DatasourceTypeGit {
+		datasource.RemoveGitRepository(*instance)
+	}
 	// create datasource
 	var ds datasource.Datasource
 	var info any
@@ -175,9 +208,30 @@ func (r *DatasourceReconciler) Checkdatasource(ctx context.Context, logger logr.
 		if err != nil {
 			return r.UpdateStatus(ctx, instance, err)
 		}
+	case arcadiav1alpha1.DatasourceTypeGit:
+		g, err := datasource.GetGitRepository(ctx, r.Client, instance)
+		if err != nil {
+			return r.UpdateStatus(ctx, instance, err)
+		}
+		// the tracked branch may have new commits
+		if _, err = g.Refresh(ctx); err != nil {
+			return r.UpdateStatus(ctx, instance, err)
+		}
+		commit, err := g.Commit(ctx)
+		if err != nil {
+			return r.UpdateStatus(ctx, instance, err)
+		}
+		if previous := instance.Status.Commit; previous != "" && previous != commit {
+			logger.Info("git commit changed", "from", previous, "to", commit)
+			if err := r.recheckKnowledgeBases(ctx, logger, instance); err != nil {
+				return r.UpdateStatus(ctx, instance, err)
+			}
+		}
+		instance.Status.Commit = commit
+		ds = g
 	case arcadiav1alpha1.DatasourceTypeWeb:
 		info = instance.Spec.Web.DeepCopy()
-		ds, err = datasource.NewWeb(ctx, endpoint.URL)
+		ds, err = datasource.NewWeb(ctx, endpoint.URL, nil)
 		if err != nil {
 			return r.UpdateStatus(ctx, instance, err)
 		}
@@ -212,13 +266,254 @@ func (r *DatasourceReconciler) UpdateStatus(ctx context.Context, instance *arcad
 	return errors.Join(err, r.Client.Status().Update(ctx, instanceCopy))
 }
 
-func (r *DatasourceReconciler) RemoveDatasource(logger logr.Logger, instance *arcadiav1alpha1.Datasource) {
+func (r *DatasourceReconciler) RemoveDatasource(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.Datasource) {
 	logger.V(5).Info("remove datasource")
 	switch instance.Spec.Type() {
 	case arcadiav1alpha1.DatasourceTypeOSS:
 	case arcadiav1alpha1.DatasourceTypeRDMA:
 	case arcadiav1alpha1.DatasourceTypePostgreSQL:
 		datasource.RemovePostgreSQLPool(*instance)
+	case arcadiav1alpha1.DatasourceTypeGit:
+		datasource.RemoveGitRepository(*instance)
+	case arcadiav1alpha1.DatasourceTypeWeb:
+		if !instance.Spec.IsWebCrawler() {
+			return
+		}
+		// remove crawled pages, best effort
+		oss, err := config.GetSystemDatasourceOSS(ctx)
+		if err != nil {
+			logger.Error(err, "failed to get system datasource, crawled pages are left")
+			return
+		}
+		if err := oss.Remove(ctx, &arcadiav1alpha1.OSS{Bucket: instance.Namespace, Object: instance.WebCrawlObjectPrefix()}); err != nil {
+			logger.Error(err, "failed to remove crawled pages")
+		}
 	default:
 	}
 }
+
+// reconcileWebCrawler starts a crawl if the datasource is never crawled, its spec changed or the schedule is due.
+// The crawl runs in background and its result is recorded in `status.crawl`.
+func (r *DatasourceReconciler) reconcileWebCrawler(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.Datasource) ctrl.Result {
+	if r.isCrawling(instance) {
+		logger.V(5).Info("web datasource is being crawled")
+		return ctrl.Result{RequeueAfter: waitMedium}
+	}
+	if crawl := instance.Status.Crawl; crawl != nil && crawl.ObservedGeneration == instance.Generation {
+		if crawl.NextCrawlTime == nil {
+			return ctrl.Result{}
+		}
+		if wait := time.Until(crawl.NextCrawlTime.Time); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}
+		}
+	}
+	if !r.startCrawling(instance) {
+		return ctrl.Result{RequeueAfter: waitMedium}
+	}
+	go func() {
+		defer r.stopCrawling(instance)
+		r.crawl(ctx, logger.WithValues("crawl", instance.Spec.Endpoint.URL), instance.DeepCopy())
+	}()
+	return ctrl.Result{RequeueAfter: waitMedium}
+}
+
+func (r *DatasourceReconciler) crawl(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.Datasource) {
+	logger.Info("start to crawl website")
+	status := &arcadiav1alpha1.WebCrawlStatus{ObservedGeneration: instance.Generation}
+	pages, removed, err := r.crawlPages(ctx, instance)
+	if err == nil {
+		err = r.notifyKnowledgeBases(ctx, logger, instance, pages, removed)
+	}
+	status.LastCrawlTime = metav1.Now()
+	status.Pages = len(pages)
+	for _, p := range pages {
+		if p.Changed {
+			status.ChangedPages++
+		}
+	}
+	if err != nil {
+		logger.Error(err, "failed to crawl website")
+		status.Message = err.Error()
+	}
+	if schedule := instance.Spec.Web.Crawler.Schedule; schedule != "" {
+		cron, parseErr := utils.ParseCron(schedule)
+		if parseErr != nil {
+			status.Message = strings.TrimSpace(status.Message + " " + parseErr.Error())
+		} else if next := cron.Next(status.LastCrawlTime.Time); !next.IsZero() {
+			status.NextCrawlTime = &metav1.Time{Time: next}
+		}
+	} else if err != nil {
+		status.NextCrawlTime = &metav1.Time{Time: status.LastCrawlTime.Add(crawlRetryDelay)}
+	}
+	logger.Info("crawl website done", "pages", status.Pages, "changedPages", status.ChangedPages, "removedPages", len(removed))
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &arcadiav1alpha1.Datasource{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(instance), latest); err != nil {
+			return err
+		}
+		latest.Status.Crawl = status
+		return r.Client.Status().Update(ctx, latest)
+	}); err != nil {
+		logger.Error(err, "failed to update crawl status")
+	}
+}
+
+// crawlPages crawls the website and stores pages into the system datasource.
+// It returns the crawled pages and the objects of the pages removed from the website.
+func (r *DatasourceReconciler) crawlPages(ctx context.Context, instance *arcadiav1alpha1.Datasource) ([]datasource.CrawledPage, []string, error) {
+	oss, err := config.GetSystemDatasourceOSS(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	exists, err := oss.Client.BucketExists(ctx, instance.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		if err = oss.MakeBucket(ctx, instance.Namespace, minio.MakeBucketOptions{}); err != nil {
+			return nil, nil, err
+		}
+	}
+	crawler, err := datasource.NewWebCrawler(oss, instance.Namespace, instance.WebCrawlObjectPrefix(), instance.Spec.Endpoint.URL, instance.Spec.Web.Crawler)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages, err := crawler.Crawl(ctx)
+	if err != nil {
+		return pages, nil, err
+	}
+	removed, err := crawler.RemoveStalePages(ctx, pages)
+	return pages, removed, err
+}
+
+// notifyKnowledgeBases adds new pages to the KnowledgeBases which use this datasource,
+// asks them to re-embed the changed pages, and removes the pages removed from the website.
+func (r *DatasourceReconciler) notifyKnowledgeBases(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.Datasource, pages []datasource.CrawledPage, removed []string) error {
+	changed := make([]string, 0)
+	for _, p := range pages {
+		if p.Changed {
+			changed = append(changed, p.Object)
+		}
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+	removedSet := make(map[string]bool, len(removed))
+	for _, object := range removed {
+		removedSet[object] = true
+	}
+	list := &arcadiav1alpha1.KnowledgeBaseList{}
+	if err := r.List(ctx, list); err != nil {
+		return err
+	}
+	var errs error
+	for i := range list.Items {
+		kb := &list.Items[i]
+		if kb.GetDeletionTimestamp() != nil {
+			continue
+		}
+		newKB := kb.DeepCopy()
+		used := false
+		for idx, fg := range newKB.Spec.FileGroups {
+			if !isDatasourceFileGroup(kb, fg, instance) {
+				continue
+			}
+			used = true
+			exists := make(map[string]bool, len(fg.Files))
+			// the knowledgebase removes the chunks of the files removed from its spec
+			files := make([]arcadiav1alpha1.FileWithVersion, 0, len(fg.Files))
+			for _, f := range fg.Files {
+				exists[f.Path] = true
+				if !removedSet[f.Path] {
+					files = append(files, f)
+				}
+			}
+			for _, object := range changed {
+				if !exists[object] {
+					files = append(files, arcadiav1alpha1.FileWithVersion{Path: object})
+				}
+			}
+			newKB.Spec.FileGroups[idx].Files = files
+		}
+		if !used {
+			continue
+		}
+		if len(changed) > 0 {
+			if newKB.Annotations == nil {
+				newKB.Annotations = make(map[string]string)
+			}
+			newKB.Annotations[arcadiav1alpha1.UpdateSourceFileAnnotationKey] = retryForChanged
+		}
+		logger.Info("notify knowledgebase of changed pages", "knowledgebase", client.ObjectKeyFromObject(kb), "changed", len(changed), "removed", len(removed))
+		if err := r.Patch(ctx, newKB, client.MergeFrom(kb)); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// recheckKnowledgeBases asks the KnowledgeBases which use this datasource to re-embed the changed files
+func (r *DatasourceReconciler) recheckKnowledgeBases(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.Datasource) error {
+	list := &arcadiav1alpha1.KnowledgeBaseList{}
+	if err := r.List(ctx, list); err != nil {
+		return err
+	}
+	var errs error
+	for i := range list.Items {
+		kb := &list.Items[i]
+		if kb.GetDeletionTimestamp() != nil || !usesDatasource(kb, instance) {
+			continue
+		}
+		newKB := kb.DeepCopy()
+		if newKB.Annotations == nil {
+			newKB.Annotations = make(map[string]string)
+		}
+		newKB.Annotations[arcadiav1alpha1.UpdateSourceFileAnnotationKey] = retryForChanged
+		logger.Info("notify knowledgebase of changed files", "knowledgebase", client.ObjectKeyFromObject(kb))
+		if err := r.Patch(ctx, newKB, client.MergeFrom(kb)); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// usesDatasource returns true if any file group of the KnowledgeBase comes from the datasource
+func usesDatasource(kb *arcadiav1alpha1.KnowledgeBase, instance *arcadiav1alpha1.Datasource) bool {
+	for _, fg := range kb.Spec.FileGroups {
+		if isDatasourceFileGroup(kb, fg, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDatasourceFileGroup(kb *arcadiav1alpha1.KnowledgeBase, fg arcadiav1alpha1.FileGroup, instance *arcadiav1alpha1.Datasource) bool {
+	return fg.Source != nil && fg.Source.Name == instance.Name && fg.Source.GetNamespace(kb.Namespace) == instance.Namespace &&
+		(fg.Source.Kind == "" || strings.EqualFold(fg.Source.Kind, "datasource"))
+}
+
+func (r *DatasourceReconciler) isCrawling(instance *arcadiav1alpha1.Datasource) bool {
+	r.crawlingMu.Lock()
+	defer r.crawlingMu.Unlock()
+	return r.crawling[string(instance.GetUID())]
+}
+
+func (r *DatasourceReconciler) startCrawling(instance *arcadiav1alpha1.Datasource) bool {
+	r.crawlingMu.Lock()
+	defer r.crawlingMu.Unlock()
+	if r.crawling == nil {
+		r.crawling = make(map[string]bool)
+	}
+	if r.crawling[string(instance.GetUID())] {
+		return false
+	}
+	r.crawling[string(instance.GetUID())] = true
+	return true
+}
+
+func (r *DatasourceReconciler) stopCrawling(instance *arcadiav1alpha1.Datasource) {
+	r.crawlingMu.Lock()
+	defer r.crawlingMu.Unlock()
+	delete(r.crawling, string(instance.GetUID()))
+}
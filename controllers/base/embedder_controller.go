@@ -41,7 +41,9 @@ import (
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
 	"github.com/kubeagi/arcadia/pkg/embeddings"
+	embeddingsdashscope "github.com/kubeagi/arcadia/pkg/embeddings/dashscope"
 	embeddingszhipuai "github.com/kubeagi/arcadia/pkg/embeddings/zhipuai"
+	"github.com/kubeagi/arcadia/pkg/llms/dashscope"
 	"github.com/kubeagi/arcadia/pkg/llms/zhipuai"
 )
 
@@ -249,6 +251,17 @@ func (r *EmbedderReconciler) check3rdPartyEmbedder(ctx context.Context, logger l
 			}
 			msg = "Success"
 		}
+	case embeddings.DashScope:
+		// validate all embedding models
+		for _, model := range models {
+			embedClient := embeddingsdashscope.NewDashScopeEmbedder(apiKey,
+				dashscope.WithBaseURL(instance.Get3rdPartyEmbedderBaseURL()), dashscope.WithEmbeddingModel(dashscope.Model(model)))
+			_, err = embedClient.EmbedQuery(ctx, embedingText)
+			if err != nil {
+				return r.UpdateStatus(ctx, instance, nil, err)
+			}
+			msg = "Success"
+		}
 	default:
 		return r.UpdateStatus(ctx, instance, nil, fmt.Errorf("unsupported service type: %s", instance.Spec.Type))
 	}
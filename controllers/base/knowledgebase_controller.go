@@ -39,11 +39,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -64,7 +66,9 @@ const (
 	waitSmaller = time.Second * 3
 	waitMedium  = time.Minute
 
-	retryForFailed = "for-failed"
+	retryForFailed = arcadiav1alpha1.UpdateSourceFileForFailed
+	// retryForChanged rechecks the processed files, only files whose checksum changed will be embedded again
+	retryForChanged = arcadiav1alpha1.UpdateSourceFileForChanged
 )
 
 var (
@@ -82,6 +86,11 @@ type KnowledgeBaseReconciler struct {
 	HasHandledSuccessPath map[string]bool
 	readyMu               sync.Mutex
 	ReadyMap              map[string]bool
+	// IngestionWorkers is the number of files embedded in parallel
+	IngestionWorkers int
+
+	apiReader client.Reader
+	ingestion *ingestion
 }
 
 //+kubebuilder:rbac:groups=arcadia.kubeagi.k8s.com.cn,resources=knowledgebases,verbs=get;list;watch;create;update;patch;delete
@@ -163,9 +172,10 @@ func (r *KnowledgeBaseReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if v := kb.Annotations[arcadiav1alpha1.UpdateSourceFileAnnotationKey]; v != "" {
 		log.Info("Manual update")
 		kbNew := kb.DeepCopy()
-		if v != retryForFailed && len(kb.Status.FileGroupDetail) != 0 {
+		if v != retryForFailed && v != retryForChanged && len(kb.Status.FileGroupDetail) != 0 {
 			log.Info("set FileGroupDetail to nil to redo embedder...")
 			kbNew.Status.FileGroupDetail = nil
+			vectorstore.RemoveDeduplicationIndex(kb.VectorStoreCollectionName())
 			kbNew = r.setCondition(log, kbNew, kbNew.InitCondition())
 			return reconcile.Result{}, r.patchStatus(ctx, log, kbNew)
 		}
@@ -178,6 +188,7 @@ func (r *KnowledgeBaseReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 						kbNew.Status.FileGroupDetail[out].FileDetails[in].Phase = arcadiav1alpha1.FileProcessPhaseProcessing
 						kbNew.Status.FileGroupDetail[out].FileDetails[in].LastUpdateTime = metav1.Now()
 						kbNew.Status.FileGroupDetail[out].FileDetails[in].ErrMessage = ""
+						kbNew.Status.FileGroupDetail[out].FileDetails[in].Attempts = 0
 					}
 				}
 			}
@@ -187,12 +198,31 @@ func (r *KnowledgeBaseReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				return reconcile.Result{}, r.patchStatus(ctx, log, kbNew)
 			}
 		}
+		if v == retryForChanged {
+			found := false
+			for out, fg := range kbNew.Status.FileGroupDetail {
+				for in, f := range fg.FileDetails {
+					if f.Phase == arcadiav1alpha1.FileProcessPhaseSucceeded {
+						found = true
+						kbNew.Status.FileGroupDetail[out].FileDetails[in].Phase = arcadiav1alpha1.FileProcessPhaseProcessing
+						kbNew.Status.FileGroupDetail[out].FileDetails[in].LastUpdateTime = metav1.Now()
+						kbNew.Status.FileGroupDetail[out].FileDetails[in].Attempts = 0
+					}
+				}
+			}
+			if found {
+				log.Info("source files may be changed, recheck the processed files.")
+				kbNew = r.setCondition(log, kbNew, kbNew.InitCondition())
+				return reconcile.Result{}, r.patchStatus(ctx, log, kbNew)
+			}
+		}
 		delete(kbNew.Annotations, arcadiav1alpha1.UpdateSourceFileAnnotationKey)
 		return reconcile.Result{}, r.Patch(ctx, kbNew, client.MergeFrom(kb))
 	}
 
 	dp := kb.DeepCopy()
-	if r.syncStatus(ctx, dp) {
+	if changed, removed := r.syncStatus(ctx, dp); changed {
+		r.removeFiles(ctx, log, kb, removed)
 		log.V(5).Info(fmt.Sprintf("status is different from spec. new status: %+v\n, old status: %+v", dp.Status.FileGroupDetail, kb.Status.FileGroupDetail))
 		return reconcile.Result{}, r.Client.Status().Patch(ctx, dp, client.MergeFrom(kb))
 	}
@@ -214,6 +244,7 @@ func (r *KnowledgeBaseReconciler) patchStatus(ctx context.Context, log logr.Logg
 		log.V(5).Info("status is ready,but not get it from cluster, has cache, skip update status")
 		return nil
 	}
+	mergeFileDetails(latest, kb)
 	log.V(5).Info(fmt.Sprintf("try to patch status %#v", kb.Status))
 	patch := client.MergeFrom(latest.DeepCopy())
 	latest.Status = kb.Status
@@ -222,6 +253,12 @@ func (r *KnowledgeBaseReconciler) patchStatus(ctx context.Context, log logr.Logg
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KnowledgeBaseReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.apiReader = mgr.GetAPIReader()
+	r.ingestion = newIngestion(r.IngestionWorkers, workqueue.NewItemExponentialFailureRateLimiter(ingestionBaseDelay, ingestionMaxDelay), r.ingest)
+	if err := mgr.Add(manager.RunnableFunc(r.startIngestion)); err != nil {
+		return err
+	}
+
 	if err := mgr.GetFieldIndexer().IndexField(ctx, &arcadiav1alpha1.KnowledgeBase{}, EmbedderIndexKey,
 		func(o client.Object) []string {
 			kb, ok := o.(*arcadiav1alpha1.KnowledgeBase)
@@ -284,6 +321,7 @@ func (r *KnowledgeBaseReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 				}
 				return reqs
 			})).
+		Watches(&source.Channel{Source: r.ingestion.done}, &handler.EnqueueRequestForObject{}).
 		Complete(r)
 }
 
@@ -334,23 +372,32 @@ func (r *KnowledgeBaseReconciler) reconcile(ctx context.Context, log logr.Logger
 		return ctrl.Result{}, nil
 	}
 
-	haveFailed := false
+	pending := false
+	for out, fg := range kb.Status.FileGroupDetail {
+		for in, f := range fg.FileDetails {
+			if fg.Source != nil && f.Phase == arcadiav1alpha1.FileProcessPhasePending {
+				log.V(5).Info(fmt.Sprintf("source: %s/%s file: %s, cur is Pending,change it to Processing", fg.Source.Kind, fg.Source.Name, f.Path))
+				kb.Status.FileGroupDetail[out].FileDetails[in].Phase = arcadiav1alpha1.FileProcessPhaseProcessing
+				kb.Status.FileGroupDetail[out].FileDetails[in].LastUpdateTime = metav1.Now()
+				pending = true
+			}
+		}
+	}
+	if pending {
+		return ctrl.Result{}, r.patchStatus(ctx, log, kb)
+	}
+
+	haveFailed, processing := false, false
 	for out, fg := range kb.Status.FileGroupDetail {
 		if fg.Source == nil {
 			log.Info(fmt.Sprintf("kb.Status.FileGroupDetail[%d] source is nil, skip", out))
 			continue
 		}
-		for in, f := range fg.FileDetails {
+		for _, f := range fg.FileDetails {
 			if f.Phase == arcadiav1alpha1.FileProcessPhaseSkipped {
 				log.Info(fmt.Sprintf("source %s/%s, file %s, the current phase is skip and will not be processed.", fg.Source.Kind, fg.Source.Name, f.Path))
 				continue
 			}
-			if f.Phase == arcadiav1alpha1.FileProcessPhasePending {
-				log.V(5).Info(fmt.Sprintf("source: %s/%s file: %s, cur is Pending,change it to Processing", fg.Source.Kind, fg.Source.Name, f.Path))
-				kb.Status.FileGroupDetail[out].FileDetails[in].Phase = arcadiav1alpha1.FileProcessPhaseProcessing
-				kb.Status.FileGroupDetail[out].FileDetails[in].LastUpdateTime = metav1.Now()
-				return ctrl.Result{}, r.patchStatus(ctx, log, kb)
-			}
 			if f.Phase == arcadiav1alpha1.FileProcessPhaseFailed {
 				log.Info(fmt.Sprintf("source: %s/%s, file: %s, is failed skip.", fg.Source.Kind, fg.Source.Name, f.Path))
 				haveFailed = true
@@ -362,14 +409,15 @@ func (r *KnowledgeBaseReconciler) reconcile(ctx context.Context, log logr.Logger
 			}
 			if f.Phase == arcadiav1alpha1.FileProcessPhaseProcessing {
 				log.Info(fmt.Sprintf("source: %s/%s, file: %s, is Processing", fg.Source.Kind, fg.Source.Name, f.Path))
-				err := r.reconcileFileGroup(ctx, log, kb, vectorStore, embedder, out, in)
-				if err != nil {
-					log.Error(err, "failed to handle single file", "FileName", f.Path)
-				}
-				return ctrl.Result{Requeue: true}, r.patchStatus(ctx, log, kb)
+				r.enqueue(kb, fg, f)
+				processing = true
 			}
 		}
 	}
+	if processing {
+		// the ingestion workers will trigger the reconciliation when files are processed
+		return ctrl.Result{}, nil
+	}
 	if haveFailed {
 		r.setCondition(log, kb, kb.ErrorCondition("some files failed to process."))
 		return ctrl.Result{RequeueAfter: waitMedium}, r.patchStatus(ctx, log, kb)
@@ -422,6 +470,9 @@ func (r *KnowledgeBaseReconciler) reconcileFileGroup(
 	var ds datasource.Datasource
 	info := &arcadiav1alpha1.OSS{Bucket: ns}
 	var vsBasePath string
+	// pg is set for a table, whose rows are embedded one by one
+	var pg *datasource.PostgreSQL
+	var watermark string
 	switch lowerKind {
 	case "versioneddataset":
 		versionedDataset := &arcadiav1alpha1.VersionedDataset{}
@@ -464,6 +515,40 @@ func (r *KnowledgeBaseReconciler) reconcileFileGroup(
 		if !dsObj.Status.IsReady() {
 			return errDataSourceNotReady
 		}
+		if dsObj.Spec.IsWebCrawler() {
+			// crawled pages are stored in the system datasource
+			storage, err := config.GetSystemDatasourceOSS(ctx)
+			if err != nil {
+				return err
+			}
+			ds, err = datasource.NewWeb(ctx, dsObj.Spec.Endpoint.URL, storage)
+			if err != nil {
+				return err
+			}
+			info.Bucket = dsObj.Namespace
+			info.Object = fileDetail.Path
+			break
+		}
+		if dsObj.Spec.Type() == arcadiav1alpha1.DatasourceTypeGit {
+			ds, err = datasource.GetGitRepository(ctx, r.Client, dsObj)
+			if err != nil {
+				return err
+			}
+			info.Object = fileDetail.Path
+			break
+		}
+		if dsObj.Spec.Type() == arcadiav1alpha1.DatasourceTypePostgreSQL {
+			pg, err = datasource.GetPostgreSQLPool(ctx, r.Client, dsObj)
+			if err != nil {
+				return err
+			}
+			ds = pg
+			info.Object = fileDetail.Path
+			// only rows updated after the last sync need to be embedded
+			watermark = pg.Watermark(info, fileDetail.Checksum)
+			info.VersionID = watermark
+			break
+		}
 		// set endpoint's auth secret namespace to current datasource if not set
 		endpoint := dsObj.Spec.Endpoint.DeepCopy()
 		if endpoint != nil && endpoint.AuthSecret != nil {
@@ -483,7 +568,9 @@ func (r *KnowledgeBaseReconciler) reconcileFileGroup(
 		return fmt.Errorf("source type %s not supported yet", group.Source.Kind)
 	}
 
-	info.VersionID = fileDetail.Version
+	if info.VersionID == "" {
+		info.VersionID = fileDetail.Version
+	}
 
 	stat, err := ds.StatFile(ctx, info)
 	log.V(5).Info(fmt.Sprintf("raw StatFile:%#v", stat), "path", fileDetail.Path)
@@ -506,7 +593,6 @@ func (r *KnowledgeBaseReconciler) reconcileFileGroup(
 		kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].Phase = arcadiav1alpha1.FileProcessPhaseSucceeded
 		return nil
 	}
-	kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].Checksum = objectStat.ETag
 
 	tags, err := ds.GetTags(ctx, info)
 	if err != nil {
@@ -521,14 +607,44 @@ func (r *KnowledgeBaseReconciler) reconcileFileGroup(
 	// File data count in string
 	kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].Count = tags[arcadiav1alpha1.ObjectCountTag]
 
-	file, err := ds.ReadFile(ctx, info)
+	var file io.ReadCloser
+	var rows []datasource.PostgreSQLRow
+	if pg != nil {
+		rows, err = pg.ReadRows(ctx, info)
+	} else {
+		file, err = ds.ReadFile(ctx, info)
+	}
 	if err != nil {
 		kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].UpdateErr(err, arcadiav1alpha1.FileProcessPhaseFailed)
 		return err
 	}
-	defer file.Close()
+	if file != nil {
+		defer file.Close()
+	}
+	source := fileSource(kb, group.Source, fileDetail.Path)
+	// the chunks of the previous content are replaced, the incremental rows of a table are replaced by their keys
+	if fileDetail.Checksum != "" && watermark == "" {
+		if err = vectorstore.RemoveDocumentsBySource(ctx, log, vectorStore, kb.VectorStoreCollectionName(), r.Client, source); err != nil {
+			kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].UpdateErr(err, arcadiav1alpha1.FileProcessPhaseFailed)
+			return err
+		}
+	}
 	startTime := time.Now()
-	if err = r.handleFile(ctx, log, file, info.Object, tags, kb, vectorStore, embedder); err != nil {
+	var duplicates int
+	if pg != nil {
+		duplicates, err = r.handleRows(ctx, log, rows, info.Object, source, tags, watermark != "", kb, vectorStore, embedder)
+		// the rows deleted after the last sync are not read incrementally, their chunks are removed by the keys of remaining rows
+		if err == nil && watermark != "" && datasource.PostgreSQLRowsMayBeDeleted(fileDetail.Checksum, objectStat.ETag, len(rows)) {
+			var keys []string
+			if keys, err = pg.RowKeys(ctx, info); err == nil {
+				err = vectorstore.RemoveDocumentsExceptRows(ctx, log, vectorStore, kb.VectorStoreCollectionName(), r.Client, source, keys)
+			}
+		}
+	} else {
+		duplicates, err = r.handleFile(ctx, log, file, info.Object, source, tags, kb, vectorStore, embedder)
+	}
+	kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].DuplicateCount = duplicates
+	if err != nil {
 		if errors.Is(err, errFileSkipped) {
 			kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].UpdateErr(err, arcadiav1alpha1.FileProcessPhaseSkipped)
 		} else {
@@ -539,31 +655,87 @@ func (r *KnowledgeBaseReconciler) reconcileFileGroup(
 	cost := int64(time.Since(startTime).Milliseconds())
 
 	kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].TimeCost = cost
+	// only record the checksum after the file is processed, so that the failed file will be processed again
+	kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].Checksum = objectStat.ETag
 	log.Info("handle FileGroup succeeded", "timecost(milliseconds)", cost)
 	kb.Status.FileGroupDetail[groupIndex].FileDetails[fileIndex].UpdateErr(err, arcadiav1alpha1.FileProcessPhaseSucceeded)
 	return nil
 }
 
-func (r *KnowledgeBaseReconciler) handleFile(ctx context.Context, log logr.Logger, file io.ReadCloser, fileName string, tags map[string]string, kb *arcadiav1alpha1.KnowledgeBase, store *arcadiav1alpha1.VectorStore, embedder *arcadiav1alpha1.Embedder) (err error) {
+// fileSource identifies the chunks of a file in the vector store
+func fileSource(kb *arcadiav1alpha1.KnowledgeBase, source *arcadiav1alpha1.TypedObjectReference, path string) string {
+	kind := strings.ToLower(source.Kind)
+	if kind == "" {
+		kind = "datasource"
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", kind, source.GetNamespace(kb.Namespace), source.Name, path)
+}
+
+// handleFile embeds the file and returns the number of duplicated chunks.
+// The chunks are recorded with the source so that they can be removed when the file is changed or removed.
+func (r *KnowledgeBaseReconciler) handleFile(ctx context.Context, log logr.Logger, file io.ReadCloser, fileName, source string, tags map[string]string, kb *arcadiav1alpha1.KnowledgeBase, store *arcadiav1alpha1.VectorStore, embedder *arcadiav1alpha1.Embedder) (duplicates int, err error) {
 	log = log.WithValues("fileName", fileName, "tags", tags)
 	if !embedder.Status.IsReady() {
-		return errEmbedderNotReady
+		return 0, errEmbedderNotReady
 	}
 	if !store.Status.IsReady() {
-		return errVectorStoreNotReady
-	}
-	embeddingOptions := kb.EmbeddingOptions()
-	em, err := langchainwrap.GetLangchainEmbedder(ctx, embedder, r.Client, "", embeddings.WithBatchSize(embeddingOptions.BatchSize))
-	if err != nil {
-		return err
+		return 0, errVectorStoreNotReady
 	}
 	data, err := io.ReadAll(file) // TODO Load large files in pieces to save memory
 	// TODO Line or single line byte exceeds embedder limit
 	if err != nil {
-		return err
+		return 0, err
+	}
+	documents, err := loadDocuments(ctx, data, fileName, tags, kb.EmbeddingOptions())
+	if err != nil {
+		return 0, err
+	}
+	for i := range documents {
+		documents[i].Metadata[vectorstore.SourceMetadataKey] = source
+	}
+	return r.addDocuments(ctx, log, source, documents, kb, store, embedder)
+}
+
+// handleRows embeds the rows of a table and returns the number of duplicated chunks.
+// The chunks are recorded with the row keys, and the previous chunks of the rows are replaced if `replace` is true.
+func (r *KnowledgeBaseReconciler) handleRows(ctx context.Context, log logr.Logger, rows []datasource.PostgreSQLRow, fileName, source string, tags map[string]string, replace bool, kb *arcadiav1alpha1.KnowledgeBase, store *arcadiav1alpha1.VectorStore, embedder *arcadiav1alpha1.Embedder) (duplicates int, err error) {
+	log = log.WithValues("fileName", fileName, "tags", tags, "rows", len(rows))
+	if !embedder.Status.IsReady() {
+		return 0, errEmbedderNotReady
+	}
+	if !store.Status.IsReady() {
+		return 0, errVectorStoreNotReady
+	}
+	embeddingOptions := kb.EmbeddingOptions()
+	documents := make([]schema.Document, 0, len(rows))
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		rowDocuments, err := loadDocuments(ctx, row.Content, fileName, tags, embeddingOptions)
+		if err != nil {
+			return 0, err
+		}
+		for i := range rowDocuments {
+			rowDocuments[i].Metadata[vectorstore.SourceMetadataKey] = source
+			if row.Key != "" {
+				rowDocuments[i].Metadata[vectorstore.RowMetadataKey] = row.Key
+			}
+		}
+		if row.Key != "" {
+			keys = append(keys, row.Key)
+		}
+		documents = append(documents, rowDocuments...)
+	}
+	if replace {
+		if err = vectorstore.RemoveDocumentsByRows(ctx, log, store, kb.VectorStoreCollectionName(), r.Client, source, keys); err != nil {
+			return 0, err
+		}
 	}
+	return r.addDocuments(ctx, log, source, documents, kb, store, embedder)
+}
+
+// loadDocuments loads and splits the content of a file into documents
+func loadDocuments(ctx context.Context, data []byte, fileName string, tags map[string]string, embeddingOptions arcadiav1alpha1.EmbeddingOptions) ([]schema.Document, error) {
 	dataReader := bytes.NewReader(data)
-	var documents []schema.Document
 	var loader documentloaders.Loader
 	switch filepath.Ext(fileName) {
 	case ".txt":
@@ -609,17 +781,44 @@ func (r *KnowledgeBaseReconciler) handleFile(ctx context.Context, log logr.Logge
 	//	)
 	//}
 
-	documents, err = loader.LoadAndSplit(ctx, split)
+	documents, err := loader.LoadAndSplit(ctx, split)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	for i := range documents {
+		if documents[i].Metadata == nil {
+			documents[i].Metadata = make(map[string]any)
+		}
+	}
+	return documents, nil
+}
 
-	return vectorstore.AddDocuments(ctx, log, store, em, kb.VectorStoreCollectionName(), r.Client, documents)
+// addDocuments embeds the documents of the source into the vector store and returns the number of duplicated chunks
+func (r *KnowledgeBaseReconciler) addDocuments(ctx context.Context, log logr.Logger, source string, documents []schema.Document, kb *arcadiav1alpha1.KnowledgeBase, store *arcadiav1alpha1.VectorStore, embedder *arcadiav1alpha1.Embedder) (duplicates int, err error) {
+	embeddingOptions := kb.EmbeddingOptions()
+	em, err := langchainwrap.GetLangchainEmbedder(ctx, embedder, r.Client, "", embeddings.WithBatchSize(embeddingOptions.BatchSize))
+	if err != nil {
+		return 0, err
+	}
+	commit := func() {}
+	if embeddingOptions.Deduplication != nil {
+		documents, duplicates, commit, err = vectorstore.DeduplicateDocuments(ctx, log, store, kb.VectorStoreCollectionName(), r.Client, *embeddingOptions.Deduplication, source, documents)
+		if err != nil {
+			return 0, err
+		}
+		log.Info("handle file: deduplicate documents done", "duplicates", duplicates)
+	}
+	if err = vectorstore.AddDocuments(ctx, log, store, em, kb.VectorStoreCollectionName(), r.Client, documents); err != nil {
+		return duplicates, err
+	}
+	commit()
+	return duplicates, nil
 }
 
 func (r *KnowledgeBaseReconciler) reconcileDelete(ctx context.Context, log logr.Logger, kb *arcadiav1alpha1.KnowledgeBase) {
 	// r.cleanupHasHandledSuccessPath(kb)
 	// r.unready(log, kb)
+	vectorstore.RemoveDeduplicationIndex(kb.VectorStoreCollectionName())
 	vectorStore := &arcadiav1alpha1.VectorStore{}
 	if err := r.Get(ctx, types.NamespacedName{Name: kb.Spec.VectorStore.Name, Namespace: kb.Spec.VectorStore.GetNamespace(kb.GetNamespace())}, vectorStore); err != nil {
 		log.Error(err, "reconcile delete: get vector store error, may leave garbage data")
@@ -693,9 +892,11 @@ func isFileDetailDiff(a, b arcadiav1alpha1.FileDetails) bool {
 		a.Version != b.Version
 }
 
-func (r *KnowledgeBaseReconciler) syncStatus(ctx context.Context, kb *arcadiav1alpha1.KnowledgeBase) bool {
+// syncStatus syncs the files in status with spec, and returns the sources of the embedded files removed from spec
+func (r *KnowledgeBaseReconciler) syncStatus(ctx context.Context, kb *arcadiav1alpha1.KnowledgeBase) (bool, []string) {
 	log, _ := logr.FromContext(ctx)
 	newStatus := make([]arcadiav1alpha1.FileGroupDetail, 0)
+	removed := make([]string, 0)
 	specSource := make(map[string]map[string][2]int)
 	now := metav1.Now()
 	for _, fg := range kb.Spec.FileGroups {
@@ -732,13 +933,13 @@ func (r *KnowledgeBaseReconciler) syncStatus(ctx context.Context, kb *arcadiav1a
 			ns = *fgd.Source.Namespace
 		}
 		key := fmt.Sprintf("%s/%s/%s", fgd.Source.Kind, ns, fgd.Source.Name)
-		fileDetails, ok := specSource[key]
-		if !ok {
-			continue
-		}
+		fileDetails := specSource[key]
 		for _, f := range fgd.FileDetails {
 			v, ok := fileDetails[f.Path]
 			if !ok {
+				if f.Checksum != "" {
+					removed = append(removed, fileSource(kb, fgd.Source, f.Path))
+				}
 				continue
 			}
 			vv := newStatus[v[0]].FileDetails[v[1]].Version
@@ -753,7 +954,7 @@ func (r *KnowledgeBaseReconciler) syncStatus(ctx context.Context, kb *arcadiav1a
 	log.V(5).Info(fmt.Sprintf("old status: %+v\n", kb.Status.FileGroupDetail))
 	if len(newStatus) != len(kb.Status.FileGroupDetail) {
 		kb.Status.FileGroupDetail = newStatus
-		return true
+		return true, removed
 	}
 
 	for i := 0; i < len(newStatus); i++ {
@@ -761,16 +962,38 @@ func (r *KnowledgeBaseReconciler) syncStatus(ctx context.Context, kb *arcadiav1a
 			log.V(5).Info(fmt.Sprintf("len diff %+v | %+v\n", newStatus[i].FileDetails,
 				kb.Status.FileGroupDetail[i].FileDetails))
 			kb.Status.FileGroupDetail = newStatus
-			return true
+			return true, removed
 		}
 		for j := range newStatus[i].FileDetails {
 			if isFileDetailDiff(newStatus[i].FileDetails[j], kb.Status.FileGroupDetail[i].FileDetails[j]) {
 				log.V(5).Info(fmt.Sprintf("fileDetail diff %+v | %+v\n",
 					newStatus[i].FileDetails[j], kb.Status.FileGroupDetail[i].FileDetails[j]))
 				kb.Status.FileGroupDetail = newStatus
-				return true
+				return true, removed
 			}
 		}
 	}
-	return false
+	return false, nil
+}
+
+// removeFiles removes the chunks of the files removed from the knowledgebase
+func (r *KnowledgeBaseReconciler) removeFiles(ctx context.Context, log logr.Logger, kb *arcadiav1alpha1.KnowledgeBase, sources []string) {
+	if len(sources) == 0 || kb.Spec.VectorStore == nil {
+		return
+	}
+	vectorStore := &arcadiav1alpha1.VectorStore{}
+	if err := r.Get(ctx, types.NamespacedName{Name: kb.Spec.VectorStore.Name, Namespace: kb.Spec.VectorStore.GetNamespace(kb.GetNamespace())}, vectorStore); err != nil {
+		// nothing to remove if the vector store is gone
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to get the vector store, the chunks of removed files are kept", "sources", sources)
+		}
+		return
+	}
+	// the removed files are dropped from status even if their chunks can't be removed, such as the vector store
+	// doesn't support deleting, otherwise the status would never be synced
+	for _, source := range sources {
+		if err := vectorstore.RemoveDocumentsBySource(ctx, log, vectorStore, kb.VectorStoreCollectionName(), r.Client, source); err != nil {
+			log.Error(err, "failed to remove the chunks of removed file, they are kept in the vector store", "source", source)
+		}
+	}
 }
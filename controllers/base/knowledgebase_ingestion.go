@@ -0,0 +1,245 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+const (
+	// DefaultIngestionWorkers is the default number of files embedded in parallel
+	DefaultIngestionWorkers = 4
+
+	ingestionBaseDelay = 5 * time.Second
+	ingestionMaxDelay  = 5 * time.Minute
+)
+
+// ingestionTask is a file of a knowledgebase to be embedded
+type ingestionTask struct {
+	knowledgebase types.NamespacedName
+	// source is the key of the file group source in `kind/namespace/name`
+	source string
+	path   string
+}
+
+func sourceKey(kb *arcadiav1alpha1.KnowledgeBase, source *arcadiav1alpha1.TypedObjectReference) string {
+	return fmt.Sprintf("%s/%s/%s", source.Kind, source.GetNamespace(kb.Namespace), source.Name)
+}
+
+// locate returns the index of the file in the status of the knowledgebase
+func (task ingestionTask) locate(kb *arcadiav1alpha1.KnowledgeBase) (int, int) {
+	for out, fg := range kb.Status.FileGroupDetail {
+		if fg.Source == nil || sourceKey(kb, fg.Source) != task.source {
+			continue
+		}
+		for in, f := range fg.FileDetails {
+			if f.Path == task.path {
+				return out, in
+			}
+		}
+	}
+	return -1, -1
+}
+
+// ingestion embeds files in a work queue, so that files can be processed in parallel out of the reconcile loop.
+// Files failed with transient errors are retried with exponential backoff.
+type ingestion struct {
+	workers int
+	queue   workqueue.RateLimitingInterface
+	// process processes a file and returns true if it should be retried later
+	process func(ctx context.Context, log logr.Logger, task ingestionTask) bool
+	// done notifies the reconciler when a file is processed
+	done chan event.GenericEvent
+}
+
+func newIngestion(workers int, rateLimiter workqueue.RateLimiter, process func(ctx context.Context, log logr.Logger, task ingestionTask) bool) *ingestion {
+	if workers <= 0 {
+		workers = DefaultIngestionWorkers
+	}
+	return &ingestion{
+		workers: workers,
+		queue:   workqueue.NewNamedRateLimitingQueue(rateLimiter, "knowledgebase-ingestion"),
+		process: process,
+		done:    make(chan event.GenericEvent, 1024),
+	}
+}
+
+// enqueue adds the file into the queue. A file already in the queue or being processed will not be processed in parallel.
+func (r *KnowledgeBaseReconciler) enqueue(kb *arcadiav1alpha1.KnowledgeBase, fg arcadiav1alpha1.FileGroupDetail, f arcadiav1alpha1.FileDetails) {
+	r.ingestion.add(ingestionTask{
+		knowledgebase: types.NamespacedName{Namespace: kb.Namespace, Name: kb.Name},
+		source:        sourceKey(kb, fg.Source),
+		path:          f.Path,
+	})
+}
+
+// add adds the task into the queue unless it is waiting for a retry
+func (i *ingestion) add(task ingestionTask) {
+	if i.queue.NumRequeues(task) > 0 {
+		return
+	}
+	i.queue.Add(task)
+}
+
+// startIngestion runs the ingestion workers until the context is done
+func (r *KnowledgeBaseReconciler) startIngestion(ctx context.Context) error {
+	r.ingestion.start(ctx, ctrl.Log.WithName("knowledgebase-ingestion"))
+	return nil
+}
+
+func (i *ingestion) start(ctx context.Context, log logr.Logger) {
+	log.Info("start ingestion workers", "workers", i.workers)
+	for n := 0; n < i.workers; n++ {
+		go func() {
+			for i.processNext(ctx, log) {
+			}
+		}()
+	}
+	<-ctx.Done()
+	i.queue.ShutDown()
+}
+
+func (i *ingestion) processNext(ctx context.Context, log logr.Logger) bool {
+	item, shutdown := i.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer i.queue.Done(item)
+	task := item.(ingestionTask)
+	log = log.WithValues("knowledgebase", task.knowledgebase, "source", task.source, "file", task.path)
+	if i.process(ctx, log, task) {
+		i.queue.AddRateLimited(task)
+		return true
+	}
+	i.queue.Forget(task)
+	return true
+}
+
+// shouldRetry returns true if a file failed with the error should be processed again
+func shouldRetry(err error, attempts, maxAttempts int) bool {
+	return err != nil && utils.IsTransientError(err) && attempts < maxAttempts
+}
+
+// ingest processes a file and returns true if it should be retried later
+func (r *KnowledgeBaseReconciler) ingest(ctx context.Context, log logr.Logger, task ingestionTask) bool {
+	// always read the latest status because the file may have been processed by another worker
+	kb := &arcadiav1alpha1.KnowledgeBase{}
+	if err := r.apiReader.Get(ctx, task.knowledgebase, kb); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to get knowledgebase")
+			return true
+		}
+		return false
+	}
+	if kb.GetDeletionTimestamp() != nil {
+		return false
+	}
+	out, in := task.locate(kb)
+	if out < 0 || kb.Status.FileGroupDetail[out].FileDetails[in].Phase != arcadiav1alpha1.FileProcessPhaseProcessing {
+		return false
+	}
+
+	embedder := &arcadiav1alpha1.Embedder{}
+	if err := r.Get(ctx, types.NamespacedName{Name: kb.Spec.Embedder.Name, Namespace: kb.Spec.Embedder.GetNamespace(kb.GetNamespace())}, embedder); err != nil {
+		// the reconciler will update the condition and enqueue the file again when the embedder is ready
+		log.Info("get embedder error " + err.Error())
+		return false
+	}
+	vectorStore := &arcadiav1alpha1.VectorStore{}
+	if err := r.Get(ctx, types.NamespacedName{Name: kb.Spec.VectorStore.Name, Namespace: kb.Spec.VectorStore.GetNamespace(kb.GetNamespace())}, vectorStore); err != nil {
+		log.Info("get vectorstore error " + err.Error())
+		return false
+	}
+
+	log.Info("start to process file")
+	err := r.reconcileFileGroup(ctx, log, kb, vectorStore, embedder, out, in)
+	detail := kb.Status.FileGroupDetail[out].FileDetails[in]
+	detail.Attempts++
+	requeue := false
+	if err != nil {
+		if maxAttempts := kb.EmbeddingOptions().MaxAttempts; shouldRetry(err, detail.Attempts, maxAttempts) {
+			log.Info(fmt.Sprintf("failed to process file with a transient error, retry later. attempts: %d/%d", detail.Attempts, maxAttempts), "error", err.Error())
+			detail.Phase = arcadiav1alpha1.FileProcessPhaseProcessing
+			requeue = true
+		} else {
+			log.Error(err, "failed to process file", "attempts", detail.Attempts)
+		}
+	}
+	if err := r.updateFileDetail(ctx, task, detail); err != nil {
+		log.Error(err, "failed to update the file status")
+		return true
+	}
+	if !requeue {
+		select {
+		case r.ingestion.done <- event.GenericEvent{Object: kb}:
+		case <-ctx.Done():
+		}
+	}
+	return requeue
+}
+
+// updateFileDetail updates the status of a single file, so that files processed in parallel don't overwrite each other
+func (r *KnowledgeBaseReconciler) updateFileDetail(ctx context.Context, task ingestionTask, detail arcadiav1alpha1.FileDetails) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &arcadiav1alpha1.KnowledgeBase{}
+		if err := r.apiReader.Get(ctx, task.knowledgebase, latest); err != nil {
+			return err
+		}
+		out, in := task.locate(latest)
+		if out < 0 {
+			return nil
+		}
+		latest.Status.FileGroupDetail[out].FileDetails[in] = detail
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+// mergeFileDetails keeps the file details in latest which are updated by the ingestion workers after kb is read
+func mergeFileDetails(latest, kb *arcadiav1alpha1.KnowledgeBase) {
+	updated := make(map[string]arcadiav1alpha1.FileDetails)
+	for _, fg := range latest.Status.FileGroupDetail {
+		if fg.Source == nil {
+			continue
+		}
+		for _, f := range fg.FileDetails {
+			updated[sourceKey(latest, fg.Source)+"/"+f.Path] = f
+		}
+	}
+	for out, fg := range kb.Status.FileGroupDetail {
+		if fg.Source == nil {
+			continue
+		}
+		for in, f := range fg.FileDetails {
+			if u, ok := updated[sourceKey(kb, fg.Source)+"/"+f.Path]; ok && u.LastUpdateTime.After(f.LastUpdateTime.Time) {
+				kb.Status.FileGroupDetail[out].FileDetails[in] = u
+			}
+		}
+	}
+}
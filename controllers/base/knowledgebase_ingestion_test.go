@@ -0,0 +1,151 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func TestIngestionRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	attempts := make(map[string][]time.Time)
+	processed := make(chan string, 10)
+	i := newIngestion(2, workqueue.NewItemExponentialFailureRateLimiter(20*time.Millisecond, time.Second), func(_ context.Context, _ logr.Logger, task ingestionTask) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[task.path] = append(attempts[task.path], time.Now())
+		// a.txt fails twice with transient errors
+		if task.path == "a.txt" && len(attempts[task.path]) < 3 {
+			return true
+		}
+		processed <- task.path
+		return false
+	})
+	go i.start(ctx, logr.Discard())
+
+	kb := types.NamespacedName{Namespace: "default", Name: "kb"}
+	i.add(ingestionTask{knowledgebase: kb, source: "Datasource/default/ds", path: "a.txt"})
+	i.add(ingestionTask{knowledgebase: kb, source: "Datasource/default/ds", path: "b.txt"})
+	for n := 0; n < 2; n++ {
+		select {
+		case <-processed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the files to be processed")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(attempts["a.txt"]) != 3 || len(attempts["b.txt"]) != 1 {
+		t.Fatalf("unexpected attempts %v", attempts)
+	}
+	// the retries are delayed with exponential backoff
+	a := attempts["a.txt"]
+	if d := a[1].Sub(a[0]); d < 20*time.Millisecond {
+		t.Errorf("expected the first retry delayed at least 20ms, but got %s", d)
+	}
+	if d := a[2].Sub(a[1]); d < 40*time.Millisecond {
+		t.Errorf("expected the second retry delayed at least 40ms, but got %s", d)
+	}
+	if n := i.queue.NumRequeues(ingestionTask{knowledgebase: kb, source: "Datasource/default/ds", path: "a.txt"}); n != 0 {
+		t.Errorf("expected the backoff forgotten after success, but got %d requeues", n)
+	}
+}
+
+func TestIngestionAddWhileWaitingForRetry(t *testing.T) {
+	i := newIngestion(1, workqueue.NewItemExponentialFailureRateLimiter(time.Hour, time.Hour), func(context.Context, logr.Logger, ingestionTask) bool {
+		return true
+	})
+	defer i.queue.ShutDown()
+	task := ingestionTask{knowledgebase: types.NamespacedName{Namespace: "default", Name: "kb"}, source: "Datasource/default/ds", path: "a.txt"}
+	i.add(task)
+	if !i.processNext(context.Background(), logr.Discard()) {
+		t.Fatal("expected the queue running")
+	}
+	// the reconciler enqueues the file again, it should not skip the backoff
+	i.add(task)
+	if i.queue.Len() != 0 {
+		t.Errorf("expected the file to wait for the retry, but got %d files in the queue", i.queue.Len())
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	testCases := []struct {
+		err      error
+		attempts int
+		retry    bool
+	}{
+		{nil, 1, false},
+		{errors.New("API returned unexpected status code: 429: Rate limit reached"), 1, true},
+		{errors.New("API returned unexpected status code: 429: Rate limit reached"), 3, false},
+		{context.DeadlineExceeded, 2, true},
+		{errors.New("API returned unexpected status code: 400: invalid input"), 1, false},
+		{errors.New("quota exceeded"), 1, false},
+	}
+	for _, tc := range testCases {
+		if got := shouldRetry(tc.err, tc.attempts, 3); got != tc.retry {
+			t.Errorf("%v after %d attempts: expected retry %v, but got %v", tc.err, tc.attempts, tc.retry, got)
+		}
+	}
+}
+
+func TestMergeFileDetails(t *testing.T) {
+	source := &arcadiav1alpha1.TypedObjectReference{Kind: "Datasource", Name: "ds"}
+	before, after := metav1.NewTime(time.Now().Add(-time.Minute)), metav1.Now()
+	kb := &arcadiav1alpha1.KnowledgeBase{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kb"}}
+	kb.Status.FileGroupDetail = []arcadiav1alpha1.FileGroupDetail{{
+		Source: source,
+		FileDetails: []arcadiav1alpha1.FileDetails{
+			{Path: "a.txt", Phase: arcadiav1alpha1.FileProcessPhaseProcessing, LastUpdateTime: before},
+			{Path: "b.txt", Phase: arcadiav1alpha1.FileProcessPhaseProcessing, LastUpdateTime: after},
+		},
+	}}
+	latest := kb.DeepCopy()
+	latest.Status.FileGroupDetail[0].FileDetails[0] = arcadiav1alpha1.FileDetails{Path: "a.txt", Phase: arcadiav1alpha1.FileProcessPhaseSucceeded, LastUpdateTime: after}
+	latest.Status.FileGroupDetail[0].FileDetails[1] = arcadiav1alpha1.FileDetails{Path: "b.txt", Phase: arcadiav1alpha1.FileProcessPhaseSucceeded, LastUpdateTime: before}
+
+	mergeFileDetails(latest, kb)
+	if phase := kb.Status.FileGroupDetail[0].FileDetails[0].Phase; phase != arcadiav1alpha1.FileProcessPhaseSucceeded {
+		t.Errorf("expected the file processed by the workers to be kept, but got %s", phase)
+	}
+	if phase := kb.Status.FileGroupDetail[0].FileDetails[1].Phase; phase != arcadiav1alpha1.FileProcessPhaseProcessing {
+		t.Errorf("expected the newer file detail to be kept, but got %s", phase)
+	}
+
+	task := ingestionTask{knowledgebase: types.NamespacedName{Namespace: "default", Name: "kb"}, source: "Datasource/default/ds", path: "b.txt"}
+	if out, in := task.locate(kb); out != 0 || in != 1 {
+		t.Errorf("expected b.txt at (0, 1), but got (%d, %d)", out, in)
+	}
+	task.path = "c.txt"
+	if out, _ := task.locate(kb); out != -1 {
+		t.Errorf("expected c.txt not found, but got %d", out)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/go-logr/logr"
 	langchainllms "github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/googleai"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,6 +42,9 @@ import (
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
 	"github.com/kubeagi/arcadia/pkg/llms"
+	"github.com/kubeagi/arcadia/pkg/llms/anthropic"
+	"github.com/kubeagi/arcadia/pkg/llms/dashscope"
+	"github.com/kubeagi/arcadia/pkg/llms/ollama"
 	"github.com/kubeagi/arcadia/pkg/llms/openai"
 	"github.com/kubeagi/arcadia/pkg/llms/zhipuai"
 )
@@ -161,6 +165,8 @@ func (r *LLMReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				}
 				return []ctrl.Request{}
 			})).
+		// Check the composite llms again when the status of their backends changes
+		Watches(&source.Kind{Type: &arcadiav1alpha1.LLM{}}, handler.EnqueueRequestsFromMapFunc(r.compositeLLMsOf)).
 		Complete(r)
 }
 
@@ -168,6 +174,10 @@ func (r *LLMReconciler) SetupWithManager(mgr ctrl.Manager) error {
 func (r *LLMReconciler) CheckLLM(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.LLM) error {
 	logger.Info("Checking LLM instance")
 
+	if instance.Spec.Type == llms.Composite {
+		return r.checkCompositeLLM(ctx, logger, instance)
+	}
+
 	switch instance.Spec.Provider.GetType() {
 	case arcadiav1alpha1.ProviderType3rdParty:
 		return r.check3rdPartyLLM(ctx, logger, instance)
@@ -190,6 +200,18 @@ func (r *LLMReconciler) check3rdPartyLLM(ctx context.Context, logger logr.Logger
 		return r.UpdateStatus(ctx, instance, nil, err)
 	}
 
+	// Discover models served by the llm service
+	discovered, err := r.discoverModels(ctx, instance, apiKey)
+	if err != nil {
+		if instance.Spec.Type != llms.Anthropic {
+			return r.UpdateStatus(ctx, instance, nil, fmt.Errorf("failed to discover models: %w", err))
+		}
+		// the models api may not be available in anthropic compatible services, fallback to the default models
+		logger.Info("Failed to discover models, fallback to the default ones", "error", err.Error())
+		discovered = nil
+	}
+	instance.Status.Models = discovered
+
 	models := instance.Get3rdPartyModels()
 	if len(models) == 0 {
 		return r.UpdateStatus(ctx, instance, nil, errors.New("no models provided by this embedder"))
@@ -229,6 +251,39 @@ func (r *LLMReconciler) check3rdPartyLLM(ctx context.Context, logger logr.Logger
 			}
 			msg = strings.Join([]string{msg, res}, "\n")
 		}
+	case llms.DashScope:
+		llmClient := dashscope.NewDashScope(apiKey, false, dashscope.WithBaseURL(instance.Get3rdPartyLLMBaseURL()))
+		// validate against models
+		for _, model := range models {
+			res, err := llmClient.Validate(ctx, langchainllms.WithModel(model))
+			if err != nil {
+				return r.UpdateStatus(ctx, instance, nil, err)
+			}
+			msg = strings.Join([]string{msg, res.String()}, "\n")
+		}
+	case llms.Ollama, llms.OpenAICompatible:
+		// the service is reachable as the models are discovered, so only need to check the models are served
+		if err := checkModelsServed(models, discovered); err != nil {
+			return r.UpdateStatus(ctx, instance, nil, err)
+		}
+		msg = fmt.Sprintf("%d models available", len(discovered))
+	case llms.Anthropic:
+		if len(discovered) != 0 {
+			if err := checkModelsServed(models, discovered); err != nil {
+				return r.UpdateStatus(ctx, instance, nil, err)
+			}
+			msg = fmt.Sprintf("%d models available", len(discovered))
+			break
+		}
+		llmClient := anthropic.NewAnthropic(apiKey, instance.Get3rdPartyLLMBaseURL())
+		// validate against models
+		for _, model := range models {
+			res, err := llmClient.Validate(ctx, langchainllms.WithModel(model))
+			if err != nil {
+				return r.UpdateStatus(ctx, instance, nil, err)
+			}
+			msg = strings.Join([]string{msg, res.String()}, "\n")
+		}
 	default:
 		return r.UpdateStatus(ctx, instance, nil, fmt.Errorf("unsupported service type: %s", instance.Spec.Type))
 	}
@@ -236,6 +291,117 @@ func (r *LLMReconciler) check3rdPartyLLM(ctx context.Context, logger logr.Logger
 	return r.UpdateStatus(ctx, instance, msg, err)
 }
 
+// discoverModels lists the models served by the llm service, returns nil if the llm type doesn't support model discovery
+func (r *LLMReconciler) discoverModels(ctx context.Context, instance *arcadiav1alpha1.LLM, apiKey string) ([]string, error) {
+	var lister llms.ModelLister
+	switch instance.Spec.Type {
+	case llms.Ollama:
+		lister = ollama.NewOllama(instance.Get3rdPartyLLMBaseURL())
+	case llms.OpenAICompatible:
+		lister = openai.NewOpenAICompatible(apiKey, instance.Get3rdPartyLLMBaseURL())
+	case llms.Anthropic:
+		lister = anthropic.NewAnthropic(apiKey, instance.Get3rdPartyLLMBaseURL())
+	default:
+		return nil, nil
+	}
+	models, err := lister.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, errors.New("no models served by this llm")
+	}
+	return models, nil
+}
+
+// checkModelsServed checks all the models are in the served model list
+func checkModelsServed(models, served []string) error {
+	servedSet := make(map[string]bool, len(served))
+	for _, m := range served {
+		servedSet[m] = true
+	}
+	for _, m := range models {
+		if !servedSet[m] {
+			return fmt.Errorf("model %s is not served by this llm", m)
+		}
+	}
+	return nil
+}
+
+func (r *LLMReconciler) checkCompositeLLM(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.LLM) error {
+	logger.Info("Checking composite LLM resource")
+
+	if instance.Spec.Composite == nil || len(instance.Spec.Composite.Backends) == 0 {
+		return r.UpdateStatus(ctx, instance, nil, errors.New("no backends provided by this composite llm"))
+	}
+
+	now := metav1.Now()
+	backends := make([]arcadiav1alpha1.LLMBackendStatus, 0, len(instance.Spec.Composite.Backends))
+	healthy := 0
+	for _, backend := range instance.Spec.Composite.Backends {
+		status := arcadiav1alpha1.LLMBackendStatus{
+			Name:      backend.LLM.Name,
+			Namespace: backend.LLM.GetNamespace(instance.GetNamespace()),
+			Healthy:   true,
+		}
+		backendLLM := &arcadiav1alpha1.LLM{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: status.Namespace, Name: status.Name}, backendLLM); err != nil {
+			status.Healthy = false
+			status.Message = err.Error()
+		} else if backendLLM.Spec.Type == llms.Composite {
+			status.Healthy = false
+			status.Message = "composite llm can't be a backend"
+		} else if msg, ready := backendLLM.Status.LLMReady(); !ready {
+			status.Healthy = false
+			status.Message = msg
+		}
+		// keep the circuit opened by the runtime until the cooldown ends
+		curr := instance.Status.GetBackendStatus(status.Namespace, status.Name)
+		if curr != nil && curr.CircuitOpenUntil != nil && now.Before(curr.CircuitOpenUntil) {
+			status.CircuitOpenUntil = curr.CircuitOpenUntil
+			if status.Healthy {
+				status.Healthy = false
+				status.Message = curr.Message
+			}
+		}
+		status.LastTransitionTime = now
+		if curr != nil && curr.Healthy == status.Healthy {
+			status.LastTransitionTime = curr.LastTransitionTime
+		}
+		if status.Healthy {
+			healthy++
+		}
+		backends = append(backends, status)
+	}
+	instance.Status.Backends = backends
+
+	if healthy == 0 {
+		return r.UpdateStatus(ctx, instance, nil, errors.New("no healthy backends"))
+	}
+	return r.UpdateStatus(ctx, instance, fmt.Sprintf("%d/%d backends are healthy", healthy, len(backends)), nil)
+}
+
+// compositeLLMsOf returns the composite llms which use the llm as a backend
+func (r *LLMReconciler) compositeLLMsOf(o client.Object) []reconcile.Request {
+	list := &arcadiav1alpha1.LLMList{}
+	if err := r.Client.List(context.TODO(), list); err != nil {
+		return []reconcile.Request{}
+	}
+	requests := make([]reconcile.Request, 0)
+	for _, llm := range list.Items {
+		if llm.Spec.Type != llms.Composite || llm.Spec.Composite == nil {
+			continue
+		}
+		for _, backend := range llm.Spec.Composite.Backends {
+			if backend.LLM.Name == o.GetName() && backend.LLM.GetNamespace(llm.GetNamespace()) == o.GetNamespace() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: llm.Namespace, Name: llm.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
 func (r *LLMReconciler) checkWorkerLLM(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.LLM) error {
 	logger.Info("Checking Worker's LLM resource")
 
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+// newModelsServer starts a fake llm service which serves the models by both ollama and openai compatible apis
+func newModelsServer(models ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			tags := make([]map[string]string, 0, len(models))
+			for _, m := range models {
+				tags = append(tags, map[string]string{"name": m, "model": m})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"models": tags})
+		case "/v1/models":
+			data := make([]map[string]string, 0, len(models))
+			for _, m := range models {
+				data = append(data, map[string]string{"id": m, "object": "model"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newLLM(llmType llms.LLMType, url string, models ...string) *arcadiav1alpha1.LLM {
+	llm := &arcadiav1alpha1.LLM{ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "default"}}
+	llm.Spec.Type = llmType
+	llm.Spec.Endpoint = &arcadiav1alpha1.Endpoint{URL: url}
+	llm.Spec.Models = models
+	return llm
+}
+
+func newLLMReconciler(t *testing.T, objs ...client.Object) *LLMReconciler {
+	scheme := runtime.NewScheme()
+	if err := arcadiav1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return &LLMReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), Scheme: scheme}
+}
+
+func TestDiscoverModels(t *testing.T) {
+	server := newModelsServer("qwen:7b", "llama2:latest")
+	defer server.Close()
+	empty := newModelsServer()
+	defer empty.Close()
+
+	testCases := []struct {
+		name   string
+		llm    *arcadiav1alpha1.LLM
+		models []string
+		err    bool
+	}{
+		{name: "ollama", llm: newLLM(llms.Ollama, server.URL), models: []string{"qwen:7b", "llama2:latest"}},
+		{name: "openai compatible", llm: newLLM(llms.OpenAICompatible, server.URL+"/v1"), models: []string{"qwen:7b", "llama2:latest"}},
+		{name: "no models served", llm: newLLM(llms.Ollama, empty.URL), err: true},
+		{name: "unreachable", llm: newLLM(llms.OpenAICompatible, server.URL+"/not-found"), err: true},
+		{name: "discovery not supported", llm: newLLM(llms.ZhiPuAI, server.URL)},
+	}
+	r := newLLMReconciler(t)
+	for _, tc := range testCases {
+		models, err := r.discoverModels(context.Background(), tc.llm, "")
+		if (err != nil) != tc.err {
+			t.Errorf("%s: expected error %v, but got %v", tc.name, tc.err, err)
+			continue
+		}
+		if len(models) != len(tc.models) {
+			t.Errorf("%s: expected models %v, but got %v", tc.name, tc.models, models)
+			continue
+		}
+		for i := range tc.models {
+			if models[i] != tc.models[i] {
+				t.Errorf("%s: expected models %v, but got %v", tc.name, tc.models, models)
+			}
+		}
+	}
+}
+
+func TestCheckModelsServed(t *testing.T) {
+	served := []string{"qwen:7b", "llama2:latest"}
+	testCases := []struct {
+		name   string
+		models []string
+		err    bool
+	}{
+		{name: "all served", models: []string{"llama2:latest", "qwen:7b"}},
+		{name: "nothing required", models: nil},
+		{name: "not served", models: []string{"qwen:7b", "qwen:14b"}, err: true},
+	}
+	for _, tc := range testCases {
+		if err := checkModelsServed(tc.models, served); (err != nil) != tc.err {
+			t.Errorf("%s: expected error %v, but got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func TestCheck3rdPartyLLMDiscovered(t *testing.T) {
+	server := newModelsServer("qwen:7b", "llama2:latest")
+	defer server.Close()
+
+	testCases := []struct {
+		name   string
+		llm    *arcadiav1alpha1.LLM
+		status corev1.ConditionStatus
+	}{
+		{name: "discovered models", llm: newLLM(llms.Ollama, server.URL), status: corev1.ConditionTrue},
+		{name: "served models", llm: newLLM(llms.OpenAICompatible, server.URL+"/v1", "qwen:7b"), status: corev1.ConditionTrue},
+		{name: "model not served", llm: newLLM(llms.Ollama, server.URL, "qwen:14b"), status: corev1.ConditionFalse},
+		{name: "unreachable", llm: newLLM(llms.OpenAICompatible, server.URL+"/not-found"), status: corev1.ConditionFalse},
+	}
+	for _, tc := range testCases {
+		r := newLLMReconciler(t, tc.llm)
+		_ = r.check3rdPartyLLM(context.Background(), logr.Discard(), tc.llm)
+		llm := &arcadiav1alpha1.LLM{}
+		if err := r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llm"}, llm); err != nil {
+			t.Fatal(err)
+		}
+		if condition := llm.Status.GetCondition(arcadiav1alpha1.TypeReady); condition.Status != tc.status {
+			t.Errorf("%s: expected ready %s, but got %s(%s)", tc.name, tc.status, condition.Status, condition.Message)
+		}
+		// the discovered models are recorded in status
+		if tc.status == corev1.ConditionTrue && len(llm.Status.Models) != 2 {
+			t.Errorf("%s: expected the discovered models in status, but got %v", tc.name, llm.Status.Models)
+		}
+	}
+}
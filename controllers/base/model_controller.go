@@ -89,6 +89,7 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	// indicated by the deletion timestamp being set.
 	if instance.GetDeletionTimestamp() != nil && controllerutil.ContainsFinalizer(instance, arcadiav1alpha1.Finalizer) {
 		logger.Info("Performing Finalizer Operations for Model before delete CR")
+		r.CancelImport(instance)
 		// remove all model files from storage service
 		if err := r.RemoveModel(ctx, logger, instance); err != nil {
 			return reconcile.Result{}, fmt.Errorf("failed to remove model: %w", err)
@@ -112,6 +113,18 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// import model files from the model hub if configured
+	if instance.Spec.Import != nil {
+		imported, err := r.ReconcileImport(ctx, logger, instance)
+		if err != nil {
+			return reconcile.Result{RequeueAfter: waitMedium}, err
+		}
+		if !imported {
+			// the import updates the status when done, retry if failed
+			return reconcile.Result{RequeueAfter: waitLonger}, nil
+		}
+	}
+
 	if err := r.CheckModel(ctx, logger, instance); err != nil {
 		// Update conditioned status
 		return reconcile.Result{RequeueAfter: waitMedium}, err
@@ -186,7 +199,8 @@ func (r *ModelReconciler) CheckModel(ctx context.Context, logger logr.Logger, in
 
 	// If source is empty, it means that the data is still sourced from the internal minio and a state check is required,
 	// otherwise we consider the model file for the trans-core service to be ready.
-	if instance.Spec.Source == nil && (instance.Spec.HuggingFaceRepo == "" && instance.Spec.ModelScopeRepo == "") {
+	// The imported model files are in the internal minio as well.
+	if instance.Spec.Source == nil && ((instance.Spec.HuggingFaceRepo == "" && instance.Spec.ModelScopeRepo == "") || instance.IsImported()) {
 		logger.V(5).Info(fmt.Sprintf("model %s source is empty, check minio status.", instance.Name))
 		system, err := config.GetSystemDatasource(ctx)
 		if err != nil {
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/datasource"
+	"github.com/kubeagi/arcadia/pkg/modelimport"
+)
+
+// modelImportReportInterval is the min interval to update the import progress into model status
+const modelImportReportInterval = 5 * time.Second
+
+// modelImport is an import running in background
+type modelImport struct {
+	digest string
+	cancel context.CancelFunc
+}
+
+// modelImports are the running imports by model uid
+var modelImports sync.Map
+
+// ReconcileImport starts an import if the model files haven't been imported, returns true when the import is done
+func (r *ModelReconciler) ReconcileImport(ctx context.Context, logger logr.Logger, instance *arcadiav1alpha1.Model) (bool, error) {
+	digest := instance.ImportDigest()
+	if running, ok := modelImports.Load(instance.UID); ok {
+		if running.(*modelImport).digest == digest {
+			return false, nil
+		}
+		// the repo or import spec changed
+		running.(*modelImport).cancel()
+		modelImports.Delete(instance.UID)
+	}
+	if instance.IsImported() {
+		return true, nil
+	}
+	if instance.Spec.HuggingFaceRepo == "" && instance.Spec.ModelScopeRepo == "" {
+		return false, r.UpdateStatus(ctx, instance, errors.New("huggingFaceRepo or modelScopeRepo is required to import model files"))
+	}
+
+	hub, err := r.modelHub(ctx, instance)
+	if err != nil {
+		return false, r.UpdateStatus(ctx, instance, err)
+	}
+	system, err := config.GetSystemDatasource(ctx)
+	if err != nil {
+		return false, r.UpdateStatus(ctx, instance, err)
+	}
+	endpoint := system.Spec.Endpoint.DeepCopy()
+	if endpoint != nil && endpoint.AuthSecret != nil {
+		endpoint.AuthSecret.WithNameSpace(system.Namespace)
+	}
+	oss, err := datasource.NewOSS(ctx, r.Client, endpoint)
+	if err != nil {
+		return false, r.UpdateStatus(ctx, instance, err)
+	}
+	opts := modelimport.Options{
+		Repo:     instance.Spec.HuggingFaceRepo,
+		Revision: instance.Spec.Revision,
+		Include:  instance.Spec.Import.Include,
+		Exclude:  instance.Spec.Import.Exclude,
+		Prefix:   instance.ObjectPath(),
+	}
+	if opts.Repo == "" {
+		opts.Repo = instance.Spec.ModelScopeRepo
+	}
+
+	// the import runs in background as it takes a long time, and it's resumed if the controller restarts
+	importCtx, cancel := context.WithCancel(context.Background())
+	modelImports.Store(instance.UID, &modelImport{digest: digest, cancel: cancel})
+	key := client.ObjectKeyFromObject(instance)
+	start := metav1.Now()
+	logger.Info("Start to import model files", "repo", opts.Repo, "revision", opts.Revision)
+	go func() {
+		defer cancel()
+		var lastReport time.Time
+		progress, err := modelimport.Import(importCtx, hub, modelimport.NewOSSStore(oss, instance.Namespace), opts, func(p modelimport.Progress) {
+			if time.Since(lastReport) < modelImportReportInterval {
+				return
+			}
+			lastReport = time.Now()
+			r.patchImportStatus(importCtx, logger, key, importStatus(digest, p, start, arcadiav1alpha1.ModelImportPhaseRunning, ""), instance.ImportingCondition())
+		})
+		// canceled by a new import or the deletion
+		if importCtx.Err() != nil {
+			return
+		}
+		modelImports.Delete(instance.UID)
+		status := importStatus(digest, progress, start, arcadiav1alpha1.ModelImportPhaseSucceeded, "")
+		condition := instance.ReadyCondition()
+		if err != nil {
+			logger.Error(err, "Failed to import model files")
+			status.Phase = arcadiav1alpha1.ModelImportPhaseFailed
+			status.Message = err.Error()
+			condition = instance.ErrorCondition(fmt.Sprintf("failed to import model files: %s", err))
+		} else {
+			logger.Info("Import model files done", "commit", progress.Commit, "files", progress.ImportedFiles, "size", progress.ImportedSize)
+		}
+		now := metav1.Now()
+		status.CompletionTime = &now
+		r.patchImportStatus(context.Background(), logger, key, status, condition)
+	}()
+
+	status := importStatus(digest, modelimport.Progress{}, start, arcadiav1alpha1.ModelImportPhaseRunning, "")
+	r.patchImportStatus(ctx, logger, key, status, instance.ImportingCondition())
+	return false, nil
+}
+
+// CancelImport stops the running import of the model
+func (r *ModelReconciler) CancelImport(instance *arcadiav1alpha1.Model) {
+	if running, ok := modelImports.LoadAndDelete(instance.UID); ok {
+		running.(*modelImport).cancel()
+	}
+}
+
+// modelHub to import the model files from
+func (r *ModelReconciler) modelHub(ctx context.Context, instance *arcadiav1alpha1.Model) (modelimport.Hub, error) {
+	var token string
+	if ref := instance.Spec.Import.TokenSecret; ref != nil {
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ref.GetNamespace(instance.Namespace), Name: ref.Name}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get token secret: %w", err)
+		}
+		token = string(secret.Data["token"])
+	}
+	if instance.Spec.HuggingFaceRepo != "" {
+		return modelimport.NewHuggingFace(instance.Spec.Import.HubURL, token), nil
+	}
+	return modelimport.NewModelScope(instance.Spec.Import.HubURL, token), nil
+}
+
+func importStatus(digest string, p modelimport.Progress, start metav1.Time, phase arcadiav1alpha1.ModelImportPhase, msg string) *arcadiav1alpha1.ModelImportStatus {
+	return &arcadiav1alpha1.ModelImportStatus{
+		Phase:         phase,
+		Commit:        p.Commit,
+		TotalFiles:    p.TotalFiles,
+		ImportedFiles: p.ImportedFiles,
+		TotalSize:     p.TotalSize,
+		ImportedSize:  p.ImportedSize,
+		Progress:      p.Percentage(),
+		Message:       msg,
+		Digest:        digest,
+		StartTime:     &start,
+	}
+}
+
+func (r *ModelReconciler) patchImportStatus(ctx context.Context, logger logr.Logger, key types.NamespacedName, status *arcadiav1alpha1.ModelImportStatus, condition arcadiav1alpha1.Condition) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &arcadiav1alpha1.Model{}
+		if err := r.Client.Get(ctx, key, latest); err != nil {
+			return err
+		}
+		latest.Status.Import = status
+		latest.Status.SetConditions(condition)
+		return r.Client.Status().Update(ctx, latest)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to update import status")
+	}
+}
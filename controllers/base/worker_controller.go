@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -119,9 +120,20 @@ func (r *WorkerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// decide replicas by the requests if autoscaling configured
+	requeueAfter := arcadiaworker.Autoscale(worker, time.Now())
+
 	// core rereconcile for worker
 	reconciledWorker, err := r.reconcile(ctx, log, worker)
-	if err != nil {
+	switch {
+	case errors.Is(err, arcadiaworker.ErrModelLoading):
+		// the worker is reconciled when the model files are copied, check again in case the copy failed
+		log.V(1).Info("Waiting for the model files", "reason", err.Error())
+		r.setCondition(reconciledWorker, reconciledWorker.PendingCondition())
+		if requeueAfter == 0 || requeueAfter > waitMedium {
+			requeueAfter = waitMedium
+		}
+	case err != nil:
 		log.Error(err, "Failed to reconcile worker")
 		r.setCondition(worker, worker.ErrorCondition(err.Error()))
 	}
@@ -133,7 +145,7 @@ func (r *WorkerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 		return ctrl.Result{Requeue: true}, updateStatusErr
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *WorkerReconciler) initialize(ctx context.Context, _ logr.Logger, instance *arcadiav1alpha1.Worker) (bool, error) {
@@ -252,6 +264,28 @@ func (r *WorkerReconciler) patchStatus(ctx context.Context, worker *arcadiav1alp
 	return r.Client.Status().Patch(ctx, latest, patch, client.FieldOwner("worker-controller"))
 }
 
+// workersOfModel returns the workers using this model, which need to be reconciled when the model is ready(like imported)
+func (r *WorkerReconciler) workersOfModel(o client.Object) []reconcile.Request {
+	workers := &arcadiav1alpha1.WorkerList{}
+	if err := r.List(context.TODO(), workers); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, worker := range workers.Items {
+		models := []arcadiav1alpha1.TypedObjectReference{worker.Model()}
+		for i := range worker.Spec.Adapters {
+			models = append(models, worker.Adapter(i))
+		}
+		for _, model := range models {
+			if model.Name == o.GetName() && model.Namespace != nil && *model.Namespace == o.GetNamespace() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&worker)})
+				break
+			}
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *WorkerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -260,9 +294,12 @@ func (r *WorkerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				oldWorker := ue.ObjectOld.(*arcadiav1alpha1.Worker)
 				newWorker := ue.ObjectNew.(*arcadiav1alpha1.Worker)
 
-				return !reflect.DeepEqual(oldWorker.Spec, newWorker.Spec) || newWorker.DeletionTimestamp != nil
+				return !reflect.DeepEqual(oldWorker.Spec, newWorker.Spec) || newWorker.DeletionTimestamp != nil ||
+					// requests reported for autoscaling
+					!reflect.DeepEqual(arcadiaworker.RequestsReports(oldWorker), arcadiaworker.RequestsReports(newWorker))
 			},
 		})).
+		Watches(&source.Kind{Type: &arcadiav1alpha1.Model{}}, handler.EnqueueRequestsFromMapFunc(r.workersOfModel)).
 		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
 			pod := o.(*corev1.Pod)
 			if pod.Labels != nil && pod.Labels[arcadiav1alpha1.WorkerPodLabel] != "" {
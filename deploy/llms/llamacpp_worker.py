@@ -0,0 +1,134 @@
+#
+# Copyright contributors to the KubeAGI project
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at:
+#
+#         http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+"""
+A fastchat model worker which forwards the requests to a llama.cpp server,
+so the models served by llama.cpp can be registered into the fastchat controller.
+"""
+
+import argparse
+import json
+import uuid
+
+import requests
+import uvicorn
+from fastchat.serve.base_model_worker import BaseModelWorker, app
+
+worker_id = str(uuid.uuid4())[:8]
+
+
+class LlamaCppWorker(BaseModelWorker):
+    def __init__(self, llamacpp_address: str, context_len: int, **kwargs):
+        super().__init__(**kwargs)
+        self.llamacpp_address = llamacpp_address.rstrip("/")
+        self.context_len = context_len
+        self.init_heart_beat()
+
+    def completion_params(self, params):
+        stop = params.get("stop") or []
+        if isinstance(stop, str):
+            stop = [stop]
+        return {
+            "prompt": params["prompt"],
+            "temperature": float(params.get("temperature", 1.0)),
+            "top_p": float(params.get("top_p", 1.0)),
+            "n_predict": int(params.get("max_new_tokens", 256)),
+            "stop": stop,
+        }
+
+    def generate_stream_gate(self, params):
+        self.call_ct += 1
+        body = self.completion_params(params)
+        body["stream"] = True
+        text = ""
+        try:
+            with requests.post(
+                f"{self.llamacpp_address}/completion", json=body, stream=True
+            ) as resp:
+                for line in resp.iter_lines():
+                    if not line.startswith(b"data: "):
+                        continue
+                    chunk = json.loads(line[len(b"data: ") :])
+                    text += chunk.get("content", "")
+                    ret = {"text": text, "error_code": 0}
+                    if chunk.get("stop"):
+                        ret["usage"] = {
+                            "prompt_tokens": chunk.get("tokens_evaluated", 0),
+                            "completion_tokens": chunk.get("tokens_predicted", 0),
+                            "total_tokens": chunk.get("tokens_evaluated", 0)
+                            + chunk.get("tokens_predicted", 0),
+                        }
+                        ret["finish_reason"] = (
+                            "length" if chunk.get("stopped_limit") else "stop"
+                        )
+                    yield json.dumps(ret).encode() + b"\0"
+        except Exception as e:
+            ret = {"text": f"llama.cpp server error: {e}", "error_code": 50001}
+            yield json.dumps(ret).encode() + b"\0"
+
+    def generate_gate(self, params):
+        for x in self.generate_stream_gate(params):
+            pass
+        return json.loads(x[:-1].decode())
+
+    def get_embeddings(self, params):
+        self.call_ct += 1
+        embeddings, token_num = [], 0
+        for text in params["input"]:
+            resp = requests.post(
+                f"{self.llamacpp_address}/embedding", json={"content": text}
+            )
+            resp.raise_for_status()
+            embeddings.append(resp.json()["embedding"])
+            token_num += self.count_token({"prompt": text})["count"]
+        return {"embedding": embeddings, "token_num": token_num}
+
+    def count_token(self, params):
+        resp = requests.post(
+            f"{self.llamacpp_address}/tokenize", json={"content": params["prompt"]}
+        )
+        resp.raise_for_status()
+        return {"count": len(resp.json()["tokens"]), "error_code": 0}
+
+
+if __name__ == "__main__":
+    parser = argparse.ArgumentParser()
+    parser.add_argument("--host", type=str, default="0.0.0.0")
+    parser.add_argument("--port", type=int, default=21002)
+    parser.add_argument("--worker-address", type=str, required=True)
+    parser.add_argument("--controller-address", type=str, required=True)
+    parser.add_argument("--llamacpp-address", type=str, default="http://127.0.0.1:8080")
+    parser.add_argument("--model-path", type=str, required=True)
+    parser.add_argument("--model-names", type=lambda s: s.split(","), required=True)
+    parser.add_argument("--conv-template", type=str, default=None)
+    parser.add_argument("--context-len", type=int, default=2048)
+    parser.add_argument("--limit-worker-concurrency", type=int, default=5)
+    args = parser.parse_args()
+
+    worker = LlamaCppWorker(
+        llamacpp_address=args.llamacpp_address,
+        context_len=args.context_len,
+        controller_addr=args.controller_address,
+        worker_addr=args.worker_address,
+        worker_id=worker_id,
+        model_path=args.model_path,
+        model_names=args.model_names,
+        limit_worker_concurrency=args.limit_worker_concurrency,
+        conv_template=args.conv_template,
+    )
+    uvicorn.run(app, host=args.host, port=args.port, log_level="info")
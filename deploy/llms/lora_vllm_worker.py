@@ -0,0 +1,112 @@
+#
+# Copyright contributors to the KubeAGI project
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at:
+#
+#         http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+"""
+A fastchat vllm worker which serves LoRA adapters together with the base model.
+Each adapter is registered with its own model name(--lora-modules name=path),
+the requests to an adapter's model name are generated with that adapter.
+"""
+
+import argparse
+import contextvars
+
+import uvicorn
+from fastchat.serve import vllm_worker
+from fastchat.serve.base_model_worker import app, worker_id
+from fastchat.serve.vllm_worker import VLLMWorker
+from vllm import AsyncLLMEngine
+from vllm.engine.arg_utils import AsyncEngineArgs
+from vllm.lora.request import LoRARequest
+
+# model name of the request being generated
+current_model = contextvars.ContextVar("current_model", default=None)
+
+
+class LoRAEngine:
+    """Proxy of the vllm engine which generates with the adapter of current model"""
+
+    def __init__(self, engine, lora_requests):
+        self.engine = engine
+        self.lora_requests = lora_requests
+
+    def generate(self, *args, **kwargs):
+        lora_request = self.lora_requests.get(current_model.get())
+        if lora_request is not None:
+            kwargs["lora_request"] = lora_request
+        return self.engine.generate(*args, **kwargs)
+
+    def __getattr__(self, name):
+        return getattr(self.engine, name)
+
+
+class LoRAVLLMWorker(VLLMWorker):
+    async def generate_stream(self, params):
+        current_model.set(params.get("model"))
+        async for x in super().generate_stream(params):
+            yield x
+
+
+def parse_lora_modules(modules):
+    lora_requests = {}
+    for i, module in enumerate(modules or []):
+        name, path = module.split("=", 1)
+        lora_requests[name] = LoRARequest(name, i + 1, path)
+    return lora_requests
+
+
+if __name__ == "__main__":
+    parser = argparse.ArgumentParser()
+    parser.add_argument("--host", type=str, default="localhost")
+    parser.add_argument("--port", type=int, default=21002)
+    parser.add_argument("--worker-address", type=str, default="http://localhost:21002")
+    parser.add_argument("--controller-address", type=str, default="http://localhost:21001")
+    parser.add_argument("--model-path", type=str, default="lmsys/vicuna-7b-v1.5")
+    parser.add_argument("--model-names", type=lambda s: s.split(","))
+    parser.add_argument("--limit-worker-concurrency", type=int, default=1024)
+    parser.add_argument("--no-register", action="store_true")
+    parser.add_argument("--num-gpus", type=int, default=1)
+    parser.add_argument("--conv-template", type=str, default=None)
+    parser.add_argument("--gpu_memory_utilization", type=float, default=0.9)
+    parser.add_argument("--lora-modules", type=str, nargs="+", default=None, help="LoRA adapters in name=path format")
+    parser = AsyncEngineArgs.add_cli_args(parser)
+    args = parser.parse_args()
+    if args.model_path:
+        args.model = args.model_path
+    if args.num_gpus > 1:
+        args.tensor_parallel_size = args.num_gpus
+
+    lora_requests = parse_lora_modules(args.lora_modules)
+    engine_args = AsyncEngineArgs.from_cli_args(args)
+    engine_args.max_loras = max(engine_args.max_loras, len(lora_requests))
+    engine = LoRAEngine(AsyncLLMEngine.from_engine_args(engine_args), lora_requests)
+
+    worker = LoRAVLLMWorker(
+        args.controller_address,
+        args.worker_address,
+        worker_id,
+        args.model_path,
+        args.model_names,
+        args.limit_worker_concurrency,
+        args.no_register,
+        engine,
+        args.conv_template,
+    )
+    # the api handlers of vllm_worker refer to its module level engine and worker
+    vllm_worker.engine = engine
+    vllm_worker.worker = worker
+    uvicorn.run(app, host=args.host, port=args.port, log_level="info")
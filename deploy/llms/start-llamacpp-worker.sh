@@ -0,0 +1,56 @@
+#!/bin/bash
+#
+# Copyright contributors to the KubeAGI project
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at:
+#
+#         http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# Pick the GGUF model file with the configured quantization(the first one by default)
+MODEL_FILE=$(find $FASTCHAT_MODEL_NAME_PATH -iname "*${LLAMACPP_QUANTIZATION}*.gguf" | sort | head -n 1)
+if [[ $MODEL_FILE == "" ]]; then
+    echo "No GGUF model file found in $FASTCHAT_MODEL_NAME_PATH with quantization '$LLAMACPP_QUANTIZATION'"
+    exit 1
+fi
+
+CONTEXT_LEN=2048
+if [[ $EXTRA_ARGS =~ --ctx-size[[:space:]]+([0-9]+) ]]; then
+    CONTEXT_LEN=${BASH_REMATCH[1]}
+fi
+
+echo "Run llama.cpp server with $MODEL_FILE..."
+/llama-server --model $MODEL_FILE --host 127.0.0.1 --port 8080 $EXTRA_ARGS &
+LLAMACPP_PID=$!
+
+# wait for the model to be loaded, 600 seconds by default
+DEADLINE=$((SECONDS + ${LLAMACPP_LOAD_TIMEOUT:-600}))
+until curl -sf http://127.0.0.1:8080/health > /dev/null; do
+    if ! kill -0 $LLAMACPP_PID 2> /dev/null; then
+        echo "llama.cpp server exited before the model was loaded"
+        exit 1
+    fi
+    if ((SECONDS >= DEADLINE)); then
+        echo "Timeout waiting for llama.cpp server to load $MODEL_FILE"
+        kill $LLAMACPP_PID
+        exit 1
+    fi
+    sleep 1
+done
+
+echo "Run model worker..."
+python3 /llamacpp_worker.py --model-names $FASTCHAT_REGISTRATION_MODEL_NAME \
+    --model-path $FASTCHAT_MODEL_NAME_PATH --worker-address $FASTCHAT_WORKER_ADDRESS \
+    --controller-address $FASTCHAT_CONTROLLER_ADDRESS \
+    --llamacpp-address http://127.0.0.1:8080 --context-len $CONTEXT_LEN \
+    --host 0.0.0.0 --port 21002
@@ -80,9 +80,10 @@ func init() {
 
 func main() {
 	var (
-		configFile      string
-		enableProfiling bool
-		probeAddr       string
+		configFile       string
+		enableProfiling  bool
+		probeAddr        string
+		ingestionWorkers int
 	)
 	flag.StringVar(&configFile, "config", "",
 		"The controller will load its initial configuration from this file. "+
@@ -91,6 +92,8 @@ func main() {
 	flag.BoolVar(&enableProfiling, "profiling", true,
 		"Enable profiling via web interface host:port/debug/pprof/")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.IntVar(&ingestionWorkers, "knowledgebase-ingestion-workers", basecontrollers.DefaultIngestionWorkers,
+		"The number of files embedded in parallel across all knowledgebases.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -213,11 +216,19 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Model")
 		os.Exit(1)
 	}
+	if err = (&basecontrollers.ApplicationBatchRunReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApplicationBatchRun")
+		os.Exit(1)
+	}
 	if err = (&basecontrollers.KnowledgeBaseReconciler{
 		Client:                mgr.GetClient(),
 		Scheme:                mgr.GetScheme(),
 		HasHandledSuccessPath: make(map[string]bool),
 		ReadyMap:              make(map[string]bool),
+		IngestionWorkers:      ingestionWorkers,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KnowledgeBase")
 		os.Exit(1)
@@ -68,23 +68,35 @@ func (p *Executor) Run(ctx context.Context, cli client.Client, args map[string]a
 			return args, errors.New("history not memory.ChatMessageHistory")
 		}
 	}
+	// Only show tool action in the streaming output if configured
+	var handler callbacks.Handler = log.KLogHandler{LogLevel: 3}
+	if instance.Spec.Options.ShowToolAction {
+		if needStream, ok := args[base.InputIsNeedStreamKeyInArg].(bool); ok && needStream {
+			handler = StreamHandler{callbacks.SimpleHandler{}, args}
+		}
+	}
 	// Initialize executor using langchaingo
 	executorOptions := func(o *agents.CreationOptions) {
-		agents.WithCallbacksHandler(log.KLogHandler{LogLevel: 3})(o)
+		agents.WithCallbacksHandler(handler)(o)
 		agents.WithMaxIterations(instance.Spec.Options.MaxIterations)(o)
-		// Only show tool action in the streaming output if configured
-		if instance.Spec.Options.ShowToolAction {
-			if needStream, ok := args[base.InputIsNeedStreamKeyInArg].(bool); ok && needStream {
-				streamHandler := StreamHandler{callbacks.SimpleHandler{}, args}
-				agents.WithCallbacksHandler(streamHandler)(o)
-			}
-		}
 		agents.WithMemory(chain.GetMemory(llm, instance.Spec.AgentConfig.Options.Memory, history, "", ""))(o)
 	}
-	executor, err := agents.Initialize(llm, allowedTools, agents.ZeroShotReactDescription, executorOptions)
-	if err != nil {
-		return args, fmt.Errorf("failed to initialize executor: %w", err)
+	var agent agents.Agent
+	switch instance.Spec.AgentConfig.Type {
+	case v1alpha1.AgentTypeConversational:
+		agent = agents.NewConversationalAgent(llm, allowedTools, executorOptions)
+	case v1alpha1.AgentTypeFunctionCalling:
+		reactAgent := agents.NewOneShotAgent(llm, allowedTools, executorOptions)
+		if caller, ok := ToolCallerOf(llm); ok && len(allowedTools) > 0 {
+			agent = NewFunctionCallingAgent(caller, reactAgent, allowedTools, handler)
+		} else {
+			klog.FromContext(ctx).Info("tool calling is not supported by the llm, fall back to ReAct")
+			agent = reactAgent
+		}
+	default:
+		agent = agents.NewOneShotAgent(llm, allowedTools, executorOptions)
 	}
+	executor := agents.NewExecutor(agent, allowedTools, executorOptions)
 	input := make(map[string]any)
 	input["input"] = fmt.Sprintf("%s, %s", instance.Spec.Prompt, args["question"])
 	response, err := executor.Call(ctx, input)
@@ -0,0 +1,204 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+	langchainllms "github.com/tmc/langchaingo/llms"
+	langchaingoschema "github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/llm"
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+const (
+	// toolInputKey is the only argument of the tools, because the tools take a string as input
+	toolInputKey = "input"
+)
+
+// the function name in the tool calling apis must match ^[a-zA-Z0-9_-]{1,64}$
+var invalidFunctionNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// FunctionCallingAgent is an agent using the native tool calling of the llm, multiple tools can be called in one step.
+// It falls back to the ReAct agent if the llm rejects the tools in the first step.
+type FunctionCallingAgent struct {
+	caller           llms.ToolCaller
+	fallback         agents.Agent
+	callbacksHandler callbacks.Handler
+
+	definitions []llms.ToolDefinition
+	// toolNames maps the function names sent to the llm to the tool names, because a tool name can contain spaces
+	toolNames map[string]string
+
+	useFallback bool
+	// calls keeps the tool calls of each step, the results of the calls are the intermediate steps in the same order
+	calls [][]llms.ToolCall
+}
+
+var _ agents.Agent = (*FunctionCallingAgent)(nil)
+
+func NewFunctionCallingAgent(caller llms.ToolCaller, fallback agents.Agent, allowedTools []tools.Tool, callbacksHandler callbacks.Handler) *FunctionCallingAgent {
+	a := &FunctionCallingAgent{
+		caller:           caller,
+		fallback:         fallback,
+		callbacksHandler: callbacksHandler,
+		toolNames:        make(map[string]string, len(allowedTools)),
+	}
+	for _, tool := range allowedTools {
+		name := invalidFunctionNameChars.ReplaceAllString(tool.Name(), "_")
+		if len(name) > 64 {
+			name = name[:64]
+		}
+		a.toolNames[name] = tool.Name()
+		a.definitions = append(a.definitions, llms.ToolDefinition{
+			Name:        name,
+			Description: tool.Description(),
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					toolInputKey: map[string]any{
+						"type":        "string",
+						"description": "the input of the tool",
+					},
+				},
+				"required": []string{toolInputKey},
+			},
+		})
+	}
+	return a
+}
+
+// ToolCallerOf returns the native tool calling of the llm if supported
+func ToolCallerOf(model langchainllms.Model) (llms.ToolCaller, bool) {
+	if node, ok := model.(*llm.LLM); ok {
+		model = node.Model
+	}
+	caller, ok := model.(llms.ToolCaller)
+	return caller, ok
+}
+
+func (a *FunctionCallingAgent) Plan(ctx context.Context, intermediateSteps []langchaingoschema.AgentStep, inputs map[string]string) ([]langchaingoschema.AgentAction, *langchaingoschema.AgentFinish, error) {
+	if a.useFallback {
+		return a.fallback.Plan(ctx, intermediateSteps, inputs)
+	}
+	if len(intermediateSteps) == 0 {
+		a.calls = nil
+	}
+	resp, err := a.caller.GenerateWithTools(ctx, a.messages(intermediateSteps, inputs), a.definitions)
+	if err != nil {
+		if errors.Is(err, llms.ErrToolCallingNotSupported) && len(intermediateSteps) == 0 {
+			klog.FromContext(ctx).Info("tool calling is not supported by the llm, fall back to ReAct", "error", err)
+			a.useFallback = true
+			return a.fallback.Plan(ctx, intermediateSteps, inputs)
+		}
+		return nil, nil, err
+	}
+	if len(resp.ToolCalls) == 0 {
+		a.stream(ctx, resp.Content)
+		return nil, &langchaingoschema.AgentFinish{
+			ReturnValues: map[string]any{"output": resp.Content},
+			Log:          resp.Content,
+		}, nil
+	}
+
+	calls := make([]llms.ToolCall, 0, len(resp.ToolCalls))
+	actions := make([]langchaingoschema.AgentAction, 0, len(resp.ToolCalls))
+	for i, call := range resp.ToolCalls {
+		// some services don't return the call id, but it's required to pass the results back
+		if call.ID == "" {
+			call.ID = fmt.Sprintf("call_%d_%d", len(a.calls), i)
+		}
+		calls = append(calls, call)
+		tool, ok := a.toolNames[call.Name]
+		if !ok {
+			tool = call.Name
+		}
+		action := langchaingoschema.AgentAction{
+			Tool:      tool,
+			ToolInput: toolInputOf(call.Arguments),
+			Log:       fmt.Sprintf("Invoking: %s with %s\n", tool, call.Arguments),
+		}
+		a.stream(ctx, action.Log)
+		actions = append(actions, action)
+	}
+	a.calls = append(a.calls, calls)
+	return actions, nil, nil
+}
+
+func (a *FunctionCallingAgent) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+func (a *FunctionCallingAgent) GetOutputKeys() []string {
+	return []string{"output"}
+}
+
+func (a *FunctionCallingAgent) messages(steps []langchaingoschema.AgentStep, inputs map[string]string) []llms.ToolMessage {
+	messages := make([]llms.ToolMessage, 0, len(steps)+len(a.calls)+2)
+	// the history is loaded by the memory of the executor
+	if history := inputs["history"]; history != "" {
+		messages = append(messages, llms.ToolMessage{
+			Role:    langchaingoschema.ChatMessageTypeSystem,
+			Content: "The previous conversation:\n" + history,
+		})
+	}
+	messages = append(messages, llms.ToolMessage{Role: langchaingoschema.ChatMessageTypeHuman, Content: inputs["input"]})
+	i := 0
+	for _, calls := range a.calls {
+		messages = append(messages, llms.ToolMessage{Role: langchaingoschema.ChatMessageTypeAI, ToolCalls: calls})
+		for _, call := range calls {
+			observation := ""
+			if i < len(steps) {
+				observation = steps[i].Observation
+			}
+			i++
+			messages = append(messages, llms.ToolMessage{
+				Role:       langchaingoschema.ChatMessageTypeFunction,
+				Content:    observation,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+	return messages
+}
+
+func (a *FunctionCallingAgent) stream(ctx context.Context, text string) {
+	if a.callbacksHandler != nil && text != "" {
+		a.callbacksHandler.HandleStreamingFunc(ctx, []byte(text))
+	}
+}
+
+// toolInputOf gets the input from the arguments generated by the llm, the whole arguments are used if not found
+func toolInputOf(arguments string) string {
+	args := make(map[string]any)
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return arguments
+	}
+	if input, ok := args[toolInputKey].(string); ok {
+		return input
+	}
+	return arguments
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/tmc/langchaingo/agents"
+	langchainllms "github.com/tmc/langchaingo/llms"
+	langchaingoschema "github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+type fakeTool struct {
+	name string
+}
+
+func (t fakeTool) Name() string        { return t.name }
+func (t fakeTool) Description() string { return "fake tool " + t.name }
+func (t fakeTool) Call(_ context.Context, input string) (string, error) {
+	return t.name + ":" + input, nil
+}
+
+type fakeToolCaller struct {
+	responses []*llms.ToolResponse
+	err       error
+	requests  [][]llms.ToolMessage
+}
+
+func (f *fakeToolCaller) GenerateWithTools(_ context.Context, messages []llms.ToolMessage, _ []llms.ToolDefinition, _ ...langchainllms.CallOption) (*llms.ToolResponse, error) {
+	f.requests = append(f.requests, messages)
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+	return resp, nil
+}
+
+type fakeAgent struct {
+	planned bool
+}
+
+func (f *fakeAgent) Plan(context.Context, []langchaingoschema.AgentStep, map[string]string) ([]langchaingoschema.AgentAction, *langchaingoschema.AgentFinish, error) {
+	f.planned = true
+	return nil, &langchaingoschema.AgentFinish{ReturnValues: map[string]any{"output": "react"}}, nil
+}
+func (f *fakeAgent) GetInputKeys() []string  { return []string{"input"} }
+func (f *fakeAgent) GetOutputKeys() []string { return []string{"output"} }
+
+func TestFunctionCallingAgent(t *testing.T) {
+	allowedTools := []tools.Tool{fakeTool{name: "Weather Query API"}, fakeTool{name: "calculator"}}
+	caller := &fakeToolCaller{responses: []*llms.ToolResponse{
+		{ToolCalls: []llms.ToolCall{
+			{ID: "a", Name: "Weather_Query_API", Arguments: `{"input":"Beijing"}`},
+			{ID: "b", Name: "calculator", Arguments: `{"input":"1+1"}`},
+		}},
+		{Content: "done"},
+	}}
+	agent := NewFunctionCallingAgent(caller, &fakeAgent{}, allowedTools, nil)
+	out, err := agents.NewExecutor(agent, allowedTools).Call(context.Background(), map[string]any{"input": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out["output"] != "done" {
+		t.Fatalf("unexpected output: %v", out)
+	}
+	if len(caller.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(caller.requests))
+	}
+	// human, ai with 2 calls and 2 tool results
+	messages := caller.requests[1]
+	if len(messages) != 4 || len(messages[1].ToolCalls) != 2 {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+	if messages[2].ToolCallID != "a" || messages[2].Content != "Weather Query API:Beijing" {
+		t.Fatalf("unexpected tool result: %+v", messages[2])
+	}
+	if messages[3].ToolCallID != "b" || messages[3].Content != "calculator:1+1" {
+		t.Fatalf("unexpected tool result: %+v", messages[3])
+	}
+}
+
+func TestFunctionCallingAgentFallback(t *testing.T) {
+	allowedTools := []tools.Tool{fakeTool{name: "calculator"}}
+	caller := &fakeToolCaller{err: fmt.Errorf("%w: 400 Bad Request", llms.ErrToolCallingNotSupported)}
+	fallback := &fakeAgent{}
+	agent := NewFunctionCallingAgent(caller, fallback, allowedTools, nil)
+	out, err := agents.NewExecutor(agent, allowedTools).Call(context.Background(), map[string]any{"input": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fallback.planned || out["output"] != "react" {
+		t.Fatalf("expected to fall back to ReAct, got %v", out)
+	}
+}
@@ -21,9 +21,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"runtime/debug"
 	"strings"
 
+	langchainllms "github.com/tmc/langchaingo/llms"
 	langchaingoschema "github.com/tmc/langchaingo/schema"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,6 +42,8 @@ import (
 	"github.com/kubeagi/arcadia/pkg/appruntime/llm"
 	"github.com/kubeagi/arcadia/pkg/appruntime/prompt"
 	"github.com/kubeagi/arcadia/pkg/appruntime/retriever"
+	"github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/datasource"
 )
 
 type Input struct {
@@ -48,6 +52,9 @@ type Input struct {
 	// Files from user upload
 	// normally, the user will upload the files to s3 first and use the name of files here
 	Files []string
+	// Images from user upload, which are sent to the llm with the question.
+	// Like files, they are the object names in the system datasource
+	Images []string
 	// overrideConfig
 	NeedStream     bool
 	History        langchaingoschema.ChatMessageHistory
@@ -141,6 +148,16 @@ func (a *Application) Init(ctx context.Context, cli client.Client) (err error) {
 	return nil
 }
 
+// LLM returns the model of the first llm node in the app, which is initialized with the app
+func (a *Application) LLM() (langchainllms.Model, bool) {
+	for _, node := range a.Spec.Nodes {
+		if l, ok := a.Nodes[node.Name].(*llm.LLM); ok && l.Model != nil {
+			return l.Model, true
+		}
+	}
+	return nil, false
+}
+
 func (a *Application) Run(ctx context.Context, cli client.Client, respStream chan string, input Input) (output Output, err error) {
 	out := map[string]any{
 		base.InputQuestionKeyInArg:                 input.Question,
@@ -154,6 +171,13 @@ func (a *Application) Run(ctx context.Context, cli client.Client, respStream cha
 	if a.Spec.DocNullReturn != "" {
 		out[base.APPDocNullReturn] = a.Spec.DocNullReturn
 	}
+	if len(input.Images) > 0 {
+		images, err := a.loadImages(ctx, cli, input.Images)
+		if err != nil {
+			return output, fmt.Errorf("failed to load images: %w", err)
+		}
+		out[base.InputImagesKeyInArg] = images
+	}
 	if input.ConversationID != "" { // means this is not a new conversation
 		conversationKnowledgebaseExist := true
 		kb := &arcadiav1alpha1.KnowledgeBase{}
@@ -331,3 +355,40 @@ func FindNodesHas(app *arcadiav1alpha1.Application, group, kind string) (has boo
 	}
 	return false, "", ""
 }
+
+// loadImages reads the images from the system datasource
+func (a *Application) loadImages(ctx context.Context, cli client.Client, names []string) ([]base.Image, error) {
+	system, err := config.GetSystemDatasource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := system.Spec.Endpoint.DeepCopy()
+	if endpoint != nil && endpoint.AuthSecret != nil {
+		endpoint.AuthSecret.WithNameSpace(system.Namespace)
+	}
+	ossDatasource, err := datasource.NewLocal(ctx, cli, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return readImages(ctx, ossDatasource, a.Namespace, names)
+}
+
+// readImages reads the images from the bucket of the datasource
+func readImages(ctx context.Context, ds datasource.Datasource, bucket string, names []string) ([]base.Image, error) {
+	images := make([]base.Image, 0, len(names))
+	for _, name := range names {
+		data, err := func() ([]byte, error) {
+			file, err := ds.ReadFile(ctx, &arcadiav1alpha1.OSS{Bucket: bucket, Object: name})
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			return io.ReadAll(file)
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("read image %s: %w", name, err)
+		}
+		images = append(images, base.NewImage(name, data))
+	}
+	return images, nil
+}
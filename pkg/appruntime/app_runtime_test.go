@@ -0,0 +1,89 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appruntime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
+	"github.com/kubeagi/arcadia/pkg/appruntime/llm"
+	"github.com/kubeagi/arcadia/pkg/datasource"
+)
+
+type fakeDatasource struct {
+	datasource.Datasource
+	files map[string][]byte
+}
+
+func (f *fakeDatasource) ReadFile(_ context.Context, info any) (io.ReadCloser, error) {
+	oss := info.(*arcadiav1alpha1.OSS)
+	data, ok := f.files[oss.Bucket+"/"+oss.Object]
+	if !ok {
+		return nil, errors.New("no such object")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestReadImages(t *testing.T) {
+	ctx := context.Background()
+	ds := &fakeDatasource{files: map[string][]byte{
+		"default/images/cat.png": []byte("cat"),
+		"default/images/dog.jpg": []byte("dog"),
+	}}
+	images, err := readImages(ctx, ds, "default", []string{"images/cat.png", "images/dog.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 2 || images[0].Name != "images/cat.png" || string(images[0].Data) != "cat" || images[1].MIMEType != "image/jpeg" {
+		t.Errorf("unexpected images %+v", images)
+	}
+
+	if _, err := readImages(ctx, ds, "other", []string{"images/cat.png"}); err == nil || !strings.Contains(err.Error(), "images/cat.png") {
+		t.Errorf("expected the error of the missing image, but got %v", err)
+	}
+}
+
+type fakeModel struct {
+	langchainllms.Model
+}
+
+func TestApplicationLLM(t *testing.T) {
+	model := &fakeModel{}
+	ref := arcadiav1alpha1.TypedObjectReference{Kind: "LLM", Name: "llm"}
+	a := &Application{
+		Spec: arcadiav1alpha1.ApplicationSpec{Nodes: []arcadiav1alpha1.Node{{NodeConfig: arcadiav1alpha1.NodeConfig{Name: "Input"}}, {NodeConfig: arcadiav1alpha1.NodeConfig{Name: "llm-node"}}}},
+		Nodes: map[string]base.Node{
+			"Input":    base.NewInput(base.NewBaseNode("default", "Input", ref)),
+			"llm-node": &llm.LLM{BaseNode: base.NewBaseNode("default", "llm-node", ref), Model: model},
+		},
+	}
+	if got, ok := a.LLM(); !ok || got != model {
+		t.Errorf("expected the model of the llm node, but got %v", got)
+	}
+	delete(a.Nodes, "llm-node")
+	if _, ok := a.LLM(); ok {
+		t.Error("expected no model without llm node")
+	}
+}
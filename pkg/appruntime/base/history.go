@@ -0,0 +1,55 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"strings"
+
+	langchaingoschema "github.com/tmc/langchaingo/schema"
+)
+
+// ConversationHistory is the chat history of a conversation with the context before the history,
+// which is the running summary of the earlier turns and the facts remembered about the user.
+type ConversationHistory struct {
+	langchaingoschema.ChatMessageHistory
+	// Summary of the turns before the history
+	Summary string
+	// Facts remembered about the user from the former conversations
+	Facts []string
+	// SummarizedTurns is the count of turns at the beginning of the history which are merged into the summary in this run,
+	// so the caller can save the summary along with the last summarized turn
+	SummarizedTurns int
+}
+
+func NewConversationHistory(history langchaingoschema.ChatMessageHistory) *ConversationHistory {
+	return &ConversationHistory{ChatMessageHistory: history}
+}
+
+// Context returns the summary and facts as a system message, nil if both are empty
+func (h *ConversationHistory) Context() langchaingoschema.ChatMessage {
+	var parts []string
+	if len(h.Facts) > 0 {
+		parts = append(parts, "Facts about the user:\n- "+strings.Join(h.Facts, "\n- "))
+	}
+	if h.Summary != "" {
+		parts = append(parts, "Summary of the earlier conversation:\n"+h.Summary)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return langchaingoschema.SystemChatMessage{Content: strings.Join(parts, "\n\n")}
+}
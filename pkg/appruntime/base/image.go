@@ -0,0 +1,89 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	langchaingoschema "github.com/tmc/langchaingo/schema"
+)
+
+var imageMIMETypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+}
+
+// IsImage checks whether the file is an image by its extension name
+func IsImage(file string) bool {
+	_, ok := imageMIMETypes[strings.ToLower(filepath.Ext(file))]
+	return ok
+}
+
+// Image is an image from user upload, which is sent to the llm together with the question
+type Image struct {
+	// Name is the object name of the image in the system datasource
+	Name     string
+	MIMEType string
+	Data     []byte
+}
+
+func NewImage(name string, data []byte) Image {
+	return Image{
+		Name:     name,
+		MIMEType: imageMIMETypes[strings.ToLower(filepath.Ext(name))],
+		Data:     data,
+	}
+}
+
+// Base64 returns the image data encoded in base64
+func (i Image) Base64() string {
+	return base64.StdEncoding.EncodeToString(i.Data)
+}
+
+// DataURL returns the image in a data url, which is accepted by the openai compatible apis
+func (i Image) DataURL() string {
+	return fmt.Sprintf("data:%s;base64,%s", i.MIMEType, i.Base64())
+}
+
+var _ langchaingoschema.ChatMessage = MultimodalHumanMessage{}
+
+// MultimodalHumanMessage is a human message with images in the chat history.
+// The memory only keeps the text in the history buffer, so the images are referred by their names
+// and won't be sent to the llm again.
+type MultimodalHumanMessage struct {
+	Content string
+	Images  []string
+}
+
+func (m MultimodalHumanMessage) GetType() langchaingoschema.ChatMessageType {
+	return langchaingoschema.ChatMessageTypeHuman
+}
+
+func (m MultimodalHumanMessage) GetContent() string {
+	content := m.Content
+	for _, image := range m.Images {
+		content += fmt.Sprintf("\n[image: %s]", filepath.Base(image))
+	}
+	return content
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"testing"
+
+	langchaingoschema "github.com/tmc/langchaingo/schema"
+)
+
+func TestIsImage(t *testing.T) {
+	testCases := map[string]bool{
+		"a.png":          true,
+		"dir/b.JPG":      true,
+		"c.jpeg":         true,
+		"d.webp":         true,
+		"e.pdf":          false,
+		"png":            false,
+		"image.png.txt":  false,
+		"no-extension":   false,
+		"dir.png/f.docx": false,
+	}
+	for file, expected := range testCases {
+		if got := IsImage(file); got != expected {
+			t.Errorf("%s: expected %v, but got %v", file, expected, got)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	image := NewImage("images/cat.PNG", []byte("cat"))
+	if image.MIMEType != "image/png" {
+		t.Errorf("expected image/png, but got %s", image.MIMEType)
+	}
+	if image.Base64() != "Y2F0" {
+		t.Errorf("expected Y2F0, but got %s", image.Base64())
+	}
+	if image.DataURL() != "data:image/png;base64,Y2F0" {
+		t.Errorf("unexpected data url %s", image.DataURL())
+	}
+}
+
+func TestMultimodalHumanMessage(t *testing.T) {
+	var message langchaingoschema.ChatMessage = MultimodalHumanMessage{Content: "what are they?", Images: []string{"images/cat.png", "images/dog.jpg"}}
+	if message.GetType() != langchaingoschema.ChatMessageTypeHuman {
+		t.Errorf("expected a human message, but got %s", message.GetType())
+	}
+	if content := message.GetContent(); content != "what are they?\n[image: cat.png]\n[image: dog.jpg]" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
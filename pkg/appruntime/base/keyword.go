@@ -19,6 +19,7 @@ package base
 const (
 	InputQuestionKeyInArg                 = "question"
 	InputIsNeedStreamKeyInArg             = "_need_stream"
+	InputImagesKeyInArg                   = "_images"
 	LangchaingoChatMessageHistoryKeyInArg = "_history"
 	OutputAnserKeyInArg                   = "_answer"
 	AgentOutputInArg                      = "_agent_answer"
@@ -27,6 +28,7 @@ const (
 	RuntimeRetrieverReferencesKeyInArg    = "_references"
 	LangchaingoRetrieverKeyInArg          = "retriever"
 	LangchaingoLLMKeyInArg                = "llm"
+	LangchaingoAnswerLLMKeyInArg          = "_answer_llm" // the llm sending the images with the question, only used to generate the answer
 	LangchaingoPromptKeyInArg             = "prompt"
 	APPDocNullReturn                      = "_app_doc_null_return"
 	ConversationKnowledgeBaseInArg        = "_conversation_knowledgebase" // the conversation Knowledgebase cr in args, status has ready
@@ -88,6 +88,7 @@ func (l *APIChain) Run(ctx context.Context, _ client.Client, args map[string]any
 	options := GetChainOptions(instance.Spec.CommonChainConfig)
 
 	chain := chains.NewAPIChain(llm, http.DefaultClient)
+	chain.AnswerChain.LLM = answerLLM(args, llm)
 	chain.RequestChain.Memory = GetMemory(llm, instance.Spec.Memory, history, "", "")
 	chain.AnswerChain.Memory = GetMemory(llm, instance.Spec.Memory, history, "input", "")
 	l.APIChain = chain
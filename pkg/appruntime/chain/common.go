@@ -83,6 +83,14 @@ func GetChainOptions(config v1alpha1.CommonChainConfig) []chains.ChainCallOption
 	return options
 }
 
+// answerLLM returns the llm to generate the answer, which sends the images of the question if any
+func answerLLM(args map[string]any, llm llms.Model) llms.Model {
+	if answer, ok := args[base.LangchaingoAnswerLLMKeyInArg].(llms.Model); ok {
+		return answer
+	}
+	return llm
+}
+
 func GetMemory(llm llms.Model, config v1alpha1.Memory, history langchaingoschema.ChatMessageHistory, inputKey, outputKey string) langchaingoschema.Memory {
 	if inputKey == "" {
 		inputKey = "question"
@@ -90,13 +98,27 @@ func GetMemory(llm llms.Model, config v1alpha1.Memory, history langchaingoschema
 	if outputKey == "" {
 		outputKey = "text"
 	}
-	if config.MaxTokenLimit > 0 {
-		return memory.NewConversationTokenBuffer(llm, config.MaxTokenLimit, memory.WithInputKey(inputKey), memory.WithOutputKey(outputKey), memory.WithChatHistory(history))
-	}
-	if config.ConversionWindowSize != nil {
-		return memory.NewConversationWindowBuffer(*config.ConversionWindowSize, memory.WithInputKey(inputKey), memory.WithOutputKey(outputKey), memory.WithChatHistory(history))
-	}
-	return memory.NewSimple()
+	conversationHistory, ok := history.(*base.ConversationHistory)
+	if config.SummaryTokenLimit > 0 {
+		if !ok {
+			conversationHistory = base.NewConversationHistory(history)
+		}
+		return NewConversationSummaryBuffer(llm, config.SummaryTokenLimit, conversationHistory, memory.WithInputKey(inputKey), memory.WithOutputKey(outputKey))
+	}
+	var mem langchaingoschema.Memory
+	switch {
+	case config.MaxTokenLimit > 0:
+		mem = memory.NewConversationTokenBuffer(llm, config.MaxTokenLimit, memory.WithInputKey(inputKey), memory.WithOutputKey(outputKey), memory.WithChatHistory(history))
+	case config.ConversionWindowSize != nil:
+		mem = memory.NewConversationWindowBuffer(*config.ConversionWindowSize, memory.WithInputKey(inputKey), memory.WithOutputKey(outputKey), memory.WithChatHistory(history))
+	default:
+		return memory.NewSimple()
+	}
+	// the facts remembered about the user are still useful without the running summary
+	if ok {
+		return &contextMemory{Memory: mem, history: conversationHistory}
+	}
+	return mem
 }
 
 /*
@@ -0,0 +1,34 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
+)
+
+func TestAnswerLLM(t *testing.T) {
+	llm, withImages := &fakeSummaryLLM{}, &fakeSummaryLLM{}
+	if got := answerLLM(map[string]any{base.LangchaingoLLMKeyInArg: llm}, llm); got != llm {
+		t.Errorf("expected the llm without images, but got %v", got)
+	}
+	args := map[string]any{base.LangchaingoLLMKeyInArg: llm, base.LangchaingoAnswerLLMKeyInArg: withImages}
+	if got := answerLLM(args, llm); got != withImages {
+		t.Errorf("expected the llm with images to answer, but got %v", got)
+	}
+}
@@ -130,7 +130,7 @@ func (l *LLMChain) Run(ctx context.Context, cli client.Client, args map[string]a
 		args["context"] = fmt.Sprintf("%s\n%s", args["context"], args[base.MapReduceDocumentOutputInArg])
 	}
 
-	chain := chains.NewLLMChain(llm, prompt)
+	chain := chains.NewLLMChain(answerLLM(args, llm), prompt)
 	if history != nil {
 		chain.Memory = GetMemory(llm, instance.Spec.Memory, history, "", "")
 	}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	langchaingoschema "github.com/tmc/langchaingo/schema"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
+)
+
+const DefaultPromptTemplateForSummary = `Progressively summarize the lines of conversation provided, adding onto the previous summary and returning a new summary.
+Keep the names, numbers, facts and decisions mentioned, and write the new summary in the language of the conversation.
+
+Current summary:
+{{.summary}}
+
+New lines of conversation:
+{{.new_lines}}
+
+New summary:`
+
+// contextMemory prepends the summary and facts of the conversation history to the loaded memory variables
+type contextMemory struct {
+	langchaingoschema.Memory
+	history *base.ConversationHistory
+}
+
+func (m *contextMemory) LoadMemoryVariables(ctx context.Context, inputs map[string]any) (map[string]any, error) {
+	vars, err := m.Memory.LoadMemoryVariables(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return withContext(vars, m.history.Context())
+}
+
+func withContext(vars map[string]any, context langchaingoschema.ChatMessage) (map[string]any, error) {
+	if context == nil {
+		return vars, nil
+	}
+	for k, v := range vars {
+		switch v := v.(type) {
+		case string:
+			prefix, err := langchaingoschema.GetBufferString([]langchaingoschema.ChatMessage{context}, "Human", "AI")
+			if err != nil {
+				return nil, err
+			}
+			if v != "" {
+				prefix += "\n" + v
+			}
+			vars[k] = prefix
+		case []langchaingoschema.ChatMessage:
+			vars[k] = append([]langchaingoschema.ChatMessage{context}, v...)
+		}
+	}
+	return vars, nil
+}
+
+// ConversationSummaryBuffer keeps the recent turns within MaxTokenLimit in memory,
+// the older turns are summarized by the llm into the running summary of the history.
+type ConversationSummaryBuffer struct {
+	memory.ConversationBuffer
+	LLM           llms.Model
+	MaxTokenLimit int
+	History       *base.ConversationHistory
+}
+
+func NewConversationSummaryBuffer(llm llms.Model, maxTokenLimit int, history *base.ConversationHistory, options ...memory.ConversationBufferOption) *ConversationSummaryBuffer {
+	options = append(options, memory.WithChatHistory(history))
+	return &ConversationSummaryBuffer{
+		ConversationBuffer: *memory.NewConversationBuffer(options...),
+		LLM:                llm,
+		MaxTokenLimit:      maxTokenLimit,
+		History:            history,
+	}
+}
+
+func (sb *ConversationSummaryBuffer) LoadMemoryVariables(ctx context.Context, inputs map[string]any) (map[string]any, error) {
+	if err := sb.summarize(ctx); err != nil {
+		return nil, err
+	}
+	vars, err := sb.ConversationBuffer.LoadMemoryVariables(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return withContext(vars, sb.History.Context())
+}
+
+// summarize merges the oldest turns into the summary, turn by turn, until the rest of the history fits in MaxTokenLimit
+func (sb *ConversationSummaryBuffer) summarize(ctx context.Context) error {
+	messages, err := sb.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+	cut := 0
+	for cut < len(messages) {
+		tokens, err := sb.countTokens(messages[cut:])
+		if err != nil {
+			return err
+		}
+		if tokens <= sb.MaxTokenLimit {
+			break
+		}
+		cut += 2
+	}
+	if cut == 0 {
+		return nil
+	}
+	if cut > len(messages) {
+		cut = len(messages)
+	}
+	newLines, err := langchaingoschema.GetBufferString(messages[:cut], sb.HumanPrefix, sb.AIPrefix)
+	if err != nil {
+		return err
+	}
+	prompt, err := prompts.NewPromptTemplate(DefaultPromptTemplateForSummary, []string{"summary", "new_lines"}).Format(map[string]any{
+		"summary":   sb.History.Summary,
+		"new_lines": newLines,
+	})
+	if err != nil {
+		return err
+	}
+	summary, err := llms.GenerateFromSinglePrompt(ctx, sb.LLM, prompt)
+	if err != nil {
+		return err
+	}
+	sb.History.Summary = strings.TrimSpace(summary)
+	sb.History.SummarizedTurns += (cut + 1) / 2
+	return sb.ChatHistory.SetMessages(ctx, messages[cut:])
+}
+
+func (sb *ConversationSummaryBuffer) countTokens(messages []langchaingoschema.ChatMessage) (int, error) {
+	buffer, err := langchaingoschema.GetBufferString(messages, sb.HumanPrefix, sb.AIPrefix)
+	if err != nil {
+		return 0, err
+	}
+	return llms.CountTokens("", buffer), nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+
+	"github.com/kubeagi/arcadia/api/app-node/chain/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
+)
+
+type fakeSummaryLLM struct {
+	prompts []string
+}
+
+func (l *fakeSummaryLLM) GenerateContent(_ context.Context, messages []langchainllms.MessageContent, _ ...langchainllms.CallOption) (*langchainllms.ContentResponse, error) {
+	for _, part := range messages[0].Parts {
+		if text, ok := part.(langchainllms.TextContent); ok {
+			l.prompts = append(l.prompts, text.Text)
+		}
+	}
+	return &langchainllms.ContentResponse{Choices: []*langchainllms.ContentChoice{{Content: " the user is bob "}}}, nil
+}
+
+func (l *fakeSummaryLLM) Call(ctx context.Context, prompt string, options ...langchainllms.CallOption) (string, error) {
+	return langchainllms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func TestConversationSummaryBuffer(t *testing.T) {
+	ctx := context.Background()
+	history := base.NewConversationHistory(memory.NewChatMessageHistory())
+	history.Summary = "old summary"
+	for _, turn := range [][2]string{{"my name is bob", "hello bob"}, {strings.Repeat("long question ", 50), "ok"}, {"hi", "hi"}} {
+		_ = history.AddUserMessage(ctx, turn[0])
+		_ = history.AddAIMessage(ctx, turn[1])
+	}
+	llm := &fakeSummaryLLM{}
+	mem := GetMemory(llm, v1alpha1.Memory{SummaryTokenLimit: 20}, history, "", "")
+	vars, err := mem.LoadMemoryVariables(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(llm.prompts) != 1 || !strings.Contains(llm.prompts[0], "old summary") || !strings.Contains(llm.prompts[0], "Human: my name is bob") {
+		t.Fatalf("unexpected summary prompts: %v", llm.prompts)
+	}
+	if history.Summary != "the user is bob" || history.SummarizedTurns != 2 {
+		t.Fatalf("unexpected summary %q of %d turns", history.Summary, history.SummarizedTurns)
+	}
+	want := "System: Summary of the earlier conversation:\nthe user is bob\nHuman: hi\nAI: hi"
+	if vars["history"] != want {
+		t.Fatalf("want history %q, got %q", want, vars["history"])
+	}
+}
+
+func TestGetMemoryWithFacts(t *testing.T) {
+	ctx := context.Background()
+	history := base.NewConversationHistory(memory.NewChatMessageHistory())
+	history.Facts = []string{"the user is bob"}
+	_ = history.AddUserMessage(ctx, "hi")
+	_ = history.AddAIMessage(ctx, "hello")
+	size := 5
+	vars, err := GetMemory(nil, v1alpha1.Memory{ConversionWindowSize: &size}, history, "", "").LoadMemoryVariables(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "System: Facts about the user:\n- the user is bob\nHuman: hi\nAI: hello"
+	if vars["history"] != want {
+		t.Fatalf("want history %q, got %q", want, vars["history"])
+	}
+}
@@ -158,7 +158,7 @@ func (l *RetrievalQAChain) Run(ctx context.Context, cli client.Client, args map[
 		retriever = &appruntimeretriever.Fakeretriever{Docs: []langchainschema.Document{doc}, Name: "AddMapReduceOutputRetriever"}
 	}
 
-	llmChain := chains.NewLLMChain(llm, prompt)
+	llmChain := chains.NewLLMChain(answerLLM(args, llm), prompt)
 	if history != nil {
 		llmChain.Memory = GetMemory(llm, instance.Spec.Memory, history, "", "")
 	}
@@ -93,6 +93,10 @@ func (dl *DocumentLoader) Run(ctx context.Context, cli client.Client, args map[s
 
 	// TODO: skip if document already been processed,just return a abstract summary
 	for _, file := range files {
+		// images are sent to the llm directly, not loaded as text
+		if base.IsImage(file) {
+			continue
+		}
 		ossInfo := &arcadiav1alpha1.OSS{Bucket: dl.RefNamespace()}
 		ossInfo.Object = file
 		klog.Infoln("handling file", ossInfo.Object)
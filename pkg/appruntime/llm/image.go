@@ -0,0 +1,74 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package llm
+
+import (
+	"context"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+var _ langchainllms.Model = (*imageAttacher)(nil)
+
+// imageAttacher attaches the images to the last human message of each call,
+// so the chains with text prompts can send images to vision llms without changes.
+type imageAttacher struct {
+	langchainllms.Model
+	parts []langchainllms.ContentPart
+}
+
+// WithImages wraps the model to send the images with the question
+func WithImages(model langchainllms.Model, llmType llms.LLMType, images []base.Image) langchainllms.Model {
+	parts := make([]langchainllms.ContentPart, 0, len(images))
+	for _, image := range images {
+		parts = append(parts, ImagePart(llmType, image))
+	}
+	return &imageAttacher{Model: model, parts: parts}
+}
+
+// ImagePart converts the image to the content part accepted by the llm
+func ImagePart(llmType llms.LLMType, image base.Image) langchainllms.ContentPart {
+	switch llmType {
+	case llms.Gemini:
+		return langchainllms.BinaryPart(image.MIMEType, image.Data)
+	default:
+		return langchainllms.ImageURLPart(image.DataURL())
+	}
+}
+
+func (a *imageAttacher) Call(ctx context.Context, prompt string, options ...langchainllms.CallOption) (string, error) {
+	return langchainllms.GenerateFromSinglePrompt(ctx, a, prompt, options...)
+}
+
+func (a *imageAttacher) GenerateContent(ctx context.Context, messages []langchainllms.MessageContent, options ...langchainllms.CallOption) (*langchainllms.ContentResponse, error) {
+	withImages := make([]langchainllms.MessageContent, len(messages))
+	copy(withImages, messages)
+	for i := len(withImages) - 1; i >= 0; i-- {
+		if withImages[i].Role != schema.ChatMessageTypeHuman {
+			continue
+		}
+		parts := make([]langchainllms.ContentPart, 0, len(withImages[i].Parts)+len(a.parts))
+		parts = append(parts, withImages[i].Parts...)
+		withImages[i].Parts = append(parts, a.parts...)
+		break
+	}
+	return a.Model.GenerateContent(ctx, withImages, options...)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package llm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/kubeagi/arcadia/pkg/appruntime/base"
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+type fakeModel struct {
+	messages []langchainllms.MessageContent
+}
+
+func (m *fakeModel) GenerateContent(_ context.Context, messages []langchainllms.MessageContent, _ ...langchainllms.CallOption) (*langchainllms.ContentResponse, error) {
+	m.messages = messages
+	return &langchainllms.ContentResponse{Choices: []*langchainllms.ContentChoice{{Content: "ok"}}}, nil
+}
+
+func (m *fakeModel) Call(ctx context.Context, prompt string, options ...langchainllms.CallOption) (string, error) {
+	return langchainllms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestImagePart(t *testing.T) {
+	image := base.NewImage("cat.png", []byte("cat"))
+	if part := ImagePart(llms.Gemini, image); !reflect.DeepEqual(part, langchainllms.BinaryPart("image/png", []byte("cat"))) {
+		t.Errorf("expected binary content for gemini, but got %#v", part)
+	}
+	if part := ImagePart(llms.OpenAI, image); !reflect.DeepEqual(part, langchainllms.ImageURLPart("data:image/png;base64,Y2F0")) {
+		t.Errorf("expected a data url for openai, but got %#v", part)
+	}
+}
+
+func TestImageAttacher(t *testing.T) {
+	ctx := context.Background()
+	model := &fakeModel{}
+	attacher := WithImages(model, llms.OpenAI, []base.Image{base.NewImage("cat.png", []byte("cat"))})
+	messages := []langchainllms.MessageContent{
+		langchainllms.TextParts(schema.ChatMessageTypeSystem, "you are an assistant"),
+		langchainllms.TextParts(schema.ChatMessageTypeHuman, "hello"),
+		langchainllms.TextParts(schema.ChatMessageTypeAI, "hi"),
+		langchainllms.TextParts(schema.ChatMessageTypeHuman, "what is it?"),
+	}
+	if _, err := attacher.GenerateContent(ctx, messages); err != nil {
+		t.Fatal(err)
+	}
+	image := langchainllms.ImageURLPart("data:image/png;base64,Y2F0")
+	if parts := model.messages[3].Parts; len(parts) != 2 || parts[1] != image {
+		t.Errorf("expected the image attached to the last human message, but got %#v", parts)
+	}
+	if len(model.messages[1].Parts) != 1 {
+		t.Errorf("expected the earlier human message unchanged, but got %#v", model.messages[1].Parts)
+	}
+	if len(messages[3].Parts) != 1 {
+		t.Error("expected the messages of the caller unchanged")
+	}
+
+	if _, err := attacher.Call(ctx, "describe it"); err != nil {
+		t.Fatal(err)
+	}
+	if parts := model.messages[0].Parts; len(parts) != 2 || parts[1] != image {
+		t.Errorf("expected the image attached to the prompt, but got %#v", parts)
+	}
+}
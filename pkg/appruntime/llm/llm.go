@@ -51,7 +51,8 @@ func (z *LLM) Init(ctx context.Context, cli client.Client, _ map[string]any) err
 	if err != nil {
 		return fmt.Errorf("can't convert to langchain llm: %w", err)
 	}
-	z.Model = llm
+	// record the token usage of every call made by the chains, agents and retrievers using this llm
+	z.Model = WithUsageRecorder(llm)
 	z.Instance = instance
 	return nil
 }
@@ -60,6 +61,13 @@ func (z *LLM) Run(ctx context.Context, _ client.Client, args map[string]any) (ma
 	args[base.LangchaingoLLMKeyInArg] = z
 	logger := klog.FromContext(ctx)
 	logger.Info("use llm", "name", z.Ref.Name, "namespace", z.RefNamespace())
+	if images, ok := args[base.InputImagesKeyInArg].([]base.Image); ok && len(images) > 0 {
+		if !z.Instance.HasCapability(v1alpha1.LLMCapabilityVision) {
+			return args, fmt.Errorf("llm %s doesn't accept images, add vision to its capabilities if the models are vision capable", z.Ref.Name)
+		}
+		// the images are only sent with the question to answer, not the calls like summarizing the history
+		args[base.LangchaingoAnswerLLMKeyInArg] = WithImages(z, z.Instance.Spec.Type, images)
+	}
 	return args, nil
 }
 
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package llm
+
+import (
+	"context"
+	"sync"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+// Usage is the token usage of llm calls
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	// Requests is the count of llm calls
+	Requests int
+}
+
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.Requests += other.Requests
+}
+
+// UsageCollector collects the token usage of all llm calls with the same context
+type UsageCollector struct {
+	mu    sync.Mutex
+	usage Usage
+}
+
+func (c *UsageCollector) Add(usage Usage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage.Add(usage)
+}
+
+func (c *UsageCollector) Usage() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+type usageCollectorKey struct{}
+
+// WithUsageCollector returns a context with a new UsageCollector,
+// the usage of llm calls with this context will be collected.
+func WithUsageCollector(ctx context.Context) (context.Context, *UsageCollector) {
+	collector := &UsageCollector{}
+	return context.WithValue(ctx, usageCollectorKey{}, collector), collector
+}
+
+func UsageCollectorFromContext(ctx context.Context) *UsageCollector {
+	collector, _ := ctx.Value(usageCollectorKey{}).(*UsageCollector)
+	return collector
+}
+
+var (
+	_ langchainllms.Model = (*usageRecorder)(nil)
+	_ llms.ToolCaller     = (*toolCallingUsageRecorder)(nil)
+)
+
+// usageRecorder records the token usage of each call to the UsageCollector in the context
+type usageRecorder struct {
+	langchainllms.Model
+}
+
+// WithUsageRecorder wraps the model to record the token usage of each call
+func WithUsageRecorder(model langchainllms.Model) langchainllms.Model {
+	switch model.(type) {
+	case *usageRecorder, *toolCallingUsageRecorder:
+		return model
+	}
+	if _, ok := model.(llms.ToolCaller); ok {
+		return &toolCallingUsageRecorder{usageRecorder{Model: model}}
+	}
+	return &usageRecorder{Model: model}
+}
+
+func (r *usageRecorder) Call(ctx context.Context, prompt string, options ...langchainllms.CallOption) (string, error) {
+	return langchainllms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+func (r *usageRecorder) GenerateContent(ctx context.Context, messages []langchainllms.MessageContent, options ...langchainllms.CallOption) (*langchainllms.ContentResponse, error) {
+	resp, err := r.Model.GenerateContent(ctx, messages, options...)
+	if collector := UsageCollectorFromContext(ctx); collector != nil && resp != nil {
+		collector.Add(UsageOf(messages, resp))
+	}
+	return resp, err
+}
+
+// toolCallingUsageRecorder keeps the native tool calling of the wrapped model
+type toolCallingUsageRecorder struct {
+	usageRecorder
+}
+
+func (r *toolCallingUsageRecorder) GenerateWithTools(ctx context.Context, messages []llms.ToolMessage, tools []llms.ToolDefinition, options ...langchainllms.CallOption) (*llms.ToolResponse, error) {
+	resp, err := r.Model.(llms.ToolCaller).GenerateWithTools(ctx, messages, tools, options...)
+	if collector := UsageCollectorFromContext(ctx); collector != nil && resp != nil {
+		mcs := make([]langchainllms.MessageContent, 0, len(messages))
+		for _, m := range messages {
+			mcs = append(mcs, langchainllms.TextParts(m.Role, m.Content))
+		}
+		collector.Add(UsageOf(mcs, &langchainllms.ContentResponse{Choices: []*langchainllms.ContentChoice{{
+			Content:        resp.Content,
+			GenerationInfo: resp.GenerationInfo,
+		}}}))
+	}
+	return resp, err
+}
+
+// UsageOf returns the token usage of a call, which is read from the generation info of the response.
+// The tokens are estimated if not provided by the llm, for example, in streaming mode.
+func UsageOf(messages []langchainllms.MessageContent, resp *langchainllms.ContentResponse) Usage {
+	usage := Usage{Requests: 1}
+	completion := ""
+	for _, choice := range resp.Choices {
+		completion += choice.Content
+		info := choice.GenerationInfo
+		if info == nil {
+			continue
+		}
+		usage.PromptTokens = max(usage.PromptTokens, intOf(info["PromptTokens"]), intOf(info["InputTokens"]))
+		usage.CompletionTokens += max(intOf(info["CompletionTokens"]), intOf(info["OutputTokens"]))
+		usage.TotalTokens = max(usage.TotalTokens, intOf(info["TotalTokens"]))
+	}
+	if usage.CompletionTokens == 0 {
+		usage.CompletionTokens = langchainllms.CountTokens("gpt2", completion)
+	}
+	if usage.PromptTokens == 0 {
+		if usage.TotalTokens > usage.CompletionTokens {
+			usage.PromptTokens = usage.TotalTokens - usage.CompletionTokens
+		} else {
+			prompt := ""
+			for _, m := range messages {
+				for _, part := range m.Parts {
+					if text, ok := part.(langchainllms.TextContent); ok {
+						prompt += text.Text
+					}
+				}
+			}
+			usage.PromptTokens = langchainllms.CountTokens("gpt2", prompt)
+		}
+	}
+	if usage.TotalTokens < usage.PromptTokens+usage.CompletionTokens {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
+
+func intOf(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func TestReadRowsCSV(t *testing.T) {
+	data := "\ufeffid,question,expected\n1,hello,hi\n2,,skipped\n3,\"how, are you\",fine\n"
+	columns, rows, err := ReadRows(strings.NewReader(data), v1alpha1.BatchRunFileFormatCSV, "question")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(columns, []string{"id", "expected"}) {
+		t.Errorf("unexpected columns %v", columns)
+	}
+	want := []Row{
+		{Index: 1, Question: "hello", Fields: map[string]string{"id": "1", "expected": "hi"}},
+		{Index: 3, Question: "how, are you", Fields: map[string]string{"id": "3", "expected": "fine"}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+
+	if _, _, err := ReadRows(strings.NewReader("id,q\n1,hello\n"), v1alpha1.BatchRunFileFormatCSV, "question"); err == nil {
+		t.Error("expect an error without the question column")
+	}
+}
+
+func TestReadRowsJSONL(t *testing.T) {
+	data := `{"question":"hello","id":1}
+
+{"question":"","id":2}
+{"q":"bye","question":"bye","tags":["a"]}
+`
+	columns, rows, err := ReadRows(strings.NewReader(data), v1alpha1.BatchRunFileFormatJSONL, "question")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(columns, []string{"id", "q", "tags"}) {
+		t.Errorf("unexpected columns %v", columns)
+	}
+	want := []Row{
+		{Index: 1, Question: "hello", Fields: map[string]string{"id": "1"}},
+		{Index: 4, Question: "bye", Fields: map[string]string{"q": "bye", "tags": `["a"]`}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+
+	if _, _, err := ReadRows(strings.NewReader("{bad\n"), v1alpha1.BatchRunFileFormatJSONL, "question"); err == nil {
+		t.Error("expect an error of invalid json")
+	}
+}
+
+func TestRun(t *testing.T) {
+	rows := make([]Row, 0, 20)
+	for i := 1; i <= 20; i++ {
+		rows = append(rows, Row{Index: i, Question: fmt.Sprintf("q%d", i)})
+	}
+	var running, maxRunning int32
+	answer := func(ctx context.Context, question string) (Answer, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		switch question {
+		case "q3":
+			return Answer{}, errors.New("failed")
+		case "q5":
+			panic("boom")
+		case "q7":
+			<-ctx.Done()
+			return Answer{}, ctx.Err()
+		}
+		return Answer{Answer: "a" + question[1:]}, nil
+	}
+	var reports int32
+	results, progress := Run(context.Background(), rows, answer, Options{
+		Concurrency: 4,
+		Timeout:     50 * time.Millisecond,
+		OnProgress:  func(Progress) { atomic.AddInt32(&reports, 1) },
+	})
+	if len(results) != len(rows) {
+		t.Fatalf("expect %d results, got %d", len(rows), len(results))
+	}
+	for i, result := range results {
+		if result.Index != i+1 {
+			t.Errorf("result %d is out of order: row %d", i, result.Index)
+		}
+	}
+	if results[0].Answer.Answer != "a1" || results[0].Error != "" {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+	if !strings.Contains(results[4].Error, "panic") {
+		t.Errorf("expect the panic to be recovered, got %q", results[4].Error)
+	}
+	if progress.Total != 20 || progress.Succeeded != 17 || progress.Failed != 3 || progress.Percentage() != 100 {
+		t.Errorf("unexpected progress %+v", progress)
+	}
+	wantFailures := []int{3, 5, 7}
+	for i, failure := range progress.Failures {
+		if failure.Row != wantFailures[i] {
+			t.Errorf("unexpected failures %+v", progress.Failures)
+		}
+	}
+	if reports != 20 {
+		t.Errorf("expect 20 progress reports, got %d", reports)
+	}
+	if maxRunning > 4 {
+		t.Errorf("expect at most 4 questions at the same time, got %d", maxRunning)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rows := []Row{{Index: 1, Question: "q1"}, {Index: 2, Question: "q2"}}
+	results, progress := Run(ctx, rows, func(context.Context, string) (Answer, error) {
+		t.Error("no question should be answered")
+		return Answer{}, nil
+	}, Options{})
+	if progress.Failed != 2 || results[1].Error == "" {
+		t.Errorf("expect all rows to fail, got %+v", progress)
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []Result{
+		{Row: Row{Index: 1, Question: "hello", Fields: map[string]string{"id": "1"}}, Answer: Answer{Answer: "hi <b>"}, Latency: 1500 * time.Millisecond},
+		{Row: Row{Index: 2, Question: "bye", Fields: map[string]string{"id": "2"}}, Error: "timeout"},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteResults(buf, v1alpha1.BatchRunFileFormatJSONL, []string{"id"}, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "hi <b>") {
+		t.Fatalf("unexpected jsonl %s", buf.String())
+	}
+	var row OutputRow
+	if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+		t.Fatal(err)
+	}
+	if row.Row != 2 || row.Error != "timeout" || row.Fields["id"] != "2" {
+		t.Errorf("unexpected row %+v", row)
+	}
+
+	buf.Reset()
+	if err := WriteResults(buf, v1alpha1.BatchRunFileFormatCSV, []string{"id"}, results); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0][0] != "id" || records[0][1] != "row" {
+		t.Fatalf("unexpected csv %v", records)
+	}
+	if !reflect.DeepEqual(records[1][:6], []string{"1", "1", "hello", "hi <b>", "", "1500"}) {
+		t.Errorf("unexpected csv record %v", records[1])
+	}
+
+	// the copied columns with the same names as the output columns are renamed
+	buf.Reset()
+	results[0].Fields["answer"] = "hello"
+	results[0].Fields["input_answer"] = "hey"
+	if err := WriteResults(buf, v1alpha1.BatchRunFileFormatCSV, []string{"answer", "input_answer"}, results); err != nil {
+		t.Fatal(err)
+	}
+	records, err = csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(records[0][:5], []string{"input_answer", "input_input_answer", "row", "question", "answer"}) {
+		t.Errorf("unexpected csv header %v", records[0])
+	}
+	if !reflect.DeepEqual(records[1][:5], []string{"hello", "hey", "1", "hello", "hi <b>"}) {
+		t.Errorf("unexpected csv record %v", records[1])
+	}
+}
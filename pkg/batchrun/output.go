@@ -0,0 +1,142 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchrun
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/appruntime/retriever"
+)
+
+// outputColumns are the columns of the answer file after the columns copied from the input file
+var outputColumns = []string{"row", "question", "answer", "references", "latency", "prompt_tokens", "completion_tokens", "total_tokens", "error"}
+
+// inputColumnPrefix is prepended to the columns copied from the input file which have the same name as the output columns
+const inputColumnPrefix = "input_"
+
+// csvHeader returns the header of the answer file in csv format, the copied columns are renamed to keep the names unique,
+// such as the answer column of the expected answers is renamed to input_answer.
+func csvHeader(columns []string) []string {
+	used := make(map[string]bool, len(columns)+len(outputColumns))
+	for _, name := range outputColumns {
+		used[name] = true
+	}
+	header := make([]string, 0, len(columns)+len(outputColumns))
+	for _, name := range columns {
+		for used[name] {
+			name = inputColumnPrefix + name
+		}
+		used[name] = true
+		header = append(header, name)
+	}
+	return append(header, outputColumns...)
+}
+
+// OutputRow is a line of the answer file in jsonl format
+type OutputRow struct {
+	// Row of the question in the input file, starting from 1
+	Row        int                   `json:"row"`
+	Question   string                `json:"question"`
+	Answer     string                `json:"answer"`
+	References []retriever.Reference `json:"references,omitempty"`
+	// Latency in milliseconds
+	Latency          int64  `json:"latency"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+	Error            string `json:"error,omitempty"`
+	// Fields are the other columns of the question in the input file
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+func newOutputRow(result Result) OutputRow {
+	return OutputRow{
+		Row:              result.Index,
+		Question:         result.Question,
+		Answer:           result.Answer.Answer,
+		References:       result.References,
+		Latency:          result.Latency.Milliseconds(),
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+		TotalTokens:      result.Usage.TotalTokens,
+		Error:            result.Error,
+		Fields:           result.Fields,
+	}
+}
+
+// WriteResults writes the results to the answer file in csv or jsonl format.
+// In csv format, the columns copied from the input file come first, and the references are in json.
+func WriteResults(w io.Writer, format v1alpha1.BatchRunFileFormat, columns []string, results []Result) error {
+	switch format {
+	case v1alpha1.BatchRunFileFormatCSV:
+		return writeCSV(w, columns, results)
+	case v1alpha1.BatchRunFileFormatJSONL:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		for _, result := range results {
+			if err := encoder.Encode(newOutputRow(result)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported format %s", format)
+}
+
+func writeCSV(w io.Writer, columns []string, results []Result) error {
+	writer := csv.NewWriter(w)
+	header := csvHeader(columns)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, result := range results {
+		row := newOutputRow(result)
+		references := ""
+		if len(row.References) > 0 {
+			b, err := json.Marshal(row.References)
+			if err != nil {
+				return err
+			}
+			references = string(b)
+		}
+		record := make([]string, 0, len(header))
+		for _, name := range columns {
+			record = append(record, row.Fields[name])
+		}
+		record = append(record,
+			strconv.Itoa(row.Row),
+			row.Question,
+			row.Answer,
+			references,
+			strconv.FormatInt(row.Latency, 10),
+			strconv.Itoa(row.PromptTokens),
+			strconv.Itoa(row.CompletionTokens),
+			strconv.Itoa(row.TotalTokens),
+			row.Error,
+		)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchrun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+// maxJSONLLineSize is the max size of a line in the jsonl file
+const maxJSONLLineSize = 1 << 20
+
+// Row is a question in the input file
+type Row struct {
+	// Index of the row, starting from 1
+	Index    int
+	Question string
+	// Fields are the other columns of the row, which are copied to the answer file
+	Fields map[string]string
+}
+
+// ReadRows reads the questions from a csv or jsonl file.
+// It returns the names of the other columns in the order of the file, which are copied to the answer file.
+// The rows with an empty question are skipped.
+func ReadRows(r io.Reader, format v1alpha1.BatchRunFileFormat, questionColumn string) ([]string, []Row, error) {
+	switch format {
+	case v1alpha1.BatchRunFileFormatCSV:
+		return readCSV(r, questionColumn)
+	case v1alpha1.BatchRunFileFormatJSONL:
+		return readJSONL(r, questionColumn)
+	}
+	return nil, nil, fmt.Errorf("unsupported format %s", format)
+}
+
+func readCSV(r io.Reader, questionColumn string) ([]string, []Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("empty csv file")
+		}
+		return nil, nil, err
+	}
+	// the utf-8 bom written by excel
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	questionIndex := -1
+	columns := make([]string, 0, len(header))
+	for i, name := range header {
+		if name == questionColumn {
+			questionIndex = i
+			continue
+		}
+		columns = append(columns, name)
+	}
+	if questionIndex < 0 {
+		return nil, nil, fmt.Errorf("question column %s is not found in the csv header", questionColumn)
+	}
+	rows := make([]Row, 0)
+	for index := 1; ; index++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if questionIndex >= len(record) || record[questionIndex] == "" {
+			continue
+		}
+		row := Row{Index: index, Question: record[questionIndex], Fields: make(map[string]string, len(header)-1)}
+		for i, name := range header {
+			if i != questionIndex && i < len(record) {
+				row.Fields[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+func readJSONL(r io.Reader, questionColumn string) ([]string, []Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+	rows := make([]Row, 0)
+	seen := make(map[string]bool)
+	columns := make([]string, 0)
+	for index := 1; scanner.Scan(); index++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		object := make(map[string]any)
+		if err := json.Unmarshal(line, &object); err != nil {
+			return nil, nil, fmt.Errorf("invalid json in line %d: %w", index, err)
+		}
+		question, _ := object[questionColumn].(string)
+		if question == "" {
+			continue
+		}
+		row := Row{Index: index, Question: question, Fields: make(map[string]string, len(object)-1)}
+		// the keys of a json object are not ordered, the new columns of a line are sorted by name
+		names := make([]string, 0, len(object))
+		for name := range object {
+			if name != questionColumn {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			row.Fields[name] = fieldString(object[name])
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return columns, rows, nil
+}
+
+func fieldString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
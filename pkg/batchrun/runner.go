@@ -0,0 +1,176 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchrun
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/memory"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/appruntime"
+	"github.com/kubeagi/arcadia/pkg/appruntime/llm"
+	"github.com/kubeagi/arcadia/pkg/appruntime/retriever"
+)
+
+// MaxFailures is the max number of failed questions reported in progress
+const MaxFailures = 10
+
+// Answer of a question by the application
+type Answer struct {
+	Answer     string
+	References []retriever.Reference
+	Usage      llm.Usage
+}
+
+// AnswerFunc answers a question
+type AnswerFunc func(ctx context.Context, question string) (Answer, error)
+
+// Result of a row
+type Result struct {
+	Row
+	Answer
+	// Latency to answer the question
+	Latency time.Duration
+	// Error if failed to answer the question
+	Error string
+}
+
+// Progress of a run
+type Progress struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	// Failures are the first MaxFailures failed rows
+	Failures []v1alpha1.BatchRunFailure
+}
+
+// Percentage of the answered questions, including the failed ones
+func (p Progress) Percentage() int {
+	if p.Total == 0 {
+		return 100
+	}
+	return (p.Succeeded + p.Failed) * 100 / p.Total
+}
+
+// Options of a run
+type Options struct {
+	// Concurrency is the number of questions answered at the same time
+	Concurrency int
+	// Timeout to answer one question, no timeout if zero
+	Timeout time.Duration
+	// OnProgress is called after each question is answered, it must not block
+	OnProgress func(Progress)
+}
+
+// Run answers the questions of the rows, and returns the results in the order of rows.
+// A failed question doesn't stop the run, its error is in the result.
+// The run stops if ctx is done, and the questions not answered are failed.
+func Run(ctx context.Context, rows []Row, answer AnswerFunc, opts Options) ([]Result, Progress) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	results := make([]Result, len(rows))
+	progress := Progress{Total: len(rows)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				result := runRow(ctx, rows[index], answer, opts.Timeout)
+				mu.Lock()
+				results[index] = result
+				if result.Error == "" {
+					progress.Succeeded++
+				} else {
+					progress.Failed++
+					progress.Failures = append(progress.Failures, v1alpha1.BatchRunFailure{Row: result.Index, Error: result.Error})
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(progress.snapshot())
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range rows {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results, progress.snapshot()
+}
+
+// snapshot copies the progress with the first MaxFailures failures in the order of rows
+func (p Progress) snapshot() Progress {
+	failures := make([]v1alpha1.BatchRunFailure, len(p.Failures))
+	copy(failures, p.Failures)
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Row < failures[j].Row })
+	if len(failures) > MaxFailures {
+		failures = failures[:MaxFailures]
+	}
+	p.Failures = failures
+	return p
+}
+
+func runRow(ctx context.Context, row Row, answer AnswerFunc, timeout time.Duration) (result Result) {
+	result.Row = row
+	if err := ctx.Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	defer func() {
+		if e := recover(); e != nil {
+			result.Error = fmt.Sprintf("a panic occurred: %v", e)
+		}
+		result.Latency = time.Since(start)
+	}()
+	out, err := answer(ctx, row.Question)
+	result.Answer = out
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// NewAppAnswerFunc answers the questions by the application, each question is answered without history
+func NewAppAnswerFunc(ctx context.Context, cli client.Client, app *v1alpha1.Application) (AnswerFunc, error) {
+	runapp, err := appruntime.NewAppOrGetFromCache(ctx, cli, app)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, question string) (Answer, error) {
+		runCtx, usageCollector := llm.WithUsageCollector(ctx)
+		out, err := runapp.Run(runCtx, cli, nil, appruntime.Input{Question: question, NeedStream: false, History: memory.NewChatMessageHistory()})
+		return Answer{Answer: out.Answer, References: out.References, Usage: usageCollector.Usage()}, err
+	}, nil
+}
@@ -187,6 +187,33 @@ func GetRayClusters(ctx context.Context) ([]RayCluster, error) {
 	return config.RayClusters, nil
 }
 
+// GetQuotas gets the quotas of chat, returns nil if no quotas configured
+func GetQuotas(ctx context.Context) ([]Quota, error) {
+	config, err := getConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return config.Quotas, nil
+}
+
+// GetRetentions gets the retentions of conversations, returns nil if no retentions configured
+func GetRetentions(ctx context.Context) ([]Retention, error) {
+	config, err := getConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return config.Retentions, nil
+}
+
+// GetRedaction gets the redaction of chat, returns nil if no redaction configured
+func GetRedaction(ctx context.Context) (*Redaction, error) {
+	config, err := getConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return config.Redaction, nil
+}
+
 // GetDefaultRerankModel gets the default reranking model which is recommended by kubeagi
 func GetDefaultRerankModel(ctx context.Context) (*arcadiav1alpha1.TypedObjectReference, error) {
 	config, err := getConfig(ctx)
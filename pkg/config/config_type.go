@@ -45,6 +45,15 @@ type Config struct {
 	// Streamlit to get the Streamlit configuration
 	// Deprecated: this field no longer maintained
 	Streamlit *Streamlit `json:"streamlit,omitempty"`
+
+	// Quotas limit the usage of applications in chat
+	Quotas []Quota `json:"quotas,omitempty"`
+
+	// Retentions remove the conversations which are not updated for days
+	Retentions []Retention `json:"retentions,omitempty"`
+
+	// Redaction masks the personal information in queries and answers before they are stored
+	Redaction *Redaction `json:"redaction,omitempty"`
 }
 
 // EmbeddingSuite contains everything required to provide embedding service
@@ -66,6 +75,68 @@ type Gateway struct {
 	Controller string `json:"controller,omitempty"`
 }
 
+// QuotaScope is the scope which a quota applies to
+type QuotaScope string
+
+const (
+	QuotaScopeUser        QuotaScope = "user"
+	QuotaScopeApplication QuotaScope = "application"
+	QuotaScopeNamespace   QuotaScope = "namespace"
+)
+
+// Quota limits the requests and tokens of each user, application or namespace in the scope
+type Quota struct {
+	// Scope is user, application or namespace
+	Scope QuotaScope `json:"scope"`
+	// Name of the user, application or namespace this quota applies to,
+	// applies to each one in the scope if empty
+	Name string `json:"name,omitempty"`
+	// Namespace of the application, only for application scope
+	Namespace string `json:"namespace,omitempty"`
+	// TokensPerDay limits the total tokens of llm calls in a day, no limit if zero
+	TokensPerDay int64 `json:"tokensPerDay,omitempty"`
+	// RequestsPerMinute limits the chat requests in a minute, no limit if zero
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+}
+
+// RetentionScope is the scope which a retention applies to
+type RetentionScope string
+
+const (
+	RetentionScopeApplication RetentionScope = "application"
+	RetentionScopeNamespace   RetentionScope = "namespace"
+)
+
+// Retention removes the conversations of applications in the scope, including the soft-deleted ones,
+// after they are not updated for the days. The shortest one applies when several retentions match an application.
+// The facts about the users remembered from the removed conversations are removed too.
+type Retention struct {
+	// Scope is application or namespace
+	Scope RetentionScope `json:"scope"`
+	// Name of the application or namespace this retention applies to, required
+	Name string `json:"name"`
+	// Namespace of the application, required for application scope
+	Namespace string `json:"namespace,omitempty"`
+	// Days to keep the conversations
+	Days int `json:"days"`
+}
+
+// RedactionRule is the kind of personal information to mask
+type RedactionRule string
+
+const (
+	RedactionRuleEmail  RedactionRule = "email"
+	RedactionRulePhone  RedactionRule = "phone"
+	RedactionRuleIDCard RedactionRule = "idCard"
+)
+
+// Redaction masks the personal information in the queries and answers of chat
+type Redaction struct {
+	Enabled bool `json:"enabled"`
+	// Rules to apply, all rules are applied if empty
+	Rules []RedactionRule `json:"rules,omitempty"`
+}
+
 // Streamlit defines the configuration of streamlit app
 // Deprecated: no longer maintained
 type Streamlit struct {
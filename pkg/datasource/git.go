@@ -0,0 +1,646 @@
+/*
+Copyright 2024 KubeAGI.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/minio/minio-go/v7"
+	cryptossh "golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+// gitMetaBlob is the user metadata of an object copied from git, which records the git blob hash
+const gitMetaBlob = "git-blob"
+
+var (
+	_ Datasource = (*Git)(nil)
+
+	ErrGitReadOnly     = errors.New("git datasource is read only")
+	ErrGitNoSuchObject = errors.New("no such file in git repository")
+
+	gitReposMutex sync.Mutex
+	gitRepos      = make(map[string]*Git)
+)
+
+// GetGitRepository returns a cached git repository for the datasource.
+// The repository is cloned again when the datasource's generation changed, and the stale clone is removed.
+func GetGitRepository(ctx context.Context, c client.Client, datasource *v1alpha1.Datasource) (*Git, error) {
+	if datasource.Spec.Type() != v1alpha1.DatasourceTypeGit {
+		return nil, ErrUnknowDatasourceType
+	}
+	gitReposMutex.Lock()
+	g, ok := gitRepos[string(datasource.GetUID())]
+	gitReposMutex.Unlock()
+	if ok && g.generation == datasource.GetGeneration() {
+		return g, nil
+	}
+	endpoint := datasource.Spec.Endpoint.DeepCopy()
+	if endpoint.AuthSecret != nil && endpoint.AuthSecret.Namespace == nil {
+		endpoint.AuthSecret.WithNameSpace(datasource.Namespace)
+	}
+	g, err := NewGit(ctx, c, endpoint, datasource.Spec.Git)
+	if err != nil {
+		return nil, err
+	}
+	if err = g.open(ctx); err != nil {
+		return nil, err
+	}
+	g.generation = datasource.GetGeneration()
+	gitReposMutex.Lock()
+	if previous, ok := gitRepos[string(datasource.GetUID())]; ok {
+		previous.Close()
+	}
+	gitRepos[string(datasource.GetUID())] = g
+	gitReposMutex.Unlock()
+	return g, nil
+}
+
+// RemoveGitRepository evicts the cached repository of the datasource and removes its clone
+func RemoveGitRepository(datasource v1alpha1.Datasource) {
+	gitReposMutex.Lock()
+	g, ok := gitRepos[string(datasource.GetUID())]
+	delete(gitRepos, string(datasource.GetUID()))
+	gitReposMutex.Unlock()
+	if ok {
+		g.Close()
+	}
+}
+
+// Git is a read-only datasource backed by a clone of a git repository in a temporary directory
+type Git struct {
+	url    string
+	config v1alpha1.Git
+	auth   transport.AuthMethod
+
+	// generation of the datasource which this repository is cloned for
+	generation int64
+
+	mu     sync.Mutex
+	commit *object.Commit
+	// dir is the temporary directory of the clone which the commit is read from
+	dir   string
+	paths []*regexp.Regexp
+}
+
+// NewGit creates a git datasource. The repository is cloned lazily.
+func NewGit(ctx context.Context, c client.Client, endpoint *v1alpha1.Endpoint, config *v1alpha1.Git) (*Git, error) {
+	if endpoint == nil || endpoint.URL == "" {
+		return nil, errors.New("no git repository url provided")
+	}
+	g := &Git{url: endpoint.URL}
+	if config != nil {
+		g.config = *config
+	}
+	for _, p := range g.config.Paths {
+		r, err := globToRegexp(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path glob %s: %w", p, err)
+		}
+		g.paths = append(g.paths, r)
+	}
+	if endpoint.AuthSecret != nil {
+		if endpoint.AuthSecret.Namespace == nil {
+			return nil, errors.New("no namespace found for endpoint.authsecret")
+		}
+		data, err := endpoint.AuthData(ctx, *endpoint.AuthSecret.Namespace, c)
+		if err != nil {
+			return nil, err
+		}
+		if g.auth, err = gitAuth(data); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func gitAuth(data map[string][]byte) (transport.AuthMethod, error) {
+	if key := data[v1alpha1.GitSSHPrivateKey]; len(key) != 0 {
+		user := string(data[v1alpha1.GitUsername])
+		if user == "" {
+			user = "git"
+		}
+		auth, err := gitssh.NewPublicKeys(user, key, string(data[v1alpha1.GitPassword]))
+		if err != nil {
+			return nil, err
+		}
+		if knownHosts := data[v1alpha1.GitKnownHosts]; len(knownHosts) != 0 {
+			callback, err := knownHostsCallback(knownHosts)
+			if err != nil {
+				return nil, err
+			}
+			auth.HostKeyCallback = callback
+		} else {
+			// no known_hosts provided, trust the host like `StrictHostKeyChecking=no`
+			auth.HostKeyCallback = cryptossh.InsecureIgnoreHostKey() // nolint:gosec
+		}
+		return auth, nil
+	}
+	if len(data[v1alpha1.GitUsername]) != 0 || len(data[v1alpha1.GitPassword]) != 0 {
+		user := string(data[v1alpha1.GitUsername])
+		if user == "" {
+			// token based auth requires a non-empty username
+			user = "git"
+		}
+		return &githttp.BasicAuth{Username: user, Password: string(data[v1alpha1.GitPassword])}, nil
+	}
+	return nil, nil
+}
+
+func knownHostsCallback(knownHosts []byte) (cryptossh.HostKeyCallback, error) {
+	f, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(knownHosts); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err = f.Close(); err != nil {
+		return nil, err
+	}
+	return gitssh.NewKnownHostsCallback(f.Name())
+}
+
+// open clones the repository and resolves the commit if not cloned yet
+func (g *Git) open(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.commit != nil {
+		return nil
+	}
+	commit, dir, err := g.clone(ctx)
+	if err != nil {
+		return err
+	}
+	g.commit, g.dir = commit, dir
+	return nil
+}
+
+// Close removes the clone. The repository is cloned again if it is used after closed.
+func (g *Git) Close() {
+	g.mu.Lock()
+	dir := g.dir
+	g.commit, g.dir = nil, ""
+	g.mu.Unlock()
+	removeCloneDir(dir)
+}
+
+func removeCloneDir(dir string) {
+	if dir == "" {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		klog.Errorf("failed to remove git clone %s: %v", dir, err)
+	}
+}
+
+// Refresh resolves the head of the tracked branch again, and clones the repository if the head moved.
+// A repository pinned to a tag or commit never changes. It returns true if the commit changed.
+func (g *Git) Refresh(ctx context.Context) (bool, error) {
+	if g.config.Commit != "" || g.config.Tag != "" {
+		return false, g.open(ctx)
+	}
+	head, err := g.remoteHead(ctx)
+	if err != nil {
+		return false, err
+	}
+	g.mu.Lock()
+	current := g.commit
+	g.mu.Unlock()
+	if current != nil && current.Hash == head {
+		return false, nil
+	}
+	commit, dir, err := g.clone(ctx)
+	if err != nil {
+		return false, err
+	}
+	g.mu.Lock()
+	previousDir := g.dir
+	g.commit, g.dir = commit, dir
+	g.mu.Unlock()
+	// the files of the previous commit are not read any more
+	removeCloneDir(previousDir)
+	return current != nil && current.Hash != commit.Hash, nil
+}
+
+// remoteHead lists the remote references to resolve the head of the tracked branch without cloning
+func (g *Git) remoteHead(ctx context.Context) (plumbing.Hash, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{g.url}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: g.auth})
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to list references of %s: %w", g.url, err)
+	}
+	name := plumbing.HEAD
+	if g.config.Branch != "" {
+		name = plumbing.NewBranchReferenceName(g.config.Branch)
+	}
+	byName := make(map[plumbing.ReferenceName]*plumbing.Reference, len(refs))
+	for _, ref := range refs {
+		byName[ref.Name()] = ref
+	}
+	// HEAD is a symbolic reference to the default branch
+	for i := 0; i < 2; i++ {
+		ref, ok := byName[name]
+		if !ok {
+			return plumbing.ZeroHash, fmt.Errorf("reference %s not found in %s", name, g.url)
+		}
+		if ref.Type() == plumbing.HashReference {
+			return ref.Hash(), nil
+		}
+		name = ref.Target()
+	}
+	return plumbing.ZeroHash, fmt.Errorf("failed to resolve reference %s in %s", name, g.url)
+}
+
+// clone clones the repository into a temporary directory and resolves the commit.
+// It returns the commit with the directory, which should be removed when the commit is not read any more.
+func (g *Git) clone(ctx context.Context) (*object.Commit, string, error) {
+	dir, err := os.MkdirTemp("", "arcadia-git-")
+	if err != nil {
+		return nil, "", err
+	}
+	commit, err := g.cloneInto(ctx, dir)
+	if err != nil {
+		removeCloneDir(dir)
+		return nil, "", err
+	}
+	return commit, dir, nil
+}
+
+func (g *Git) cloneInto(ctx context.Context, dir string) (*object.Commit, error) {
+	// fetch only the pinned commit if the server allows, or clone the full history to find it
+	if plumbing.IsHash(g.config.Commit) {
+		commit, err := g.fetchCommit(ctx, dir)
+		if err == nil || !errors.Is(err, git.ErrExactSHA1NotSupported) {
+			return commit, err
+		}
+		// start over in an empty directory
+		if err = os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+		if err = os.Mkdir(dir, 0o700); err != nil {
+			return nil, err
+		}
+	}
+	options := &git.CloneOptions{
+		URL:          g.url,
+		Auth:         g.auth,
+		SingleBranch: true,
+		Depth:        1,
+		Tags:         git.NoTags,
+	}
+	switch {
+	case g.config.Commit != "":
+		// a pinned commit may not be the head of any branch, so the full history is required
+		options.SingleBranch = false
+		options.Depth = 0
+		if g.config.Tag != "" {
+			options.ReferenceName = plumbing.NewTagReferenceName(g.config.Tag)
+		} else if g.config.Branch != "" {
+			options.ReferenceName = plumbing.NewBranchReferenceName(g.config.Branch)
+		}
+	case g.config.Tag != "":
+		options.ReferenceName = plumbing.NewTagReferenceName(g.config.Tag)
+	case g.config.Branch != "":
+		options.ReferenceName = plumbing.NewBranchReferenceName(g.config.Branch)
+	}
+	repo, err := git.CloneContext(ctx, cloneStorage(dir), nil, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", g.url, err)
+	}
+
+	var hash plumbing.Hash
+	if g.config.Commit != "" {
+		h, err := repo.ResolveRevision(plumbing.Revision(g.config.Commit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve commit %s: %w", g.config.Commit, err)
+		}
+		hash = *h
+	} else {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		hash = head.Hash()
+		// an annotated tag points to a tag object instead of a commit
+		if tag, err := repo.TagObject(hash); err == nil {
+			hash = tag.Target
+		}
+	}
+	return repo.CommitObject(hash)
+}
+
+// fetchCommit fetches the pinned commit without history, which requires the server to allow fetching a commit by hash
+func (g *Git) fetchCommit(ctx context.Context, dir string) (*object.Commit, error) {
+	repo, err := git.Init(cloneStorage(dir), nil)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{g.url}})
+	if err != nil {
+		return nil, err
+	}
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec(g.config.Commit + ":refs/heads/pinned")},
+		Auth:     g.auth,
+		Depth:    1,
+		Tags:     git.NoTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commit %s of %s: %w", g.config.Commit, g.url, err)
+	}
+	return repo.CommitObject(plumbing.NewHash(g.config.Commit))
+}
+
+// cloneStorage stores the git objects in the directory instead of memory, because a model repository may be huge
+func cloneStorage(dir string) *filesystem.Storage {
+	return filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault())
+}
+
+// head returns the resolved commit
+func (g *Git) head(ctx context.Context) (*object.Commit, error) {
+	if err := g.open(ctx); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.commit, nil
+}
+
+// Commit returns the resolved commit hash
+func (g *Git) Commit(ctx context.Context) (string, error) {
+	commit, err := g.head(ctx)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash.String(), nil
+}
+
+// Stat checks whether the repository can be cloned and the reference can be resolved
+func (g *Git) Stat(ctx context.Context, info any) error {
+	return g.open(ctx)
+}
+
+func (g *Git) Remove(ctx context.Context, info any) error {
+	return ErrGitReadOnly
+}
+
+// ReadFile reads a file at the resolved commit. A file stored by git lfs is downloaded from the lfs server.
+func (g *Git) ReadFile(ctx context.Context, info any) (io.ReadCloser, error) {
+	file, _, err := g.file(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := g.readFile(ctx, file)
+	return r, err
+}
+
+// readFile returns the content of the file and its size
+func (g *Git) readFile(ctx context.Context, file *object.File) (io.ReadCloser, int64, error) {
+	r, err := file.Reader()
+	if err != nil {
+		return nil, 0, err
+	}
+	if file.Size > maxLFSPointerSize {
+		return r, file.Size, nil
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pointer, ok := parseLFSPointer(data); ok {
+		lfs, err := g.downloadLFS(ctx, pointer)
+		return lfs, pointer.Size, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+}
+
+// StatFile returns a minio.ObjectInfo so that a git file can be handled the same way as an object.
+// The ETag is the git blob hash.
+func (g *Git) StatFile(ctx context.Context, info any) (any, error) {
+	file, commit, err := g.file(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	return objectInfo(commit, file), nil
+}
+
+func (g *Git) GetTags(ctx context.Context, info any) (map[string]string, error) {
+	if _, _, err := g.file(ctx, info); err != nil {
+		return nil, err
+	}
+	return map[string]string{}, nil
+}
+
+// ListObjects lists files matching `spec.git.paths` under the prefix `source`
+func (g *Git) ListObjects(ctx context.Context, source string, info any) (any, error) {
+	commit, err := g.head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]minio.ObjectInfo, 0)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !strings.HasPrefix(f.Name, source) || !g.match(f.Name) {
+			return nil
+		}
+		result = append(result, objectInfo(commit, f))
+		return nil
+	})
+	return result, err
+}
+
+// CopyTo copies the files under the prefix `source` at the resolved commit into the bucket with the object prefix `prefix`.
+// A file is skipped if the object is copied from the same git blob before, and objects not in the repository are removed.
+// It returns the copied commit.
+func (g *Git) CopyTo(ctx context.Context, oss *OSS, bucket, prefix, source string) (string, error) {
+	commit, err := g.head(ctx)
+	if err != nil {
+		return "", err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+	files := make(map[string]bool)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !strings.HasPrefix(f.Name, source) || !g.match(f.Name) {
+			return nil
+		}
+		object := prefix + strings.TrimPrefix(f.Name, source)
+		files[object] = true
+		return g.copyFile(ctx, oss, bucket, object, f)
+	})
+	if err != nil {
+		return "", err
+	}
+	for object := range oss.Client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return "", object.Err
+		}
+		if files[object.Key] {
+			continue
+		}
+		if err := oss.Client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return "", err
+		}
+	}
+	return commit.Hash.String(), nil
+}
+
+// CopyFileTo copies a file at the resolved commit into the bucket as the object
+func (g *Git) CopyFileTo(ctx context.Context, oss *OSS, bucket, object, filePath string) error {
+	f, _, err := g.file(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	return g.copyFile(ctx, oss, bucket, object, f)
+}
+
+// copyFile puts the file into the bucket unless the object is copied from the same git blob before
+func (g *Git) copyFile(ctx context.Context, oss *OSS, bucket, object string, f *object.File) error {
+	if stat, err := oss.Client.StatObject(ctx, bucket, object, minio.StatObjectOptions{}); err == nil &&
+		stat.UserMetadata[gitMetaBlob] == f.Hash.String() {
+		return nil
+	}
+	r, size, err := g.readFile(ctx, f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer r.Close()
+	_, err = oss.Client.PutObject(ctx, bucket, object, r, size, minio.PutObjectOptions{
+		UserMetadata: map[string]string{gitMetaBlob: f.Hash.String()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+func (g *Git) file(ctx context.Context, info any) (*object.File, *object.Commit, error) {
+	var filePath string
+	switch v := info.(type) {
+	case *v1alpha1.OSS:
+		if v != nil {
+			filePath = v.Object
+		}
+	case string:
+		filePath = v
+	}
+	filePath = strings.TrimPrefix(filePath, "/")
+	if filePath == "" {
+		return nil, nil, ErrOSSNoConfig
+	}
+	if !g.match(filePath) {
+		return nil, nil, ErrGitNoSuchObject
+	}
+	commit, err := g.head(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := commit.File(filePath)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil, nil, ErrGitNoSuchObject
+		}
+		return nil, nil, err
+	}
+	return file, commit, nil
+}
+
+func objectInfo(commit *object.Commit, f *object.File) minio.ObjectInfo {
+	return minio.ObjectInfo{
+		Key:          f.Name,
+		Size:         f.Size,
+		ETag:         f.Hash.String(),
+		LastModified: commit.Committer.When,
+		VersionID:    commit.Hash.String(),
+	}
+}
+
+func (g *Git) match(filePath string) bool {
+	if len(g.paths) == 0 {
+		return true
+	}
+	for _, r := range g.paths {
+		if r.MatchString(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp converts a glob to a regular expression.
+// `**` matches any number of directories, `*` matches any characters except `/` and `?` matches one character except `/`.
+// A glob ending with `/` matches all files under the directory.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimPrefix(glob, "/")
+	if strings.HasSuffix(glob, "/") {
+		glob += "**"
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					sb.WriteString("(.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 KubeAGI.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datasource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	// maxLFSPointerSize is the max size of a git lfs pointer file
+	maxLFSPointerSize = 1024
+
+	lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+	lfsMediaType      = "application/vnd.git-lfs+json"
+)
+
+// lfsPointer is the content of a file stored by git lfs
+type lfsPointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// parseLFSPointer parses a git lfs pointer file, false if the content is not a pointer
+func parseLFSPointer(data []byte) (lfsPointer, bool) {
+	pointer := lfsPointer{Size: -1}
+	if !bytes.HasPrefix(data, []byte(lfsPointerVersion)) {
+		return pointer, false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			pointer.OID = strings.TrimPrefix(value, "sha256:")
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return pointer, false
+			}
+			pointer.Size = size
+		}
+	}
+	return pointer, pointer.OID != "" && pointer.Size >= 0
+}
+
+type lfsBatchRequest struct {
+	Operation string       `json:"operation"`
+	Transfers []string     `json:"transfers"`
+	Objects   []lfsPointer `json:"objects"`
+}
+
+type lfsBatchResponse struct {
+	Objects []struct {
+		lfsPointer
+		Actions struct {
+			Download *struct {
+				Href   string            `json:"href"`
+				Header map[string]string `json:"header"`
+			} `json:"download"`
+		} `json:"actions"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+// lfsEndpoint returns the lfs server of the repository, which is `<repository>.git/info/lfs` by default
+func (g *Git) lfsEndpoint() (string, error) {
+	u, err := url.Parse(g.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("git lfs is only supported for http(s) repositories, but got %s", g.url)
+	}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.Path += "/info/lfs"
+	return u.String(), nil
+}
+
+// downloadLFS downloads a file from the lfs server by the basic transfer api
+func (g *Git) downloadLFS(ctx context.Context, pointer lfsPointer) (io.ReadCloser, error) {
+	endpoint, err := g.lfsEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(lfsBatchRequest{Operation: "download", Transfers: []string{"basic"}, Objects: []lfsPointer{pointer}})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	if auth, ok := g.auth.(*githttp.BasicAuth); ok {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("git lfs batch request failed with status code %d", resp.StatusCode)
+	}
+	batch := &lfsBatchResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(batch); err != nil {
+		return nil, fmt.Errorf("invalid git lfs batch response: %w", err)
+	}
+	if len(batch.Objects) != 1 {
+		return nil, errors.New("invalid git lfs batch response: no object found")
+	}
+	object := batch.Objects[0]
+	if object.Error != nil {
+		return nil, fmt.Errorf("git lfs object %s: %d %s", pointer.OID, object.Error.Code, object.Error.Message)
+	}
+	if object.Actions.Download == nil {
+		return nil, fmt.Errorf("git lfs object %s has no download action", pointer.OID)
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, object.Actions.Download.Href, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range object.Actions.Download.Header {
+		req.Header.Set(k, v)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("git lfs download of %s failed with status code %d", pointer.OID, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,390 @@
+/*
+Copyright 2024 KubeAGI.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/minio/minio-go/v7"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+// newBareRepo creates a bare repository with two commits and returns its path with the commit hashes
+func newBareRepo(t *testing.T) (string, []string) {
+	t.Helper()
+	workDir := filepath.Join(t.TempDir(), "work")
+	repo, err := git.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := make([]string, 0)
+	for i, files := range []map[string]string{
+		{"README.md": "v1", "docs/guide.md": "guide v1", "src/main.go": "package main"},
+		{"README.md": "v2", "docs/api/index.md": "api"},
+	} {
+		for name, content := range files {
+			p := filepath.Join(workDir, name)
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := wt.Add(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		hash, err := wt.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@kubeagi.io", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, hash.String())
+		if i == 0 {
+			if _, err := repo.CreateTag("v0.1.0", hash, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	bareDir := filepath.Join(t.TempDir(), "repo.git")
+	if _, err := git.PlainInit(bareDir, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{bareDir}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"}}); err != nil {
+		t.Fatal(err)
+	}
+	return bareDir, commits
+}
+
+func TestGitDatasource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required by the local transport")
+	}
+	ctx := context.Background()
+	url, commits := newBareRepo(t)
+
+	testCases := []struct {
+		name       string
+		config     *v1alpha1.Git
+		commit     string
+		readme     string
+		listPrefix string
+		files      []string
+	}{
+		{name: "default branch", config: nil, commit: commits[1], readme: "v2", files: []string{"README.md", "docs/api/index.md", "docs/guide.md", "src/main.go"}},
+		{name: "tag", config: &v1alpha1.Git{Tag: "v0.1.0"}, commit: commits[0], readme: "v1", files: []string{"README.md", "docs/guide.md", "src/main.go"}},
+		{name: "pinned commit", config: &v1alpha1.Git{Commit: commits[0]}, commit: commits[0], readme: "v1", files: []string{"README.md", "docs/guide.md", "src/main.go"}},
+		{name: "path globs", config: &v1alpha1.Git{Paths: []string{"docs/**/*.md", "README.md"}}, commit: commits[1], readme: "v2", files: []string{"README.md", "docs/api/index.md", "docs/guide.md"}},
+		{name: "list prefix", config: &v1alpha1.Git{Branch: "master"}, commit: commits[1], readme: "v2", listPrefix: "docs/", files: []string{"docs/api/index.md", "docs/guide.md"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := NewGit(ctx, nil, &v1alpha1.Endpoint{URL: url}, tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := g.Stat(ctx, nil); err != nil {
+				t.Fatalf("unexpected stat error: %v", err)
+			}
+			commit, err := g.Commit(ctx)
+			if err != nil || commit != tc.commit {
+				t.Fatalf("expected commit %s, but got %s(%v)", tc.commit, commit, err)
+			}
+			r, err := g.ReadFile(ctx, &v1alpha1.OSS{Object: "README.md"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			content, _ := io.ReadAll(r)
+			if string(content) != tc.readme {
+				t.Errorf("expected README.md %q, but got %q", tc.readme, content)
+			}
+			objects, err := g.ListObjects(ctx, tc.listPrefix, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0)
+			for _, o := range objects.([]minio.ObjectInfo) {
+				got = append(got, o.Key)
+			}
+			if len(got) != len(tc.files) {
+				t.Fatalf("expected files %v, but got %v", tc.files, got)
+			}
+			for i := range got {
+				if got[i] != tc.files[i] {
+					t.Errorf("expected files %v, but got %v", tc.files, got)
+				}
+			}
+			stat, err := g.StatFile(ctx, &v1alpha1.OSS{Object: "README.md"})
+			if err != nil || stat.(minio.ObjectInfo).ETag == "" {
+				t.Errorf("unexpected stat %v(%v)", stat, err)
+			}
+			if _, err := g.ReadFile(ctx, &v1alpha1.OSS{Object: "not-exist"}); err != ErrGitNoSuchObject {
+				t.Errorf("expected %v, but got %v", ErrGitNoSuchObject, err)
+			}
+		})
+	}
+}
+
+// pushCommit commits a file to the default branch of the bare repository and returns the commit hash
+func pushCommit(t *testing.T, bareDir, name, content string) string {
+	t.Helper()
+	workDir := filepath.Join(t.TempDir(), "push")
+	repo, err := git.PlainClone(workDir, false, &git.CloneOptions{URL: bareDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add(name); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("update "+name, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@kubeagi.io", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Push(&git.PushOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func TestGitRefresh(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required by the local transport")
+	}
+	ctx := context.Background()
+	url, commits := newBareRepo(t)
+
+	branch, err := NewGit(ctx, nil, &v1alpha1.Endpoint{URL: url}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := NewGit(ctx, nil, &v1alpha1.Endpoint{URL: url}, &v1alpha1.Git{Tag: "v0.1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first refresh clones the repository
+	for _, g := range []*Git{branch, tag} {
+		if changed, err := g.Refresh(ctx); err != nil || changed {
+			t.Fatalf("expected the first refresh unchanged, but got %v(%v)", changed, err)
+		}
+	}
+	if changed, err := branch.Refresh(ctx); err != nil || changed {
+		t.Fatalf("expected no change without new commits, but got %v(%v)", changed, err)
+	}
+
+	latest := pushCommit(t, url, "README.md", "v3")
+	// the cached commit is used until refreshed
+	if commit, _ := branch.Commit(ctx); commit != commits[1] {
+		t.Errorf("expected commit %s before refresh, but got %s", commits[1], commit)
+	}
+	if changed, err := branch.Refresh(ctx); err != nil || !changed {
+		t.Fatalf("expected the branch head changed, but got %v(%v)", changed, err)
+	}
+	if commit, _ := branch.Commit(ctx); commit != latest {
+		t.Errorf("expected commit %s after refresh, but got %s", latest, commit)
+	}
+	r, err := branch.ReadFile(ctx, "README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if content, _ := io.ReadAll(r); string(content) != "v3" {
+		t.Errorf("expected README.md v3, but got %q", content)
+	}
+
+	// a tag never moves
+	if changed, err := tag.Refresh(ctx); err != nil || changed {
+		t.Fatalf("expected the tag unchanged, but got %v(%v)", changed, err)
+	}
+	if commit, _ := tag.Commit(ctx); commit != commits[0] {
+		t.Errorf("expected tag commit %s, but got %s", commits[0], commit)
+	}
+}
+
+func TestGitFetchCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required by the local transport")
+	}
+	ctx := context.Background()
+	url, commits := newBareRepo(t)
+	// allow fetching a commit by hash like the most git servers
+	if out, err := exec.Command("git", "-C", url, "config", "uploadpack.allowAnySHA1InWant", "true").CombinedOutput(); err != nil {
+		t.Fatalf("failed to configure the repository: %v %s", err, out)
+	}
+
+	g, err := NewGit(ctx, nil, &v1alpha1.Endpoint{URL: url}, &v1alpha1.Git{Commit: commits[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit, err := g.Commit(ctx); err != nil || commit != commits[0] {
+		t.Fatalf("expected commit %s, but got %s(%v)", commits[0], commit, err)
+	}
+	// only the pinned commit is fetched
+	if _, err := os.Stat(filepath.Join(g.dir, "shallow")); err != nil {
+		t.Errorf("expected a shallow clone, but got %v", err)
+	}
+	r, err := g.ReadFile(ctx, "README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if content, _ := io.ReadAll(r); string(content) != "v1" {
+		t.Errorf("expected README.md v1, but got %q", content)
+	}
+
+	dir := g.dir
+	g.Close()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected the clone removed after closed, but got %v", err)
+	}
+}
+
+func TestParseLFSPointer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		pointer lfsPointer
+		ok      bool
+	}{
+		{
+			name:    "pointer",
+			data:    "version https://git-lfs.github.com/spec/v1\noid sha256:4d7a2146\nsize 12345\n",
+			pointer: lfsPointer{OID: "4d7a2146", Size: 12345},
+			ok:      true,
+		},
+		{name: "plain file", data: "hello world"},
+		{name: "no oid", data: "version https://git-lfs.github.com/spec/v1\nsize 1\n"},
+		{name: "invalid size", data: "version https://git-lfs.github.com/spec/v1\noid sha256:4d7a2146\nsize big\n"},
+	}
+	for _, tc := range testCases {
+		pointer, ok := parseLFSPointer([]byte(tc.data))
+		if ok != tc.ok || (ok && pointer != tc.pointer) {
+			t.Errorf("%s: expected %+v(%v), but got %+v(%v)", tc.name, tc.pointer, tc.ok, pointer, ok)
+		}
+	}
+}
+
+func TestGitDownloadLFS(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/model.git/info/lfs/objects/batch":
+			if user, password, _ := r.BasicAuth(); user != "user" || password != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			req := lfsBatchRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Operation != "download" || len(req.Objects) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if req.Objects[0].OID != "4d7a2146" {
+				fmt.Fprintf(w, `{"objects":[{"oid":%q,"error":{"code":404,"message":"not found"}}]}`, req.Objects[0].OID)
+				return
+			}
+			fmt.Fprintf(w, `{"objects":[{"oid":"4d7a2146","size":7,"actions":{"download":{"href":"%s/content/4d7a2146","header":{"X-Token":"download"}}}}]}`, server.URL)
+		case "/content/4d7a2146":
+			if r.Header.Get("X-Token") != "download" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, "weights")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	g := &Git{url: server.URL + "/org/model", auth: &githttp.BasicAuth{Username: "user", Password: "token"}}
+	r, err := g.downloadLFS(ctx, lfsPointer{OID: "4d7a2146", Size: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if content, _ := io.ReadAll(r); string(content) != "weights" {
+		t.Errorf("expected the lfs object content, but got %q", content)
+	}
+	if _, err := g.downloadLFS(ctx, lfsPointer{OID: "missing", Size: 1}); err == nil {
+		t.Error("expected an error of the missing lfs object")
+	}
+
+	ssh := &Git{url: "git@github.com:org/model.git"}
+	if _, err := ssh.downloadLFS(ctx, lfsPointer{OID: "4d7a2146", Size: 7}); err == nil {
+		t.Error("expected an error of git lfs over ssh")
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	testCases := []struct {
+		glob    string
+		matches []string
+		misses  []string
+	}{
+		{"*.md", []string{"README.md"}, []string{"docs/a.md", "a.go"}},
+		{"docs/**/*.md", []string{"docs/a.md", "docs/x/y/b.md"}, []string{"README.md", "docs/a.go"}},
+		{"docs/", []string{"docs/a.md", "docs/x/b.go"}, []string{"doc/a.md"}},
+		{"src/?.go", []string{"src/a.go"}, []string{"src/ab.go", "src/x/a.go"}},
+	}
+	for _, tc := range testCases {
+		r, err := globToRegexp(tc.glob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range tc.matches {
+			if !r.MatchString(m) {
+				t.Errorf("%s: expected to match %s", tc.glob, m)
+			}
+		}
+		for _, m := range tc.misses {
+			if r.MatchString(m) {
+				t.Errorf("%s: expected not to match %s", tc.glob, m)
+			}
+		}
+	}
+}
@@ -16,16 +16,28 @@ limitations under the License.
 package datasource
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"text/template"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/minio/minio-go/v7"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/documentloaders"
 )
 
 var (
@@ -33,6 +45,9 @@ var (
 	pgEnvMutex sync.Mutex
 	poolsMutex sync.Mutex
 	pools      = make(map[string]*PostgreSQL)
+
+	ErrPostgreSQLReadOnly     = errors.New("postgresql datasource is read only")
+	ErrPostgreSQLNoSuchSource = errors.New("no such source in postgresql datasource")
 )
 
 func GetPostgreSQLPool(ctx context.Context, c client.Client, datasource *v1alpha1.Datasource) (*PostgreSQL, error) {
@@ -43,6 +58,11 @@ func GetPostgreSQLPool(ctx context.Context, c client.Client, datasource *v1alpha
 	if ok && pg.Ref.GetGeneration() == datasource.GetGeneration() {
 		return pg, nil
 	}
+	if datasource.Spec.PostgreSQL != nil {
+		if err := ValidatePostgreSQLSources(datasource.Spec.PostgreSQL.Sources); err != nil {
+			return nil, err
+		}
+	}
 	pg, err := newPostgreSQL(ctx, c, datasource.Spec.PostgreSQL, &datasource.Spec.Endpoint)
 	if err != nil {
 		return nil, err
@@ -54,6 +74,26 @@ func GetPostgreSQLPool(ctx context.Context, c client.Client, datasource *v1alpha
 	return pg, nil
 }
 
+// ValidatePostgreSQLSources checks that each source reads either a table or a query
+func ValidatePostgreSQLSources(sources []v1alpha1.PostgreSQLSource) error {
+	for _, s := range sources {
+		switch {
+		case s.Table == "" && s.Query == "":
+			return fmt.Errorf("neither table nor query is set for source %s", s.Name)
+		case s.Table != "" && s.Query != "":
+			return fmt.Errorf("only one of table and query can be set for source %s", s.Name)
+		case s.Query != "" && len(s.Columns) != 0:
+			return fmt.Errorf("columns can only be set with table for source %s", s.Name)
+		}
+		if s.Template != "" {
+			if _, err := template.New(s.Name).Parse(s.Template); err != nil {
+				return fmt.Errorf("invalid template of source %s: %w", s.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
 func RemovePostgreSQLPool(datasource v1alpha1.Datasource) {
 	pg, ok := pools[string(datasource.GetUID())]
 	if !ok {
@@ -205,26 +245,383 @@ func (p *PostgreSQL) Stat(ctx context.Context, _ any) error {
 }
 
 func (p *PostgreSQL) Remove(ctx context.Context, info any) error {
-	// TODO implement me
-	panic("implement me")
+	return ErrPostgreSQLReadOnly
+}
+
+// PostgreSQLRow is a row rendered into text, or into a QA csv with a single pair
+type PostgreSQLRow struct {
+	// Key is the value of the key column, empty without `keyColumn`
+	Key     string
+	Content []byte
 }
 
+// ReadFile renders the rows of a source into a text document, or a QA csv.
+// When `info.VersionID` is a watermark returned by Watermark,
+// only rows updated after the watermark are rendered.
 func (p *PostgreSQL) ReadFile(ctx context.Context, info any) (io.ReadCloser, error) {
-	// TODO implement me
-	panic("implement me")
+	source, watermark, err := p.source(info)
+	if err != nil {
+		return nil, err
+	}
+	r, rows, _, err := p.render(ctx, source, watermark)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(r.join(rows))), nil
 }
 
+// ReadRows renders the rows of a source one by one, so that the content of a row can be replaced by its key.
+// A QA row is rendered into a csv with the header. `info.VersionID` is handled the same way as ReadFile.
+func (p *PostgreSQL) ReadRows(ctx context.Context, info any) ([]PostgreSQLRow, error) {
+	source, watermark, err := p.source(info)
+	if err != nil {
+		return nil, err
+	}
+	r, rows, _, err := p.render(ctx, source, watermark)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Content = r.join(rows[i : i+1])
+	}
+	return rows, nil
+}
+
+// StatFile returns a minio.ObjectInfo so that a source can be handled the same way as an object.
+// With `updatedAtColumn`, the ETag is composed of the row count and the latest modification time,
+// otherwise it is the checksum of the rows fingerprinted by the database and the way to render them.
 func (p *PostgreSQL) StatFile(ctx context.Context, info any) (any, error) {
-	// TODO implement me
-	panic("implement me")
+	source, _, err := p.source(info)
+	if err != nil {
+		return nil, err
+	}
+	return p.objectInfo(ctx, source)
 }
 
 func (p *PostgreSQL) GetTags(ctx context.Context, info any) (map[string]string, error) {
-	// TODO implement me
-	panic("implement me")
+	source, _, err := p.source(info)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := p.QueryRow(ctx, "SELECT count(*) FROM ("+source.query()+") AS s").Scan(&count); err != nil {
+		return nil, err
+	}
+	tags := map[string]string{
+		v1alpha1.ObjectCountTag: strconv.FormatInt(count, 10),
+	}
+	if source.IsQA() {
+		tags[v1alpha1.ObjectTypeTag] = v1alpha1.ObjectTypeQA
+	}
+	return tags, nil
 }
 
+// ListObjects lists sources whose object name has the prefix `source`
 func (p *PostgreSQL) ListObjects(ctx context.Context, source string, info any) (any, error) {
-	// TODO implement me
-	panic("implement me")
+	result := make([]minio.ObjectInfo, 0)
+	for _, s := range p.sources() {
+		if !strings.HasPrefix(s.ObjectName(), source) {
+			continue
+		}
+		object, err := p.objectInfo(ctx, pgSource{s})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, object)
+	}
+	return result, nil
+}
+
+// Watermark returns the watermark to read rows updated after the sync of the ETag returned by StatFile.
+// It is empty if the source can not be synced incrementally, which needs both `updatedAtColumn` and `keyColumn`.
+func (p *PostgreSQL) Watermark(info any, etag string) string {
+	source, _, err := p.source(info)
+	if err != nil || source.UpdatedAtColumn == "" || source.KeyColumn == "" {
+		return ""
+	}
+	return PostgreSQLWatermark(etag)
+}
+
+// RowKeys returns the keys of all rows of the source, the chunks of other rows are deleted from the source
+func (p *PostgreSQL) RowKeys(ctx context.Context, info any) ([]string, error) {
+	source, _, err := p.source(info)
+	if err != nil {
+		return nil, err
+	}
+	if source.KeyColumn == "" {
+		return nil, fmt.Errorf("no key column of source %s", source.Name)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM (%s) AS s", pgx.Identifier{source.KeyColumn}.Sanitize(), source.query())
+	rows, err := p.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]string, 0)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		// the same as the key of a rendered row
+		keys = append(keys, pgValueToString(values[0]))
+	}
+	return keys, rows.Err()
+}
+
+// PostgreSQLRowsMayBeDeleted checks whether rows may be deleted between the syncs of two ETags returned by StatFile,
+// with the number of rows updated after the previous sync, which are either inserted or changed.
+// As the count is `previous + inserted - deleted`, no row is deleted if the count is not less than `previous + updated`.
+func PostgreSQLRowsMayBeDeleted(previousETag, etag string, updated int) bool {
+	previousCount, _, _ := strings.Cut(previousETag, "@")
+	count, _, _ := strings.Cut(etag, "@")
+	previous, err := strconv.ParseInt(previousCount, 10, 64)
+	if err != nil {
+		return true
+	}
+	current, err := strconv.ParseInt(count, 10, 64)
+	if err != nil {
+		return true
+	}
+	return previous+int64(updated) > current
+}
+
+// PostgreSQLWatermark returns the latest modification time recorded in an ETag returned by StatFile.
+// It can be passed as `VersionID` to ReadFile to read rows updated after the last sync.
+func PostgreSQLWatermark(etag string) string {
+	_, watermark, found := strings.Cut(etag, "@")
+	if !found {
+		return ""
+	}
+	return watermark
+}
+
+func (p *PostgreSQL) sources() []v1alpha1.PostgreSQLSource {
+	if p.Ref == nil || p.Ref.Spec.PostgreSQL == nil {
+		return nil
+	}
+	return p.Ref.Spec.PostgreSQL.Sources
+}
+
+func (p *PostgreSQL) source(info any) (pgSource, string, error) {
+	var object, watermark string
+	switch v := info.(type) {
+	case *v1alpha1.OSS:
+		if v != nil {
+			object, watermark = v.Object, v.VersionID
+		}
+	case string:
+		object = v
+	}
+	object = strings.TrimPrefix(object, "/")
+	if object == "" {
+		return pgSource{}, "", ErrOSSNoConfig
+	}
+	for _, s := range p.sources() {
+		if s.ObjectName() == object || s.Name == object {
+			if err := ValidatePostgreSQLSources([]v1alpha1.PostgreSQLSource{s}); err != nil {
+				return pgSource{}, "", err
+			}
+			return pgSource{s}, watermark, nil
+		}
+	}
+	return pgSource{}, "", ErrPostgreSQLNoSuchSource
+}
+
+func (p *PostgreSQL) objectInfo(ctx context.Context, source pgSource) (minio.ObjectInfo, error) {
+	object := minio.ObjectInfo{Key: source.ObjectName()}
+	if source.UpdatedAtColumn == "" {
+		// the rows are fingerprinted by the database instead of being read, ordered by their hashes to be stable
+		var count int64
+		var fingerprint string
+		sql := fmt.Sprintf("SELECT count(*), coalesce(md5(string_agg(h, '' ORDER BY h)), '') FROM (SELECT md5(s::text) AS h FROM (%s) AS s) AS t", source.query())
+		if err := p.QueryRow(ctx, sql).Scan(&count, &fingerprint); err != nil {
+			return object, err
+		}
+		object.ETag = source.checksum(count, fingerprint)
+		return object, nil
+	}
+	var count int64
+	var lastModified *time.Time
+	sql := fmt.Sprintf("SELECT count(*), max(%s) FROM (%s) AS s", pgx.Identifier{source.UpdatedAtColumn}.Sanitize(), source.query())
+	if err := p.QueryRow(ctx, sql).Scan(&count, &lastModified); err != nil {
+		return object, err
+	}
+	object.ETag = strconv.FormatInt(count, 10)
+	if lastModified != nil {
+		object.LastModified = *lastModified
+		object.ETag += "@" + lastModified.UTC().Format(time.RFC3339Nano)
+	}
+	return object, nil
+}
+
+// render renders rows updated after the watermark and returns the latest modification time of them
+func (p *PostgreSQL) render(ctx context.Context, source pgSource, watermark string) (*pgRenderer, []PostgreSQLRow, time.Time, error) {
+	var lastModified time.Time
+	r, err := newPGRenderer(source)
+	if err != nil {
+		return nil, nil, lastModified, err
+	}
+
+	sql := "SELECT * FROM (" + source.query() + ") AS s"
+	args := make([]any, 0, 1)
+	if source.UpdatedAtColumn != "" {
+		updatedAt := pgx.Identifier{source.UpdatedAtColumn}.Sanitize()
+		if watermark != "" {
+			t, err := time.Parse(time.RFC3339Nano, watermark)
+			if err != nil {
+				return nil, nil, lastModified, fmt.Errorf("invalid watermark %s: %w", watermark, err)
+			}
+			sql += " WHERE " + updatedAt + " > $1"
+			args = append(args, t)
+		}
+		sql += " ORDER BY " + updatedAt
+	}
+	rows, err := p.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, lastModified, err
+	}
+	defer rows.Close()
+	columns := make([]string, 0)
+	for _, f := range rows.FieldDescriptions() {
+		columns = append(columns, f.Name)
+	}
+
+	result := make([]PostgreSQLRow, 0)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, nil, lastModified, err
+		}
+		row, updatedAt, err := r.row(columns, values)
+		if err != nil {
+			return nil, nil, lastModified, err
+		}
+		if updatedAt.After(lastModified) {
+			lastModified = updatedAt
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, lastModified, err
+	}
+	return r, result, lastModified, nil
+}
+
+// pgRenderer renders the rows of a source
+type pgRenderer struct {
+	source pgSource
+	tmpl   *template.Template
+}
+
+func newPGRenderer(source pgSource) (*pgRenderer, error) {
+	r := &pgRenderer{source: source}
+	if source.Template != "" {
+		var err error
+		if r.tmpl, err = template.New(source.Name).Option("missingkey=zero").Parse(source.Template); err != nil {
+			return nil, fmt.Errorf("invalid template of source %s: %w", source.Name, err)
+		}
+	}
+	return r, nil
+}
+
+// row renders a row and returns its modification time
+func (r *pgRenderer) row(columns []string, values []any) (PostgreSQLRow, time.Time, error) {
+	var updatedAt time.Time
+	fields := make(map[string]string, len(columns))
+	for i, v := range values {
+		fields[columns[i]] = pgValueToString(v)
+		if columns[i] == r.source.UpdatedAtColumn {
+			if t, ok := v.(time.Time); ok {
+				updatedAt = t
+			}
+		}
+	}
+	row := PostgreSQLRow{}
+	if r.source.KeyColumn != "" {
+		row.Key = fields[r.source.KeyColumn]
+	}
+	buf := &bytes.Buffer{}
+	switch {
+	case r.source.IsQA():
+		qa := csv.NewWriter(buf)
+		if err := qa.Write([]string{fields[r.source.QuestionColumn], fields[r.source.AnswerColumn], r.source.ObjectName()}); err != nil {
+			return row, updatedAt, err
+		}
+		qa.Flush()
+		if err := qa.Error(); err != nil {
+			return row, updatedAt, err
+		}
+	case r.tmpl != nil:
+		if err := r.tmpl.Execute(buf, fields); err != nil {
+			return row, updatedAt, fmt.Errorf("failed to render source %s: %w", r.source.Name, err)
+		}
+		buf.WriteString("\n\n")
+	default:
+		for _, column := range columns {
+			fmt.Fprintf(buf, "%s: %s\n", column, fields[column])
+		}
+		buf.WriteString("\n")
+	}
+	row.Content = buf.Bytes()
+	return row, updatedAt, nil
+}
+
+// join joins rendered rows into a document, QA rows are joined into a csv with the header
+func (r *pgRenderer) join(rows []PostgreSQLRow) []byte {
+	buf := &bytes.Buffer{}
+	if r.source.IsQA() {
+		qa := csv.NewWriter(buf)
+		_ = qa.Write([]string{documentloaders.QuestionCol, documentloaders.AnswerCol, documentloaders.FileNameCol})
+		qa.Flush()
+	}
+	for _, row := range rows {
+		buf.Write(row.Content)
+	}
+	return buf.Bytes()
+}
+
+type pgSource struct {
+	v1alpha1.PostgreSQLSource
+}
+
+// checksum returns the checksum of the rows fingerprint and the way to render them,
+// so that the rows are embedded again when the rendering changes
+func (s pgSource) checksum(count int64, fingerprint string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%s\n%s\n%s\n%s\n%s\n", count, fingerprint, strings.Join(s.Columns, ","), s.Template, s.QuestionColumn, s.AnswerColumn)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// query returns the sql to read the source
+func (s pgSource) query() string {
+	if s.Query != "" {
+		return strings.TrimRight(strings.TrimSpace(s.Query), ";")
+	}
+	columns := "*"
+	if len(s.Columns) != 0 {
+		quoted := make([]string, len(s.Columns))
+		for i, c := range s.Columns {
+			quoted[i] = pgx.Identifier{c}.Sanitize()
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", columns, pgx.Identifier(strings.Split(s.Table, ".")).Sanitize())
+}
+
+func pgValueToString(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
 }
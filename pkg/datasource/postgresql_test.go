@@ -0,0 +1,202 @@
+/*
+Copyright 2024 KubeAGI.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datasource
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func TestPostgreSQLSourceQuery(t *testing.T) {
+	testCases := []struct {
+		source v1alpha1.PostgreSQLSource
+		sql    string
+	}{
+		{v1alpha1.PostgreSQLSource{Table: "products"}, `SELECT * FROM "products"`},
+		{v1alpha1.PostgreSQLSource{Table: "public.products", Columns: []string{"name", "Price"}}, `SELECT "name", "Price" FROM "public"."products"`},
+		{v1alpha1.PostgreSQLSource{Table: "products", Query: "SELECT q, a FROM faq;\n"}, `SELECT q, a FROM faq`},
+	}
+	for _, tc := range testCases {
+		if sql := (pgSource{tc.source}).query(); sql != tc.sql {
+			t.Errorf("expected %s, but got %s", tc.sql, sql)
+		}
+	}
+}
+
+func TestPostgreSQLWatermark(t *testing.T) {
+	testCases := map[string]string{
+		"":                                 "",
+		"3a7bd3e2360a3d29eea436fcfb7e44c7": "",
+		"10@2024-01-02T03:04:05.000006Z":   "2024-01-02T03:04:05.000006Z",
+	}
+	for etag, watermark := range testCases {
+		if got := PostgreSQLWatermark(etag); got != watermark {
+			t.Errorf("%s: expected %s, but got %s", etag, watermark, got)
+		}
+	}
+}
+
+func TestPostgreSQLRowsMayBeDeleted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		previous string
+		etag     string
+		updated  int
+		deleted  bool
+	}{
+		{name: "unchanged", previous: "10@2024-01-02T03:04:05Z", etag: "10@2024-01-02T03:04:05Z", updated: 0},
+		{name: "inserted", previous: "10@2024-01-02T03:04:05Z", etag: "12@2024-01-03T03:04:05Z", updated: 2},
+		// a changed row can't be told from an inserted row with another row deleted
+		{name: "changed", previous: "10@2024-01-02T03:04:05Z", etag: "10@2024-01-03T03:04:05Z", updated: 1, deleted: true},
+		{name: "deleted", previous: "10@2024-01-02T03:04:05Z", etag: "9@2024-01-02T03:04:05Z", updated: 0, deleted: true},
+		{name: "changed and deleted", previous: "10@2024-01-02T03:04:05Z", etag: "9@2024-01-03T03:04:05Z", updated: 1, deleted: true},
+		{name: "unknown count", previous: "3a7bd3e2360a3d29eea436fcfb7e44c7", etag: "10@2024-01-02T03:04:05Z", deleted: true},
+	}
+	for _, tc := range testCases {
+		if got := PostgreSQLRowsMayBeDeleted(tc.previous, tc.etag, tc.updated); got != tc.deleted {
+			t.Errorf("%s: expected %v, but got %v", tc.name, tc.deleted, got)
+		}
+	}
+}
+
+func TestPostgreSQLSourceChecksum(t *testing.T) {
+	source := pgSource{v1alpha1.PostgreSQLSource{Name: "products", Table: "products", Template: "{{.name}}"}}
+	checksum := source.checksum(10, "3a7bd3e2360a3d29eea436fcfb7e44c7")
+	if got := source.checksum(10, "3a7bd3e2360a3d29eea436fcfb7e44c7"); got != checksum {
+		t.Errorf("expected a stable checksum %s, but got %s", checksum, got)
+	}
+	if got := source.checksum(11, "3a7bd3e2360a3d29eea436fcfb7e44c7"); got == checksum {
+		t.Errorf("expected the checksum changed with the row count")
+	}
+	// the rows are rendered differently
+	source.Template = "{{.name}}: {{.price}}"
+	if got := source.checksum(10, "3a7bd3e2360a3d29eea436fcfb7e44c7"); got == checksum {
+		t.Errorf("expected the checksum changed with the template")
+	}
+	if PostgreSQLWatermark(checksum) != "" {
+		t.Errorf("expected no watermark in checksum %s", checksum)
+	}
+}
+
+func TestValidatePostgreSQLSources(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source v1alpha1.PostgreSQLSource
+		valid  bool
+	}{
+		{name: "table", source: v1alpha1.PostgreSQLSource{Name: "products", Table: "products", Columns: []string{"name"}}, valid: true},
+		{name: "query", source: v1alpha1.PostgreSQLSource{Name: "faq", Query: "SELECT q, a FROM faq"}, valid: true},
+		{name: "none", source: v1alpha1.PostgreSQLSource{Name: "none"}},
+		{name: "both", source: v1alpha1.PostgreSQLSource{Name: "both", Table: "products", Query: "SELECT 1"}},
+		{name: "columns of query", source: v1alpha1.PostgreSQLSource{Name: "columns", Query: "SELECT 1", Columns: []string{"name"}}},
+		{name: "invalid template", source: v1alpha1.PostgreSQLSource{Name: "template", Table: "products", Template: "{{.name"}},
+	}
+	for _, tc := range testCases {
+		err := ValidatePostgreSQLSources([]v1alpha1.PostgreSQLSource{tc.source})
+		if (err == nil) != tc.valid {
+			t.Errorf("%s: expected valid %v, but got %v", tc.name, tc.valid, err)
+		}
+	}
+}
+
+func TestPostgreSQLWatermarkOfSource(t *testing.T) {
+	p := &PostgreSQL{Ref: &v1alpha1.Datasource{Spec: v1alpha1.DatasourceSpec{PostgreSQL: &v1alpha1.PostgreSQL{
+		Sources: []v1alpha1.PostgreSQLSource{
+			{Name: "keyed", Table: "products", UpdatedAtColumn: "updated_at", KeyColumn: "id"},
+			{Name: "unkeyed", Table: "products", UpdatedAtColumn: "updated_at"},
+		},
+	}}}}
+	etag := "10@2024-01-02T03:04:05Z"
+	if got := p.Watermark(&v1alpha1.OSS{Object: "keyed.txt"}, etag); got != "2024-01-02T03:04:05Z" {
+		t.Errorf("expected the watermark of a keyed source, but got %q", got)
+	}
+	// rows without keys can not be replaced, so the source is always synced fully
+	if got := p.Watermark(&v1alpha1.OSS{Object: "unkeyed.txt"}, etag); got != "" {
+		t.Errorf("expected no watermark without key column, but got %q", got)
+	}
+	if got := p.Watermark(&v1alpha1.OSS{Object: "missing.txt"}, etag); got != "" {
+		t.Errorf("expected no watermark of a missing source, but got %q", got)
+	}
+}
+
+func TestPostgreSQLRender(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"id", "name", "price", "updated_at"}
+	values := [][]any{
+		{int64(1), "apple", 3.5, updatedAt},
+		{int64(2), "pear", nil, updatedAt.Add(-time.Hour)},
+	}
+	testCases := []struct {
+		name   string
+		source v1alpha1.PostgreSQLSource
+		keys   []string
+		rows   []string
+		joined string
+	}{
+		{
+			name:   "columns",
+			source: v1alpha1.PostgreSQLSource{Name: "products", Table: "products", UpdatedAtColumn: "updated_at", KeyColumn: "id"},
+			keys:   []string{"1", "2"},
+			rows: []string{
+				"id: 1\nname: apple\nprice: 3.5\nupdated_at: 2024-01-02T03:04:05Z\n\n",
+				"id: 2\nname: pear\nprice: \nupdated_at: 2024-01-02T02:04:05Z\n\n",
+			},
+		},
+		{
+			name:   "template",
+			source: v1alpha1.PostgreSQLSource{Name: "products", Table: "products", Template: "{{.name}} costs {{.price}}{{.missing}}"},
+			keys:   []string{"", ""},
+			rows:   []string{"apple costs 3.5\n\n", "pear costs \n\n"},
+		},
+		{
+			name:   "qa",
+			source: v1alpha1.PostgreSQLSource{Name: "products", Query: "SELECT * FROM products", QuestionColumn: "name", AnswerColumn: "price", KeyColumn: "id"},
+			keys:   []string{"1", "2"},
+			rows:   []string{"apple,3.5,products.csv\n", "pear,,products.csv\n"},
+			joined: "q,a,file_name\napple,3.5,products.csv\npear,,products.csv\n",
+		},
+	}
+	for _, tc := range testCases {
+		r, err := newPGRenderer(pgSource{tc.source})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := make([]PostgreSQLRow, 0, len(values))
+		for i, v := range values {
+			row, rowUpdatedAt, err := r.row(columns, v)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if row.Key != tc.keys[i] || string(row.Content) != tc.rows[i] {
+				t.Errorf("%s: expected row %q(%q), but got %q(%q)", tc.name, tc.rows[i], tc.keys[i], row.Content, row.Key)
+			}
+			if tc.source.UpdatedAtColumn != "" && !rowUpdatedAt.Equal(v[3].(time.Time)) {
+				t.Errorf("%s: expected updated at %s, but got %s", tc.name, v[3], rowUpdatedAt)
+			}
+			rows = append(rows, row)
+		}
+		joined := tc.joined
+		if joined == "" {
+			joined = strings.Join(tc.rows, "")
+		}
+		if got := string(r.join(rows)); got != joined {
+			t.Errorf("%s: expected document %q, but got %q", tc.name, joined, got)
+		}
+	}
+}
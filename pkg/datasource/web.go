@@ -16,50 +16,71 @@ package datasource
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/url"
 )
 
-var _ Datasource = (*Web)(nil)
+var (
+	_ Datasource = (*Web)(nil)
 
+	ErrWebNotCrawled = errors.New("web datasource has no crawled pages")
+)
+
+// Web is a datasource for web resources
+// - `storage` is the system datasource oss where crawled pages are stored. It is nil if the web is not crawled.
 type Web struct {
-	url string
+	url     string
+	storage *OSS
 }
 
-func NewWeb(ctx context.Context, url string) (*Web, error) {
+func NewWeb(ctx context.Context, url string, storage *OSS) (*Web, error) {
 	return &Web{
-		url: url,
+		url:     url,
+		storage: storage,
 	}, nil
 }
 
 func (w *Web) Stat(ctx context.Context, info any) error {
-	_, err := url.ParseRequestURI("http://google.com/")
+	_, err := url.ParseRequestURI(w.url)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// Remove crawled pages
 func (w *Web) Remove(ctx context.Context, info any) error {
-	return nil
+	if w.storage == nil {
+		return nil
+	}
+	return w.storage.Remove(ctx, info)
 }
 
 func (w *Web) ReadFile(ctx context.Context, info any) (io.ReadCloser, error) {
-	// TODO implement me
-	panic("implement me")
+	if w.storage == nil {
+		return nil, ErrWebNotCrawled
+	}
+	return w.storage.ReadFile(ctx, info)
 }
 
 func (w *Web) StatFile(ctx context.Context, info any) (any, error) {
-	// TODO implement me
-	panic("implement me")
+	if w.storage == nil {
+		return nil, ErrWebNotCrawled
+	}
+	return w.storage.StatFile(ctx, info)
 }
 
 func (w *Web) GetTags(ctx context.Context, info any) (map[string]string, error) {
-	// TODO implement me
-	panic("implement me")
+	if w.storage == nil {
+		return nil, ErrWebNotCrawled
+	}
+	return w.storage.GetTags(ctx, info)
 }
 
 func (w *Web) ListObjects(ctx context.Context, source string, info any) (any, error) {
-	// TODO implement me
-	panic("implement me")
+	if w.storage == nil {
+		return nil, ErrWebNotCrawled
+	}
+	return w.storage.ListObjects(ctx, source, info)
 }
@@ -0,0 +1,469 @@
+/*
+Copyright 2024 KubeAGI.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/minio/minio-go/v7"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+const (
+	webCrawlerUserAgent = "arcadia-web-crawler"
+
+	// user metadata of crawled objects
+	webMetaSourceURL  = "source-url"
+	webMetaSourceETag = "source-etag"
+	webMetaChecksum   = "checksum"
+
+	// max number of urls read from sitemaps
+	maxSitemapURLs = 10000
+)
+
+var errMaxPagesReached = errors.New("max pages reached")
+
+// CrawledPage is a page stored by WebCrawler
+type CrawledPage struct {
+	// URL is where the page comes from
+	URL string
+	// Object is the object path of the page in the bucket
+	Object string
+	// Changed is true when the page is new or its content changed since the last crawl
+	Changed bool
+}
+
+// pageStore stores the crawled pages
+type pageStore interface {
+	// metadata returns the user metadata of a stored page, false if it is not found
+	metadata(ctx context.Context, object string) (http.Header, bool)
+	put(ctx context.Context, object, contentType string, body []byte, metadata map[string]string) error
+	// list returns the objects with the prefix
+	list(ctx context.Context, prefix string) ([]string, error)
+	remove(ctx context.Context, object string) error
+}
+
+// ossPageStore stores pages into a bucket of the oss
+type ossPageStore struct {
+	oss    *OSS
+	bucket string
+}
+
+func (s *ossPageStore) metadata(ctx context.Context, object string) (http.Header, bool) {
+	stat, err := s.oss.Client.StatObject(ctx, s.bucket, object, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, false
+	}
+	return http.Header(stat.Metadata), true
+}
+
+func (s *ossPageStore) put(ctx context.Context, object, contentType string, body []byte, metadata map[string]string) error {
+	_, err := s.oss.Client.PutObject(ctx, s.bucket, object, bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: metadata,
+	})
+	return err
+}
+
+func (s *ossPageStore) list(ctx context.Context, prefix string) ([]string, error) {
+	objects := make([]string, 0)
+	for object := range s.oss.Client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, object.Key)
+	}
+	return objects, nil
+}
+
+func (s *ossPageStore) remove(ctx context.Context, object string) error {
+	return s.oss.Client.RemoveObject(ctx, s.bucket, object, minio.RemoveObjectOptions{})
+}
+
+// WebCrawler crawls a website recursively and stores pages into the oss
+type WebCrawler struct {
+	pages  pageStore
+	prefix string
+
+	config    v1alpha1.WebCrawler
+	startURLs []*url.URL
+
+	// incomplete is set when some pages may be missed by the last crawl
+	incomplete bool
+}
+
+// NewWebCrawler creates a crawler which stores pages into `bucket` with object prefix `prefix`.
+// `endpointURL` is used as the start url if `config.StartURLs` is empty.
+func NewWebCrawler(oss *OSS, bucket, prefix, endpointURL string, config *v1alpha1.WebCrawler) (*WebCrawler, error) {
+	if oss == nil || bucket == "" {
+		return nil, ErrOSSNoConfig
+	}
+	return newWebCrawler(&ossPageStore{oss: oss, bucket: bucket}, prefix, endpointURL, config)
+}
+
+func newWebCrawler(store pageStore, prefix, endpointURL string, config *v1alpha1.WebCrawler) (*WebCrawler, error) {
+	if config == nil {
+		return nil, errors.New("no web crawler config")
+	}
+	crawler := &WebCrawler{
+		pages:  store,
+		prefix: prefix,
+		config: *config,
+	}
+	startURLs := config.StartURLs
+	if len(startURLs) == 0 {
+		startURLs = []string{endpointURL}
+	}
+	for _, s := range startURLs {
+		u, err := url.ParseRequestURI(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start url %s: %w", s, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid start url %s: only http and https are supported", s)
+		}
+		crawler.startURLs = append(crawler.startURLs, u)
+	}
+	return crawler, nil
+}
+
+// Crawl visits the website and stores html/text pages.
+// Unchanged pages are detected by the ETag header or the content checksum and will not be uploaded again.
+func (c *WebCrawler) Crawl(ctx context.Context) ([]CrawledPage, error) {
+	collector, err := c.newCollector()
+	if err != nil {
+		return nil, err
+	}
+	c.incomplete = false
+
+	var (
+		mu       sync.Mutex
+		pages    = make([]CrawledPage, 0)
+		stored   = make(map[string]bool)
+		storeErr error
+	)
+	maxPagesReached := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return c.config.MaxPages > 0 && len(pages) >= c.config.MaxPages
+	}
+
+	collector.OnRequest(func(r *colly.Request) {
+		if ctx.Err() != nil || maxPagesReached() {
+			r.Abort()
+		}
+	})
+	collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+		link := e.Request.AbsoluteURL(e.Attr("href"))
+		if link == "" || maxPagesReached() {
+			return
+		}
+		// ignore the fragment so the same page won't be visited twice
+		if u, err := url.Parse(link); err == nil {
+			u.Fragment = ""
+			link = u.String()
+		}
+		_ = e.Request.Visit(link)
+	})
+	collector.OnResponse(func(r *colly.Response) {
+		ext := pageExt(r.Headers.Get("Content-Type"))
+		if ext == "" {
+			return
+		}
+		object := c.prefix + pageObjectPath(r.Request.URL, ext)
+		mu.Lock()
+		if stored[object] || (c.config.MaxPages > 0 && len(pages) >= c.config.MaxPages) {
+			mu.Unlock()
+			return
+		}
+		stored[object] = true
+		mu.Unlock()
+
+		changed, err := c.store(ctx, object, r.Request.URL.String(), r.Headers.Get("ETag"), r.Headers.Get("Content-Type"), r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			klog.Errorf("failed to store page %s: %s", r.Request.URL, err)
+			storeErr = errors.Join(storeErr, err)
+			return
+		}
+		pages = append(pages, CrawledPage{URL: r.Request.URL.String(), Object: object, Changed: changed})
+	})
+	collector.OnError(func(r *colly.Response, err error) {
+		klog.V(3).Infof("failed to crawl %s: %s", r.Request.URL, err)
+		// a page not found is removed from the website, other errors may be temporary
+		if r.StatusCode != http.StatusNotFound && r.StatusCode != http.StatusGone {
+			mu.Lock()
+			c.incomplete = true
+			mu.Unlock()
+		}
+	})
+
+	startURLs := make([]string, 0, len(c.startURLs))
+	for _, u := range c.startURLs {
+		startURLs = append(startURLs, u.String())
+	}
+	if c.config.Sitemap {
+		startURLs = append(startURLs, c.sitemapURLs(ctx)...)
+	}
+	for _, u := range startURLs {
+		if maxPagesReached() {
+			break
+		}
+		if err := collector.Visit(u); err != nil && !errors.Is(err, colly.ErrAlreadyVisited) {
+			klog.V(3).Infof("skip start url %s: %s", u, err)
+		}
+	}
+	collector.Wait()
+
+	if maxPagesReached() || storeErr != nil {
+		c.incomplete = true
+	}
+	if err := ctx.Err(); err != nil {
+		c.incomplete = true
+		return pages, err
+	}
+	return pages, storeErr
+}
+
+// RemoveStalePages removes the stored pages which are not found by the last crawl, and returns their objects.
+// Nothing is removed if the last crawl may have missed some pages, such as failed requests or max pages reached.
+func (c *WebCrawler) RemoveStalePages(ctx context.Context, pages []CrawledPage) ([]string, error) {
+	if c.incomplete {
+		return nil, nil
+	}
+	crawled := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		crawled[p.Object] = true
+	}
+	objects, err := c.pages.list(ctx, c.prefix)
+	if err != nil {
+		return nil, err
+	}
+	removed := make([]string, 0)
+	for _, object := range objects {
+		if crawled[object] {
+			continue
+		}
+		if err := c.pages.remove(ctx, object); err != nil {
+			return removed, err
+		}
+		removed = append(removed, object)
+	}
+	return removed, nil
+}
+
+func (c *WebCrawler) newCollector() (*colly.Collector, error) {
+	allowedDomains := c.config.AllowedDomains
+	if len(allowedDomains) == 0 {
+		for _, u := range c.startURLs {
+			// colly matches the host with port
+			allowedDomains = append(allowedDomains, u.Hostname())
+			if u.Host != u.Hostname() {
+				allowedDomains = append(allowedDomains, u.Host)
+			}
+		}
+	}
+	options := []func(*colly.Collector){
+		colly.UserAgent(webCrawlerUserAgent),
+		colly.AllowedDomains(allowedDomains...),
+	}
+	// start urls are at depth 1 in colly
+	if c.config.MaxDepth > 0 {
+		options = append(options, colly.MaxDepth(c.config.MaxDepth+1))
+	}
+	if len(c.config.IncludePatterns) > 0 {
+		filters, err := compilePatterns(c.config.IncludePatterns)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, colly.URLFilters(filters...))
+	}
+	if len(c.config.ExcludePatterns) > 0 {
+		filters, err := compilePatterns(c.config.ExcludePatterns)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, colly.DisallowedURLFilters(filters...))
+	}
+	collector := colly.NewCollector(options...)
+	collector.IgnoreRobotsTxt = c.config.IgnoreRobotsTxt
+	if err := collector.Limit(&colly.LimitRule{
+		DomainGlob:  "*",
+		Parallelism: 1,
+		Delay:       time.Duration(c.config.Delay) * time.Millisecond,
+	}); err != nil {
+		return nil, err
+	}
+	return collector, nil
+}
+
+// store uploads the page if it is new or changed
+func (c *WebCrawler) store(ctx context.Context, object, sourceURL, etag, contentType string, body []byte) (bool, error) {
+	sum := sha256.Sum256(body)
+	checksum := hex.EncodeToString(sum[:])
+	if meta, ok := c.pages.metadata(ctx, object); ok {
+		if etag != "" && meta.Get("X-Amz-Meta-"+webMetaSourceETag) == etag {
+			return false, nil
+		}
+		if meta.Get("X-Amz-Meta-"+webMetaChecksum) == checksum {
+			return false, nil
+		}
+	}
+	err := c.pages.put(ctx, object, contentType, body, map[string]string{
+		webMetaSourceURL:  sourceURL,
+		webMetaSourceETag: etag,
+		webMetaChecksum:   checksum,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type sitemap struct {
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// sitemapURLs reads urls from /sitemap.xml of each start url's host.
+// Nested sitemaps in a sitemap index are followed only one level.
+func (c *WebCrawler) sitemapURLs(ctx context.Context) []string {
+	urls := make([]string, 0)
+	visited := make(map[string]bool)
+	for _, u := range c.startURLs {
+		sitemapURL := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/sitemap.xml"}).String()
+		if visited[sitemapURL] {
+			continue
+		}
+		visited[sitemapURL] = true
+		root, err := fetchSitemap(ctx, sitemapURL)
+		if err != nil {
+			klog.V(3).Infof("failed to read sitemap %s: %s", sitemapURL, err)
+			continue
+		}
+		for _, loc := range root.URLs {
+			urls = append(urls, strings.TrimSpace(loc.Loc))
+		}
+		for _, nested := range root.Sitemaps {
+			nestedURL := strings.TrimSpace(nested.Loc)
+			if visited[nestedURL] || len(urls) >= maxSitemapURLs {
+				continue
+			}
+			visited[nestedURL] = true
+			sub, err := fetchSitemap(ctx, nestedURL)
+			if err != nil {
+				klog.V(3).Infof("failed to read sitemap %s: %s", nestedURL, err)
+				continue
+			}
+			for _, loc := range sub.URLs {
+				urls = append(urls, strings.TrimSpace(loc.Loc))
+			}
+		}
+	}
+	if len(urls) > maxSitemapURLs {
+		urls = urls[:maxSitemapURLs]
+	}
+	return urls
+}
+
+func fetchSitemap(ctx context.Context, sitemapURL string) (*sitemap, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", webCrawlerUserAgent)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	s := &sitemap{}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 50<<20)).Decode(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", p, err)
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// pageExt returns the object extension by content type. Empty means the page should not be stored.
+func pageExt(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return ".html"
+	case "text/plain", "text/markdown":
+		return ".txt"
+	default:
+		return ""
+	}
+}
+
+// pageObjectPath converts a page url to an object path like `host/path/to/page.html`.
+// The query is kept as a short hash so that different queries are stored separately.
+func pageObjectPath(u *url.URL, ext string) string {
+	p := u.Path
+	if p == "" || strings.HasSuffix(p, "/") {
+		p += "index"
+	}
+	p = path.Clean("/" + p)
+	if e := path.Ext(p); e == ".html" || e == ".htm" || e == ".txt" || e == ".md" {
+		p = strings.TrimSuffix(p, e)
+	}
+	if u.RawQuery != "" {
+		sum := sha256.Sum256([]byte(u.RawQuery))
+		p += "_" + hex.EncodeToString(sum[:4])
+	}
+	return u.Hostname() + p + ext
+}
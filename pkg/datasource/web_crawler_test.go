@@ -0,0 +1,242 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+type memoryPageStore struct {
+	mu    sync.Mutex
+	pages map[string]http.Header
+}
+
+func (s *memoryPageStore) metadata(_ context.Context, object string) (http.Header, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta, ok := s.pages[object]
+	return meta, ok
+}
+
+func (s *memoryPageStore) put(_ context.Context, object, _ string, _ []byte, metadata map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta := http.Header{}
+	for k, v := range metadata {
+		meta.Set("X-Amz-Meta-"+k, v)
+	}
+	s.pages[object] = meta
+	return nil
+}
+
+func (s *memoryPageStore) list(_ context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects := make([]string, 0)
+	for object := range s.pages {
+		if strings.HasPrefix(object, prefix) {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+func (s *memoryPageStore) remove(_ context.Context, object string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pages, object)
+	return nil
+}
+
+// testSite serves html pages, a page not in pages is not found
+type testSite struct {
+	mu    sync.Mutex
+	pages map[string]string
+	etags map[string]string
+	// status overrides the response status of a path
+	status map[string]int
+}
+
+func (s *testSite) set(path, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[path] = body
+}
+
+func (s *testSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Path == "/robots.txt" {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		return
+	}
+	if code, ok := s.status[r.URL.Path]; ok {
+		w.WriteHeader(code)
+		return
+	}
+	body, ok := s.pages[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if etag := s.etags[r.URL.Path]; etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, body)
+}
+
+func crawledObjects(pages []CrawledPage, changedOnly bool) []string {
+	objects := make([]string, 0, len(pages))
+	for _, p := range pages {
+		if !changedOnly || p.Changed {
+			objects = append(objects, p.Object)
+		}
+	}
+	sort.Strings(objects)
+	return objects
+}
+
+func TestWebCrawler(t *testing.T) {
+	site := &testSite{
+		pages: map[string]string{
+			"/":             `<a href="/a">a</a><a href="/b#top">b</a><a href="/private/x">private</a>`,
+			"/a":            `<a href="/a/deep">deep</a>`,
+			"/b":            `b`,
+			"/a/deep":       `deep`,
+			"/private/x":    `private`,
+			"/other/orphan": `orphan`,
+		},
+		etags:  map[string]string{"/b": `"b1"`},
+		status: map[string]int{},
+	}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	ctx := context.Background()
+	store := &memoryPageStore{pages: make(map[string]http.Header)}
+	crawler, err := newWebCrawler(store, "web/test/", server.URL+"/", &v1alpha1.WebCrawler{MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	crawl := func() []CrawledPage {
+		t.Helper()
+		pages, err := crawler.Crawl(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pages
+	}
+	want := func(paths ...string) []string {
+		objects := make([]string, 0, len(paths))
+		for _, p := range paths {
+			objects = append(objects, "web/test/127.0.0.1"+p+".html")
+		}
+		sort.Strings(objects)
+		return objects
+	}
+	equal := func(name string, got, want []string) {
+		t.Helper()
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: expected %v, but got %v", name, want, got)
+		}
+	}
+
+	// robots.txt disallows /private and /a/deep is deeper than MaxDepth
+	pages := crawl()
+	equal("first crawl", crawledObjects(pages, false), want("/index", "/a", "/b"))
+	equal("first crawl changed", crawledObjects(pages, true), want("/index", "/a", "/b"))
+
+	pages = crawl()
+	equal("unchanged crawl", crawledObjects(pages, true), want())
+
+	// /b is unchanged by its etag even if the content changed
+	site.set("/a", `<a href="/a/deep">deep</a> changed`)
+	site.set("/b", `b changed`)
+	pages = crawl()
+	equal("changed crawl", crawledObjects(pages, true), want("/a"))
+
+	// a server error may be temporary, nothing is removed
+	site.mu.Lock()
+	site.status["/b"] = http.StatusInternalServerError
+	site.mu.Unlock()
+	pages = crawl()
+	removed, err := crawler.RemoveStalePages(ctx, pages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal("removed after server error", removed, nil)
+
+	// a page not found is removed
+	site.mu.Lock()
+	delete(site.status, "/b")
+	delete(site.pages, "/b")
+	site.mu.Unlock()
+	pages = crawl()
+	removed, err = crawler.RemoveStalePages(ctx, pages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal("removed", removed, want("/b"))
+	objects, _ := store.list(ctx, "web/test/")
+	sort.Strings(objects)
+	equal("stored", objects, want("/index", "/a"))
+}
+
+func TestWebCrawlerMaxPages(t *testing.T) {
+	site := &testSite{
+		pages: map[string]string{
+			"/":  `<a href="/a">a</a><a href="/b">b</a>`,
+			"/a": `a`,
+			"/b": `b`,
+		},
+		status: map[string]int{},
+	}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	ctx := context.Background()
+	store := &memoryPageStore{pages: map[string]http.Header{"web/test/127.0.0.1/stale.html": {}}}
+	crawler, err := newWebCrawler(store, "web/test/", server.URL+"/", &v1alpha1.WebCrawler{MaxPages: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := crawler.Crawl(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 {
+		t.Errorf("expected 2 pages, but got %d", len(pages))
+	}
+	// some pages may be missed when max pages is reached, nothing is removed
+	removed, err := crawler.RemoveStalePages(ctx, pages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected nothing removed, but got %v", removed)
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dedup detects exact duplicated and near-duplicated chunks of a collection.
+//
+// Exact duplicates are detected by the sha256 of the normalized content.
+// Near-duplicates are detected by MinHash or SimHash over character shingles,
+// so that it works for both space separated languages and CJK text.
+package dedup
+
+import (
+	"crypto/sha256"
+	"math/bits"
+	"strings"
+	"sync"
+	"unicode"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+const (
+	// shingleSize is the number of runes in a shingle
+	shingleSize = 5
+
+	minHashSize  = 64
+	minHashBands = 16
+	minHashRows  = minHashSize / minHashBands
+
+	// simHashBands splits the 64 bits simhash into 8 bands, two fingerprints within hamming distance 7 share at least one band
+	simHashBands    = 8
+	simHashBandBits = 64 / simHashBands
+)
+
+var (
+	indexesMutex sync.Mutex
+	indexes      = make(map[string]*Index)
+)
+
+// GetIndex returns the index of a collection. A new empty index is returned if
+// it doesn't exist or the options changed, and `created` is true for it.
+func GetIndex(key string, options arcadiav1alpha1.Deduplication) (index *Index, created bool) {
+	indexesMutex.Lock()
+	defer indexesMutex.Unlock()
+	if index, ok := indexes[key]; ok && index.options == options {
+		return index, false
+	}
+	index = NewIndex(options)
+	indexes[key] = index
+	return index, true
+}
+
+// RemoveIndex drops the index of a collection
+func RemoveIndex(key string) {
+	indexesMutex.Lock()
+	delete(indexes, key)
+	indexesMutex.Unlock()
+}
+
+// RemoveSource drops the chunks of a source from the index of a collection if the index exists
+func RemoveSource(key, source string) {
+	indexesMutex.Lock()
+	index, ok := indexes[key]
+	indexesMutex.Unlock()
+	if ok {
+		index.RemoveSource(source)
+	}
+}
+
+// Entry is a chunk recorded by the index
+type Entry struct {
+	// Content of the chunk
+	Content string
+	// Source is the file where the chunk comes from, empty if unknown
+	Source string
+
+	// position of the chunk in the input of a batch
+	position int
+}
+
+// Duplicate is a chunk found to be a duplicate of a recorded one
+type Duplicate struct {
+	// Index of the duplicated chunk in the input
+	Index int
+	// Of is the recorded chunk which the duplicated one duplicates
+	Of Entry
+	// Exact is true when the content is identical after normalization
+	Exact bool
+	// Batch is the index in the input of the chunk which is duplicated by this one,
+	// or -1 if it is recorded before
+	Batch int
+}
+
+// Index is a set of fingerprints of chunks in a collection
+type Index struct {
+	options arcadiav1alpha1.Deduplication
+	parent  *Index
+
+	mu        sync.RWMutex
+	entries   []Entry
+	exact     map[[sha256.Size]byte]int
+	minHashes [][]uint64
+	simHashes []uint64
+	buckets   map[bucket][]int
+}
+
+type bucket struct {
+	band int
+	key  uint64
+}
+
+// NewIndex creates an empty index
+func NewIndex(options arcadiav1alpha1.Deduplication) *Index {
+	return &Index{
+		options: options,
+		exact:   make(map[[sha256.Size]byte]int),
+		buckets: make(map[bucket][]int),
+	}
+}
+
+// Add records chunks into the index
+func (index *Index) Add(entries ...Entry) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	for _, e := range entries {
+		index.add(e, -1)
+	}
+}
+
+// RemoveSource drops the chunks of a source, so that they are neither duplicates nor stored before any more.
+// It returns the number of dropped chunks.
+func (index *Index) RemoveSource(source string) int {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	entries := index.entries
+	index.entries = nil
+	index.exact = make(map[[sha256.Size]byte]int)
+	index.minHashes = nil
+	index.simHashes = nil
+	index.buckets = make(map[bucket][]int)
+	removed := 0
+	for _, e := range entries {
+		if e.Source == source {
+			removed++
+			continue
+		}
+		index.add(e, e.position)
+	}
+	return removed
+}
+
+// Len returns the number of recorded chunks
+func (index *Index) Len() int {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return len(index.entries)
+}
+
+// NewBatch returns a batch to check chunks against the index and each other.
+// Chunks are recorded into the index only when the batch is committed,
+// so chunks which failed to be stored will not be treated as duplicates later.
+func (index *Index) NewBatch() *Index {
+	batch := NewIndex(index.options)
+	batch.parent = index
+	return batch
+}
+
+// Commit records chunks kept by the batch into its index
+func (index *Index) Commit() {
+	if index.parent == nil {
+		return
+	}
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	index.parent.Add(index.entries...)
+}
+
+// Deduplicate checks chunks from source one by one, and returns positions of the kept chunks and the duplicated ones.
+// Kept chunks are recorded by this batch.
+// Chunks identical to a recorded chunk of the same or an unknown source are regarded as stored before,
+// their positions are returned as `existed` and they are not reported as duplicates.
+func (index *Index) Deduplicate(contents []string, source string) (kept []int, duplicates []Duplicate, existed []int) {
+	for i, content := range contents {
+		fp := index.fingerprint(content)
+		if index.parent != nil {
+			if of, exact, ok := index.parent.match(fp, source); ok {
+				if exact && (of.Source == source || of.Source == "") {
+					existed = append(existed, i)
+					continue
+				}
+				duplicates = append(duplicates, Duplicate{Index: i, Of: of, Exact: exact, Batch: -1})
+				continue
+			}
+		}
+		if of, exact, ok := index.match(fp, ""); ok {
+			duplicates = append(duplicates, Duplicate{Index: i, Of: of, Exact: exact, Batch: of.position})
+			continue
+		}
+		index.mu.Lock()
+		index.addFingerprint(Entry{Content: content, Source: source}, fp, i)
+		index.mu.Unlock()
+		kept = append(kept, i)
+	}
+	return kept, duplicates, existed
+}
+
+type fingerprint struct {
+	sum     [sha256.Size]byte
+	minHash []uint64
+	simHash uint64
+}
+
+func (index *Index) fingerprint(content string) fingerprint {
+	normalized := normalize(content)
+	fp := fingerprint{sum: sha256.Sum256([]byte(normalized))}
+	switch index.options.Algorithm {
+	case arcadiav1alpha1.DeduplicationAlgorithmMinHash:
+		fp.minHash = minHash(shingles(normalized))
+	case arcadiav1alpha1.DeduplicationAlgorithmSimHash:
+		fp.simHash = simHash(shingles(normalized))
+	}
+	return fp
+}
+
+func (index *Index) add(e Entry, position int) {
+	index.addFingerprint(e, index.fingerprint(e.Content), position)
+}
+
+func (index *Index) addFingerprint(e Entry, fp fingerprint, position int) {
+	i := len(index.entries)
+	e.position = position
+	index.entries = append(index.entries, e)
+	if _, ok := index.exact[fp.sum]; !ok {
+		index.exact[fp.sum] = i
+	}
+	switch index.options.Algorithm {
+	case arcadiav1alpha1.DeduplicationAlgorithmMinHash:
+		index.minHashes = append(index.minHashes, fp.minHash)
+		for band := 0; band < minHashBands; band++ {
+			b := bucket{band: band, key: bandKey(fp.minHash[band*minHashRows : (band+1)*minHashRows])}
+			index.buckets[b] = append(index.buckets[b], i)
+		}
+	case arcadiav1alpha1.DeduplicationAlgorithmSimHash:
+		index.simHashes = append(index.simHashes, fp.simHash)
+		for band := 0; band < simHashBands; band++ {
+			b := bucket{band: band, key: (fp.simHash >> (band * simHashBandBits)) & (1<<simHashBandBits - 1)}
+			index.buckets[b] = append(index.buckets[b], i)
+		}
+	}
+}
+
+// match finds a recorded chunk which the fingerprint duplicates.
+// Near-duplicates of chunks from the excluded source are ignored.
+func (index *Index) match(fp fingerprint, exclude string) (Entry, bool, bool) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	if i, ok := index.exact[fp.sum]; ok {
+		return index.entries[i], true, true
+	}
+	best, bestSimilarity := -1, 0.0
+	check := func(i int, similarity float64) {
+		if similarity < index.options.Threshold || similarity <= bestSimilarity {
+			return
+		}
+		if exclude != "" && index.entries[i].Source == exclude {
+			return
+		}
+		best, bestSimilarity = i, similarity
+	}
+	switch index.options.Algorithm {
+	case arcadiav1alpha1.DeduplicationAlgorithmMinHash:
+		for band := 0; band < minHashBands; band++ {
+			b := bucket{band: band, key: bandKey(fp.minHash[band*minHashRows : (band+1)*minHashRows])}
+			for _, i := range index.buckets[b] {
+				check(i, minHashSimilarity(fp.minHash, index.minHashes[i]))
+			}
+		}
+	case arcadiav1alpha1.DeduplicationAlgorithmSimHash:
+		for band := 0; band < simHashBands; band++ {
+			b := bucket{band: band, key: (fp.simHash >> (band * simHashBandBits)) & (1<<simHashBandBits - 1)}
+			for _, i := range index.buckets[b] {
+				check(i, simHashSimilarity(fp.simHash, index.simHashes[i]))
+			}
+		}
+	}
+	if best < 0 {
+		return Entry{}, false, false
+	}
+	return index.entries[best], false, true
+}
+
+// normalize lowercases the content and collapses spaces
+func normalize(content string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(content, unicode.IsSpace), " "))
+}
+
+// shingles returns hashes of all runes shingles of the normalized content
+func shingles(normalized string) []uint64 {
+	runes := []rune(normalized)
+	if len(runes) <= shingleSize {
+		return []uint64{hashString(normalized)}
+	}
+	result := make([]uint64, 0, len(runes)-shingleSize+1)
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		result = append(result, hashString(string(runes[i:i+shingleSize])))
+	}
+	return result
+}
+
+// hashString is the 64-bit FNV-1a hash
+func hashString(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// mix is the finalizer of splitmix64, used to derive independent hash functions
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func minHash(shingles []uint64) []uint64 {
+	signature := make([]uint64, minHashSize)
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+	for _, s := range shingles {
+		for i := range signature {
+			if h := mix(s + uint64(i)*0x9e3779b97f4a7c15); h < signature[i] {
+				signature[i] = h
+			}
+		}
+	}
+	return signature
+}
+
+// minHashSimilarity estimates the jaccard similarity of two shingle sets
+func minHashSimilarity(a, b []uint64) float64 {
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+func bandKey(rows []uint64) uint64 {
+	key := uint64(0)
+	for _, r := range rows {
+		key = mix(key ^ r)
+	}
+	return key
+}
+
+func simHash(shingles []uint64) uint64 {
+	var weights [64]int
+	for _, s := range shingles {
+		h := mix(s)
+		for i := 0; i < 64; i++ {
+			if h&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	var result uint64
+	for i, w := range weights {
+		if w > 0 {
+			result |= 1 << i
+		}
+	}
+	return result
+}
+
+// simHashSimilarity is the proportion of the same bits of two simhashes
+func simHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
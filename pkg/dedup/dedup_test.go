@@ -0,0 +1,118 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"reflect"
+	"testing"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+const (
+	clause     = "The employee shall keep all confidential information of the company secret during and after the term of employment, and shall not disclose it to any third party without prior written consent."
+	clauseEdit = "The employee shall keep all confidential information of the company secret during and after the term of employment, and shall not disclose it to any third party without the prior written consent."
+	other      = "Annual leave is granted on a pro rata basis in the first year. Unused leave may be carried over to the next year with the approval of the manager."
+)
+
+func TestDeduplicate(t *testing.T) {
+	for _, algorithm := range []arcadiav1alpha1.DeduplicationAlgorithm{
+		arcadiav1alpha1.DeduplicationAlgorithmNone,
+		arcadiav1alpha1.DeduplicationAlgorithmMinHash,
+		arcadiav1alpha1.DeduplicationAlgorithmSimHash,
+	} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			near := algorithm != arcadiav1alpha1.DeduplicationAlgorithmNone
+			index := NewIndex(arcadiav1alpha1.Deduplication{Algorithm: algorithm, Threshold: 0.8})
+
+			// duplicates in the same file
+			batch := index.NewBatch()
+			kept, dups, existed := batch.Deduplicate([]string{clause, "  THE employee shall keep all confidential information of the company secret during and after the term of employment,\nand shall not disclose it to any third party without prior written consent.", other}, "v1.txt")
+			if !reflect.DeepEqual(kept, []int{0, 2}) || len(dups) != 1 || !dups[0].Exact || dups[0].Batch != 0 || len(existed) != 0 {
+				t.Fatalf("unexpected result kept:%v duplicates:%+v existed:%v", kept, dups, existed)
+			}
+			if index.Len() != 0 {
+				t.Fatalf("chunks should not be recorded before commit")
+			}
+			batch.Commit()
+			if index.Len() != 2 {
+				t.Fatalf("expected 2 chunks recorded, but got %d", index.Len())
+			}
+
+			// near-duplicates across files
+			batch = index.NewBatch()
+			kept, dups, _ = batch.Deduplicate([]string{clauseEdit, "A totally different paragraph about something else entirely."}, "v2.txt")
+			if near {
+				if !reflect.DeepEqual(kept, []int{1}) || len(dups) != 1 || dups[0].Exact || dups[0].Batch != -1 || dups[0].Of.Content != clause || dups[0].Of.Source != "v1.txt" {
+					t.Fatalf("unexpected result kept:%v duplicates:%+v", kept, dups)
+				}
+			} else if len(kept) != 2 || len(dups) != 0 {
+				t.Fatalf("unexpected result kept:%v duplicates:%+v", kept, dups)
+			}
+
+			// reprocess the changed file, its own stored chunks are neither duplicates nor near-duplicates
+			batch = index.NewBatch()
+			kept, dups, existed = batch.Deduplicate([]string{clauseEdit, other}, "v1.txt")
+			if !reflect.DeepEqual(kept, []int{0}) || len(dups) != 0 || !reflect.DeepEqual(existed, []int{1}) {
+				t.Fatalf("unexpected result kept:%v duplicates:%+v existed:%v", kept, dups, existed)
+			}
+		})
+	}
+}
+
+func TestRemoveSource(t *testing.T) {
+	for _, algorithm := range []arcadiav1alpha1.DeduplicationAlgorithm{
+		arcadiav1alpha1.DeduplicationAlgorithmNone,
+		arcadiav1alpha1.DeduplicationAlgorithmMinHash,
+		arcadiav1alpha1.DeduplicationAlgorithmSimHash,
+	} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			index := NewIndex(arcadiav1alpha1.Deduplication{Algorithm: algorithm, Threshold: 0.8})
+			index.Add(Entry{Content: clause, Source: "v1.txt"}, Entry{Content: other, Source: "v2.txt"})
+
+			if removed := index.RemoveSource("v1.txt"); removed != 1 || index.Len() != 1 {
+				t.Fatalf("expected 1 chunk removed and 1 left, but got %d and %d", removed, index.Len())
+			}
+			// the chunks of the removed file are neither duplicates nor stored before
+			batch := index.NewBatch()
+			kept, dups, existed := batch.Deduplicate([]string{clause, clauseEdit}, "v1.txt")
+			if len(existed) != 0 || len(kept)+len(dups) != 2 || kept[0] != 0 {
+				t.Fatalf("unexpected result kept:%v duplicates:%+v existed:%v", kept, dups, existed)
+			}
+			kept, dups, _ = index.NewBatch().Deduplicate([]string{other}, "v3.txt")
+			if len(kept) != 0 || len(dups) != 1 || dups[0].Of.Source != "v2.txt" {
+				t.Fatalf("expected the chunks of other files kept, but got kept:%v duplicates:%+v", kept, dups)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	a, b, c := shingles(normalize(clause)), shingles(normalize(clauseEdit)), shingles(normalize(other))
+	if s := minHashSimilarity(minHash(a), minHash(b)); s < 0.8 {
+		t.Errorf("expected minhash similarity of near-duplicates >= 0.8, but got %f", s)
+	}
+	if s := minHashSimilarity(minHash(a), minHash(c)); s > 0.2 {
+		t.Errorf("expected minhash similarity of different texts <= 0.2, but got %f", s)
+	}
+	if s := simHashSimilarity(simHash(a), simHash(b)); s < 0.8 {
+		t.Errorf("expected simhash similarity of near-duplicates >= 0.8, but got %f", s)
+	}
+	if s := simHashSimilarity(simHash(a), simHash(c)); s > 0.8 {
+		t.Errorf("expected simhash similarity of different texts <= 0.8, but got %f", s)
+	}
+}
@@ -34,9 +34,9 @@ const (
 	MaxTextLength = 25 // https://help.aliyun.com/zh/dashscope/developer-reference/text-embedding-quick-start
 )
 
-func NewDashScopeEmbedder(apiKey string) *DashScopeEmbedder {
+func NewDashScopeEmbedder(apiKey string, opts ...dashscope.Option) *DashScopeEmbedder {
 	return &DashScopeEmbedder{
-		DashScope: dashscope.NewDashScope(apiKey, false),
+		DashScope: dashscope.NewDashScope(apiKey, false, opts...),
 	}
 }
 func (d DashScopeEmbedder) EmbedDocuments(ctx context.Context, texts []string) (res [][]float32, err error) {
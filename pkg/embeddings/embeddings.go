@@ -19,14 +19,16 @@ package embeddings
 type EmbeddingType string
 
 const (
-	OpenAI  EmbeddingType = "openai"
-	ZhiPuAI EmbeddingType = "zhipuai"
-	Gemini  EmbeddingType = "gemini"
-	Unknown EmbeddingType = "unknown"
+	OpenAI    EmbeddingType = "openai"
+	ZhiPuAI   EmbeddingType = "zhipuai"
+	Gemini    EmbeddingType = "gemini"
+	DashScope EmbeddingType = "dashscope"
+	Unknown   EmbeddingType = "unknown"
 )
 
 var (
-	ZhiPuAIModels = []string{"text_embedding"}
-	OpenAIModels  = []string{"text-embedding-ada-002"}
-	GeminiModels  = []string{"embedding-001"}
+	ZhiPuAIModels   = []string{"text_embedding"}
+	OpenAIModels    = []string{"text-embedding-ada-002"}
+	GeminiModels    = []string{"embedding-001"}
+	DashScopeModels = []string{"text-embedding-v1"}
 )
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langchainwrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/llms"
+	"github.com/kubeagi/arcadia/pkg/llms/composite"
+)
+
+// getCompositeLLM creates a llm which retries and fails over across the backends of the composite llm
+func getCompositeLLM(ctx context.Context, llm *v1alpha1.LLM, c client.Client, model string) (langchainllms.Model, error) {
+	spec := llm.Spec.Composite
+	if spec == nil {
+		return nil, fmt.Errorf("llm.spec.composite not defined")
+	}
+	backends := make([]composite.Backend, 0, len(spec.Backends))
+	for _, ref := range spec.Backends {
+		namespace := ref.LLM.GetNamespace(llm.GetNamespace())
+		backendLLM := &v1alpha1.LLM{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.LLM.Name}, backendLLM); err != nil {
+			klog.Warningf("skip backend %s/%s of composite llm %s/%s: %s", namespace, ref.LLM.Name, llm.Namespace, llm.Name, err)
+			continue
+		}
+		if backendLLM.Spec.Type == llms.Composite {
+			return nil, fmt.Errorf("backend %s/%s is a composite llm, which is not supported", namespace, ref.LLM.Name)
+		}
+		backendModel := ref.Model
+		if mapped, ok := ref.ModelMapping[model]; ok {
+			backendModel = mapped
+		}
+		backend, err := GetLangchainLLM(ctx, backendLLM, c, backendModel)
+		if err != nil {
+			klog.Warningf("skip backend %s/%s of composite llm %s/%s: %s", namespace, ref.LLM.Name, llm.Namespace, llm.Name, err)
+			continue
+		}
+		backends = append(backends, composite.Backend{
+			Name:         namespace + "/" + ref.LLM.Name,
+			Model:        backend,
+			Weight:       ref.Weight,
+			ModelMapping: ref.ModelMapping,
+		})
+	}
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no available backends for composite llm %s/%s", llm.Namespace, llm.Name)
+	}
+
+	failureThreshold, cooldown := composite.DefaultFailureThreshold, composite.DefaultCooldown
+	if spec.CircuitBreaker != nil {
+		if spec.CircuitBreaker.FailureThreshold > 0 {
+			failureThreshold = spec.CircuitBreaker.FailureThreshold
+		}
+		if spec.CircuitBreaker.CooldownSeconds > 0 {
+			cooldown = time.Duration(spec.CircuitBreaker.CooldownSeconds) * time.Second
+		}
+	}
+	return composite.NewCompositeLLM(backends,
+		composite.WithKey(llm.Namespace+"/"+llm.Name),
+		composite.WithStrategy(spec.Strategy),
+		composite.WithRetry(spec.MaxRetries, time.Second),
+		composite.WithCircuitBreaker(failureThreshold, cooldown),
+		composite.WithStateChangeHandler(backendStatusUpdater(c, llm, cooldown)))
+}
+
+// backendStatusUpdater updates the backend status of the composite llm when a circuit is opened or closed
+func backendStatusUpdater(c client.Client, llm *v1alpha1.LLM, cooldown time.Duration) composite.StateChangeHandler {
+	key := client.ObjectKeyFromObject(llm)
+	return func(backend string, healthy bool, err error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		namespace, name, _ := cache.SplitMetaNamespaceKey(backend)
+		status := v1alpha1.LLMBackendStatus{
+			Name:      name,
+			Namespace: namespace,
+			Healthy:   healthy,
+		}
+		if !healthy {
+			openUntil := metav1.NewTime(time.Now().Add(cooldown))
+			status.CircuitOpenUntil = &openUntil
+			status.Message = fmt.Sprintf("circuit opened: %s", err)
+		}
+		latest := &v1alpha1.LLM{}
+		if err := c.Get(ctx, key, latest); err != nil {
+			klog.Errorf("failed to get composite llm %s: %s", key, err)
+			return
+		}
+		patch := client.MergeFrom(latest.DeepCopy())
+		latest.Status.SetBackendStatus(status)
+		if err := c.Status().Patch(ctx, latest, patch); err != nil {
+			klog.Errorf("failed to update backend status of composite llm %s: %s", key, err)
+		}
+	}
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/kubeagi/arcadia/api/base/v1alpha1"
 	"github.com/kubeagi/arcadia/pkg/config"
 	"github.com/kubeagi/arcadia/pkg/embeddings"
+	dashscopeembeddings "github.com/kubeagi/arcadia/pkg/embeddings/dashscope"
 	zhipuaiembeddings "github.com/kubeagi/arcadia/pkg/embeddings/zhipuai"
+	"github.com/kubeagi/arcadia/pkg/llms/dashscope"
 	"github.com/kubeagi/arcadia/pkg/llms/zhipuai"
 )
 
@@ -43,9 +45,13 @@ func GetLangchainEmbedder(ctx context.Context, e *v1alpha1.Embedder, c client.Cl
 			if err != nil {
 				return nil, err
 			}
-			return zhipuaiembeddings.NewZhiPuAI(
+			em, err := zhipuaiembeddings.NewZhiPuAI(
 				zhipuaiembeddings.WithClient(*zhipuai.NewZhiPuAI(apiKey)),
 			)
+			if err != nil {
+				return nil, err
+			}
+			return newRateLimitedEmbedder(e, em), nil
 		case embeddings.OpenAI:
 			apiKey, err := e.AuthAPIKey(ctx, c)
 			if err != nil {
@@ -68,7 +74,7 @@ func GetLangchainEmbedder(ctx context.Context, e *v1alpha1.Embedder, c client.Cl
 			if err != nil {
 				return nil, err
 			}
-			return langchaingoembeddings.NewEmbedder(llm, opts...)
+			return langchaingoembeddings.NewEmbedder(newRateLimitedClient(e, llm), opts...)
 		case embeddings.Gemini:
 			apiKey, err := e.AuthAPIKey(ctx, c)
 			if err != nil {
@@ -87,7 +93,22 @@ func GetLangchainEmbedder(ctx context.Context, e *v1alpha1.Embedder, c client.Cl
 			if err != nil {
 				return nil, err
 			}
-			return langchaingoembeddings.NewEmbedder(llm, opts...)
+			return langchaingoembeddings.NewEmbedder(newRateLimitedClient(e, llm), opts...)
+		case embeddings.DashScope:
+			apiKey, err := e.AuthAPIKey(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			if model == "" {
+				models := e.GetModelList()
+				if len(models) == 0 {
+					return nil, errors.New("no valid models for this Embedder")
+				}
+				model = models[0]
+			}
+			em := dashscopeembeddings.NewDashScopeEmbedder(apiKey,
+				dashscope.WithBaseURL(e.Get3rdPartyEmbedderBaseURL()), dashscope.WithEmbeddingModel(dashscope.Model(model)))
+			return newRateLimitedEmbedder(e, em), nil
 		}
 	case v1alpha1.ProviderTypeWorker:
 		gateway, err := config.GetGateway(ctx)
@@ -114,7 +135,7 @@ func GetLangchainEmbedder(ctx context.Context, e *v1alpha1.Embedder, c client.Cl
 		if err != nil {
 			return nil, err
 		}
-		return langchaingoembeddings.NewEmbedder(llm, opts...)
+		return langchaingoembeddings.NewEmbedder(newRateLimitedClient(e, newWorkerEmbedderClient(llm, c, worker)), opts...)
 	}
 	return nil, fmt.Errorf("unknown provider type")
 }
@@ -24,6 +24,7 @@ import (
 
 	langchainllms "github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/googleai"
+	"github.com/tmc/langchaingo/llms/ollama"
 	"github.com/tmc/langchaingo/llms/openai"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,6 +33,9 @@ import (
 	"github.com/kubeagi/arcadia/pkg/appruntime/log"
 	"github.com/kubeagi/arcadia/pkg/config"
 	"github.com/kubeagi/arcadia/pkg/llms"
+	"github.com/kubeagi/arcadia/pkg/llms/anthropic"
+	"github.com/kubeagi/arcadia/pkg/llms/dashscope"
+	arcadiaopenai "github.com/kubeagi/arcadia/pkg/llms/openai"
 	"github.com/kubeagi/arcadia/pkg/llms/zhipuai"
 )
 
@@ -40,6 +44,9 @@ const (
 )
 
 func GetLangchainLLM(ctx context.Context, llm *v1alpha1.LLM, c client.Client, model string) (langchainllms.Model, error) {
+	if llm.Spec.Type == llms.Composite {
+		return getCompositeLLM(ctx, llm, c, model)
+	}
 	switch llm.Spec.Provider.GetType() {
 	case v1alpha1.ProviderType3rdParty:
 		apiKey, err := llm.AuthAPIKey(ctx, c)
@@ -48,7 +55,12 @@ func GetLangchainLLM(ctx context.Context, llm *v1alpha1.LLM, c client.Client, mo
 		}
 		switch llm.Spec.Type {
 		case llms.ZhiPuAI:
-			return zhipuai.NewZhiPuAILLM(apiKey, zhipuai.WithRetryTimes(3), zhipuai.WithCallback(log.KLogHandler{LogLevel: 3})), nil
+			// use the customized model by default, the default models of zhipuai are chosen by each call
+			if model == "" && len(llm.Spec.Models) != 0 {
+				model = llm.Spec.Models[0]
+			}
+			return zhipuai.NewZhiPuAILLM(apiKey, zhipuai.WithRetryTimes(3), zhipuai.WithCallback(log.KLogHandler{LogLevel: 3}),
+				zhipuai.WithModel(model), zhipuai.WithBaseURL(llm.Get3rdPartyLLMBaseURL())), nil
 		case llms.OpenAI:
 			// When apitype is OpenAI,there are two possible sources:
 			// 1. From official OpenAI
@@ -61,7 +73,11 @@ func GetLangchainLLM(ctx context.Context, llm *v1alpha1.LLM, c client.Client, mo
 				}
 				model = models[0]
 			}
-			return openai.New(openai.WithToken(apiKey), openai.WithBaseURL(llm.Get3rdPartyLLMBaseURL()), openai.WithModel(model), openai.WithCallback(log.KLogHandler{LogLevel: 3}))
+			openaiLLM, err := openai.New(openai.WithToken(apiKey), openai.WithBaseURL(llm.Get3rdPartyLLMBaseURL()), openai.WithModel(model), openai.WithCallback(log.KLogHandler{LogLevel: 3}))
+			if err != nil {
+				return nil, err
+			}
+			return arcadiaopenai.NewToolCallingLLM(openaiLLM, apiKey, llm.Get3rdPartyLLMBaseURL(), model), nil
 		case llms.Gemini:
 			if model == "" {
 				models := llm.GetModelList()
@@ -76,6 +92,59 @@ func GetLangchainLLM(ctx context.Context, llm *v1alpha1.LLM, c client.Client, mo
 			}
 			googleLLM.CallbacksHandler = log.GeminiKLogHandler{KLogHandler: &log.KLogHandler{LogLevel: 3}}
 			return googleLLM, nil
+		case llms.DashScope:
+			if model == "" {
+				models := llm.GetModelList()
+				if len(models) == 0 {
+					return nil, errors.New("no valid models for this LLM")
+				}
+				model = models[0]
+			}
+			return dashscope.NewDashScopeLLM(apiKey, dashscope.WithModel(model),
+				dashscope.WithClientOptions(dashscope.WithBaseURL(llm.Get3rdPartyLLMBaseURL())),
+				dashscope.WithCallback(log.KLogHandler{LogLevel: 3})), nil
+		case llms.Ollama:
+			if model == "" {
+				models := llm.GetModelList()
+				if len(models) == 0 {
+					return nil, errors.New("no valid models for this LLM")
+				}
+				model = models[0]
+			}
+			ollamaLLM, err := ollama.New(ollama.WithServerURL(llm.Get3rdPartyLLMBaseURL()), ollama.WithModel(model))
+			if err != nil {
+				return nil, err
+			}
+			ollamaLLM.CallbacksHandler = log.KLogHandler{LogLevel: 3}
+			return ollamaLLM, nil
+		case llms.OpenAICompatible:
+			if model == "" {
+				models := llm.GetModelList()
+				if len(models) == 0 {
+					return nil, errors.New("no valid models for this LLM")
+				}
+				model = models[0]
+			}
+			// the openai client requires a token even if the service doesn't
+			if apiKey == "" {
+				apiKey = arcadiaopenai.EmptyAPIKey
+			}
+			openaiLLM, err := openai.New(openai.WithToken(apiKey), openai.WithBaseURL(llm.Get3rdPartyLLMBaseURL()), openai.WithModel(model), openai.WithCallback(log.KLogHandler{LogLevel: 3}))
+			if err != nil {
+				return nil, err
+			}
+			// tool calling depends on the service, the agent falls back to ReAct if it's not supported
+			return arcadiaopenai.NewToolCallingLLM(openaiLLM, apiKey, llm.Get3rdPartyLLMBaseURL(), model), nil
+		case llms.Anthropic:
+			if model == "" {
+				models := llm.GetModelList()
+				if len(models) == 0 {
+					return nil, errors.New("no valid models for this LLM")
+				}
+				model = models[0]
+			}
+			return anthropic.NewAnthropicLLM(apiKey, anthropic.WithModel(model), anthropic.WithBaseURL(llm.Get3rdPartyLLMBaseURL()),
+				anthropic.WithCallback(log.KLogHandler{LogLevel: 3})), nil
 		}
 	case v1alpha1.ProviderTypeWorker:
 		gateway, err := config.GetGateway(ctx)
@@ -104,7 +173,11 @@ func GetLangchainLLM(ctx context.Context, llm *v1alpha1.LLM, c client.Client, mo
 		if os.Getenv(GatewayUseExternalURLEnv) == "true" {
 			gatewayURL = gateway.ExternalAPIServer
 		}
-		return openai.New(openai.WithModel(modelName), openai.WithBaseURL(gatewayURL), openai.WithToken("fake"), openai.WithCallback(log.KLogHandler{LogLevel: 3}))
+		llm, err := openai.New(openai.WithModel(modelName), openai.WithBaseURL(gatewayURL), openai.WithToken("fake"), openai.WithCallback(log.KLogHandler{LogLevel: 3}))
+		if err != nil {
+			return nil, err
+		}
+		return newWorkerLLM(llm, c, worker), nil
 	}
 	return nil, fmt.Errorf("unknown provider type")
 }
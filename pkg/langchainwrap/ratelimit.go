@@ -0,0 +1,184 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langchainwrap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	langchaingoembeddings "github.com/tmc/langchaingo/embeddings"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+const (
+	// rateLimitedRetries is the max number of retries of a request which is rate limited by the service
+	rateLimitedRetries = 5
+	rateLimitedBackoff = 2 * time.Second
+)
+
+var (
+	limitersMutex sync.Mutex
+	limiters      = make(map[string]*Limiter)
+)
+
+// Limiter limits the requests and tokens sent to a model service in a minute
+type Limiter struct {
+	limit    v1alpha1.RateLimit
+	requests *rate.Limiter
+	tokens   *rate.Limiter
+	// backoff is the delay before the first retry of a rate limited request, doubled for each retry
+	backoff time.Duration
+}
+
+// GetLimiter returns the limiter shared by all callers of the same service.
+// A new limiter is created when the limit changes.
+func GetLimiter(key string, limit *v1alpha1.RateLimit) *Limiter {
+	if limit == nil {
+		limit = &v1alpha1.RateLimit{}
+	}
+	limitersMutex.Lock()
+	defer limitersMutex.Unlock()
+	if l, ok := limiters[key]; ok && l.limit == *limit {
+		return l
+	}
+	l := &Limiter{limit: *limit, backoff: rateLimitedBackoff}
+	if limit.RequestsPerMinute > 0 {
+		l.requests = rate.NewLimiter(rate.Limit(float64(limit.RequestsPerMinute)/60), limit.RequestsPerMinute)
+	}
+	if limit.TokensPerMinute > 0 {
+		l.tokens = rate.NewLimiter(rate.Limit(float64(limit.TokensPerMinute)/60), limit.TokensPerMinute)
+	}
+	limiters[key] = l
+	return l
+}
+
+// Wait blocks until a request with these texts is allowed
+func (l *Limiter) Wait(ctx context.Context, texts []string) error {
+	if l == nil {
+		return nil
+	}
+	if l.requests != nil {
+		if err := l.requests.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.tokens != nil {
+		tokens := 0
+		for _, t := range texts {
+			tokens += EstimateTokens(t)
+		}
+		// a request larger than the limit of a minute can only wait for the whole minute
+		if tokens > l.tokens.Burst() {
+			tokens = l.tokens.Burst()
+		}
+		if err := l.tokens.WaitN(ctx, tokens); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EstimateTokens estimates the tokens of a text without a tokenizer.
+// Most tokenizers encode about 4 bytes of english or 1 CJK character into a token.
+func EstimateTokens(text string) int {
+	tokens, ascii := 0, 0
+	for _, r := range text {
+		if r < 128 {
+			ascii++
+			continue
+		}
+		tokens++
+	}
+	return tokens + (ascii+3)/4
+}
+
+// rateLimitedClient limits the embedding requests and retries them when they are rate limited by the service
+type rateLimitedClient struct {
+	client  langchaingoembeddings.EmbedderClient
+	limiter *Limiter
+}
+
+var _ langchaingoembeddings.EmbedderClient = (*rateLimitedClient)(nil)
+
+func newRateLimitedClient(e *v1alpha1.Embedder, client langchaingoembeddings.EmbedderClient) langchaingoembeddings.EmbedderClient {
+	return &rateLimitedClient{
+		client:  client,
+		limiter: GetLimiter(string(e.GetUID()), e.Spec.RateLimit),
+	}
+}
+
+func (c *rateLimitedClient) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	return c.limiter.do(ctx, texts, func() ([][]float32, error) {
+		return c.client.CreateEmbedding(ctx, texts)
+	})
+}
+
+// do calls the service when it is allowed, and retries when it is rate limited by the service
+func (l *Limiter) do(ctx context.Context, texts []string, call func() ([][]float32, error)) (embeddings [][]float32, err error) {
+	backoff := l.backoff
+	for i := 0; ; i++ {
+		if err = l.Wait(ctx, texts); err != nil {
+			return nil, err
+		}
+		embeddings, err = call()
+		if err == nil || !utils.IsRateLimitedError(err) || i >= rateLimitedRetries {
+			return embeddings, err
+		}
+		klog.V(3).Infof("embedding request is rate limited, retry after %s: %s", backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// rateLimitedEmbedder limits an embedder which doesn't support custom clients
+type rateLimitedEmbedder struct {
+	langchaingoembeddings.Embedder
+	limiter *Limiter
+}
+
+func newRateLimitedEmbedder(e *v1alpha1.Embedder, embedder langchaingoembeddings.Embedder) langchaingoembeddings.Embedder {
+	return &rateLimitedEmbedder{
+		Embedder: embedder,
+		limiter:  GetLimiter(string(e.GetUID()), e.Spec.RateLimit),
+	}
+}
+
+func (r *rateLimitedEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	return r.limiter.do(ctx, texts, func() ([][]float32, error) {
+		return r.Embedder.EmbedDocuments(ctx, texts)
+	})
+}
+
+func (r *rateLimitedEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := r.limiter.do(ctx, []string{text}, func() ([][]float32, error) {
+		embedding, err := r.Embedder.EmbedQuery(ctx, text)
+		return [][]float32{embedding}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
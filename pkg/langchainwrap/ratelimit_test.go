@@ -0,0 +1,125 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langchainwrap
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	testCases := map[string]int{
+		"":              0,
+		"abcd":          1,
+		"hello world":   3,
+		"你好":            2,
+		"你好 world":      4,
+		"   ":           1,
+		"a longer text": 4,
+	}
+	for text, tokens := range testCases {
+		if got := EstimateTokens(text); got != tokens {
+			t.Errorf("%q: expected %d tokens, but got %d", text, tokens, got)
+		}
+	}
+}
+
+func TestGetLimiter(t *testing.T) {
+	limit := &v1alpha1.RateLimit{RequestsPerMinute: 60}
+	l := GetLimiter("test-get-limiter", limit)
+	if l.requests == nil || l.tokens != nil {
+		t.Fatalf("expected only a request limiter, but got %+v", l)
+	}
+	if GetLimiter("test-get-limiter", &v1alpha1.RateLimit{RequestsPerMinute: 60}) != l {
+		t.Error("expected the limiter to be shared by the same service")
+	}
+	changed := GetLimiter("test-get-limiter", &v1alpha1.RateLimit{RequestsPerMinute: 60, TokensPerMinute: 1000})
+	if changed == l || changed.tokens == nil {
+		t.Error("expected a new limiter when the limit changes")
+	}
+	if unlimited := GetLimiter("test-get-limiter-nil", nil); unlimited.requests != nil || unlimited.tokens != nil {
+		t.Error("expected no limit without a rate limit")
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	ctx := context.Background()
+	l := GetLimiter("test-limiter-wait", &v1alpha1.RateLimit{TokensPerMinute: 60})
+	// a request larger than the limit of a minute takes the whole minute instead of failing forever
+	if err := l.Wait(ctx, []string{strings.Repeat("a", 1000)}); err != nil {
+		t.Fatalf("expected the first request allowed, but got %v", err)
+	}
+	timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(timeout, []string{"abcdefgh"}); err == nil {
+		t.Error("expected the request to wait for tokens over the limit")
+	}
+
+	var unlimited *Limiter
+	if err := unlimited.Wait(ctx, []string{"abcd"}); err != nil {
+		t.Errorf("expected a nil limiter to allow all requests, but got %v", err)
+	}
+}
+
+func TestLimiterDo(t *testing.T) {
+	ctx := context.Background()
+	l := &Limiter{backoff: time.Millisecond}
+
+	calls := 0
+	embeddings, err := l.do(ctx, []string{"text"}, func() ([][]float32, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.New("API returned unexpected status code: 429: Rate limit reached")
+		}
+		return [][]float32{{1}}, nil
+	})
+	if err != nil || len(embeddings) != 1 || calls != 3 {
+		t.Errorf("expected rate limited requests to be retried, but got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	_, err = l.do(ctx, []string{"text"}, func() ([][]float32, error) {
+		calls++
+		return nil, errors.New("API returned unexpected status code: 401: invalid api key")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("expected other errors not to be retried, but got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	_, err = l.do(ctx, []string{"text"}, func() ([][]float32, error) {
+		calls++
+		return nil, errors.New("exception: 429 Too Many Requests")
+	})
+	if err == nil || calls != rateLimitedRetries+1 {
+		t.Errorf("expected %d calls before giving up, but got %d", rateLimitedRetries+1, calls)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	l.backoff = time.Hour
+	if _, err = l.do(canceled, []string{"text"}, func() ([][]float32, error) {
+		return nil, errors.New("exception: 429 Too Many Requests")
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the retry to stop with the context, but got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package langchainwrap
+
+import (
+	"context"
+
+	langchaingoembeddings "github.com/tmc/langchaingo/embeddings"
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/worker"
+)
+
+var _ langchainllms.Model = (*workerLLM)(nil)
+
+// workerLLM tracks the requests to the worker for autoscaling, and wakes it up if scaled to zero
+type workerLLM struct {
+	langchainllms.Model
+	c      client.Client
+	worker *v1alpha1.Worker
+}
+
+func newWorkerLLM(llm langchainllms.Model, c client.Client, w *v1alpha1.Worker) langchainllms.Model {
+	if w.Spec.Autoscaling == nil {
+		return llm
+	}
+	return &workerLLM{Model: llm, c: c, worker: w}
+}
+
+func (l *workerLLM) Call(ctx context.Context, prompt string, options ...langchainllms.CallOption) (string, error) {
+	return langchainllms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l *workerLLM) GenerateContent(ctx context.Context, messages []langchainllms.MessageContent, options ...langchainllms.CallOption) (*langchainllms.ContentResponse, error) {
+	done, err := worker.TrackRequest(ctx, l.c, l.worker)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return l.Model.GenerateContent(ctx, messages, options...)
+}
+
+var _ langchaingoembeddings.EmbedderClient = (*workerEmbedderClient)(nil)
+
+// workerEmbedderClient tracks the embedding requests to the worker like workerLLM
+type workerEmbedderClient struct {
+	langchaingoembeddings.EmbedderClient
+	c      client.Client
+	worker *v1alpha1.Worker
+}
+
+func newWorkerEmbedderClient(embedderClient langchaingoembeddings.EmbedderClient, c client.Client, w *v1alpha1.Worker) langchaingoembeddings.EmbedderClient {
+	if w.Spec.Autoscaling == nil {
+		return embedderClient
+	}
+	return &workerEmbedderClient{EmbedderClient: embedderClient, c: c, worker: w}
+}
+
+func (e *workerEmbedderClient) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	done, err := worker.TrackRequest(ctx, e.c, e.worker)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return e.EmbedderClient.CreateEmbedding(ctx, texts)
+}
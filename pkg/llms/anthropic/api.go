@@ -0,0 +1,228 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+const (
+	DefaultBaseURL = "https://api.anthropic.com"
+	// APIVersion is the version of the messages api
+	APIVersion = "2023-06-01"
+	// DefaultMaxTokens is used when max tokens is not set, which is required by the messages api
+	DefaultMaxTokens = 1024
+
+	messagesPath = "/v1/messages"
+	modelsPath   = "/v1/models"
+)
+
+var (
+	_ llms.LLM         = (*Anthropic)(nil)
+	_ llms.ModelLister = (*Anthropic)(nil)
+)
+
+// Anthropic is a client of the anthropic messages api
+type Anthropic struct {
+	apiKey  string
+	baseURL string
+}
+
+func NewAnthropic(apiKey string, baseURL string) *Anthropic {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Anthropic{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (a Anthropic) Type() llms.LLMType {
+	return llms.Anthropic
+}
+
+// Call sends a MessageRequest in json
+func (a *Anthropic) Call(data []byte) (llms.Response, error) {
+	req := &MessageRequest{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	return a.CreateMessage(context.TODO(), req)
+}
+
+// Validate sends a short message to check the api key and the model
+func (a *Anthropic) Validate(ctx context.Context, options ...langchainllms.CallOption) (llms.Response, error) {
+	opts := langchainllms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Model == "" {
+		opts.Model = llms.AnthropicModels[0]
+	}
+	return a.CreateMessage(ctx, &MessageRequest{
+		Model:     opts.Model,
+		Messages:  []Message{{Role: RoleUser, Content: "Hello"}},
+		MaxTokens: 16,
+	})
+}
+
+// CreateMessage sends the request and waits for the whole response
+func (a *Anthropic) CreateMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	req.Stream = false
+	resp, err := a.do(ctx, http.MethodPost, messagesPath, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	message := &MessageResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// CreateMessageStream sends the request with streaming enabled, the generated text is passed to fn piece by piece.
+// The returned response contains the whole text.
+func (a *Anthropic) CreateMessageStream(ctx context.Context, req *MessageRequest, fn func(text string) error) (*MessageResponse, error) {
+	req.Stream = true
+	resp, err := a.do(ctx, http.MethodPost, messagesPath, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	message := &MessageResponse{}
+	text := &strings.Builder{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		event := &StreamEvent{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), event); err != nil {
+			return nil, err
+		}
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				message = event.Message
+			}
+		case "content_block_delta":
+			if event.Delta == nil || event.Delta.Text == "" {
+				continue
+			}
+			text.WriteString(event.Delta.Text)
+			if err := fn(event.Delta.Text); err != nil {
+				return nil, err
+			}
+		case "message_delta":
+			if event.Delta != nil && event.Delta.StopReason != "" {
+				message.StopReason = event.Delta.StopReason
+			}
+			if event.Usage != nil {
+				message.Usage.OutputTokens = event.Usage.OutputTokens
+			}
+		case "error":
+			if event.Error != nil {
+				return nil, &APIError{Type: event.Error.Type, Message: event.Error.Message}
+			}
+			return nil, errors.New("unknown error in the stream")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	message.Content = []ContentBlock{{Type: "text", Text: text.String()}}
+	return message, nil
+}
+
+// ListModels lists models by the `/v1/models` api
+func (a *Anthropic) ListModels(ctx context.Context) ([]string, error) {
+	models := make([]string, 0)
+	afterID := ""
+	for {
+		query := url.Values{"limit": []string{"1000"}}
+		if afterID != "" {
+			query.Set("after_id", afterID)
+		}
+		resp, err := a.do(ctx, http.MethodGet, modelsPath+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		list := &ModelList{}
+		err = json.NewDecoder(resp.Body).Decode(list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range list.Data {
+			models = append(models, m.ID)
+		}
+		if !list.HasMore || list.LastID == "" {
+			return models, nil
+		}
+		afterID = list.LastID
+	}
+}
+
+func (a *Anthropic) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", a.apiKey)
+	req.Header.Set("Anthropic-Version", APIVersion)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		errResp := &ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(errResp); err == nil {
+			apiErr.Type = errResp.Error.Type
+			apiErr.Message = errResp.Error.Message
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
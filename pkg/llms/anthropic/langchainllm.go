@@ -0,0 +1,193 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anthropic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	langchainllm "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+var (
+	ErrEmptyResponse = errors.New("no response")
+	ErrEmptyPrompt   = errors.New("empty prompt")
+)
+
+var (
+	_ langchainllm.Model = (*AnthropicLLM)(nil)
+)
+
+type options struct {
+	model            string
+	baseURL          string
+	callbacksHandler callbacks.Handler
+}
+
+type Option func(*options)
+
+// WithModel sets the default model, which can be overwritten by `llms.WithModel` when calling
+func WithModel(model string) Option {
+	return func(o *options) {
+		if model != "" {
+			o.model = model
+		}
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+func WithCallback(callbacksHandler callbacks.Handler) Option {
+	return func(o *options) {
+		o.callbacksHandler = callbacksHandler
+	}
+}
+
+// AnthropicLLM is a langchaingo llm backed by the anthropic messages api
+type AnthropicLLM struct {
+	c       *Anthropic
+	options *options
+}
+
+func NewAnthropicLLM(apiKey string, opts ...Option) *AnthropicLLM {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.model == "" {
+		o.model = llms.AnthropicModels[0]
+	}
+	return &AnthropicLLM{
+		c:       NewAnthropic(apiKey, o.baseURL),
+		options: o,
+	}
+}
+
+func (a *AnthropicLLM) GetNumTokens(text string) int {
+	return langchainllm.CountTokens("gpt2", text)
+}
+
+func (a *AnthropicLLM) Call(ctx context.Context, prompt string, options ...langchainllm.CallOption) (string, error) {
+	return langchainllm.GenerateFromSinglePrompt(ctx, a, prompt, options...)
+}
+
+func (a *AnthropicLLM) GenerateContent(ctx context.Context, messages []langchainllm.MessageContent, options ...langchainllm.CallOption) (*langchainllm.ContentResponse, error) {
+	if a.options.callbacksHandler != nil {
+		a.options.callbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+	opts := langchainllm.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	req := &MessageRequest{
+		Model:         a.options.model,
+		MaxTokens:     DefaultMaxTokens,
+		StopSequences: opts.StopWords,
+		TopK:          opts.TopK,
+	}
+	if opts.Model != "" {
+		req.Model = opts.Model
+	}
+	if opts.MaxTokens > 0 {
+		req.MaxTokens = opts.MaxTokens
+	}
+	if opts.Temperature > 0 && opts.Temperature <= 1 {
+		req.Temperature = opts.Temperature
+	}
+	if opts.TopP > 0 && opts.TopP < 1 {
+		req.TopP = opts.TopP
+	}
+	systems := make([]string, 0)
+	for _, mc := range messages {
+		texts := make([]string, 0, len(mc.Parts))
+		for _, part := range mc.Parts {
+			if text, ok := part.(langchainllm.TextContent); ok {
+				texts = append(texts, text.Text)
+			}
+		}
+		content := strings.Join(texts, "\n")
+		var role Role
+		switch mc.Role {
+		case schema.ChatMessageTypeSystem:
+			systems = append(systems, content)
+			continue
+		case schema.ChatMessageTypeAI:
+			role = RoleAssistant
+		case schema.ChatMessageTypeHuman, schema.ChatMessageTypeGeneric:
+			role = RoleUser
+		case schema.ChatMessageTypeFunction:
+			fallthrough
+		default:
+			klog.Infof("unsupported role: %s, just skip", mc.Role)
+			continue
+		}
+		// the messages api requires user and assistant messages to be alternated, so merge the successive ones
+		if n := len(req.Messages); n > 0 && req.Messages[n-1].Role == role {
+			req.Messages[n-1].Content += "\n" + content
+			continue
+		}
+		req.Messages = append(req.Messages, Message{Role: role, Content: content})
+	}
+	req.System = strings.Join(systems, "\n")
+	if len(req.Messages) == 0 {
+		return nil, ErrEmptyPrompt
+	}
+
+	var resp *MessageResponse
+	var err error
+	if opts.StreamingFunc != nil {
+		resp, err = a.c.CreateMessageStream(ctx, req, func(text string) error {
+			return opts.StreamingFunc(ctx, []byte(text))
+		})
+	} else {
+		resp, err = a.c.CreateMessage(ctx, req)
+	}
+	if err == nil && resp.String() == "" {
+		err = ErrEmptyResponse
+	}
+	if err != nil {
+		if a.options.callbacksHandler != nil {
+			a.options.callbacksHandler.HandleLLMError(ctx, err)
+		}
+		return nil, err
+	}
+
+	response := &langchainllm.ContentResponse{Choices: []*langchainllm.ContentChoice{{
+		Content:    resp.String(),
+		StopReason: resp.StopReason,
+		GenerationInfo: map[string]any{
+			"InputTokens":  resp.Usage.InputTokens,
+			"OutputTokens": resp.Usage.OutputTokens,
+			"TotalTokens":  resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+	}}}
+	if a.options.callbacksHandler != nil {
+		a.options.callbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	}
+	return response, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	langchainllm "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// newFakeServer starts a fake anthropic server which replies `reply` word by word
+func newFakeServer(t *testing.T, reply string) (*httptest.Server, *MessageRequest) {
+	t.Helper()
+	received := &MessageRequest{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(messagesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "fake-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		words := strings.Fields(reply)
+		if !received.Stream {
+			_ = json.NewEncoder(w).Encode(MessageResponse{
+				Role:       RoleAssistant,
+				Model:      received.Model,
+				Content:    []ContentBlock{{Type: "text", Text: reply}},
+				StopReason: "end_turn",
+				Usage:      Usage{InputTokens: 10, OutputTokens: len(words)},
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"role\":\"assistant\",\"usage\":{\"input_tokens\":10,\"output_tokens\":1}}}\n\n")
+		for i, word := range words {
+			if i > 0 {
+				word = " " + word
+			}
+			data, _ := json.Marshal(word)
+			fmt.Fprintf(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":%s}}\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprintf(w, "event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":%d}}\n\n", len(words))
+		fmt.Fprintf(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	})
+	mux.HandleFunc(modelsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after_id") == "" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"claude-3-opus-20240229"}],"has_more":true,"last_id":"claude-3-opus-20240229"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"claude-3-haiku-20240307"}],"has_more":false,"last_id":"claude-3-haiku-20240307"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestGenerateContent(t *testing.T) {
+	server, received := newFakeServer(t, "Hello, I am Claude.")
+	llm := NewAnthropicLLM("fake-key", WithBaseURL(server.URL))
+	messages := []langchainllm.MessageContent{
+		langchainllm.TextParts(schema.ChatMessageTypeSystem, "You are a helpful assistant."),
+		langchainllm.TextParts(schema.ChatMessageTypeHuman, "Hi."),
+		langchainllm.TextParts(schema.ChatMessageTypeHuman, "Who are you?"),
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), messages, langchainllm.WithStopWords([]string{"Observation:"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Choices[0].Content; got != "Hello, I am Claude." {
+		t.Errorf("unexpected content: %q", got)
+	}
+	if got := resp.Choices[0].GenerationInfo["TotalTokens"]; got != 14 {
+		t.Errorf("unexpected total tokens: %v", got)
+	}
+	if received.System != "You are a helpful assistant." || received.MaxTokens != DefaultMaxTokens {
+		t.Errorf("unexpected request: %+v", received)
+	}
+	if len(received.Messages) != 1 || received.Messages[0].Content != "Hi.\nWho are you?" {
+		t.Errorf("successive user messages should be merged: %+v", received.Messages)
+	}
+	if len(received.StopSequences) != 1 || received.StopSequences[0] != "Observation:" {
+		t.Errorf("unexpected stop sequences: %v", received.StopSequences)
+	}
+}
+
+func TestGenerateContentStream(t *testing.T) {
+	server, _ := newFakeServer(t, "Hello, I am Claude.")
+	llm := NewAnthropicLLM("fake-key", WithBaseURL(server.URL))
+
+	chunks := make([]string, 0)
+	resp, err := llm.GenerateContent(context.Background(), []langchainllm.MessageContent{langchainllm.TextParts(schema.ChatMessageTypeHuman, "Who are you?")},
+		langchainllm.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Choices[0].Content; got != "Hello, I am Claude." {
+		t.Errorf("unexpected content: %q", got)
+	}
+	if resp.Choices[0].StopReason != "end_turn" || resp.Choices[0].GenerationInfo["TotalTokens"] != 14 {
+		t.Errorf("unexpected choice: %+v", resp.Choices[0])
+	}
+	if want := []string{"Hello,", " I", " am", " Claude."}; strings.Join(chunks, "|") != strings.Join(want, "|") {
+		t.Errorf("unexpected chunks: %q, want %q", chunks, want)
+	}
+}
+
+func TestGenerateContentError(t *testing.T) {
+	server, _ := newFakeServer(t, "")
+
+	_, err := NewAnthropicLLM("wrong-key", WithBaseURL(server.URL)).Call(context.Background(), "Hi")
+	if err == nil || !strings.Contains(err.Error(), "authentication_error") {
+		t.Errorf("expect an authentication error, got: %v", err)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	server, _ := newFakeServer(t, "")
+
+	models, err := NewAnthropic("fake-key", server.URL).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(models, ",") != "claude-3-opus-20240229,claude-3-haiku-20240307" {
+		t.Errorf("unexpected models: %v", models)
+	}
+}
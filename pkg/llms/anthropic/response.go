@@ -0,0 +1,152 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// MessageRequest is the request of the messages api
+// ref: https://docs.anthropic.com/claude/reference/messages_post
+type MessageRequest struct {
+	Model         string    `json:"model"`
+	System        string    `json:"system,omitempty"`
+	Messages      []Message `json:"messages"`
+	MaxTokens     int       `json:"max_tokens"`
+	Temperature   float64   `json:"temperature,omitempty"`
+	TopP          float64   `json:"top_p,omitempty"`
+	TopK          int       `json:"top_k,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+	Stream        bool      `json:"stream,omitempty"`
+}
+
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+}
+
+var _ llms.Response = (*MessageResponse)(nil)
+
+// MessageResponse is the response of the messages api
+type MessageResponse struct {
+	ID         string         `json:"id"`
+	ObjectType string         `json:"type"`
+	Role       Role           `json:"role"`
+	Model      string         `json:"model"`
+	Content    []ContentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      Usage          `json:"usage"`
+}
+
+type ContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (response *MessageResponse) Type() llms.LLMType {
+	return llms.Anthropic
+}
+
+func (response *MessageResponse) String() string {
+	texts := make([]string, 0, len(response.Content))
+	for _, c := range response.Content {
+		if c.Type == "text" {
+			texts = append(texts, c.Text)
+		}
+	}
+	return strings.Join(texts, "")
+}
+
+func (response *MessageResponse) Bytes() []byte {
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		return []byte{}
+	}
+	return bytes
+}
+
+func (response *MessageResponse) Unmarshal(bytes []byte) error {
+	return json.Unmarshal(bytes, response)
+}
+
+// StreamEvent is an event of the streaming response
+type StreamEvent struct {
+	Type    string           `json:"type"`
+	Message *MessageResponse `json:"message,omitempty"`
+	Delta   *struct {
+		Type       string `json:"type"`
+		Text       string `json:"text,omitempty"`
+		StopReason string `json:"stop_reason,omitempty"`
+	} `json:"delta,omitempty"`
+	Usage *Usage       `json:"usage,omitempty"`
+	Error *ErrorDetail `json:"error,omitempty"`
+}
+
+// ModelList is the response of the models api
+type ModelList struct {
+	Data []struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"data"`
+	HasMore bool   `json:"has_more"`
+	LastID  string `json:"last_id"`
+}
+
+type ErrorResponse struct {
+	Type  string      `json:"type"`
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// APIError is returned when anthropic responds with a non-200 http status code or an error event
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("anthropic: %s: %s", e.Type, e.Message)
+	}
+	return fmt.Sprintf("anthropic: %d %s, %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Type, e.Message)
+}
+
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultFailureThreshold = 3
+	DefaultCooldown         = time.Minute
+
+	// latencyDecay is the weight of the history in the moving average of latency
+	latencyDecay = 0.8
+)
+
+// breakers holds the states of all backends in this process, the llm is usually created per request,
+// so the states must outlive it.
+var breakers sync.Map
+
+// breaker is a circuit breaker of a backend, which also records the latency of the backend
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	open      bool
+	openUntil time.Time
+	latency   time.Duration
+}
+
+func getBreaker(key string) *breaker {
+	b, _ := breakers.LoadOrStore(key, &breaker{})
+	return b.(*breaker)
+}
+
+// allow returns false if the circuit is open,
+// after the cooldown the circuit becomes half-open and a request is allowed to probe the backend
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open || !now.Before(b.openUntil)
+}
+
+// succeed closes the circuit, returns true if the circuit was open
+func (b *breaker) succeed(latency time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	recovered := b.open
+	b.failures = 0
+	b.open = false
+	if b.latency == 0 {
+		b.latency = latency
+	} else {
+		b.latency = time.Duration(latencyDecay*float64(b.latency) + (1-latencyDecay)*float64(latency))
+	}
+	return recovered
+}
+
+// fail records a failure, returns true if the circuit is opened by this failure
+func (b *breaker) fail(now time.Time, threshold int, cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures < threshold {
+		return false
+	}
+	tripped := !b.open
+	b.open = true
+	b.openUntil = now.Add(cooldown)
+	return tripped
+}
+
+func (b *breaker) averageLatency() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.latency
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	langchainllm "github.com/tmc/langchaingo/llms"
+	"k8s.io/klog/v2"
+)
+
+// Strategy defines how to choose the backend for each request
+type Strategy string
+
+const (
+	// StrategyFailover tries the backends in order
+	StrategyFailover Strategy = "failover"
+	// StrategyWeighted chooses the backends randomly by their weights
+	StrategyWeighted Strategy = "weighted"
+	// StrategyLatency prefers the backend with the lowest average latency
+	StrategyLatency Strategy = "latency"
+)
+
+var (
+	ErrNoBackends = errors.New("no backends")
+
+	// statusCodePattern matches the status code in the error messages of llm clients, like
+	// `API returned unexpected status code: 429` of openai and `googleapi: Error 503` of gemini
+	statusCodePattern = regexp.MustCompile(`(?i)(?:status code:?|error) (\d{3})\b`)
+)
+
+var (
+	_ langchainllm.Model = (*CompositeLLM)(nil)
+)
+
+// Backend is a llm served behind the composite llm
+type Backend struct {
+	// Name identifies the backend
+	Name string
+	// Model is the llm of this backend
+	Model langchainllm.Model
+	// Weight is used by StrategyWeighted, defaults to 1
+	Weight int
+	// ModelMapping maps the model requested by callers to the model served by this backend
+	ModelMapping map[string]string
+}
+
+// StateChangeHandler is called when the circuit of a backend is opened or closed
+type StateChangeHandler func(backend string, healthy bool, err error)
+
+type options struct {
+	key              string
+	strategy         Strategy
+	maxRetries       int
+	retryInterval    time.Duration
+	failureThreshold int
+	cooldown         time.Duration
+	onStateChange    StateChangeHandler
+}
+
+type Option func(*options)
+
+// WithKey sets the key to share the circuit breakers among the composite llms created for the same resource
+func WithKey(key string) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+func WithStrategy(strategy Strategy) Option {
+	return func(o *options) {
+		if strategy != "" {
+			o.strategy = strategy
+		}
+	}
+}
+
+// WithRetry sets the times to retry a backend before failover to the next one
+func WithRetry(maxRetries int, interval time.Duration) Option {
+	return func(o *options) {
+		o.maxRetries = maxRetries
+		o.retryInterval = interval
+	}
+}
+
+// WithCircuitBreaker sets the consecutive failures to open the circuit of a backend,
+// and how long the circuit keeps open
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(o *options) {
+		if failureThreshold > 0 {
+			o.failureThreshold = failureThreshold
+		}
+		if cooldown > 0 {
+			o.cooldown = cooldown
+		}
+	}
+}
+
+func WithStateChangeHandler(handler StateChangeHandler) Option {
+	return func(o *options) {
+		o.onStateChange = handler
+	}
+}
+
+// CompositeLLM is a langchaingo llm which retries and fails over across multiple backends
+type CompositeLLM struct {
+	backends []Backend
+	options  *options
+}
+
+func NewCompositeLLM(backends []Backend, opts ...Option) (*CompositeLLM, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+	o := &options{
+		strategy:         StrategyFailover,
+		retryInterval:    time.Second,
+		failureThreshold: DefaultFailureThreshold,
+		cooldown:         DefaultCooldown,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &CompositeLLM{
+		backends: backends,
+		options:  o,
+	}, nil
+}
+
+func (c *CompositeLLM) Call(ctx context.Context, prompt string, options ...langchainllm.CallOption) (string, error) {
+	return langchainllm.GenerateFromSinglePrompt(ctx, c, prompt, options...)
+}
+
+func (c *CompositeLLM) GenerateContent(ctx context.Context, messages []langchainllm.MessageContent, options ...langchainllm.CallOption) (*langchainllm.ContentResponse, error) {
+	opts := langchainllm.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	errs := make([]error, 0)
+	for _, backend := range c.order() {
+		b := c.breaker(backend)
+		callOptions := append(options[:len(options):len(options)], langchainllm.WithModel(mapModel(backend, opts.Model)))
+		streamed := false
+		if opts.StreamingFunc != nil {
+			callOptions = append(callOptions, langchainllm.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+				streamed = true
+				return opts.StreamingFunc(ctx, chunk)
+			}))
+		}
+		for attempt := 0; attempt <= c.options.maxRetries; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(c.options.retryInterval * time.Duration(attempt)):
+				}
+			}
+			start := time.Now()
+			resp, err := backend.Model.GenerateContent(ctx, messages, callOptions...)
+			if err == nil {
+				if b.succeed(time.Since(start)) {
+					klog.Infof("backend %s of composite llm %s recovered", backend.Name, c.options.key)
+					c.notify(backend.Name, true, nil)
+				}
+				return resp, nil
+			}
+			// the chunks already sent can't be taken back, so no more retries
+			if streamed || ctx.Err() != nil || !IsRetryable(err) {
+				return nil, err
+			}
+			klog.Warningf("backend %s of composite llm %s failed: %s", backend.Name, c.options.key, err)
+			errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+			if b.fail(time.Now(), c.options.failureThreshold, c.options.cooldown) {
+				klog.Warningf("circuit of backend %s of composite llm %s is opened", backend.Name, c.options.key)
+				c.notify(backend.Name, false, err)
+				break
+			}
+		}
+	}
+	return nil, fmt.Errorf("all backends failed: %w", errors.Join(errs...))
+}
+
+// order returns the backends in the order to try,
+// the backends with open circuits are skipped unless all circuits are open
+func (c *CompositeLLM) order() []Backend {
+	now := time.Now()
+	available := make([]Backend, 0, len(c.backends))
+	for _, backend := range c.backends {
+		if c.breaker(backend).allow(now) {
+			available = append(available, backend)
+		}
+	}
+	if len(available) == 0 {
+		available = append(available, c.backends...)
+	}
+
+	switch c.options.strategy {
+	case StrategyWeighted:
+		// weighted random sampling without replacement
+		ordered := make([]Backend, 0, len(available))
+		for len(available) > 0 {
+			total := 0
+			for _, backend := range available {
+				total += weight(backend)
+			}
+			n := rand.Intn(total)
+			i := 0
+			for ; i < len(available)-1; i++ {
+				n -= weight(available[i])
+				if n < 0 {
+					break
+				}
+			}
+			ordered = append(ordered, available[i])
+			available = append(available[:i], available[i+1:]...)
+		}
+		return ordered
+	case StrategyLatency:
+		// the backends not called yet have zero latency, so they will be tried first
+		latencies := make(map[string]time.Duration, len(available))
+		for _, backend := range available {
+			latencies[backend.Name] = c.breaker(backend).averageLatency()
+		}
+		sort.SliceStable(available, func(i, j int) bool {
+			return latencies[available[i].Name] < latencies[available[j].Name]
+		})
+	}
+	return available
+}
+
+func (c *CompositeLLM) breaker(backend Backend) *breaker {
+	return getBreaker(c.options.key + "/" + backend.Name)
+}
+
+// notify calls the handler asynchronously to avoid slowing down the request
+func (c *CompositeLLM) notify(backend string, healthy bool, err error) {
+	if c.options.onStateChange != nil {
+		go c.options.onStateChange(backend, healthy, err)
+	}
+}
+
+func weight(backend Backend) int {
+	if backend.Weight <= 0 {
+		return 1
+	}
+	return backend.Weight
+}
+
+// mapModel returns the model of the backend for the requested model.
+// The requested model is passed through if the backend has no mapping,
+// otherwise the unmapped models are replaced by the default model of the backend.
+func mapModel(backend Backend, model string) string {
+	if model == "" || len(backend.ModelMapping) == 0 {
+		return model
+	}
+	return backend.ModelMapping[model]
+}
+
+// statusCoder is implemented by the api errors which carry the http status code
+type statusCoder interface {
+	HTTPStatusCode() int
+}
+
+// IsRetryable returns true if the error is caused by rate limiting, server errors or network errors
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var coder statusCoder
+	if errors.As(err, &coder) {
+		return isRetryableStatusCode(coder.HTTPStatusCode())
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if matches := statusCodePattern.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		code, _ := strconv.Atoi(matches[1])
+		return isRetryableStatusCode(code)
+	}
+	return false
+}
+
+func isRetryableStatusCode(code int) bool {
+	return code == 429 || code >= 500
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	langchainllm "github.com/tmc/langchaingo/llms"
+)
+
+// fakeLLM replies its name, or fails with err
+type fakeLLM struct {
+	name  string
+	err   error
+	calls int
+	model string
+}
+
+func (f *fakeLLM) Call(ctx context.Context, prompt string, options ...langchainllm.CallOption) (string, error) {
+	return langchainllm.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func (f *fakeLLM) GenerateContent(ctx context.Context, messages []langchainllm.MessageContent, options ...langchainllm.CallOption) (*langchainllm.ContentResponse, error) {
+	f.calls++
+	opts := langchainllm.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	f.model = opts.Model
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &langchainllm.ContentResponse{Choices: []*langchainllm.ContentChoice{{Content: f.name}}}, nil
+}
+
+func TestFailover(t *testing.T) {
+	primary := &fakeLLM{name: "primary", err: errors.New("API returned unexpected status code: 429")}
+	secondary := &fakeLLM{name: "secondary"}
+	changes := make(chan bool, 1)
+	llm, err := NewCompositeLLM([]Backend{
+		{Name: "primary", Model: primary},
+		{Name: "secondary", Model: secondary, ModelMapping: map[string]string{"gpt-4": "qwen-max"}},
+	}, WithKey(t.Name()), WithRetry(1, time.Millisecond), WithCircuitBreaker(2, time.Hour),
+		WithStateChangeHandler(func(backend string, healthy bool, err error) {
+			if backend == "primary" {
+				changes <- healthy
+			}
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := llm.Call(context.Background(), "Hi", langchainllm.WithModel("gpt-4"))
+	if err != nil || resp != "secondary" {
+		t.Fatalf("expect failover to secondary, got %q, %v", resp, err)
+	}
+	if primary.calls != 2 {
+		t.Errorf("expect primary called twice with a retry, got %d", primary.calls)
+	}
+	if primary.model != "gpt-4" || secondary.model != "qwen-max" {
+		t.Errorf("unexpected models: %s, %s", primary.model, secondary.model)
+	}
+	select {
+	case healthy := <-changes:
+		if healthy {
+			t.Errorf("expect the circuit of primary is opened")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expect the circuit of primary is opened")
+	}
+
+	// primary is skipped as its circuit is open
+	if _, err := llm.Call(context.Background(), "Hi"); err != nil {
+		t.Fatal(err)
+	}
+	if primary.calls != 2 {
+		t.Errorf("expect primary skipped, but called %d times", primary.calls)
+	}
+}
+
+func TestNoFailoverOnBadRequest(t *testing.T) {
+	primary := &fakeLLM{name: "primary", err: errors.New("API returned unexpected status code: 400")}
+	secondary := &fakeLLM{name: "secondary"}
+	llm, _ := NewCompositeLLM([]Backend{{Name: "primary", Model: primary}, {Name: "secondary", Model: secondary}}, WithKey(t.Name()))
+
+	if _, err := llm.Call(context.Background(), "Hi"); err == nil {
+		t.Fatal("expect the error of primary")
+	}
+	if secondary.calls != 0 {
+		t.Errorf("expect no failover for bad requests")
+	}
+}
+
+func TestAllBackendsFailed(t *testing.T) {
+	llm, _ := NewCompositeLLM([]Backend{
+		{Name: "a", Model: &fakeLLM{err: errors.New("googleapi: Error 503: unavailable")}},
+		{Name: "b", Model: &fakeLLM{err: errors.New("API returned unexpected status code: 502")}},
+	}, WithKey(t.Name()))
+
+	if _, err := llm.Call(context.Background(), "Hi"); err == nil {
+		t.Fatal("expect an error when all backends failed")
+	}
+}
+
+func TestLatencyStrategy(t *testing.T) {
+	llm, _ := NewCompositeLLM([]Backend{{Name: "slow", Model: &fakeLLM{}}, {Name: "fast", Model: &fakeLLM{}}},
+		WithKey(t.Name()), WithStrategy(StrategyLatency))
+	getBreaker(t.Name() + "/slow").succeed(time.Second)
+	getBreaker(t.Name() + "/fast").succeed(time.Millisecond)
+
+	if order := llm.order(); order[0].Name != "fast" {
+		t.Errorf("expect the fast backend first, got %s", order[0].Name)
+	}
+}
+
+func TestWeightedStrategy(t *testing.T) {
+	llm, _ := NewCompositeLLM([]Backend{{Name: "a", Weight: 1}, {Name: "b", Weight: 9}},
+		WithKey(t.Name()), WithStrategy(StrategyWeighted))
+	firsts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		order := llm.order()
+		if len(order) != 2 {
+			t.Fatalf("expect all backends, got %d", len(order))
+		}
+		firsts[order[0].Name]++
+	}
+	if firsts["b"] < firsts["a"] {
+		t.Errorf("expect the backend with more weight chosen more, got %v", firsts)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	langchainllms "github.com/tmc/langchaingo/llms"
 
@@ -28,14 +29,24 @@ import (
 )
 
 const (
-	DashScopeChatURL          = "https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation"
-	DashScopeTextEmbeddingURL = "https://dashscope.aliyuncs.com/api/v1/services/embeddings/text-embedding/text-embedding"
-	DashScopeTaskURL          = "https://dashscope.aliyuncs.com/api/v1/tasks/"
+	DashScopeBaseURL = "https://dashscope.aliyuncs.com/api/v1"
+
+	chatPath          = "/services/aigc/text-generation/generation"
+	textEmbeddingPath = "/services/embeddings/text-embedding/text-embedding"
+	taskPath          = "/tasks/"
+
+	DashScopeChatURL          = DashScopeBaseURL + chatPath
+	DashScopeTextEmbeddingURL = DashScopeBaseURL + textEmbeddingPath
+	DashScopeTaskURL          = DashScopeBaseURL + taskPath
 )
 
 type Model string
 
 const (
+	// 通义千问商业版模型
+	QWENTurbo Model = "qwen-turbo"
+	QWENPlus  Model = "qwen-plus"
+	QWENMax   Model = "qwen-max"
 	// 通义千问对外开源的 14B / 7B 规模参数量的经过人类指令对齐的 chat 模型
 	QWEN14BChat Model = "qwen-14b-chat"
 	QWEN7BChat  Model = "qwen-7b-chat"
@@ -51,17 +62,45 @@ const (
 var _ llms.LLM = (*DashScope)(nil)
 
 type DashScope struct {
-	apiKey string
-	sse    bool
+	apiKey         string
+	sse            bool
+	baseURL        string
+	embeddingModel Model
+}
+
+type Option func(*DashScope)
+
+// WithBaseURL sets the base url of dashscope apis, defaults to DashScopeBaseURL
+func WithBaseURL(baseURL string) Option {
+	return func(z *DashScope) {
+		if baseURL != "" {
+			z.baseURL = strings.TrimSuffix(baseURL, "/")
+		}
+	}
 }
 
-func NewDashScope(apiKey string, sse bool) *DashScope {
-	return &DashScope{
-		apiKey: apiKey,
-		sse:    sse,
+// WithEmbeddingModel sets the model used to create embeddings, defaults to EmbeddingV1
+func WithEmbeddingModel(model Model) Option {
+	return func(z *DashScope) {
+		if model != "" {
+			z.embeddingModel = model
+		}
 	}
 }
 
+func NewDashScope(apiKey string, sse bool, opts ...Option) *DashScope {
+	z := &DashScope{
+		apiKey:         apiKey,
+		sse:            sse,
+		baseURL:        DashScopeBaseURL,
+		embeddingModel: EmbeddingV1,
+	}
+	for _, opt := range opts {
+		opt(z)
+	}
+	return z
+}
+
 func (z DashScope) Type() llms.LLMType {
 	return llms.DashScope
 }
@@ -72,11 +111,22 @@ func (z *DashScope) Call(data []byte) (llms.Response, error) {
 	if err := params.Unmarshal(data); err != nil {
 		return nil, err
 	}
-	return do(context.TODO(), DashScopeChatURL, z.apiKey, data, z.sse, false, params.Model)
+	return do(context.TODO(), z.baseURL+chatPath, z.apiKey, data, z.sse, false, params.Model)
 }
 
+// Validate sends a simple chat request to check the api key and the model
 func (z *DashScope) Validate(ctx context.Context, options ...langchainllms.CallOption) (llms.Response, error) {
-	return nil, errors.New("not implemented")
+	opts := langchainllms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	params := DefaultModelParams()
+	params.Model = QWENTurbo
+	if opts.Model != "" {
+		params.Model = Model(opts.Model)
+	}
+	params.Input.Messages = []Message{{Role: User, Content: "Hello"}}
+	return do(ctx, z.baseURL+chatPath, z.apiKey, params.Marshal(), false, false, params.Model)
 }
 
 func (z *DashScope) CreateEmbedding(ctx context.Context, inputTexts []string, query bool) ([]Embeddings, error) {
@@ -85,7 +135,7 @@ func (z *DashScope) CreateEmbedding(ctx context.Context, inputTexts []string, qu
 		textType = TextTypeQuery
 	}
 	reqBody := EmbeddingRequest{
-		Model: EmbeddingV1,
+		Model: z.embeddingModel,
 		Input: EmbeddingInput{
 			EmbeddingInputSync: &EmbeddingInputSync{
 				Texts: inputTexts,
@@ -99,7 +149,7 @@ func (z *DashScope) CreateEmbedding(ctx context.Context, inputTexts []string, qu
 	if err != nil {
 		return nil, err
 	}
-	resp, err := req(ctx, DashScopeTextEmbeddingURL, z.apiKey, data, false, false)
+	resp, err := req(ctx, z.baseURL+textEmbeddingPath, z.apiKey, data, false, false)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +184,7 @@ func (z *DashScope) CreateEmbeddingAsync(ctx context.Context, inputURL string, q
 	if err != nil {
 		return "", err
 	}
-	resp, err := req(ctx, DashScopeTextEmbeddingURL, z.apiKey, data, false, true)
+	resp, err := req(ctx, z.baseURL+textEmbeddingPath, z.apiKey, data, false, true)
 	if err != nil {
 		return "", err
 	}
@@ -150,7 +200,7 @@ func (z *DashScope) CreateEmbeddingAsync(ctx context.Context, inputURL string, q
 }
 
 func (z *DashScope) GetTaskDetail(ctx context.Context, taskID string) (outURL string, err error) {
-	resp, err := req(ctx, DashScopeTaskURL+taskID, z.apiKey, nil, false, false)
+	resp, err := req(ctx, z.baseURL+taskPath+taskID, z.apiKey, nil, false, false)
 	if err != nil {
 		return "", err
 	}
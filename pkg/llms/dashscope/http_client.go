@@ -60,8 +60,17 @@ func req(ctx context.Context, apiURL, token string, data []byte, sse, async bool
 	}
 
 	setHeaders(req, token, sse, async)
-
-	return http.DefaultClient.Do(req)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr.CommonResponse)
+		return nil, apiErr
+	}
+	return resp, nil
 }
 func do(ctx context.Context, apiURL, token string, data []byte, sse, async bool, model Model) (llms.Response, error) {
 	resp, err := req(ctx, apiURL, token, data, sse, async)
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/r3labs/sse/v2"
+	"github.com/tmc/langchaingo/callbacks"
+	langchainllm "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"k8s.io/klog/v2"
+)
+
+var (
+	ErrEmptyResponse = errors.New("no response")
+	ErrEmptyPrompt   = errors.New("empty prompt")
+)
+
+var (
+	_ langchainllm.Model = (*DashScopeLLM)(nil)
+)
+
+type llmOptions struct {
+	model            Model
+	clientOptions    []Option
+	callbacksHandler callbacks.Handler
+}
+
+type LLMOption func(*llmOptions)
+
+// WithModel sets the default model, which can be overwritten by `llms.WithModel` when calling
+func WithModel(model string) LLMOption {
+	return func(o *llmOptions) {
+		if model != "" {
+			o.model = Model(model)
+		}
+	}
+}
+
+// WithClientOptions sets the options of the underlying dashscope client
+func WithClientOptions(opts ...Option) LLMOption {
+	return func(o *llmOptions) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+func WithCallback(callbacksHandler callbacks.Handler) LLMOption {
+	return func(o *llmOptions) {
+		o.callbacksHandler = callbacksHandler
+	}
+}
+
+// DashScopeLLM is a langchaingo llm backed by the dashscope text generation api
+type DashScopeLLM struct {
+	c       *DashScope
+	options *llmOptions
+}
+
+func NewDashScopeLLM(apiKey string, opts ...LLMOption) *DashScopeLLM {
+	o := &llmOptions{
+		model: QWENTurbo,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &DashScopeLLM{
+		c:       NewDashScope(apiKey, false, o.clientOptions...),
+		options: o,
+	}
+}
+
+func (d *DashScopeLLM) GetNumTokens(text string) int {
+	return langchainllm.CountTokens("gpt2", text)
+}
+
+func (d *DashScopeLLM) Call(ctx context.Context, prompt string, options ...langchainllm.CallOption) (string, error) {
+	return langchainllm.GenerateFromSinglePrompt(ctx, d, prompt, options...)
+}
+
+func (d *DashScopeLLM) GenerateContent(ctx context.Context, messages []langchainllm.MessageContent, options ...langchainllm.CallOption) (*langchainllm.ContentResponse, error) {
+	if d.options.callbacksHandler != nil {
+		d.options.callbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+	opts := langchainllm.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	params := DefaultModelParams()
+	params.Model = d.options.model
+	if opts.Model != "" {
+		params.Model = Model(opts.Model)
+	}
+	if opts.TopP > 0 && opts.TopP < 1 {
+		params.Parameters.TopP = float32(opts.TopP)
+	}
+	if opts.Temperature > 0 {
+		params.Parameters.Temperature = float32(opts.Temperature)
+	}
+	if opts.TopK > 0 {
+		params.Parameters.TopK = opts.TopK
+	}
+	if opts.Seed != 0 {
+		params.Parameters.Seed = opts.Seed
+	}
+	params.Parameters.MaxTokens = opts.MaxTokens
+	params.Parameters.Stop = opts.StopWords
+	for _, mc := range messages {
+		msg := Message{}
+		switch mc.Role {
+		case schema.ChatMessageTypeSystem:
+			msg.Role = System
+		case schema.ChatMessageTypeAI:
+			msg.Role = Assistant
+		case schema.ChatMessageTypeHuman, schema.ChatMessageTypeGeneric:
+			msg.Role = User
+		case schema.ChatMessageTypeFunction:
+			fallthrough
+		default:
+			klog.Infof("unsupported role: %s, just skip", mc.Role)
+			continue
+		}
+		texts := make([]string, 0, len(mc.Parts))
+		for _, part := range mc.Parts {
+			if text, ok := part.(langchainllm.TextContent); ok {
+				texts = append(texts, text.Text)
+			}
+		}
+		msg.Content = strings.Join(texts, "\n")
+		params.Input.Messages = append(params.Input.Messages, msg)
+	}
+	if len(params.Input.Messages) == 0 {
+		return nil, ErrEmptyPrompt
+	}
+
+	var resp *Response
+	var err error
+	if opts.StreamingFunc != nil {
+		resp, err = d.stream(ctx, params, opts.StreamingFunc)
+	} else {
+		resp, err = d.invoke(ctx, params)
+	}
+	if err != nil {
+		if d.options.callbacksHandler != nil {
+			d.options.callbacksHandler.HandleLLMError(ctx, err)
+		}
+		return nil, err
+	}
+
+	generationInfo := map[string]any{
+		"InputTokens":  resp.Usage.InputTokens,
+		"OutputTokens": resp.Usage.OutputTokens,
+		"TotalTokens":  resp.Usage.InputTokens + resp.Usage.OutputTokens,
+	}
+	choice := &langchainllm.ContentChoice{
+		Content:        resp.String(),
+		GenerationInfo: generationInfo,
+	}
+	if len(resp.Output.Choices) > 0 {
+		choice.StopReason = string(resp.Output.Choices[len(resp.Output.Choices)-1].FinishReason)
+	}
+	response := &langchainllm.ContentResponse{Choices: []*langchainllm.ContentChoice{choice}}
+	if d.options.callbacksHandler != nil {
+		d.options.callbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	}
+	return response, nil
+}
+
+func (d *DashScopeLLM) invoke(ctx context.Context, params ModelParams) (*Response, error) {
+	resp, err := req(ctx, d.c.baseURL+chatPath, d.c.apiKey, params.Marshal(), false, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data := &Response{}
+	if err := json.NewDecoder(resp.Body).Decode(data); err != nil {
+		return nil, err
+	}
+	if data.Code != "" {
+		return nil, &APIError{CommonResponse: data.CommonResponse}
+	}
+	if data.String() == "" {
+		return nil, ErrEmptyResponse
+	}
+	return data, nil
+}
+
+// stream calls the api with server-sent events, the newly generated content is passed to streamingFunc
+func (d *DashScopeLLM) stream(ctx context.Context, params ModelParams, streamingFunc func(ctx context.Context, chunk []byte) error) (*Response, error) {
+	resp, err := req(ctx, d.c.baseURL+chatPath, d.c.apiKey, params.Marshal(), true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	last := &Response{}
+	content := ""
+	err = NewSSEClient().Read(resp, func(event *sse.Event) error {
+		switch string(event.Event) {
+		case "error":
+			apiErr := &APIError{}
+			if err := json.Unmarshal(event.Data, &apiErr.CommonResponse); err != nil {
+				return errors.New(string(event.Data))
+			}
+			return apiErr
+		case "result":
+			data := &Response{}
+			if err := json.Unmarshal(event.Data, data); err != nil {
+				return err
+			}
+			last = data
+			// the content of each event is the whole content generated so far,
+			// unless incremental output is enabled
+			text, delta := data.String(), data.String()
+			if strings.HasPrefix(text, content) {
+				delta = strings.TrimPrefix(text, content)
+				content = text
+			} else {
+				content += text
+			}
+			if delta == "" {
+				return nil
+			}
+			return streamingFunc(ctx, []byte(delta))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, ErrEmptyResponse
+	}
+	var finishReason FinishReason
+	if len(last.Output.Choices) > 0 {
+		finishReason = last.Output.Choices[len(last.Output.Choices)-1].FinishReason
+	}
+	last.Output.Text = ""
+	last.Output.Choices = []Choice{{FinishReason: finishReason, Message: Message{Role: Assistant, Content: content}}}
+	return last, nil
+}
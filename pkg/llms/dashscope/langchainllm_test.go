@@ -0,0 +1,170 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	langchainllm "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// newFakeServer starts a fake dashscope server which replies `reply` word by word
+func newFakeServer(t *testing.T, reply string) (*httptest.Server, *ModelParams) {
+	t.Helper()
+	received := &ModelParams{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(chatPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fake-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"InvalidApiKey","message":"Invalid API-key provided."}`))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if received.Model == "throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":"Throttling","message":"Requests rate limit exceeded."}`))
+			return
+		}
+		usage := Usage{InputTokens: 10, OutputTokens: len(strings.Fields(reply))}
+		if r.Header.Get("X-DashScope-SSE") != "enable" {
+			resp := Response{
+				Output: Output{Choices: []Choice{{FinishReason: Finish, Message: Message{Role: Assistant, Content: reply}}}},
+				Usage:  usage,
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		content := ""
+		words := strings.Fields(reply)
+		for i, word := range words {
+			if i > 0 {
+				content += " "
+			}
+			content += word
+			reason := Generating
+			if i == len(words)-1 {
+				reason = Finish
+			}
+			data, _ := json.Marshal(Response{
+				Output: Output{Choices: []Choice{{FinishReason: reason, Message: Message{Role: Assistant, Content: content}}}},
+				Usage:  usage,
+			})
+			fmt.Fprintf(w, "id:%d\nevent:result\n:HTTP_STATUS/200\ndata:%s\n\n", i+1, data)
+			w.(http.Flusher).Flush()
+		}
+	})
+	mux.HandleFunc(textEmbeddingPath, func(w http.ResponseWriter, r *http.Request) {
+		request := EmbeddingRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		resp := EmbeddingResponse{Output: EmbeddingOutput{EmbeddingOutputSync: &EmbeddingOutputSync{}}}
+		for i, text := range request.Input.Texts {
+			resp.Output.Embeddings = append(resp.Output.Embeddings, Embeddings{TextIndex: i, Embedding: []float32{float32(len(text)), 1}})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestGenerateContent(t *testing.T) {
+	server, received := newFakeServer(t, "Hello, I am Qwen.")
+	llm := NewDashScopeLLM("fake-key", WithModel(string(QWENMax)), WithClientOptions(WithBaseURL(server.URL)))
+	messages := []langchainllm.MessageContent{
+		langchainllm.TextParts(schema.ChatMessageTypeSystem, "You are a helpful assistant."),
+		langchainllm.TextParts(schema.ChatMessageTypeHuman, "Who are you?"),
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), messages, langchainllm.WithStopWords([]string{"Observation:"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Choices[0].Content; got != "Hello, I am Qwen." {
+		t.Errorf("unexpected content: %q", got)
+	}
+	if got := resp.Choices[0].GenerationInfo["TotalTokens"]; got != 14 {
+		t.Errorf("unexpected total tokens: %v", got)
+	}
+	if received.Model != QWENMax {
+		t.Errorf("unexpected model: %s", received.Model)
+	}
+	if len(received.Input.Messages) != 2 || received.Input.Messages[0].Role != System || received.Input.Messages[1].Role != User {
+		t.Errorf("unexpected messages: %+v", received.Input.Messages)
+	}
+	if len(received.Parameters.Stop) != 1 || received.Parameters.Stop[0] != "Observation:" {
+		t.Errorf("unexpected stop words: %v", received.Parameters.Stop)
+	}
+}
+
+func TestGenerateContentStream(t *testing.T) {
+	server, _ := newFakeServer(t, "Hello, I am Qwen.")
+	llm := NewDashScopeLLM("fake-key", WithClientOptions(WithBaseURL(server.URL)))
+
+	chunks := make([]string, 0)
+	resp, err := llm.Call(context.Background(), "Who are you?", langchainllm.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp != "Hello, I am Qwen." {
+		t.Errorf("unexpected content: %q", resp)
+	}
+	if want := []string{"Hello,", " I", " am", " Qwen."}; strings.Join(chunks, "|") != strings.Join(want, "|") {
+		t.Errorf("unexpected chunks: %q, want %q", chunks, want)
+	}
+}
+
+func TestGenerateContentError(t *testing.T) {
+	server, _ := newFakeServer(t, "")
+
+	_, err := NewDashScopeLLM("wrong-key", WithClientOptions(WithBaseURL(server.URL))).Call(context.Background(), "Hi")
+	if err == nil || !strings.Contains(err.Error(), "InvalidApiKey") {
+		t.Errorf("expect an invalid api key error, got: %v", err)
+	}
+
+	_, err = NewDashScopeLLM("fake-key", WithModel("throttled"), WithClientOptions(WithBaseURL(server.URL))).Call(context.Background(), "Hi")
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("expect a rate limited error, got: %v", err)
+	}
+}
+
+func TestCreateEmbedding(t *testing.T) {
+	server, _ := newFakeServer(t, "")
+	client := NewDashScope("fake-key", false, WithBaseURL(server.URL))
+
+	embeddings, err := client.CreateEmbedding(context.Background(), []string{"a", "bb"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(embeddings) != 2 || embeddings[1].Embedding[0] != 2 {
+		t.Errorf("unexpected embeddings: %+v", embeddings)
+	}
+}
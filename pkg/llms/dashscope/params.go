@@ -53,11 +53,18 @@ type Input struct {
 	History  *[]string `json:"history,omitempty"`
 }
 
+// +kubebuilder:object:generate=true
 type Parameters struct {
 	TopP         float32 `json:"top_p,omitempty"`
 	TopK         int     `json:"top_k,omitempty"`
 	Seed         int     `json:"seed,omitempty"`
 	ResultFormat string  `json:"result_format,omitempty"`
+	Temperature  float32 `json:"temperature,omitempty"`
+	MaxTokens    int     `json:"max_tokens,omitempty"`
+	// Stop stops the generation when one of these words is about to be generated
+	Stop []string `json:"stop,omitempty"`
+	// IncrementalOutput makes each stream event only contain the newly generated content
+	IncrementalOutput bool `json:"incremental_output,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -19,6 +19,8 @@ package dashscope
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"net/http"
 
 	"github.com/kubeagi/arcadia/pkg/llms"
 )
@@ -33,6 +35,24 @@ type CommonResponse struct {
 	Message    string `json:"message,omitempty"`
 	RequestID  string `json:"request_id"`
 }
+
+// APIError is returned when dashscope responds with a non-200 http status code or an error event
+type APIError struct {
+	CommonResponse
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("dashscope: code: %s, message: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("dashscope: %d %s, code: %s, message: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Code, e.Message)
+}
+
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
 type Response struct {
 	CommonResponse
 	Output Output `json:"output"`
@@ -49,7 +49,7 @@ func defaultHandler(event *sse.Event, last string) (newData string) {
 	return ""
 }
 func (z *DashScope) StreamCall(ctx context.Context, data []byte, handler func(event *sse.Event, last string) (data string)) error {
-	resp, err := req(ctx, DashScopeChatURL, z.apiKey, data, true, false)
+	resp, err := req(ctx, z.baseURL+chatPath, z.apiKey, data, true, false)
 	if err != nil {
 		return err
 	}
@@ -98,6 +98,27 @@ func (c *SSEClient) Events(resp *http.Response) (<-chan *sse.Event, <-chan error
 	return c.startReadLoop(reader)
 }
 
+// Read reads the events one by one until the stream ends or the handler returns an error
+func (c *SSEClient) Read(resp *http.Response, handler func(event *sse.Event) error) error {
+	reader := sse.NewEventStreamReader(resp.Body, c.maxBufferSize)
+	for {
+		event, err := reader.ReadEvent()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		msg, err := c.processEvent(event)
+		if err != nil || !hasContent(msg) {
+			continue
+		}
+		if err := handler(msg); err != nil {
+			return err
+		}
+	}
+}
+
 func (c *SSEClient) startReadLoop(reader *sse.EventStreamReader) (chan *sse.Event, chan error) {
 	outCh := make(chan *sse.Event)
 	erChan := make(chan error)
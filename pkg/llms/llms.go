@@ -30,12 +30,22 @@ const (
 	ZhiPuAI   LLMType = "zhipuai"
 	DashScope LLMType = "dashscope"
 	Gemini    LLMType = "gemini"
+	// Ollama serves local models with the ollama api
+	Ollama LLMType = "ollama"
+	// Anthropic serves models with the anthropic messages api
+	Anthropic LLMType = "anthropic"
+	// OpenAICompatible is a service which provides openai compatible apis, like vLLM and LiteLLM gateways
+	OpenAICompatible LLMType = "openai-compatible"
+	// Composite routes requests to multiple llms with failover and load balancing
+	Composite LLMType = "composite"
 	Unknown   LLMType = "unknown"
 )
 
 var (
-	OpenAIModels = []string{"gpt-3.5", "gpt-3.5-turbo"}
-	GeminiModels = []string{"gemini-pro"}
+	OpenAIModels    = []string{"gpt-3.5", "gpt-3.5-turbo"}
+	GeminiModels    = []string{"gemini-pro"}
+	DashScopeModels = []string{"qwen-turbo", "qwen-plus", "qwen-max"}
+	AnthropicModels = []string{"claude-3-haiku-20240307", "claude-3-sonnet-20240229", "claude-3-opus-20240229"}
 )
 
 var (
@@ -47,6 +57,8 @@ var (
 	ZhiPuAIGLM3Turbo string = "glm-3-turbo"
 	// ChatGLM4
 	ZhiPuAIGLM4 string = "glm-4"
+	// ChatGLM4 with vision, only available in the v4 api
+	ZhiPuAIGLM4V string = "glm-4v"
 	// Character LLM
 	ZhiPuAICharGLM3 string = "charglm-3"
 )
@@ -58,6 +70,11 @@ type LLM interface {
 	Validate(context.Context, ...langchainllms.CallOption) (Response, error)
 }
 
+// ModelLister lists the models served by a llm service
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
 type ModelParams interface {
 	Marshal() []byte
 	Unmarshal([]byte) error
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+const (
+	DefaultBaseURL = "http://localhost:11434"
+)
+
+var _ llms.ModelLister = (*Ollama)(nil)
+
+// Ollama is a client of the ollama api
+type Ollama struct {
+	baseURL string
+}
+
+func NewOllama(baseURL string) *Ollama {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Ollama{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (o Ollama) Type() llms.LLMType {
+	return llms.Ollama
+}
+
+// TagsResponse is the response of `/api/tags`
+type TagsResponse struct {
+	Models []struct {
+		Name  string `json:"name"`
+		Model string `json:"model"`
+		Size  int64  `json:"size"`
+	} `json:"models"`
+}
+
+// ListModels lists local models by `/api/tags`
+func (o *Ollama) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to list models: %s %s", resp.Status, body)
+	}
+	tags := &TagsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(tags); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		models = append(models, m.Name)
+	}
+	return models, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ollama
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/tags" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"models":[{"name":"llama2:latest","model":"llama2:latest","size":3826793677},{"name":"qwen:7b","model":"qwen:7b","size":4511914544}]}`))
+	}))
+	defer server.Close()
+
+	// the trailing slash of the base url is trimmed
+	models, err := NewOllama(server.URL + "/").ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"llama2:latest", "qwen:7b"}
+	if len(models) != len(expected) {
+		t.Fatalf("expected models %v, but got %v", expected, models)
+	}
+	for i := range expected {
+		if models[i] != expected[i] {
+			t.Errorf("expected models %v, but got %v", expected, models)
+		}
+	}
+}
+
+func TestListModelsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("ollama is not running"))
+	}))
+	defer server.Close()
+
+	if _, err := NewOllama(server.URL).ListModels(context.Background()); err == nil {
+		t.Error("expected an error when the server fails, but got nil")
+	}
+}
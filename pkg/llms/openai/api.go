@@ -18,8 +18,12 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	langchainllms "github.com/tmc/langchaingo/llms"
@@ -31,13 +35,20 @@ import (
 const (
 	OpenaiModelAPIURL    = "https://api.openai.com/v1"
 	OpenaiDefaultTimeout = 300 * time.Second
+
+	// EmptyAPIKey is used when the service doesn't require an api key, because the client requires one
+	EmptyAPIKey = "EMPTY"
 )
 
-var _ llms.LLM = (*OpenAI)(nil)
+var (
+	_ llms.LLM         = (*OpenAI)(nil)
+	_ llms.ModelLister = (*OpenAI)(nil)
+)
 
 type OpenAI struct {
 	apiKey  string
 	baseURL string
+	llmType llms.LLMType
 }
 
 func NewOpenAI(apiKey string, baseURL string) (*OpenAI, error) {
@@ -53,11 +64,25 @@ func NewOpenAI(apiKey string, baseURL string) (*OpenAI, error) {
 	return &OpenAI{
 		apiKey:  apiKey,
 		baseURL: baseURL,
+		llmType: llms.OpenAI,
 	}, nil
 }
 
+// NewOpenAICompatible creates a client of a service which provides openai compatible apis, like vLLM and LiteLLM.
+// The apiKey can be empty if the service doesn't require authentication.
+func NewOpenAICompatible(apiKey string, baseURL string) *OpenAI {
+	if apiKey == "" {
+		apiKey = EmptyAPIKey
+	}
+	return &OpenAI{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		llmType: llms.OpenAICompatible,
+	}
+}
+
 func (o OpenAI) Type() llms.LLMType {
-	return llms.OpenAI
+	return o.llmType
 }
 
 func (o *OpenAI) Call(data []byte) (llms.Response, error) {
@@ -87,3 +112,32 @@ func (o *OpenAI) Validate(ctx context.Context, options ...langchainllms.CallOpti
 		Success: true,
 	}, nil
 }
+
+// ListModels lists models by the `/models` api
+func (o *OpenAI) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(o.baseURL, "/")+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	if o.apiKey != EmptyAPIKey {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to list models: %s %s", resp.Status, body)
+	}
+	list := &ModelList{}
+	if err := json.NewDecoder(resp.Body).Decode(list); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
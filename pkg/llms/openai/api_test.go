@@ -0,0 +1,81 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newModelsServer starts a fake openai compatible server which requires the api key if it is not empty
+func newModelsServer(t *testing.T, apiKey string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/models" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		auth := r.Header.Get("Authorization")
+		if (apiKey == "" && auth != "") || (apiKey != "" && auth != "Bearer "+apiKey) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"qwen-7b-chat","object":"model"},{"id":"qwen-7b-chat-lora","object":"model"}]}`))
+	}))
+}
+
+func TestListModels(t *testing.T) {
+	testCases := []struct {
+		name      string
+		serverKey string
+		apiKey    string
+		baseURL   string
+		models    []string
+	}{
+		{name: "without api key", baseURL: "/v1", models: []string{"qwen-7b-chat", "qwen-7b-chat-lora"}},
+		{name: "with api key", serverKey: "fake-key", apiKey: "fake-key", baseURL: "/v1/", models: []string{"qwen-7b-chat", "qwen-7b-chat-lora"}},
+		{name: "wrong api key", serverKey: "fake-key", apiKey: "wrong-key", baseURL: "/v1"},
+		{name: "wrong base url", baseURL: "/api"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newModelsServer(t, tc.serverKey)
+			defer server.Close()
+			models, err := NewOpenAICompatible(tc.apiKey, server.URL+tc.baseURL).ListModels(context.Background())
+			if tc.models == nil {
+				if err == nil {
+					t.Errorf("expected an error, but got models %v", models)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(models) != len(tc.models) {
+				t.Fatalf("expected models %v, but got %v", tc.models, models)
+			}
+			for i := range tc.models {
+				if models[i] != tc.models[i] {
+					t.Errorf("expected models %v, but got %v", tc.models, models)
+				}
+			}
+		})
+	}
+}
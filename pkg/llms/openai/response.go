@@ -48,3 +48,10 @@ func (response *Response) String() string {
 func (response *Response) Unmarshal(bytes []byte) error {
 	return json.Unmarshal(bytes, response)
 }
+
+// ModelList is the response of the `/models` api
+type ModelList struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+var (
+	_ langchainllms.Model = (*ToolCallingLLM)(nil)
+	_ llms.ToolCaller     = (*ToolCallingLLM)(nil)
+)
+
+// ToolCallingLLM wraps a langchaingo llm of the openai compatible apis, the calls with tools are sent by the `tools` api
+// and the other calls are sent by the wrapped llm.
+type ToolCallingLLM struct {
+	langchainllms.Model
+	client *OpenAI
+	model  string
+}
+
+func NewToolCallingLLM(llm langchainllms.Model, apiKey, baseURL, model string) *ToolCallingLLM {
+	if baseURL == "" {
+		baseURL = OpenaiModelAPIURL
+	}
+	return &ToolCallingLLM{
+		Model:  llm,
+		client: NewOpenAICompatible(apiKey, baseURL),
+		model:  model,
+	}
+}
+
+func (l *ToolCallingLLM) GenerateWithTools(ctx context.Context, messages []llms.ToolMessage, tools []llms.ToolDefinition, options ...langchainllms.CallOption) (*llms.ToolResponse, error) {
+	opts := langchainllms.CallOptions{Model: l.model}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return l.client.CreateChatCompletionWithTools(ctx, messages, tools, opts)
+}
+
+type toolChatRequest struct {
+	Model       string            `json:"model"`
+	Messages    []toolChatMessage `json:"messages"`
+	Tools       []toolSpec        `json:"tools,omitempty"`
+	Temperature float64           `json:"temperature,omitempty"`
+	TopP        float64           `json:"top_p,omitempty"`
+	MaxTokens   int               `json:"max_tokens,omitempty"`
+	Stop        []string          `json:"stop,omitempty"`
+}
+
+type toolChatMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []toolCallSpec `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type toolSpec struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description,omitempty"`
+		Parameters  map[string]any `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+type toolCallSpec struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type toolChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content   string         `json:"content"`
+			ToolCalls []toolCallSpec `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// CreateChatCompletionWithTools sends the messages with tools by the `/chat/completions` api.
+// llms.ErrToolCallingNotSupported is returned if the service rejects the request.
+func (o *OpenAI) CreateChatCompletionWithTools(ctx context.Context, messages []llms.ToolMessage, tools []llms.ToolDefinition, opts langchainllms.CallOptions) (*llms.ToolResponse, error) {
+	req := &toolChatRequest{
+		Model:       opts.Model,
+		Temperature: opts.Temperature,
+		TopP:        opts.TopP,
+		MaxTokens:   opts.MaxTokens,
+		Stop:        opts.StopWords,
+	}
+	for _, m := range messages {
+		msg := toolChatMessage{Content: m.Content, ToolCallID: m.ToolCallID}
+		switch m.Role {
+		case schema.ChatMessageTypeSystem:
+			msg.Role = "system"
+		case schema.ChatMessageTypeAI:
+			msg.Role = "assistant"
+		case schema.ChatMessageTypeFunction:
+			msg.Role = "tool"
+		default:
+			msg.Role = "user"
+		}
+		for _, call := range m.ToolCalls {
+			spec := toolCallSpec{ID: call.ID, Type: "function"}
+			spec.Function.Name = call.Name
+			spec.Function.Arguments = call.Arguments
+			msg.ToolCalls = append(msg.ToolCalls, spec)
+		}
+		req.Messages = append(req.Messages, msg)
+	}
+	for _, tool := range tools {
+		spec := toolSpec{Type: "function"}
+		spec.Function.Name = tool.Name
+		spec.Function.Description = tool.Description
+		spec.Function.Parameters = tool.Parameters
+		req.Tools = append(req.Tools, spec)
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(o.baseURL, "/")+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.apiKey != EmptyAPIKey {
+		httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		switch resp.StatusCode {
+		case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+			return nil, fmt.Errorf("%w: %s %s", llms.ErrToolCallingNotSupported, resp.Status, body)
+		}
+		return nil, fmt.Errorf("failed to call with tools: %s %s", resp.Status, body)
+	}
+	completion := &toolChatResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(completion); err != nil {
+		return nil, err
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in the response")
+	}
+	choice := completion.Choices[0]
+	res := &llms.ToolResponse{
+		Content:    choice.Message.Content,
+		StopReason: choice.FinishReason,
+		GenerationInfo: map[string]any{
+			"PromptTokens":     completion.Usage.PromptTokens,
+			"CompletionTokens": completion.Usage.CompletionTokens,
+			"TotalTokens":      completion.Usage.TotalTokens,
+		},
+	}
+	for _, call := range choice.Message.ToolCalls {
+		res.ToolCalls = append(res.ToolCalls, llms.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+	return res, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package llms
+
+import (
+	"context"
+	"errors"
+
+	langchainllms "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrToolCallingNotSupported is returned when the llm service rejects the tools in the request
+var ErrToolCallingNotSupported = errors.New("tool calling not supported")
+
+// ToolDefinition describes a tool which can be called by the llm
+type ToolDefinition struct {
+	Name        string
+	Description string
+	// Parameters is the json schema of the tool arguments
+	Parameters map[string]any
+}
+
+// ToolCall is a structured tool call generated by the llm
+type ToolCall struct {
+	ID   string
+	Name string
+	// Arguments is a json object of the arguments
+	Arguments string
+}
+
+// ToolMessage is a message in a conversation with tool calls
+type ToolMessage struct {
+	Role    schema.ChatMessageType
+	Content string
+	// ToolCalls are the calls generated by the ai, only for ai messages
+	ToolCalls []ToolCall
+	// ToolCallID is the id of the call which this message is the result of, only for function messages
+	ToolCallID string
+}
+
+// ToolResponse is the response of a call with tools
+type ToolResponse struct {
+	Content string
+	// ToolCalls can contain multiple calls if the llm decides to call tools in parallel
+	ToolCalls      []ToolCall
+	StopReason     string
+	GenerationInfo map[string]any
+}
+
+// ToolCaller is implemented by the langchaingo llms which support native tool calling
+type ToolCaller interface {
+	GenerateWithTools(ctx context.Context, messages []ToolMessage, tools []ToolDefinition, options ...langchainllms.CallOption) (*ToolResponse, error)
+}
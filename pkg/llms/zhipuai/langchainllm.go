@@ -19,10 +19,13 @@ package zhipuai
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/r3labs/sse/v2"
@@ -31,6 +34,9 @@ import (
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/schema"
 	"k8s.io/klog/v2"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+	arcadiaopenai "github.com/kubeagi/arcadia/pkg/llms/openai"
 )
 
 var (
@@ -40,11 +46,14 @@ var (
 
 var (
 	_ langchainllm.Model = (*ZhiPuAILLM)(nil)
+	_ llms.ToolCaller    = (*ZhiPuAILLM)(nil)
 )
 
 type options struct {
 	retryTimes       int
 	callbacksHandler callbacks.Handler
+	model            string
+	baseURL          string
 }
 
 type Option func(*options)
@@ -61,6 +70,20 @@ func WithCallback(callbacksHandler callbacks.Handler) Option {
 	}
 }
 
+// WithModel sets the model used when the call options don't specify one
+func WithModel(model string) Option {
+	return func(o *options) {
+		o.model = model
+	}
+}
+
+// WithBaseURL sets the endpoint of the llm, the v4 api is used if it's the predefined v3 model api
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
 type ZhiPuAILLM struct {
 	c       *ZhiPuAI
 	options *options
@@ -92,10 +115,13 @@ func (z *ZhiPuAILLM) GenerateContent(ctx context.Context, messages []langchainll
 	if z.options.callbacksHandler != nil {
 		z.options.callbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
-	opts := langchainllm.CallOptions{}
+	opts := langchainllm.CallOptions{Model: z.options.model}
 	for _, opt := range options {
 		opt(&opts)
 	}
+	if hasImages(messages) {
+		return z.generateContentWithImages(ctx, messages, opts)
+	}
 	chatMsgs := make([]*openai.ChatMessage, 0, len(messages))
 	for _, mc := range messages {
 		msg := &openai.ChatMessage{MultiContent: mc.Parts}
@@ -203,3 +229,108 @@ func (z *ZhiPuAILLM) GenerateContent(ctx context.Context, messages []langchainll
 	}
 	return response, nil
 }
+
+// toolCallingModels are the models which support tool calling by the v4 api
+var toolCallingModels = map[string]bool{
+	llms.ZhiPuAIGLM4:      true,
+	llms.ZhiPuAIGLM3Turbo: true,
+}
+
+// v4APIURL returns the base url of the v4 api, which is compatible with openai
+func (z *ZhiPuAILLM) v4APIURL() string {
+	if z.options.baseURL == "" || strings.TrimSuffix(z.options.baseURL, "/") == ZhipuaiModelAPIURL {
+		return ZhipuaiV4APIURL
+	}
+	return z.options.baseURL
+}
+
+// GenerateWithTools calls the configured model with tools by the v4 api, which is compatible with openai.
+// glm-4 is used if no model is configured, llms.ErrToolCallingNotSupported is returned if the model doesn't support tool calling.
+func (z *ZhiPuAILLM) GenerateWithTools(ctx context.Context, messages []llms.ToolMessage, tools []llms.ToolDefinition, options ...langchainllm.CallOption) (*llms.ToolResponse, error) {
+	opts := langchainllm.CallOptions{Model: z.options.model}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Model == "" {
+		opts.Model = llms.ZhiPuAIGLM4
+	}
+	if !toolCallingModels[opts.Model] {
+		return nil, fmt.Errorf("%w: model %s", llms.ErrToolCallingNotSupported, opts.Model)
+	}
+	// zhipuai requires temperature and top_p in (0, 1)
+	if opts.Temperature <= 0 || opts.Temperature >= 1 {
+		opts.Temperature = 0
+	}
+	if opts.TopP <= 0 || opts.TopP >= 1 {
+		opts.TopP = 0
+	}
+	token, err := GenerateToken(z.c.apiKey, APITokenTTLSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return arcadiaopenai.NewOpenAICompatible(token, z.v4APIURL()).CreateChatCompletionWithTools(ctx, messages, tools, opts)
+}
+
+func hasImages(messages []langchainllm.MessageContent) bool {
+	for _, mc := range messages {
+		for _, part := range mc.Parts {
+			switch part.(type) {
+			case langchainllm.ImageURLContent, langchainllm.BinaryContent:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateContentWithImages calls glm-4v by the v4 api, which is compatible with openai.
+// glm-4v accepts the images in base64 without the data url prefix.
+func (z *ZhiPuAILLM) generateContentWithImages(ctx context.Context, messages []langchainllm.MessageContent, opts langchainllm.CallOptions) (*langchainllm.ContentResponse, error) {
+	converted := make([]langchainllm.MessageContent, 0, len(messages))
+	for _, mc := range messages {
+		parts := make([]langchainllm.ContentPart, 0, len(mc.Parts))
+		for _, part := range mc.Parts {
+			switch p := part.(type) {
+			case langchainllm.ImageURLContent:
+				if _, data, ok := strings.Cut(p.URL, ";base64,"); ok {
+					p.URL = data
+				}
+				parts = append(parts, p)
+			case langchainllm.BinaryContent:
+				parts = append(parts, langchainllm.ImageURLPart(base64.StdEncoding.EncodeToString(p.Data)))
+			default:
+				parts = append(parts, part)
+			}
+		}
+		converted = append(converted, langchainllm.MessageContent{Role: mc.Role, Parts: parts})
+	}
+	token, err := GenerateToken(z.c.apiKey, APITokenTTLSeconds)
+	if err != nil {
+		return nil, err
+	}
+	llm, err := openai.New(openai.WithBaseURL(z.v4APIURL()), openai.WithToken(token), openai.WithModel(llms.ZhiPuAIGLM4V))
+	if err != nil {
+		return nil, err
+	}
+	options := []langchainllm.CallOption{langchainllm.WithModel(llms.ZhiPuAIGLM4V)}
+	if opts.StreamingFunc != nil {
+		options = append(options, langchainllm.WithStreamingFunc(opts.StreamingFunc))
+	}
+	if opts.Temperature > 0 && opts.Temperature < 1 {
+		options = append(options, langchainllm.WithTemperature(opts.Temperature))
+	}
+	if opts.MaxTokens > 0 {
+		options = append(options, langchainllm.WithMaxTokens(opts.MaxTokens))
+	}
+	response, err := llm.GenerateContent(ctx, converted, options...)
+	if err != nil {
+		if z.options.callbacksHandler != nil {
+			z.options.callbacksHandler.HandleLLMError(ctx, err)
+		}
+		return nil, err
+	}
+	if z.options.callbacksHandler != nil {
+		z.options.callbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	}
+	return response, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zhipuai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	langchainllm "github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/kubeagi/arcadia/pkg/llms"
+)
+
+func TestGenerateWithTools(t *testing.T) {
+	var model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		req := struct {
+			Model string `json:"model"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		model = req.Model
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hello"}}]}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	messages := []llms.ToolMessage{{Role: schema.ChatMessageTypeHuman, Content: "hi"}}
+	z := NewZhiPuAILLM("id.secret", WithModel(llms.ZhiPuAIGLM3Turbo), WithBaseURL(server.URL))
+	if _, err := z.GenerateWithTools(ctx, messages, nil); err != nil || model != llms.ZhiPuAIGLM3Turbo {
+		t.Errorf("expected the configured model %s, but got %s with %v", llms.ZhiPuAIGLM3Turbo, model, err)
+	}
+	if _, err := z.GenerateWithTools(ctx, messages, nil, langchainllm.WithModel(llms.ZhiPuAIGLM4)); err != nil || model != llms.ZhiPuAIGLM4 {
+		t.Errorf("expected the model in options %s, but got %s with %v", llms.ZhiPuAIGLM4, model, err)
+	}
+
+	model = ""
+	unsupported := NewZhiPuAILLM("id.secret", WithModel(llms.ZhiPuAITurbo), WithBaseURL(server.URL))
+	if _, err := unsupported.GenerateWithTools(ctx, messages, nil); !errors.Is(err, llms.ErrToolCallingNotSupported) || model != "" {
+		t.Errorf("expected %s rejected without calling the api, but got %v", llms.ZhiPuAITurbo, err)
+	}
+}
+
+func TestV4APIURL(t *testing.T) {
+	testCases := map[string]string{
+		"":                       ZhipuaiV4APIURL,
+		ZhipuaiModelAPIURL:       ZhipuaiV4APIURL,
+		ZhipuaiModelAPIURL + "/": ZhipuaiV4APIURL,
+		"http://proxy.local/v4":  "http://proxy.local/v4",
+	}
+	for baseURL, expected := range testCases {
+		if got := NewZhiPuAILLM("id.secret", WithBaseURL(baseURL)).v4APIURL(); got != expected {
+			t.Errorf("%q: expected %s, but got %s", baseURL, expected, got)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	HuggingFaceURL = "https://huggingface.co"
+	ModelScopeURL  = "https://modelscope.cn"
+)
+
+// File in a model repo
+type File struct {
+	Path string
+	Size int64
+	// SHA256 of the file content, empty if not provided by the hub
+	SHA256 string
+}
+
+// Hub is a model hub which hosts model repos
+type Hub interface {
+	// Resolve the revision(branch,tag or commit) into a commit and list all files at the commit
+	Resolve(ctx context.Context, repo, revision string) (commit string, files []File, err error)
+	// Download a file at the commit
+	Download(ctx context.Context, repo, commit, path string) (io.ReadCloser, error)
+}
+
+type hubClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func (h *hubClient) get(ctx context.Context, api string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		return nil, err
+	}
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to get %s: %s %s", api, resp.Status, body)
+	}
+	return resp, nil
+}
+
+func (h *hubClient) getJSON(ctx context.Context, api string, v any) error {
+	resp, err := h.get(ctx, api)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+var _ Hub = (*HuggingFace)(nil)
+
+// HuggingFace hub(https://huggingface.co) or its mirrors
+type HuggingFace struct {
+	hubClient
+}
+
+func NewHuggingFace(baseURL, token string) *HuggingFace {
+	if baseURL == "" {
+		baseURL = HuggingFaceURL
+	}
+	return &HuggingFace{hubClient{baseURL: strings.TrimSuffix(baseURL, "/"), token: token, client: http.DefaultClient}}
+}
+
+type huggingFaceRevision struct {
+	SHA      string `json:"sha"`
+	Siblings []struct {
+		RFileName string `json:"rfilename"`
+		Size      int64  `json:"size"`
+		LFS       *struct {
+			SHA256 string `json:"sha256"`
+			Size   int64  `json:"size"`
+		} `json:"lfs"`
+	} `json:"siblings"`
+}
+
+func (h *HuggingFace) Resolve(ctx context.Context, repo, revision string) (string, []File, error) {
+	if revision == "" {
+		revision = "main"
+	}
+	rev := &huggingFaceRevision{}
+	// blobs=true returns the size and lfs info of each file
+	api := fmt.Sprintf("%s/api/models/%s/revision/%s?blobs=true", h.baseURL, repo, url.PathEscape(revision))
+	if err := h.getJSON(ctx, api, rev); err != nil {
+		return "", nil, err
+	}
+	files := make([]File, 0, len(rev.Siblings))
+	for _, s := range rev.Siblings {
+		f := File{Path: s.RFileName, Size: s.Size}
+		// only the lfs files have sha256, the others have a git blob id
+		if s.LFS != nil {
+			f.SHA256 = s.LFS.SHA256
+			f.Size = s.LFS.Size
+		}
+		files = append(files, f)
+	}
+	return rev.SHA, files, nil
+}
+
+func (h *HuggingFace) Download(ctx context.Context, repo, commit, path string) (io.ReadCloser, error) {
+	resp, err := h.get(ctx, fmt.Sprintf("%s/%s/resolve/%s/%s", h.baseURL, repo, commit, path))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+var _ Hub = (*ModelScope)(nil)
+
+// ModelScope hub(https://modelscope.cn)
+type ModelScope struct {
+	hubClient
+}
+
+func NewModelScope(baseURL, token string) *ModelScope {
+	if baseURL == "" {
+		baseURL = ModelScopeURL
+	}
+	return &ModelScope{hubClient{baseURL: strings.TrimSuffix(baseURL, "/"), token: token, client: http.DefaultClient}}
+}
+
+type modelScopeFiles struct {
+	Code    int    `json:"Code"`
+	Message string `json:"Message"`
+	Success bool   `json:"Success"`
+	Data    struct {
+		Files []struct {
+			Path     string `json:"Path"`
+			Revision string `json:"Revision"`
+			Sha256   string `json:"Sha256"`
+			Size     int64  `json:"Size"`
+			Type     string `json:"Type"`
+		} `json:"Files"`
+	} `json:"Data"`
+}
+
+func (m *ModelScope) Resolve(ctx context.Context, repo, revision string) (string, []File, error) {
+	if revision == "" {
+		revision = "master"
+	}
+	resp := &modelScopeFiles{}
+	api := fmt.Sprintf("%s/api/v1/models/%s/repo/files?Revision=%s&Recursive=true", m.baseURL, repo, url.QueryEscape(revision))
+	if err := m.getJSON(ctx, api, resp); err != nil {
+		return "", nil, err
+	}
+	if resp.Code != http.StatusOK || !resp.Success {
+		return "", nil, fmt.Errorf("failed to list files of %s: %s", repo, resp.Message)
+	}
+	commit := revision
+	files := make([]File, 0, len(resp.Data.Files))
+	for _, f := range resp.Data.Files {
+		if f.Type == "tree" {
+			continue
+		}
+		if f.Revision != "" {
+			commit = f.Revision
+		}
+		files = append(files, File{Path: f.Path, Size: f.Size, SHA256: f.Sha256})
+	}
+	return commit, files, nil
+}
+
+func (m *ModelScope) Download(ctx context.Context, repo, commit, path string) (io.ReadCloser, error) {
+	resp, err := m.get(ctx, fmt.Sprintf("%s/api/v1/models/%s/repo?Revision=%s&FilePath=%s", m.baseURL, repo, url.QueryEscape(commit), url.QueryEscape(path)))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
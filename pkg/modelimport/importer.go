@@ -0,0 +1,198 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelimport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// partialSuffix of the objects being downloaded, which are moved to the final path after verified
+	partialSuffix = ".partial"
+	// reportInterval of the progress when downloading a file
+	reportInterval = 5 * time.Second
+)
+
+// Options of an import
+type Options struct {
+	// Repo and Revision to import
+	Repo     string
+	Revision string
+	// Include and Exclude file patterns
+	Include []string
+	Exclude []string
+	// Prefix of the objects in the store
+	Prefix string
+}
+
+// Progress of an import
+type Progress struct {
+	Commit        string
+	TotalFiles    int
+	ImportedFiles int
+	TotalSize     int64
+	ImportedSize  int64
+}
+
+// Percentage of the imported size
+func (p Progress) Percentage() int {
+	if p.TotalSize == 0 {
+		if p.TotalFiles == 0 {
+			return 100
+		}
+		return p.ImportedFiles * 100 / p.TotalFiles
+	}
+	return int(p.ImportedSize * 100 / p.TotalSize)
+}
+
+// Import the files of a repo revision from the hub into the store.
+// Each file is verified by its sha256(or size if the hub doesn't provide it) before being stored,
+// and the files already imported are skipped, so a failed import can be resumed by running it again.
+func Import(ctx context.Context, hub Hub, store Store, opts Options, report func(Progress)) (Progress, error) {
+	progress := Progress{}
+	commit, files, err := hub.Resolve(ctx, opts.Repo, opts.Revision)
+	if err != nil {
+		return progress, fmt.Errorf("failed to resolve %s@%s: %w", opts.Repo, opts.Revision, err)
+	}
+	progress.Commit = commit
+	files = Filter(files, opts.Include, opts.Exclude)
+	progress.TotalFiles = len(files)
+	for _, f := range files {
+		progress.TotalSize += f.Size
+	}
+	// the progress is reported by the downloading file periodically as well
+	var mu sync.Mutex
+	reportProgress := func(p Progress) {
+		if report == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		report(p)
+	}
+	reportProgress(progress)
+
+	for _, f := range files {
+		object := opts.Prefix + f.Path
+		size, sum, exists, err := store.Stat(ctx, object)
+		if err != nil {
+			return progress, err
+		}
+		if exists && size == f.Size && (f.SHA256 == "" || strings.EqualFold(sum, f.SHA256)) {
+			klog.V(5).Infof("skip %s which has been imported", object)
+		} else {
+			imported := progress
+			if err := importFile(ctx, hub, store, opts.Repo, commit, f, object, func(n int64) {
+				imported.ImportedSize = progress.ImportedSize + n
+				reportProgress(imported)
+			}); err != nil {
+				return progress, err
+			}
+		}
+		progress.ImportedFiles++
+		progress.ImportedSize += f.Size
+		reportProgress(progress)
+	}
+	return progress, nil
+}
+
+// importFile downloads a file into a partial object, and moves it to the object after verified
+func importFile(ctx context.Context, hub Hub, store Store, repo, commit string, f File, object string, downloaded func(int64)) error {
+	klog.Infof("importing %s from %s@%s", f.Path, repo, commit)
+	body, err := hub.Download(ctx, repo, commit, f.Path)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", f.Path, err)
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(body, hash)}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(reportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				downloaded(counter.n.Load())
+			}
+		}
+	}()
+
+	partial := object + partialSuffix
+	if err := store.Put(ctx, partial, counter, f.Size); err != nil {
+		return fmt.Errorf("failed to store %s: %w", f.Path, err)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if n := counter.n.Load(); n != f.Size {
+		_ = store.Remove(ctx, partial)
+		return fmt.Errorf("size mismatch of %s: expected %d, got %d", f.Path, f.Size, n)
+	}
+	if f.SHA256 != "" && !strings.EqualFold(sum, f.SHA256) {
+		_ = store.Remove(ctx, partial)
+		return fmt.Errorf("sha256 mismatch of %s: expected %s, got %s", f.Path, f.SHA256, sum)
+	}
+	return store.Commit(ctx, partial, object, sum)
+}
+
+// Filter the files by include and exclude patterns, which are matched against the file path and the file name
+func Filter(files []File, include, exclude []string) []File {
+	matched := make([]File, 0, len(files))
+	for _, f := range files {
+		if (len(include) == 0 || match(include, f.Path)) && !match(exclude, f.Path) {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+func match(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelimport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeHub serves a huggingface repo
+type fakeHub struct {
+	files     map[string]string
+	lfs       map[string]bool
+	badSHA256 bool
+	downloads []string
+}
+
+func (h *fakeHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/api/models/kubeagi/test/revision/main":
+		type lfs struct {
+			SHA256 string `json:"sha256"`
+			Size   int    `json:"size"`
+		}
+		type sibling struct {
+			RFileName string `json:"rfilename"`
+			Size      int    `json:"size"`
+			LFS       *lfs   `json:"lfs,omitempty"`
+		}
+		siblings := make([]sibling, 0, len(h.files))
+		for name, content := range h.files {
+			s := sibling{RFileName: name, Size: len(content)}
+			if h.lfs[name] {
+				sum := sha256.Sum256([]byte(content))
+				s.LFS = &lfs{SHA256: hex.EncodeToString(sum[:]), Size: len(content)}
+				if h.badSHA256 {
+					s.LFS.SHA256 = strings.Repeat("0", 64)
+				}
+			}
+			siblings = append(siblings, s)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"sha": "abc123", "siblings": siblings})
+	case strings.HasPrefix(r.URL.Path, "/kubeagi/test/resolve/abc123/"):
+		name := strings.TrimPrefix(r.URL.Path, "/kubeagi/test/resolve/abc123/")
+		h.downloads = append(h.downloads, name)
+		_, _ = w.Write([]byte(h.files[name]))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type memoryObject struct {
+	data   []byte
+	sha256 string
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+func (s *memoryStore) Stat(_ context.Context, object string) (int64, string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[object]
+	return int64(len(o.data)), o.sha256, ok, nil
+}
+
+func (s *memoryStore) Put(_ context.Context, object string, r io.Reader, _ int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[object] = memoryObject{data: data}
+	return nil
+}
+
+func (s *memoryStore) Commit(_ context.Context, src, dst, sha256 string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[dst] = memoryObject{data: s.objects[src].data, sha256: sha256}
+	delete(s.objects, src)
+	return nil
+}
+
+func (s *memoryStore) Remove(_ context.Context, object string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, object)
+	return nil
+}
+
+func TestImport(t *testing.T) {
+	hub := &fakeHub{
+		files: map[string]string{
+			"config.json":          `{"model_type":"llama"}`,
+			"model-Q4_K_M.gguf":    "q4 weights",
+			"model-Q8_0.gguf":      "q8 weights",
+			"original/README.md":   "readme",
+			"tokenizer/vocab.json": "{}",
+		},
+		lfs: map[string]bool{"model-Q4_K_M.gguf": true, "model-Q8_0.gguf": true},
+	}
+	server := httptest.NewServer(hub)
+	defer server.Close()
+	store := &memoryStore{objects: make(map[string]memoryObject)}
+	opts := Options{
+		Repo:    "kubeagi/test",
+		Include: []string{"*.json", "*Q4_K_M.gguf"},
+		Exclude: []string{"tokenizer/*"},
+		Prefix:  "model/test/",
+	}
+
+	var last Progress
+	progress, err := Import(context.Background(), NewHuggingFace(server.URL, ""), store, opts, func(p Progress) { last = p })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Commit != "abc123" || progress.TotalFiles != 2 || progress.ImportedFiles != 2 || last.Percentage() != 100 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	gguf := store.objects["model/test/model-Q4_K_M.gguf"]
+	if !bytes.Equal(gguf.data, []byte("q4 weights")) || gguf.sha256 == "" || len(store.objects) != 2 {
+		t.Fatalf("unexpected objects %v", store.objects)
+	}
+
+	// resume skips the imported files
+	hub.downloads = nil
+	delete(store.objects, "model/test/config.json")
+	if _, err := Import(context.Background(), NewHuggingFace(server.URL, ""), store, opts, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(hub.downloads) != 1 || hub.downloads[0] != "config.json" {
+		t.Fatalf("expected to download config.json only, got %v", hub.downloads)
+	}
+
+	// files are verified by sha256
+	hub.badSHA256 = true
+	store = &memoryStore{objects: make(map[string]memoryObject)}
+	if _, err := Import(context.Background(), NewHuggingFace(server.URL, ""), store, opts, nil); err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("expected sha256 mismatch, got %v", err)
+	}
+	if _, ok := store.objects["model/test/model-Q4_K_M.gguf"]; ok {
+		t.Fatal("unverified file should not be stored")
+	}
+	if _, ok := store.objects["model/test/model-Q4_K_M.gguf"+partialSuffix]; ok {
+		t.Fatal("partial file should be removed")
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelimport
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/kubeagi/arcadia/pkg/datasource"
+)
+
+// sha256MetaKey is the user metadata to keep the verified sha256 of an imported file
+const sha256MetaKey = "Sha256"
+
+// Store keeps the imported files
+type Store interface {
+	// Stat returns the size and the verified sha256 of an object, exists is false if not found
+	Stat(ctx context.Context, object string) (size int64, sha256 string, exists bool, err error)
+	// Put an object
+	Put(ctx context.Context, object string, r io.Reader, size int64) error
+	// Commit moves the verified object from src to dst with its sha256
+	Commit(ctx context.Context, src, dst, sha256 string) error
+	// Remove an object
+	Remove(ctx context.Context, object string) error
+}
+
+var _ Store = (*OSSStore)(nil)
+
+// OSSStore stores the files into a bucket of the oss
+type OSSStore struct {
+	oss    *datasource.OSS
+	bucket string
+}
+
+func NewOSSStore(oss *datasource.OSS, bucket string) *OSSStore {
+	return &OSSStore{oss: oss, bucket: bucket}
+}
+
+func (s *OSSStore) Stat(ctx context.Context, object string) (int64, string, bool, error) {
+	info, err := s.oss.Client.StatObject(ctx, s.bucket, object, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return 0, "", false, nil
+		}
+		return 0, "", false, err
+	}
+	return info.Size, info.UserMetadata[sha256MetaKey], true, nil
+}
+
+func (s *OSSStore) Put(ctx context.Context, object string, r io.Reader, size int64) error {
+	_, err := s.oss.Client.PutObject(ctx, s.bucket, object, r, size, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *OSSStore) Commit(ctx context.Context, src, dst, sha256 string) error {
+	// compose supports the objects larger than 5GiB which can't be copied directly
+	_, err := s.oss.Client.ComposeObject(ctx,
+		minio.CopyDestOptions{
+			Bucket:          s.bucket,
+			Object:          dst,
+			UserMetadata:    map[string]string{sha256MetaKey: sha256},
+			ReplaceMetadata: true,
+		},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: src},
+	)
+	if err != nil {
+		return err
+	}
+	return s.Remove(ctx, src)
+}
+
+func (s *OSSStore) Remove(ctx context.Context, object string) error {
+	return s.oss.Client.RemoveObject(ctx, s.bucket, object, minio.RemoveObjectOptions{})
+}
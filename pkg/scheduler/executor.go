@@ -61,6 +61,7 @@ func (e *executor) generateJob(ctx context.Context, jobCh chan<- JobPayload, dat
 		dstPrefix := fmt.Sprintf("dataset/%s/%s/", e.instance.Spec.Dataset.Name, e.instance.Spec.Version)
 
 		var srcBucket, srcPrefix string
+		var srcGit *datasource.Git
 		if !removeAction {
 			switch fs.Kind {
 			case "Datasource":
@@ -77,6 +78,19 @@ func (e *executor) generateJob(ctx context.Context, jobCh chan<- JobPayload, dat
 				if ds.Spec.OSS != nil {
 					srcBucket = ds.Spec.OSS.Bucket
 				}
+				if ds.Spec.Type() == v1alpha1.DatasourceTypeGit {
+					g, err := datasource.GetGitRepository(ctx, e.client, ds)
+					if err != nil {
+						klog.Errorf("generateJob: failed to get git repository %s", err)
+						return err
+					}
+					// copy files at the head of the tracked branch
+					if _, err = g.Refresh(ctx); err != nil {
+						klog.Errorf("generateJob: failed to refresh git repository %s", err)
+						return err
+					}
+					srcGit = g
+				}
 			case "VersionedDataset":
 				srcVersion := fs.Name[len(v1alpha1.InheritedFromVersionName):]
 				srcBucket = e.instance.Namespace
@@ -125,6 +139,7 @@ func (e *executor) generateJob(ctx context.Context, jobCh chan<- JobPayload, dat
 				SrcBucket:  srcBucket,
 				DstBucket:  dstBucket,
 				Oss:        e.oss,
+				Git:        srcGit,
 				Remove:     removeAction,
 			}
 
@@ -143,6 +158,11 @@ func (e *executor) GenerateJob(ctx context.Context, jobCh chan<- JobPayload) err
 }
 
 func (e *executor) Task(ctx context.Context, job JobPayload) error {
+	if !job.Remove && job.Git != nil {
+		err := job.Git.CopyFileTo(ctx, job.Oss, job.DstBucket, job.Dst, job.Src)
+		klog.V(4).Infof("[Debug] copy git file %s to %s/%s, result: %s", job.Src, job.DstBucket, job.Dst, err)
+		return err
+	}
 	if !job.Remove {
 		klog.V(4).Infof("[Debug] copyObject task from %s/%s to %s/%s", job.SrcBucket, job.Src, job.DstBucket, job.Dst)
 		_, err := job.Oss.Client.CopyObject(ctx, minio.CopyDestOptions{
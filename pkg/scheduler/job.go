@@ -24,6 +24,8 @@ type JobPayload struct {
 	SourceName           string
 	SrcBucket, DstBucket string
 
-	Oss    *datasource.OSS
+	Oss *datasource.OSS
+	// Git is the source repository if the source is a git datasource
+	Git    *datasource.Git
 	Remove bool
 }
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	base := time.Date(2024, 3, 15, 10, 30, 20, 0, time.UTC) // friday
+	testCases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 */6 * * *", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, 3, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 10 * * 1-7", time.Date(2024, 3, 16, 10, 0, 0, 0, time.UTC)},
+		{"0 0 * * 6-7", time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * */7", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 17 * *", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@every 2h", time.Date(2024, 3, 15, 12, 30, 20, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		s, err := ParseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("parse %q: unexpected error: %v", tc.spec, err)
+		}
+		if got := s.Next(base); !got.Equal(tc.want) {
+			t.Errorf("%q: expected next %v, but got %v", tc.spec, tc.want, got)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "* * * * 8", "* * * * 7-1", "a * * * *", "@every 1ms"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("%q: expected error, but got nil", spec)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var (
+	// statusCodePattern matches the http status code in error messages of model services,
+	// like `API returned unexpected status code: 429` or `exception: 503 Service Unavailable`
+	statusCodePattern = regexp.MustCompile(`(?i)(?:status code|exception)\s*:?\s*(\d{3})\b`)
+
+	// rateLimitedMessages are parts of error messages returned by model services which rate limit the requests
+	rateLimitedMessages = []string{
+		"too many requests",
+		"rate limit",
+	}
+	// unavailableMessages are parts of error messages returned by model services which are temporarily unavailable
+	unavailableMessages = []string{
+		"bad gateway",
+		"service unavailable",
+		"gateway timeout",
+		"server overloaded",
+	}
+	// permanentMessages are parts of error messages which can not be fixed by retrying, even with a status code like 429
+	permanentMessages = []string{
+		"quota",
+	}
+)
+
+// httpStatusError is an error with the http status code returned by a service
+type httpStatusError interface {
+	HTTPStatusCode() int
+}
+
+// HTTPStatusCode returns the http status code of the error, or 0 if unknown
+func HTTPStatusCode(err error) int {
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.HTTPStatusCode()
+	}
+	if m := statusCodePattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
+
+// IsTransientError reports whether the error is likely to disappear by retrying later,
+// like being rate limited, a temporarily unavailable service or a network error.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	if containsAny(msg, permanentMessages) {
+		return false
+	}
+	switch code := HTTPStatusCode(err); code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case 0:
+		return containsAny(msg, rateLimitedMessages) || containsAny(msg, unavailableMessages)
+	default:
+		return false
+	}
+}
+
+// IsRateLimitedError reports whether the error is returned by a service which rate limits the requests
+func IsRateLimitedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	if containsAny(msg, permanentMessages) {
+		return false
+	}
+	switch code := HTTPStatusCode(err); code {
+	case http.StatusTooManyRequests:
+		return true
+	case 0:
+		return containsAny(msg, rateLimitedMessages)
+	default:
+		return false
+	}
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
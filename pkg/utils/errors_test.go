@@ -0,0 +1,71 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+)
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with %d", e.code)
+}
+
+func (e *statusError) HTTPStatusCode() int {
+	return e.code
+}
+
+func TestIsTransientError(t *testing.T) {
+	testCases := []struct {
+		err         error
+		transient   bool
+		rateLimited bool
+	}{
+		{nil, false, false},
+		{errors.New("invalid api key"), false, false},
+		{errors.New("API returned unexpected status code: 429: Rate limit reached for requests"), true, true},
+		{fmt.Errorf("failed to embed: %w", context.DeadlineExceeded), true, false},
+		{errors.New("unexpected status code: 503 Service Unavailable"), true, false},
+		{errors.New("exception: 429 Too Many Requests"), true, true},
+		{errors.New("API returned unexpected status code: 400: invalid input, rate limit is not exceeded"), false, false},
+		{errors.New("API returned unexpected status code: 429: You exceeded your current quota"), false, false},
+		{errors.New("quota exceeded"), false, false},
+		{errors.New("invalid timeout parameter"), false, false},
+		{errors.New("document 502.txt not found"), false, false},
+		{&statusError{code: 502}, true, false},
+		{fmt.Errorf("call llm: %w", &statusError{code: 429}), true, true},
+		{&statusError{code: 401}, false, false},
+		{&net.DNSError{Err: "i/o timeout", IsTimeout: true}, true, false},
+		{fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true, false},
+	}
+	for _, tc := range testCases {
+		if got := IsTransientError(tc.err); got != tc.transient {
+			t.Errorf("%v: expected transient %t, but got %t", tc.err, tc.transient, got)
+		}
+		if got := IsRateLimitedError(tc.err); got != tc.rateLimited {
+			t.Errorf("%v: expected rate limited %t, but got %t", tc.err, tc.rateLimited, got)
+		}
+	}
+}
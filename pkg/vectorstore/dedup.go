@@ -0,0 +1,161 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	chromago "github.com/amikos-tech/chroma-go"
+	"github.com/go-logr/logr"
+	lanchaingoschema "github.com/tmc/langchaingo/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/dedup"
+)
+
+// DuplicateFilesMetadataKey records the files of chunks merged into this chunk, separated by comma
+const DuplicateFilesMetadataKey = "duplicate_files"
+
+// DeduplicateDocuments drops documents duplicated with the collection or each other.
+// It returns the kept documents, the number of the duplicated ones and a function to record the kept documents
+// into the collection's index, which should be called after they are stored.
+//
+// The index of a collection is kept in memory, and it is loaded from the collection when it is created,
+// so that the chunks stored before a restart are still found. The file of a chunk is the one recorded by SourceMetadataKey.
+func DeduplicateDocuments(ctx context.Context, log logr.Logger, vs *arcadiav1alpha1.VectorStore, collectionName string, c client.Client, options arcadiav1alpha1.Deduplication, source string, documents []lanchaingoschema.Document) (kept []lanchaingoschema.Document, duplicates int, commit func(), err error) {
+	index, created := dedup.GetIndex(collectionName, options)
+	var store *PGVectorStore
+	var finish func()
+	if vs.Spec.Type() == arcadiav1alpha1.VectorStoreTypePGVector {
+		if store, finish, err = NewPGVectorStore(ctx, vs, c, nil, collectionName); err != nil {
+			dedup.RemoveIndex(collectionName)
+			return nil, 0, nil, err
+		}
+		if finish != nil {
+			defer finish()
+		}
+	}
+	if created {
+		log.V(3).Info("load the deduplication index from the collection...")
+		var entries []dedup.Entry
+		if store != nil {
+			entries, err = store.Entries(ctx)
+		} else {
+			entries, err = chromaEntries(ctx, vs, collectionName)
+		}
+		if err != nil {
+			dedup.RemoveIndex(collectionName)
+			return nil, 0, nil, err
+		}
+		index.Add(entries...)
+		log.V(3).Info("load the deduplication index from the collection done", "chunks", len(entries))
+	}
+
+	contents := make([]string, len(documents))
+	for i, doc := range documents {
+		contents[i] = doc.PageContent
+	}
+	batch := index.NewBatch()
+	keptIndexes, dups, _ := batch.Deduplicate(contents, source)
+	for _, i := range keptIndexes {
+		if documents[i].Metadata == nil {
+			documents[i].Metadata = make(map[string]any)
+		}
+		documents[i].Metadata[SourceMetadataKey] = source
+	}
+	for _, dup := range dups {
+		log.V(5).Info(fmt.Sprintf("duplicated document[%d] exact:%t: %s", dup.Index, dup.Exact, documents[dup.Index].PageContent))
+		if options.Policy != arcadiav1alpha1.DeduplicationPolicyMerge {
+			continue
+		}
+		if dup.Batch >= 0 {
+			documents[dup.Batch].Metadata[DuplicateFilesMetadataKey] = appendFile(documents[dup.Batch].Metadata[DuplicateFilesMetadataKey], source)
+			continue
+		}
+		if store == nil {
+			log.V(3).Info("merge duplicated document into the stored one is only supported by pgvector, skip it")
+			continue
+		}
+		if err = store.MergeDuplicate(ctx, dup.Of.Content, source); err != nil {
+			return nil, 0, nil, err
+		}
+	}
+	kept = make([]lanchaingoschema.Document, 0, len(keptIndexes))
+	for _, i := range keptIndexes {
+		kept = append(kept, documents[i])
+	}
+	return kept, len(dups), batch.Commit, nil
+}
+
+// chromaEntries returns all documents of a chroma collection as entries of the deduplication index
+func chromaEntries(ctx context.Context, vs *arcadiav1alpha1.VectorStore, collectionName string) ([]dedup.Entry, error) {
+	if vs.Spec.Type() != arcadiav1alpha1.VectorStoreTypeChroma {
+		return nil, ErrUnsupportedVectorStoreType
+	}
+	client := chromago.NewClient(vs.Spec.Endpoint.URL)
+	collections, err := client.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var collection *chromago.Collection
+	for _, c := range collections {
+		if c.Name == collectionName {
+			collection = c
+			break
+		}
+	}
+	// the collection is created when the first document is added
+	if collection == nil {
+		return nil, nil
+	}
+	if collection, err = collection.Get(ctx, nil, nil, nil); err != nil {
+		return nil, err
+	}
+	if collection.CollectionData == nil {
+		return nil, nil
+	}
+	entries := make([]dedup.Entry, 0, len(collection.CollectionData.Documents))
+	for i, document := range collection.CollectionData.Documents {
+		entry := dedup.Entry{Content: document}
+		if i < len(collection.CollectionData.Metadatas) {
+			entry.Source, _ = collection.CollectionData.Metadatas[i][SourceMetadataKey].(string)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// RemoveDeduplicationIndex drops the in-memory index of a collection
+func RemoveDeduplicationIndex(collectionName string) {
+	dedup.RemoveIndex(collectionName)
+}
+
+func appendFile(files any, file string) string {
+	s, _ := files.(string)
+	if s == "" {
+		return file
+	}
+	for _, f := range strings.Split(s, ",") {
+		if f == file {
+			return s
+		}
+	}
+	return s + "," + file
+}
@@ -18,6 +18,7 @@ package vectorstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -34,6 +35,7 @@ import (
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
 	"github.com/kubeagi/arcadia/pkg/datasource"
+	"github.com/kubeagi/arcadia/pkg/dedup"
 )
 
 var _ vectorstores.VectorStore = (*PGVectorStore)(nil)
@@ -179,3 +181,84 @@ func (s *PGVectorStore) RemoveExist(ctx context.Context, log logr.Logger, docume
 	}
 	return doc, nil
 }
+
+func (s *PGVectorStore) collectionUUID(ctx context.Context) (string, error) {
+	collectionUUID := ""
+	sql := fmt.Sprintf(`SELECT uuid FROM %s WHERE name = $1 ORDER BY name limit 1`, s.PGVector.CollectionTableName)
+	err := s.Conn.QueryRow(ctx, sql, s.PGVector.CollectionName).Scan(&collectionUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return collectionUUID, err
+}
+
+// Entries returns all documents of the collection as entries of the deduplication index
+func (s *PGVectorStore) Entries(ctx context.Context) ([]dedup.Entry, error) {
+	collectionUUID, err := s.collectionUUID(ctx)
+	if err != nil || collectionUUID == "" {
+		return nil, err
+	}
+	sql := fmt.Sprintf(`SELECT document, coalesce(cmetadata->>'%s', '') FROM %s WHERE collection_id = $1`, SourceMetadataKey, s.PGVector.EmbeddingTableName)
+	rows, err := s.Conn.Query(ctx, sql, collectionUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := make([]dedup.Entry, 0)
+	for rows.Next() {
+		entry := dedup.Entry{}
+		if err := rows.Scan(&entry.Content, &entry.Source); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// MergeDuplicate records the file of a duplicated document in the metadata of the stored document
+func (s *PGVectorStore) MergeDuplicate(ctx context.Context, document, file string) error {
+	collectionUUID, err := s.collectionUUID(ctx)
+	if err != nil || collectionUUID == "" {
+		return err
+	}
+	sql := fmt.Sprintf(`UPDATE %[1]s SET cmetadata = jsonb_set(coalesce(cmetadata, '{}'::jsonb), '{%[2]s}', to_jsonb(concat_ws(',', nullif(cmetadata->>'%[2]s', ''), $3::text)))
+WHERE collection_id = $1 AND document = $2 AND NOT (string_to_array(coalesce(cmetadata->>'%[2]s', ''), ',') @> ARRAY[$3::text])`,
+		s.PGVector.EmbeddingTableName, DuplicateFilesMetadataKey)
+	_, err = s.Conn.Exec(ctx, sql, collectionUUID, document, file)
+	return err
+}
+
+// RemoveSource removes the documents of a file from the collection
+func (s *PGVectorStore) RemoveSource(ctx context.Context, source string) error {
+	collectionUUID, err := s.collectionUUID(ctx)
+	if err != nil || collectionUUID == "" {
+		return err
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1 AND cmetadata->>'%s' = $2`, s.PGVector.EmbeddingTableName, SourceMetadataKey)
+	_, err = s.Conn.Exec(ctx, sql, collectionUUID, source)
+	return err
+}
+
+// RemoveRows removes the documents of the rows with the keys of a table from the collection
+func (s *PGVectorStore) RemoveRows(ctx context.Context, source string, keys []string) error {
+	collectionUUID, err := s.collectionUUID(ctx)
+	if err != nil || collectionUUID == "" {
+		return err
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1 AND cmetadata->>'%s' = $2 AND cmetadata->>'%s' = ANY($3)`,
+		s.PGVector.EmbeddingTableName, SourceMetadataKey, RowMetadataKey)
+	_, err = s.Conn.Exec(ctx, sql, collectionUUID, source, keys)
+	return err
+}
+
+// RemoveRowsExcept removes the documents of a table except the rows with the keys from the collection
+func (s *PGVectorStore) RemoveRowsExcept(ctx context.Context, source string, keys []string) error {
+	collectionUUID, err := s.collectionUUID(ctx)
+	if err != nil || collectionUUID == "" {
+		return err
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1 AND cmetadata->>'%s' = $2 AND cmetadata ? '%s' AND NOT cmetadata->>'%s' = ANY($3)`,
+		s.PGVector.EmbeddingTableName, SourceMetadataKey, RowMetadataKey, RowMetadataKey)
+	_, err = s.Conn.Exec(ctx, sql, collectionUUID, source, keys)
+	return err
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 
+	chromago "github.com/amikos-tech/chroma-go"
 	"github.com/go-logr/logr"
 	"github.com/tmc/langchaingo/embeddings"
 	lanchaingoschema "github.com/tmc/langchaingo/schema"
@@ -29,8 +30,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/dedup"
 )
 
+// SourceMetadataKey records the file of a chunk, so the chunks of a file can be removed when it is changed or removed.
+// The chunks embedded by the older versions have no file recorded, they can't be replaced or removed with the file,
+// so the knowledgebases created by the older versions should be re-ingested, such as deleting and creating them again.
+const SourceMetadataKey = "source_file"
+
+// RowMetadataKey records the row key of a chunk of a table, so the chunks of an updated row can be replaced
+const RowMetadataKey = "source_row"
+
 var (
 	ErrUnsupportedVectorStoreType = errors.New("unsupported vectorstore type")
 )
@@ -138,3 +148,108 @@ func AddDocuments(ctx context.Context, log logr.Logger, vs *arcadiav1alpha1.Vect
 	log.V(3).Info("handle file succeeded")
 	return nil
 }
+
+// RemoveDocumentsBySource removes the chunks of a file from the collection
+func RemoveDocumentsBySource(ctx context.Context, log logr.Logger, vs *arcadiav1alpha1.VectorStore, collectionName string, c client.Client, source string) (err error) {
+	log.V(3).Info("remove documents of file from vector store", "source", source)
+	defer func() {
+		if err == nil {
+			dedup.RemoveSource(collectionName, source)
+		}
+	}()
+	switch vs.Spec.Type() {
+	case arcadiav1alpha1.VectorStoreTypeChroma:
+		collection, err := chromago.NewClient(vs.Spec.Endpoint.URL).GetCollection(ctx, collectionName, nil)
+		if err != nil {
+			return err
+		}
+		_, err = collection.Delete(ctx, nil, map[string]interface{}{SourceMetadataKey: source}, nil)
+		return err
+	case arcadiav1alpha1.VectorStoreTypePGVector:
+		v, finish, err := NewPGVectorStore(ctx, vs, c, nil, collectionName)
+		if finish != nil {
+			defer finish()
+		}
+		if err != nil {
+			return err
+		}
+		return v.RemoveSource(ctx, source)
+	case arcadiav1alpha1.VectorStoreTypeUnknown:
+		fallthrough
+	default:
+		return ErrUnsupportedVectorStoreType
+	}
+}
+
+// RemoveDocumentsByRows removes the chunks of the rows with the keys of a table from the collection
+func RemoveDocumentsByRows(ctx context.Context, log logr.Logger, vs *arcadiav1alpha1.VectorStore, collectionName string, c client.Client, source string, keys []string) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+	log.V(3).Info("remove documents of rows from vector store", "source", source, "rows", len(keys))
+	// the deduplication index doesn't know the rows of chunks, so it is reloaded from the collection
+	defer dedup.RemoveIndex(collectionName)
+	switch vs.Spec.Type() {
+	case arcadiav1alpha1.VectorStoreTypeChroma:
+		collection, err := chromago.NewClient(vs.Spec.Endpoint.URL).GetCollection(ctx, collectionName, nil)
+		if err != nil {
+			return err
+		}
+		_, err = collection.Delete(ctx, nil, map[string]interface{}{
+			"$and": []map[string]interface{}{
+				{SourceMetadataKey: source},
+				{RowMetadataKey: map[string]interface{}{"$in": keys}},
+			},
+		}, nil)
+		return err
+	case arcadiav1alpha1.VectorStoreTypePGVector:
+		v, finish, err := NewPGVectorStore(ctx, vs, c, nil, collectionName)
+		if finish != nil {
+			defer finish()
+		}
+		if err != nil {
+			return err
+		}
+		return v.RemoveRows(ctx, source, keys)
+	case arcadiav1alpha1.VectorStoreTypeUnknown:
+		fallthrough
+	default:
+		return ErrUnsupportedVectorStoreType
+	}
+}
+
+// RemoveDocumentsExceptRows removes the documents of a table except the rows with the keys, which are the rows deleted from the table
+func RemoveDocumentsExceptRows(ctx context.Context, log logr.Logger, vs *arcadiav1alpha1.VectorStore, collectionName string, c client.Client, source string, keys []string) (err error) {
+	if len(keys) == 0 {
+		return RemoveDocumentsBySource(ctx, log, vs, collectionName, c, source)
+	}
+	log.V(3).Info("remove documents of deleted rows from vector store", "source", source, "rows", len(keys))
+	defer dedup.RemoveIndex(collectionName)
+	switch vs.Spec.Type() {
+	case arcadiav1alpha1.VectorStoreTypeChroma:
+		collection, err := chromago.NewClient(vs.Spec.Endpoint.URL).GetCollection(ctx, collectionName, nil)
+		if err != nil {
+			return err
+		}
+		_, err = collection.Delete(ctx, nil, map[string]interface{}{
+			"$and": []map[string]interface{}{
+				{SourceMetadataKey: source},
+				{RowMetadataKey: map[string]interface{}{"$nin": keys}},
+			},
+		}, nil)
+		return err
+	case arcadiav1alpha1.VectorStoreTypePGVector:
+		v, finish, err := NewPGVectorStore(ctx, vs, c, nil, collectionName)
+		if finish != nil {
+			defer finish()
+		}
+		if err != nil {
+			return err
+		}
+		return v.RemoveRowsExcept(ctx, source, keys)
+	case arcadiav1alpha1.VectorStoreTypeUnknown:
+		fallthrough
+	default:
+		return ErrUnsupportedVectorStoreType
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+const (
+	// DefaultIdleTimeout before scaling a worker to zero
+	DefaultIdleTimeout = 15 * time.Minute
+	// scaleDownDelay avoids scaling down right after the replicas changed,because loading a model takes a long time
+	scaleDownDelay = 5 * time.Minute
+)
+
+// Autoscale decides the replicas of an autoscaling worker by the concurrent requests reported in its annotations.
+// The decision is kept in worker.Status.Autoscaling, and the returned duration is when the worker should be checked again(0 if no need).
+func Autoscale(worker *arcadiav1alpha1.Worker, now time.Time) time.Duration {
+	policy := worker.Spec.Autoscaling
+	if policy == nil {
+		worker.Status.Autoscaling = nil
+		return 0
+	}
+	status := worker.Status.Autoscaling.DeepCopy()
+	if status == nil {
+		status = &arcadiav1alpha1.WorkerAutoscalingStatus{}
+	}
+
+	// requests reported by the callers
+	concurrency, lastRequest := Requests(worker, now)
+	status.ConcurrentRequests = concurrency
+	if !lastRequest.IsZero() && (status.LastRequestTime == nil || lastRequest.After(status.LastRequestTime.Time)) {
+		status.LastRequestTime = &metav1.Time{Time: lastRequest}
+	}
+	lastRequestTime := worker.CreationTimestamp.Time
+	if status.LastRequestTime != nil {
+		lastRequestTime = status.LastRequestTime.Time
+	}
+
+	maxReplicas := max(policy.MaxReplicas, 1)
+	target := max(policy.TargetConcurrentRequests, 1)
+	// keep at least one replica unless the worker is idle
+	desired := min(max((status.ConcurrentRequests+target-1)/target, policy.MinReplicas, 1), maxReplicas)
+
+	var requeueAfter time.Duration
+	// check again after the reports expire, in case the callers stop without reporting
+	if status.ConcurrentRequests > 0 {
+		requeueAfter = staleReportTimeout
+	}
+	if policy.MinReplicas == 0 && status.ConcurrentRequests == 0 {
+		idleTimeout := DefaultIdleTimeout
+		if policy.IdleTimeout != nil {
+			idleTimeout = policy.IdleTimeout.Duration
+		}
+		if idle := now.Sub(lastRequestTime); idle >= idleTimeout {
+			desired = 0
+		} else {
+			requeueAfter = idleTimeout - idle
+		}
+	}
+	if desired > 0 && desired < status.Replicas && status.LastScaleTime != nil {
+		if wait := scaleDownDelay - now.Sub(status.LastScaleTime.Time); wait > 0 {
+			desired = status.Replicas
+			if requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+		}
+	}
+
+	if desired != status.Replicas || status.LastScaleTime == nil {
+		status.Replicas = desired
+		status.LastScaleTime = &metav1.Time{Time: now}
+	}
+	status.ScaleState = scaleStateOf(status)
+	worker.Status.Autoscaling = status
+	return requeueAfter
+}
+
+// scaleStateOf compares the desired replicas with the ready ones
+func scaleStateOf(status *arcadiav1alpha1.WorkerAutoscalingStatus) arcadiav1alpha1.WorkerScaleState {
+	switch {
+	case status.Replicas == 0:
+		return arcadiav1alpha1.WorkerScaleStateScaledToZero
+	case status.ReadyReplicas == 0:
+		return arcadiav1alpha1.WorkerScaleStateWaking
+	case status.ReadyReplicas < status.Replicas:
+		return arcadiav1alpha1.WorkerScaleStateScalingUp
+	case status.ReadyReplicas > status.Replicas:
+		return arcadiav1alpha1.WorkerScaleStateScalingDown
+	default:
+		return arcadiav1alpha1.WorkerScaleStateActive
+	}
+}
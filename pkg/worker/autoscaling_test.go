@@ -0,0 +1,101 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func TestAutoscale(t *testing.T) {
+	now := time.Now()
+	newWorker := func(concurrency int32, lastRequest time.Time, status *arcadiav1alpha1.WorkerAutoscalingStatus) *arcadiav1alpha1.Worker {
+		report, _ := json.Marshal(RequestsReport{ConcurrentRequests: concurrency, LastRequestTime: lastRequest, ReportTime: now})
+		return &arcadiav1alpha1.Worker{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Time{Time: now.Add(-time.Hour)},
+				Annotations: map[string]string{
+					arcadiav1alpha1.WorkerRequestsAnnotationPrefix + "a": string(report),
+				},
+			},
+			Spec: arcadiav1alpha1.WorkerSpec{
+				Autoscaling: &arcadiav1alpha1.WorkerAutoscaling{
+					MaxReplicas:              3,
+					TargetConcurrentRequests: 2,
+					IdleTimeout:              &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			Status: arcadiav1alpha1.WorkerStatus{Autoscaling: status},
+		}
+	}
+	longAgo := &metav1.Time{Time: now.Add(-time.Hour)}
+
+	testCases := []struct {
+		name     string
+		worker   *arcadiav1alpha1.Worker
+		replicas int32
+		state    arcadiav1alpha1.WorkerScaleState
+		requeue  bool
+	}{
+		{
+			name:     "scale up by requests",
+			worker:   newWorker(5, now, &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 1, ReadyReplicas: 1, LastScaleTime: longAgo}),
+			replicas: 3,
+			state:    arcadiav1alpha1.WorkerScaleStateScalingUp,
+			requeue:  true,
+		},
+		{
+			name:     "keep one replica before idle timeout",
+			worker:   newWorker(0, now.Add(-time.Minute), &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 1, ReadyReplicas: 1, LastScaleTime: longAgo}),
+			replicas: 1,
+			state:    arcadiav1alpha1.WorkerScaleStateActive,
+			requeue:  true,
+		},
+		{
+			name:     "scale to zero after idle timeout",
+			worker:   newWorker(0, now.Add(-time.Hour), &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 1, ReadyReplicas: 1, LastScaleTime: longAgo}),
+			replicas: 0,
+			state:    arcadiav1alpha1.WorkerScaleStateScaledToZero,
+		},
+		{
+			name:     "wake up by a request",
+			worker:   newWorker(1, now, &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 0, LastScaleTime: longAgo}),
+			replicas: 1,
+			state:    arcadiav1alpha1.WorkerScaleStateWaking,
+			requeue:  true,
+		},
+		{
+			name:     "delay scaling down",
+			worker:   newWorker(1, now, &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 3, ReadyReplicas: 3, LastScaleTime: &metav1.Time{Time: now.Add(-time.Minute)}}),
+			replicas: 3,
+			state:    arcadiav1alpha1.WorkerScaleStateActive,
+			requeue:  true,
+		},
+	}
+	for _, tc := range testCases {
+		requeueAfter := Autoscale(tc.worker, now)
+		status := tc.worker.Status.Autoscaling
+		if status.Replicas != tc.replicas || status.ScaleState != tc.state || (requeueAfter > 0) != tc.requeue {
+			t.Errorf("%s: unexpected replicas %d, state %s, requeue after %s", tc.name, status.Replicas, status.ScaleState, requeueAfter)
+		}
+	}
+}
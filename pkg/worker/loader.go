@@ -20,12 +20,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
+	"github.com/minio/minio-go/v7"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/config"
 	"github.com/kubeagi/arcadia/pkg/datasource"
 )
 
@@ -144,11 +149,126 @@ mc --insecure cp -r oss/$MINIO_MODEL_BUCKET/model/$MINIO_MODEL_NAME /data/models
 
 var _ ModelLoader = (*LoaderGit)(nil)
 
+// ErrModelLoading means the model files are being copied, the worker should be checked again after copied
+var ErrModelLoading = errors.New("model files are being copied from git")
+
+var (
+	// gitCopies are the models whose files are being copied from git in background
+	gitCopiesMutex sync.Mutex
+	gitCopies      = make(map[string]bool)
+)
+
 // LoaderGit defines the way to load model from git
-type LoaderGit struct{}
+// The files at the head of the git datasource are copied into the system datasource in background,
+// then the worker loads them like a model stored in oss.
+// The copied commit is recorded in the model's annotation, so the files are only copied again when the commit changes.
+type LoaderGit struct {
+	c client.Client
+
+	git    *datasource.Git
+	worker *arcadiav1alpha1.Worker
+}
+
+func NewLoaderGit(ctx context.Context, c client.Client, source *arcadiav1alpha1.Datasource, worker *arcadiav1alpha1.Worker) (ModelLoader, error) {
+	g, err := datasource.GetGitRepository(ctx, c, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git repository with %w", err)
+	}
+	return &LoaderGit{
+		c:      c,
+		git:    g,
+		worker: worker,
+	}, nil
+}
 
+// Build starts copying the repository into `model/<model name>/` of the system datasource in background if the commit changed,
+// and builds a oss loader container which loads the last copied commit. It returns ErrModelLoading if nothing is copied yet.
 func (loader *LoaderGit) Build(ctx context.Context, model *arcadiav1alpha1.TypedObjectReference) (any, error) {
-	return nil, ErrNotImplementedYet
+	if model == nil || model.Namespace == nil {
+		return nil, errors.New("nil model or nil model namespace")
+	}
+	m := &arcadiav1alpha1.Model{}
+	if err := loader.c.Get(ctx, types.NamespacedName{Namespace: *model.Namespace, Name: model.Name}, m); err != nil {
+		return nil, err
+	}
+	copied := m.GetAnnotations()[arcadiav1alpha1.ModelGitCommitAnnotation]
+	loader.copyInBackground(m)
+	if copied == "" {
+		return nil, ErrModelLoading
+	}
+
+	system, err := config.GetSystemDatasource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system datasource with %w", err)
+	}
+	endpoint := system.Spec.Endpoint.DeepCopy()
+	if endpoint.AuthSecret != nil && endpoint.AuthSecret.Namespace == nil {
+		endpoint.AuthSecret.WithNameSpace(system.Namespace)
+	}
+	l, err := NewLoaderOSS(ctx, loader.c, endpoint, loader.worker)
+	if err != nil {
+		return nil, err
+	}
+	container, err := l.Build(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	// the worker restarts to load the files when a new commit is copied
+	c, _ := container.(*corev1.Container)
+	c.Env = append(c.Env, corev1.EnvVar{Name: "GIT_COMMIT", Value: copied})
+	return c, nil
+}
+
+// copyInBackground copies the files at the resolved commit unless a copy of the model is running.
+// The head of a tracked branch is refreshed by the datasource controller periodically.
+func (loader *LoaderGit) copyInBackground(model *arcadiav1alpha1.Model) {
+	key := model.Namespace + "/" + model.Name
+	gitCopiesMutex.Lock()
+	defer gitCopiesMutex.Unlock()
+	if gitCopies[key] {
+		return
+	}
+	gitCopies[key] = true
+	go func() {
+		defer func() {
+			gitCopiesMutex.Lock()
+			delete(gitCopies, key)
+			gitCopiesMutex.Unlock()
+		}()
+		if err := loader.copy(context.Background(), model); err != nil {
+			klog.Errorf("failed to copy the files of model %s from git: %v", key, err)
+		}
+	}()
+}
+
+func (loader *LoaderGit) copy(ctx context.Context, model *arcadiav1alpha1.Model) error {
+	commit, err := loader.git.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	if model.GetAnnotations()[arcadiav1alpha1.ModelGitCommitAnnotation] == commit {
+		return nil
+	}
+	oss, err := config.GetSystemDatasourceOSS(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get system oss with %w", err)
+	}
+	exists, err := oss.Client.BucketExists(ctx, model.Namespace)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err = oss.MakeBucket(ctx, model.Namespace, minio.MakeBucketOptions{}); err != nil {
+			return err
+		}
+	}
+	if commit, err = loader.git.CopyTo(ctx, oss, model.Namespace, fmt.Sprintf("model/%s/", model.Name), ""); err != nil {
+		return err
+	}
+	// record the copied commit, which triggers the workers of this model to reconcile
+	patch := client.MergeFrom(model.DeepCopy())
+	metav1.SetMetaDataAnnotation(&model.ObjectMeta, arcadiav1alpha1.ModelGitCommitAnnotation, commit)
+	return loader.c.Patch(ctx, model, patch)
 }
 
 var _ ModelLoader = (*RDMALoader)(nil)
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+const (
+	// reportInterval to report the requests to the workers
+	reportInterval = 10 * time.Second
+	// staleReportTimeout after which a report is ignored, because the reporter refreshes its report in every interval when it has requests
+	staleReportTimeout = 3 * reportInterval
+	// WakeUpTimeout is the max duration to wait for a worker scaled to zero to be ready
+	WakeUpTimeout      = 10 * time.Minute
+	wakeUpPollInterval = 2 * time.Second
+)
+
+// RequestsReport is the requests to a worker reported by a caller process
+type RequestsReport struct {
+	ConcurrentRequests int32     `json:"concurrentRequests"`
+	LastRequestTime    time.Time `json:"lastRequestTime"`
+	ReportTime         time.Time `json:"reportTime"`
+}
+
+// reportAnnotation is the annotation of the requests reported by this process
+var reportAnnotation = arcadiav1alpha1.WorkerRequestsAnnotationPrefix + reporterID()
+
+// reporterID identifies this process by the hash of its hostname(the pod name) and pid, which is short enough for an annotation key
+func reporterID() string {
+	hostname, _ := os.Hostname()
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%s/%d", hostname, os.Getpid())
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// RequestsReports returns the requests reported by the callers in the annotations of the worker
+func RequestsReports(worker *arcadiav1alpha1.Worker) map[string]RequestsReport {
+	reports := make(map[string]RequestsReport)
+	for key, value := range worker.Annotations {
+		if !strings.HasPrefix(key, arcadiav1alpha1.WorkerRequestsAnnotationPrefix) {
+			continue
+		}
+		report := RequestsReport{}
+		if err := json.Unmarshal([]byte(value), &report); err != nil {
+			continue
+		}
+		reports[key] = report
+	}
+	return reports
+}
+
+// Requests sums the concurrent requests of the reports not stale, and returns the latest request time of all reports
+func Requests(worker *arcadiav1alpha1.Worker, now time.Time) (concurrency int32, lastRequest time.Time) {
+	for _, report := range RequestsReports(worker) {
+		if now.Sub(report.ReportTime) <= staleReportTimeout {
+			concurrency += report.ConcurrentRequests
+		}
+		if report.LastRequestTime.After(lastRequest) {
+			lastRequest = report.LastRequestTime
+		}
+	}
+	return concurrency, lastRequest
+}
+
+// requestStats of a worker in this process
+type requestStats struct {
+	// client to report the requests
+	c client.Client
+	// inflight requests now
+	inflight int32
+	// peak of the inflight requests in the current report interval
+	peak int32
+	// reported concurrent requests
+	reported    int32
+	lastRequest time.Time
+	// whether new requests come after the last report
+	dirty bool
+}
+
+// requestTracker counts the requests sent to the autoscaling workers by this process,
+// and reports them periodically into the worker's annotations to drive the autoscaling.
+// The report is refreshed in every interval while there are requests, so the report of a stopped process expires.
+type requestTracker struct {
+	mu      sync.Mutex
+	workers map[types.NamespacedName]*requestStats
+	once    sync.Once
+}
+
+var tracker = &requestTracker{workers: make(map[types.NamespacedName]*requestStats)}
+
+// TrackRequest must be called before sending a request to the worker, and the returned function must be called when the request is done.
+// A worker scaled to zero will be woken up, and this blocks until the worker is ready.
+func TrackRequest(ctx context.Context, c client.Client, worker *arcadiav1alpha1.Worker) (func(), error) {
+	if worker.Spec.Autoscaling == nil {
+		return func() {}, nil
+	}
+	tracker.once.Do(func() {
+		go tracker.run(context.Background())
+	})
+	key := types.NamespacedName{Namespace: worker.Namespace, Name: worker.Name}
+	inflight := tracker.begin(key, c)
+	done := func() { tracker.end(key) }
+	if err := wakeUp(ctx, c, key, inflight); err != nil {
+		done()
+		return nil, err
+	}
+	return done, nil
+}
+
+// WorkerOfModel returns the autoscaling worker which registers the model into the gateway, nil if not found
+func WorkerOfModel(ctx context.Context, c client.Client, model string) (*arcadiav1alpha1.Worker, error) {
+	workers := &arcadiav1alpha1.WorkerList{}
+	if err := c.List(ctx, workers); err != nil {
+		return nil, err
+	}
+	for i, worker := range workers.Items {
+		if worker.Spec.Autoscaling == nil {
+			continue
+		}
+		for _, name := range worker.RegistrationModelNames() {
+			if name == model {
+				return &workers.Items[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (t *requestTracker) begin(key types.NamespacedName, c client.Client) int32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats, ok := t.workers[key]
+	if !ok {
+		stats = &requestStats{}
+		t.workers[key] = stats
+	}
+	stats.c = c
+	stats.inflight++
+	stats.peak = max(stats.peak, stats.inflight)
+	stats.lastRequest = time.Now()
+	stats.dirty = true
+	return stats.inflight
+}
+
+func (t *requestTracker) end(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if stats, ok := t.workers[key]; ok && stats.inflight > 0 {
+		stats.inflight--
+	}
+}
+
+func (t *requestTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.report(ctx)
+		}
+	}
+}
+
+// report the peak concurrent requests in the last interval and the last request time of each worker if changed
+func (t *requestTracker) report(ctx context.Context) {
+	type report struct {
+		c           client.Client
+		concurrency int32
+		lastRequest time.Time
+	}
+	reports := make(map[types.NamespacedName]report)
+	t.mu.Lock()
+	for key, stats := range t.workers {
+		if stats.dirty || stats.peak != stats.reported || stats.peak > 0 {
+			reports[key] = report{c: stats.c, concurrency: stats.peak, lastRequest: stats.lastRequest}
+			stats.reported = stats.peak
+			stats.dirty = false
+		}
+		stats.peak = stats.inflight
+		if stats.inflight == 0 && stats.reported == 0 && !stats.dirty {
+			delete(t.workers, key)
+		}
+	}
+	t.mu.Unlock()
+
+	for key, r := range reports {
+		if err := patchRequests(ctx, r.c, key, r.concurrency, r.lastRequest); err != nil {
+			klog.Errorf("failed to report requests of worker %s: %s", key, err)
+		}
+	}
+}
+
+// patchRequests into the annotation of this process, the stale reports of other processes are removed
+func patchRequests(ctx context.Context, c client.Client, key types.NamespacedName, concurrency int32, lastRequest time.Time) error {
+	worker := &arcadiav1alpha1.Worker{}
+	if err := c.Get(ctx, key, worker); err != nil {
+		return err
+	}
+	now := time.Now()
+	report, err := json.Marshal(RequestsReport{ConcurrentRequests: concurrency, LastRequestTime: lastRequest.UTC().Truncate(time.Second), ReportTime: now.UTC().Truncate(time.Second)})
+	if err != nil {
+		return err
+	}
+	annotations := map[string]any{reportAnnotation: string(report)}
+	for annotation, r := range RequestsReports(worker) {
+		// the latest request time is kept in the status of the worker, so the stale reports can be removed
+		if annotation != reportAnnotation && now.Sub(r.ReportTime) > staleReportTimeout {
+			annotations[annotation] = nil
+		}
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, worker, client.RawPatch(types.MergePatchType, patch))
+}
+
+// wakeUp the worker if it has no ready replicas, and wait until it is ready
+func wakeUp(ctx context.Context, c client.Client, key types.NamespacedName, inflight int32) error {
+	ctx, cancel := context.WithTimeout(ctx, WakeUpTimeout)
+	defer cancel()
+	patched := false
+	for {
+		worker := &arcadiav1alpha1.Worker{}
+		if err := c.Get(ctx, key, worker); err != nil {
+			return err
+		}
+		status := worker.Status.Autoscaling
+		// not observed by the controller yet or ready
+		if status == nil || status.ReadyReplicas > 0 {
+			return nil
+		}
+		// report the request right now instead of waiting for the next report
+		if !patched {
+			klog.Infof("waking up worker %s", key)
+			if err := patchRequests(ctx, c, key, inflight, time.Now()); err != nil {
+				return fmt.Errorf("failed to wake up worker %s: %w", key, err)
+			}
+			patched = true
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("worker %s is not ready after waking up: %w", key, ctx.Err())
+		case <-time.After(wakeUpPollInterval):
+		}
+	}
+}
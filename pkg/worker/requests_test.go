@@ -0,0 +1,173 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+)
+
+func reportOf(concurrency int32, lastRequest, reportTime time.Time) string {
+	report, _ := json.Marshal(RequestsReport{ConcurrentRequests: concurrency, LastRequestTime: lastRequest, ReportTime: reportTime})
+	return string(report)
+}
+
+func TestRequests(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	worker := &arcadiav1alpha1.Worker{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		arcadiav1alpha1.WorkerRequestsAnnotationPrefix + "a": reportOf(2, now.Add(-time.Minute), now),
+		arcadiav1alpha1.WorkerRequestsAnnotationPrefix + "b": reportOf(3, now, now.Add(-5*time.Second)),
+		// the process stopped without reporting
+		arcadiav1alpha1.WorkerRequestsAnnotationPrefix + "c": reportOf(4, now.Add(-time.Minute), now.Add(-time.Minute)),
+		arcadiav1alpha1.WorkerRequestsAnnotationPrefix + "d": "invalid",
+		"other": reportOf(5, now, now),
+	}}}
+	if reports := RequestsReports(worker); len(reports) != 3 {
+		t.Errorf("expected 3 reports, but got %v", reports)
+	}
+	concurrency, lastRequest := Requests(worker, now)
+	if concurrency != 5 {
+		t.Errorf("expected the sum of the reports not stale 5, but got %d", concurrency)
+	}
+	if !lastRequest.Equal(now) {
+		t.Errorf("expected the latest request time %s, but got %s", now, lastRequest)
+	}
+}
+
+func TestRequestTracker(t *testing.T) {
+	ctx := context.Background()
+	stale := arcadiav1alpha1.WorkerRequestsAnnotationPrefix + "stale"
+	worker := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker", Annotations: map[string]string{
+			stale: reportOf(1, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)),
+		}},
+		Spec: arcadiav1alpha1.WorkerSpec{Autoscaling: &arcadiav1alpha1.WorkerAutoscaling{MaxReplicas: 2}},
+	}
+	c := newFakeClient(t, worker)
+	key := types.NamespacedName{Namespace: "default", Name: "worker"}
+	reported := func() RequestsReport {
+		w := &arcadiav1alpha1.Worker{}
+		if err := c.Get(ctx, key, w); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := w.Annotations[stale]; ok {
+			t.Error("expected the stale report removed")
+		}
+		return RequestsReports(w)[reportAnnotation]
+	}
+
+	tracker := &requestTracker{workers: make(map[types.NamespacedName]*requestStats)}
+	tracker.begin(key, c)
+	tracker.begin(key, c)
+	tracker.end(key)
+	tracker.report(ctx)
+	if r := reported(); r.ConcurrentRequests != 2 || r.LastRequestTime.IsZero() {
+		t.Errorf("expected the peak concurrent requests 2 reported, but got %+v", r)
+	}
+	// refreshed while there are inflight requests
+	first := reported().ReportTime
+	time.Sleep(time.Second)
+	tracker.report(ctx)
+	if r := reported(); r.ConcurrentRequests != 1 || !r.ReportTime.After(first) {
+		t.Errorf("expected the report refreshed with 1 request, but got %+v", r)
+	}
+	// the request done in this interval is still counted
+	tracker.end(key)
+	tracker.report(ctx)
+	if r := reported(); r.ConcurrentRequests != 1 {
+		t.Errorf("expected the peak of the interval reported, but got %+v", r)
+	}
+	tracker.report(ctx)
+	if r := reported(); r.ConcurrentRequests != 0 {
+		t.Errorf("expected no requests reported, but got %+v", r)
+	}
+	if len(tracker.workers) != 0 {
+		t.Errorf("expected the idle worker removed from the tracker, but got %v", tracker.workers)
+	}
+}
+
+func TestWorkerOfModel(t *testing.T) {
+	ctx := context.Background()
+	autoscaling := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "autoscaling", UID: "uid-a"},
+		Spec: arcadiav1alpha1.WorkerSpec{
+			Autoscaling: &arcadiav1alpha1.WorkerAutoscaling{MaxReplicas: 2},
+			Adapters:    []arcadiav1alpha1.TypedObjectReference{{Kind: "Model", Name: "lora"}},
+		},
+	}
+	fixed := &arcadiav1alpha1.Worker{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fixed", UID: "uid-b"}}
+	c := newFakeClient(t, autoscaling, fixed)
+
+	testCases := map[string]string{
+		"uid-a":      "autoscaling",
+		"uid-a-lora": "autoscaling",
+		"uid-b":      "",
+		"unknown":    "",
+	}
+	for model, expected := range testCases {
+		w, err := WorkerOfModel(ctx, c, model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := ""
+		if w != nil {
+			name = w.Name
+		}
+		if name != expected {
+			t.Errorf("%s: expected worker %q, but got %q", model, expected, name)
+		}
+	}
+}
+
+func TestWakeUp(t *testing.T) {
+	ctx := context.Background()
+	ready := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready"},
+		Spec:       arcadiav1alpha1.WorkerSpec{Autoscaling: &arcadiav1alpha1.WorkerAutoscaling{MaxReplicas: 2}},
+		Status:     arcadiav1alpha1.WorkerStatus{Autoscaling: &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 1, ReadyReplicas: 1}},
+	}
+	scaledToZero := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "zero"},
+		Spec:       arcadiav1alpha1.WorkerSpec{Autoscaling: &arcadiav1alpha1.WorkerAutoscaling{MaxReplicas: 2}},
+		Status:     arcadiav1alpha1.WorkerStatus{Autoscaling: &arcadiav1alpha1.WorkerAutoscalingStatus{Replicas: 0}},
+	}
+	c := newFakeClient(t, ready, scaledToZero)
+	if err := wakeUp(ctx, c, types.NamespacedName{Namespace: "default", Name: "ready"}, 1); err != nil {
+		t.Errorf("expected the ready worker not to wait, but got %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	key := types.NamespacedName{Namespace: "default", Name: "zero"}
+	if err := wakeUp(timeout, c, key, 1); err == nil {
+		t.Error("expected the worker scaled to zero not ready")
+	}
+	w := &arcadiav1alpha1.Worker{}
+	if err := c.Get(ctx, key, w); err != nil {
+		t.Fatal(err)
+	}
+	if r := RequestsReports(w)[reportAnnotation]; r.ConcurrentRequests != 1 {
+		t.Errorf("expected the request reported to wake up the worker, but got %+v", r)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -36,9 +37,12 @@ const (
 	// tag is the same version as fastchat
 	defaultFastChatImage = "kubeagi/arcadia-fastchat-worker:v0.2.36"
 	// For ease of maintenance and stability, VLLM module is now included in standard image as a default feature.
-	defaultFastchatVLLMImage = "kubeagi/arcadia-fastchat-worker:v0.2.36"
+	// tag is the version of fastchat and vllm, which includes the lora_vllm_worker to serve LoRA adapters
+	defaultFastchatVLLMImage = "kubeagi/arcadia-fastchat-worker:v0.2.36-vllm0.3.3"
 	// defaultKubeAGIImage for RunnerKubeAGI
 	defaultKubeAGIImage = "kubeagi/core-library-cli:v0.0.1"
+	// defaultLlamaCppImage for RunnerLlamaCpp, tag is the same version as the llama.cpp release
+	defaultLlamaCppImage = "kubeagi/arcadia-llamacpp-worker:b2781"
 
 	// mount path in runner
 	defaultModelMountPath = "/data/models"
@@ -269,6 +273,19 @@ func (runner *RunnerFastchatVLLM) Build(ctx context.Context, model *arcadiav1alp
 		}
 	}
 
+	// serve LoRA adapters which are loaded next to the model
+	workerName := "fastchat.serve.vllm_worker"
+	registrationModelName := runner.w.MakeRegistrationModelName()
+	if len(runner.w.Spec.Adapters) > 0 {
+		loraModules := make([]string, 0, len(runner.w.Spec.Adapters))
+		for _, adapter := range runner.w.Spec.Adapters {
+			loraModules = append(loraModules, fmt.Sprintf("%s=%s/%s", runner.w.MakeAdapterRegistrationModelName(adapter.Name), defaultModelMountPath, adapter.Name))
+		}
+		extraAgrs += fmt.Sprintf(" --enable-lora --lora-modules %s", strings.Join(loraModules, " "))
+		workerName = "fastchat.serve.lora_vllm_worker"
+		registrationModelName = strings.Join(runner.w.RegistrationModelNames(), ",")
+	}
+
 	additionalEnvs = append(additionalEnvs, corev1.EnvVar{Name: "FASTCHAT_MODEL_NAME_PATH", Value: modelFileDir})
 	img := defaultFastchatVLLMImage
 	if runner.w.Spec.Runner.Image != "" {
@@ -279,9 +296,9 @@ func (runner *RunnerFastchatVLLM) Build(ctx context.Context, model *arcadiav1alp
 		Image:           img,
 		ImagePullPolicy: runner.w.Spec.Runner.ImagePullPolicy,
 		Env: []corev1.EnvVar{
-			{Name: "FASTCHAT_WORKER_NAME", Value: "fastchat.serve.vllm_worker"},
+			{Name: "FASTCHAT_WORKER_NAME", Value: workerName},
 			{Name: "FASTCHAT_WORKER_NAMESPACE", Value: runner.w.Namespace},
-			{Name: "FASTCHAT_REGISTRATION_MODEL_NAME", Value: runner.w.MakeRegistrationModelName()},
+			{Name: "FASTCHAT_REGISTRATION_MODEL_NAME", Value: registrationModelName},
 			{Name: "FASTCHAT_MODEL_NAME", Value: model.Name},
 			{Name: "FASTCHAT_WORKER_ADDRESS", Value: fmt.Sprintf("http://%s.%s:%d", runner.w.Name+WokerCommonSuffix, runner.w.Namespace, arcadiav1alpha1.DefaultWorkerPort)},
 			{Name: "FASTCHAT_CONTROLLER_ADDRESS", Value: gw.Controller},
@@ -383,3 +400,114 @@ func (runner *KubeAGIRunner) Build(ctx context.Context, model *arcadiav1alpha1.T
 
 	return container, nil
 }
+
+var _ ModelRunner = (*RunnerLlamaCpp)(nil)
+
+// RunnerLlamaCpp use llama.cpp to run GGUF models on CPUs.
+// The llama.cpp server is bridged by a fastchat model worker, so it registers into the gateway like other runners.
+type RunnerLlamaCpp struct {
+	c client.Client
+	w *arcadiav1alpha1.Worker
+
+	modelFileFromRemote bool
+}
+
+func NewRunnerLlamaCpp(c client.Client, w *arcadiav1alpha1.Worker, modelFileFromRemote bool) (ModelRunner, error) {
+	return &RunnerLlamaCpp{
+		c: c,
+		w: w,
+
+		modelFileFromRemote: modelFileFromRemote,
+	}, nil
+}
+
+// Device used by this runner which is always cpu
+func (runner *RunnerLlamaCpp) Device() Device {
+	return CPU
+}
+
+// NumberOfGPUs utilized by this runner
+func (runner *RunnerLlamaCpp) NumberOfGPUs() string {
+	return "0"
+}
+
+// NumberOfThreads used to generate tokens, which is the cpu limits(or requests) rounded up
+func (runner *RunnerLlamaCpp) NumberOfThreads() int64 {
+	cpu, ok := runner.w.Spec.Resources.Limits[corev1.ResourceCPU]
+	if !ok {
+		cpu, ok = runner.w.Spec.Resources.Requests[corev1.ResourceCPU]
+	}
+	if !ok {
+		return 0
+	}
+	return cpu.Value()
+}
+
+// Build a runner instance
+func (runner *RunnerLlamaCpp) Build(ctx context.Context, model *arcadiav1alpha1.TypedObjectReference) (any, error) {
+	if model == nil {
+		return nil, errors.New("nil model")
+	}
+	if runner.modelFileFromRemote {
+		return nil, errors.New("llama.cpp runner only supports the GGUF model files from datasource")
+	}
+	gw, err := config.GetGateway(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get arcadia config with %w", err)
+	}
+	m := arcadiav1alpha1.Model{}
+	if err := runner.c.Get(ctx, types.NamespacedName{Namespace: *model.Namespace, Name: model.Name}, &m); err != nil {
+		return nil, err
+	}
+
+	extraAgrs := ""
+	quantization := ""
+	for _, envItem := range runner.w.Spec.AdditionalEnvs {
+		// quantization type of the GGUF model file to load, like Q4_K_M.
+		// The first GGUF file will be loaded if not set.
+		if envItem.Name == "QUANTIZATION" {
+			quantization = envItem.Value
+		}
+		// extra arguments to run llama.cpp server
+		if envItem.Name == "EXTRA_ARGS" {
+			extraAgrs = envItem.Value
+		}
+	}
+	if threads := runner.NumberOfThreads(); threads > 0 {
+		extraAgrs += fmt.Sprintf(" --threads %d", threads)
+	}
+	if m.Spec.MaxContextLength > 0 {
+		extraAgrs += fmt.Sprintf(" --ctx-size %d", m.Spec.MaxContextLength)
+	}
+	if m.IsEmbeddingModel() {
+		extraAgrs += " --embedding"
+	}
+
+	img := defaultLlamaCppImage
+	if runner.w.Spec.Runner.Image != "" {
+		img = runner.w.Spec.Runner.Image
+	}
+	container := &corev1.Container{
+		Name:            "runner",
+		Image:           img,
+		ImagePullPolicy: runner.w.Spec.Runner.ImagePullPolicy,
+		Env: []corev1.EnvVar{
+			{Name: "FASTCHAT_WORKER_NAMESPACE", Value: runner.w.Namespace},
+			{Name: "FASTCHAT_REGISTRATION_MODEL_NAME", Value: runner.w.MakeRegistrationModelName()},
+			{Name: "FASTCHAT_MODEL_NAME", Value: model.Name},
+			{Name: "FASTCHAT_MODEL_NAME_PATH", Value: fmt.Sprintf("%s/%s", defaultModelMountPath, model.Name)},
+			{Name: "FASTCHAT_WORKER_ADDRESS", Value: fmt.Sprintf("http://%s.%s:%d", runner.w.Name+WokerCommonSuffix, runner.w.Namespace, arcadiav1alpha1.DefaultWorkerPort)},
+			{Name: "FASTCHAT_CONTROLLER_ADDRESS", Value: gw.Controller},
+			{Name: "LLAMACPP_QUANTIZATION", Value: quantization},
+			{Name: "EXTRA_ARGS", Value: strings.TrimSpace(extraAgrs)},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "http", ContainerPort: arcadiav1alpha1.DefaultWorkerPort},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "models", MountPath: defaultModelMountPath},
+		},
+		Resources: runner.w.Spec.Resources,
+	}
+	return container, nil
+}
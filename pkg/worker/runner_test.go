@@ -0,0 +1,253 @@
+/*
+Copyright 2024 KubeAGI.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/config"
+	"github.com/kubeagi/arcadia/pkg/utils"
+)
+
+// newFakeClient returns a client with the system config and the objects, which is also used as the system client
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Setenv(utils.EnvNamespaceKey, "arcadia")
+	t.Setenv(config.EnvConfigKey, config.EnvConfigDefaultValue)
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := arcadiav1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "arcadia", Name: config.EnvConfigDefaultValue},
+		Data:       map[string]string{"config": "gateway:\n  controller: http://fastchat-controller:21001\n"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, cm)...).Build()
+	config.InitSystemClient(c)
+	t.Cleanup(func() { config.InitSystemClient(nil) })
+	return c
+}
+
+func envValue(container *corev1.Container, name string) string {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+func TestRunnerLlamaCpp(t *testing.T) {
+	ctx := context.Background()
+	namespace := "default"
+	model := &arcadiav1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "qwen-gguf"},
+		Spec:       arcadiav1alpha1.ModelSpec{Types: "llm", MaxContextLength: 4096},
+	}
+	embedding := &arcadiav1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "bge-gguf"},
+		Spec:       arcadiav1alpha1.ModelSpec{Types: "embedding"},
+	}
+	c := newFakeClient(t, model, embedding)
+	w := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "qwen"},
+		Spec: arcadiav1alpha1.WorkerSpec{
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3500m")},
+			},
+			AdditionalEnvs: []corev1.EnvVar{
+				{Name: "QUANTIZATION", Value: "Q4_K_M"},
+				{Name: "EXTRA_ARGS", Value: "--parallel 2"},
+			},
+		},
+	}
+
+	runner, err := NewRunnerLlamaCpp(c, w, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runner.Device() != CPU || runner.NumberOfGPUs() != "0" {
+		t.Errorf("expected to run on cpu, but got %s with %s gpus", runner.Device(), runner.NumberOfGPUs())
+	}
+	if threads := runner.(*RunnerLlamaCpp).NumberOfThreads(); threads != 4 {
+		t.Errorf("expected 4 threads, but got %d", threads)
+	}
+
+	obj, err := runner.Build(ctx, &arcadiav1alpha1.TypedObjectReference{Namespace: &namespace, Name: "qwen-gguf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := obj.(*corev1.Container)
+	if container.Image != defaultLlamaCppImage {
+		t.Errorf("expected the default image %s, but got %s", defaultLlamaCppImage, container.Image)
+	}
+	if args := envValue(container, "EXTRA_ARGS"); args != "--parallel 2 --threads 4 --ctx-size 4096" {
+		t.Errorf("unexpected extra args %q", args)
+	}
+	if quantization := envValue(container, "LLAMACPP_QUANTIZATION"); quantization != "Q4_K_M" {
+		t.Errorf("unexpected quantization %q", quantization)
+	}
+	if controller := envValue(container, "FASTCHAT_CONTROLLER_ADDRESS"); controller != "http://fastchat-controller:21001" {
+		t.Errorf("unexpected controller address %q", controller)
+	}
+	if path := envValue(container, "FASTCHAT_MODEL_NAME_PATH"); path != defaultModelMountPath+"/qwen-gguf" {
+		t.Errorf("unexpected model path %q", path)
+	}
+
+	w.Spec.Runner.Image = "kubeagi/arcadia-llamacpp-worker:custom"
+	w.Spec.AdditionalEnvs = nil
+	w.Spec.Resources = corev1.ResourceRequirements{}
+	obj, err = runner.Build(ctx, &arcadiav1alpha1.TypedObjectReference{Namespace: &namespace, Name: "bge-gguf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container = obj.(*corev1.Container)
+	if container.Image != "kubeagi/arcadia-llamacpp-worker:custom" {
+		t.Errorf("expected the image of the worker, but got %s", container.Image)
+	}
+	if args := envValue(container, "EXTRA_ARGS"); args != "--embedding" {
+		t.Errorf("expected only the embedding arg, but got %q", args)
+	}
+
+	if _, err := runner.Build(ctx, nil); err == nil {
+		t.Error("expected an error without the model")
+	}
+	remote, _ := NewRunnerLlamaCpp(c, w, true)
+	if _, err := remote.Build(ctx, &arcadiav1alpha1.TypedObjectReference{Namespace: &namespace, Name: "qwen-gguf"}); err == nil {
+		t.Error("expected an error for the model files from remote")
+	}
+}
+
+func TestRunnerFastchatVLLMAdapters(t *testing.T) {
+	ctx := context.Background()
+	namespace := "default"
+	c := newFakeClient(t)
+	w := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "qwen", UID: "uid"},
+		Spec: arcadiav1alpha1.WorkerSpec{
+			Type:     arcadiav1alpha1.WorkerTypeFastchatVLLM,
+			Adapters: []arcadiav1alpha1.TypedObjectReference{{Kind: "Model", Name: "sql-lora"}, {Kind: "Model", Name: "chat-lora"}},
+		},
+	}
+	runner, err := NewRunnerFastchatVLLM(c, w, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := runner.Build(ctx, &arcadiav1alpha1.TypedObjectReference{Namespace: &namespace, Name: "qwen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := obj.(*corev1.Container)
+	if container.Image != defaultFastchatVLLMImage {
+		t.Errorf("expected the default image %s, but got %s", defaultFastchatVLLMImage, container.Image)
+	}
+	if name := envValue(container, "FASTCHAT_WORKER_NAME"); name != "fastchat.serve.lora_vllm_worker" {
+		t.Errorf("expected the lora vllm worker, but got %s", name)
+	}
+	if args := envValue(container, "EXTRA_ARGS"); !strings.HasSuffix(args, " --enable-lora --lora-modules uid-sql-lora=/data/models/sql-lora uid-chat-lora=/data/models/chat-lora") {
+		t.Errorf("expected the adapters in the args, but got %q", args)
+	}
+	if names := envValue(container, "FASTCHAT_REGISTRATION_MODEL_NAME"); names != "uid,uid-sql-lora,uid-chat-lora" {
+		t.Errorf("expected the model names of the adapters registered, but got %q", names)
+	}
+	if models := w.BuildLLM().Spec.Models; strings.Join(models, ",") != "uid,uid-sql-lora,uid-chat-lora" {
+		t.Errorf("expected the models of the adapters provided by the llm, but got %v", models)
+	}
+
+	w.Spec.Adapters = nil
+	obj, err = runner.Build(ctx, &arcadiav1alpha1.TypedObjectReference{Namespace: &namespace, Name: "qwen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container = obj.(*corev1.Container)
+	if name := envValue(container, "FASTCHAT_WORKER_NAME"); name != "fastchat.serve.vllm_worker" {
+		t.Errorf("expected the vllm worker without adapters, but got %s", name)
+	}
+	if args := envValue(container, "EXTRA_ARGS"); strings.Contains(args, "--enable-lora") {
+		t.Errorf("expected no lora args without adapters, but got %q", args)
+	}
+}
+
+type fakeLoader struct{}
+
+func (fakeLoader) Build(_ context.Context, model *arcadiav1alpha1.TypedObjectReference) (any, error) {
+	return &corev1.Container{Name: "loader", Args: []string{model.Name}}, nil
+}
+
+func TestBuildAdapterLoaders(t *testing.T) {
+	ctx := context.Background()
+	local := &arcadiav1alpha1.Model{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sql-lora"}}
+	remote := &arcadiav1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "remote-lora"},
+		Spec:       arcadiav1alpha1.ModelSpec{ModelSource: modelSourceFromHugginfFace, HuggingFaceRepo: "user/remote-lora"},
+	}
+	c := newFakeClient(t, local, remote)
+	w := &arcadiav1alpha1.Worker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen"},
+		Spec: arcadiav1alpha1.WorkerSpec{
+			Type:     arcadiav1alpha1.WorkerTypeFastchatVLLM,
+			Adapters: []arcadiav1alpha1.TypedObjectReference{{Kind: "Model", Name: "sql-lora"}},
+		},
+	}
+	podWorker := &PodWorker{c: c, w: w, l: fakeLoader{}}
+
+	loaders, err := podWorker.buildAdapterLoaders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaders) != 1 || loaders[0].Name != "loader-adapter-0" || loaders[0].Args[0] != "sql-lora" {
+		t.Errorf("unexpected adapter loaders %+v", loaders)
+	}
+
+	w.Spec.Adapters = append(w.Spec.Adapters, arcadiav1alpha1.TypedObjectReference{Kind: "Model", Name: "remote-lora"})
+	if _, err := podWorker.buildAdapterLoaders(ctx); err == nil || !strings.Contains(err.Error(), "remote-lora") {
+		t.Errorf("expected the adapter from remote rejected, but got %v", err)
+	}
+
+	// the adapters of a model from git are not loaded from the model's repository
+	gitSource := &arcadiav1alpha1.Datasource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "git"}}
+	gitSource.Spec.Git = &arcadiav1alpha1.Git{}
+	gitLora := &arcadiav1alpha1.Model{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "git-lora"}}
+	gitLora.Spec.Source = &arcadiav1alpha1.TypedObjectReference{Kind: "Datasource", Name: "git"}
+	gitWorker := &PodWorker{c: newFakeClient(t, gitSource, gitLora), w: w.DeepCopy(), l: &LoaderGit{}}
+	gitWorker.w.Spec.Adapters = []arcadiav1alpha1.TypedObjectReference{{Kind: "Model", Name: "git-lora"}}
+	if _, err := gitWorker.buildAdapterLoaders(ctx); err == nil || !strings.Contains(err.Error(), "must be stored in a oss datasource") {
+		t.Errorf("expected the adapter from git rejected, but got %v", err)
+	}
+
+	w.Spec.Type = arcadiav1alpha1.WorkerTypeFastchatNormal
+	if _, err := podWorker.buildAdapterLoaders(ctx); err == nil {
+		t.Error("expected the adapters rejected by the fastchat worker")
+	}
+
+	w.Spec.Adapters = nil
+	if loaders, err := podWorker.buildAdapterLoaders(ctx); err != nil || loaders != nil {
+		t.Errorf("expected no loaders without adapters, but got %v %v", loaders, err)
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	arcadiav1alpha1 "github.com/kubeagi/arcadia/api/base/v1alpha1"
+	"github.com/kubeagi/arcadia/pkg/config"
 )
 
 const (
@@ -204,9 +205,9 @@ func NewPodWorker(ctx context.Context, c client.Client, s *runtime.Scheme, w *ar
 		},
 	}
 
-	// set the worker replicas
-	if w.Spec.Replicas != nil {
-		deployment.Spec.Replicas = w.Spec.Replicas
+	// set the worker replicas(decided by autoscaling if configured)
+	if replicas := w.DesiredReplicas(); replicas != nil {
+		deployment.Spec.Replicas = replicas
 	}
 
 	podWorker.storage = storage
@@ -228,6 +229,12 @@ func NewPodWorker(ctx context.Context, c client.Client, s *runtime.Scheme, w *ar
 	case arcadiav1alpha1.DatasourceTypeRDMA:
 		l := NewRDMALoader(c, w.Spec.Model.Name, string(w.GetUID()), d, w)
 		podWorker.l = l
+	case arcadiav1alpha1.DatasourceTypeGit:
+		l, err := NewLoaderGit(ctx, c, d, w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to new a loader with %w", err)
+		}
+		podWorker.l = l
 	default:
 		return nil, fmt.Errorf("datasource %s with type %s not supported in worker", d.Name, d.Spec.Type())
 	}
@@ -374,6 +381,69 @@ func (podWorker *PodWorker) BeforeStart(ctx context.Context) error {
 	return nil
 }
 
+// buildAdapterLoaders builds a loader for each LoRA adapter of this worker.
+// Adapters must be loaded from datasource because the runner refers them by local path.
+func (podWorker *PodWorker) buildAdapterLoaders(ctx context.Context) ([]corev1.Container, error) {
+	if len(podWorker.w.Spec.Adapters) == 0 {
+		return nil, nil
+	}
+	if podWorker.w.Type() != arcadiav1alpha1.WorkerTypeFastchatVLLM {
+		return nil, fmt.Errorf("adapters are not supported by worker type %s", podWorker.w.Type())
+	}
+	loaders := make([]corev1.Container, 0, len(podWorker.w.Spec.Adapters))
+	for i := range podWorker.w.Spec.Adapters {
+		adapterRef := podWorker.w.Adapter(i)
+		adapter := &arcadiav1alpha1.Model{}
+		if err := podWorker.c.Get(ctx, types.NamespacedName{Namespace: *adapterRef.Namespace, Name: adapterRef.Name}, adapter); err != nil {
+			return nil, err
+		}
+		if adapter.Spec.ModelSource != "" && adapter.Spec.ModelSource != modelSourceFromLocal && !adapter.IsImported() {
+			return nil, fmt.Errorf("adapter %s must be uploaded or imported into datasource", adapter.Name)
+		}
+		l, err := podWorker.adapterLoader(ctx, adapter)
+		if err != nil {
+			return nil, err
+		}
+		loader, err := l.Build(ctx, &adapterRef)
+		if err != nil {
+			return nil, err
+		}
+		conLoader, _ := loader.(*corev1.Container)
+		conLoader.Name = fmt.Sprintf("loader-adapter-%d", i)
+		loaders = append(loaders, *conLoader)
+	}
+	return loaders, nil
+}
+
+// adapterLoader returns the loader of the adapter, which is the model's loader unless the model is from git.
+// The git loader copies the model's repository, so the adapters are loaded from the oss datasource which stores them.
+func (podWorker *PodWorker) adapterLoader(ctx context.Context, adapter *arcadiav1alpha1.Model) (ModelLoader, error) {
+	if _, ok := podWorker.l.(*LoaderGit); !ok {
+		return podWorker.l, nil
+	}
+	var (
+		d   = &arcadiav1alpha1.Datasource{}
+		err error
+	)
+	if adapter.Spec.Source != nil {
+		if err = podWorker.c.Get(ctx, types.NamespacedName{Namespace: adapter.Namespace, Name: adapter.Spec.Source.Name}, d); err != nil {
+			return nil, err
+		}
+	} else {
+		if d, err = config.GetSystemDatasource(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get system datasource with %w", err)
+		}
+	}
+	if d.Spec.Type() != arcadiav1alpha1.DatasourceTypeOSS {
+		return nil, fmt.Errorf("adapter %s must be stored in a oss datasource, but got %s", adapter.Name, d.Spec.Type())
+	}
+	endpoint := d.Spec.Endpoint.DeepCopy()
+	if endpoint.AuthSecret != nil && endpoint.AuthSecret.Namespace == nil {
+		endpoint.AuthSecret.WithNameSpace(d.Namespace)
+	}
+	return NewLoaderOSS(ctx, podWorker.c, endpoint, podWorker.w)
+}
+
 // Start will build and create worker pod which will host model service
 func (podWorker *PodWorker) Start(ctx context.Context) error {
 	var (
@@ -382,13 +452,20 @@ func (podWorker *PodWorker) Start(ctx context.Context) error {
 	)
 
 	// define the way to load model
-	if podWorker.m.Spec.ModelSource == "" || podWorker.m.Spec.ModelSource == modelSourceFromLocal {
+	// the model files imported from model hubs are loaded from datasource as well
+	if podWorker.m.Spec.ModelSource == "" || podWorker.m.Spec.ModelSource == modelSourceFromLocal || podWorker.m.IsImported() {
 		loader, err = podWorker.l.Build(ctx, &arcadiav1alpha1.TypedObjectReference{Namespace: &podWorker.m.Namespace, Name: podWorker.m.Name})
 		if err != nil {
 			return fmt.Errorf("failed to build loader with %w", err)
 		}
 	}
 
+	// load the LoRA adapters next to the model
+	adapterLoaders, err := podWorker.buildAdapterLoaders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to build adapter loaders with %w", err)
+	}
+
 	switch podWorker.w.Type() {
 	case arcadiav1alpha1.WorkerTypeFastchatVLLM:
 		r, err := NewRunnerFastchatVLLM(podWorker.c, podWorker.w.DeepCopy(), loader == nil)
@@ -408,6 +485,12 @@ func (podWorker *PodWorker) Start(ctx context.Context) error {
 			return fmt.Errorf("failed to new a runner with %w", err)
 		}
 		podWorker.r = r
+	case arcadiav1alpha1.WorkerTypeLlamaCpp:
+		r, err := NewRunnerLlamaCpp(podWorker.c, podWorker.w.DeepCopy(), loader == nil)
+		if err != nil {
+			return fmt.Errorf("failed to new a runner with %w", err)
+		}
+		podWorker.r = r
 	default:
 		return fmt.Errorf("worker %s with type %s not supported in worker", podWorker.w.Name, podWorker.w.Type())
 	}
@@ -450,6 +533,7 @@ func (podWorker *PodWorker) Start(ctx context.Context) error {
 		conLoader, _ := loader.(*corev1.Container)
 		podSpecTemplate.Spec.InitContainers = []corev1.Container{*conLoader}
 	}
+	podSpecTemplate.Spec.InitContainers = append(podSpecTemplate.Spec.InitContainers, adapterLoaders...)
 	if podWorker.storage.HostPath != nil {
 		podSpecTemplate.Spec.Affinity = &corev1.Affinity{
 			NodeAffinity: &corev1.NodeAffinity{
@@ -545,6 +629,7 @@ func MakeMergedDeployment(target *appsv1.Deployment, desired *appsv1.Deployment)
 
 // Actions to do after start this worker
 func (podWorker *PodWorker) AfterStart(ctx context.Context) error {
+	prevReason := podWorker.w.Status.GetCondition(arcadiav1alpha1.TypeReady).Reason
 	// get worker's latest state
 	status, err := podWorker.State(ctx)
 	if err != nil {
@@ -578,7 +663,7 @@ func (podWorker *PodWorker) AfterStart(ctx context.Context) error {
 		podWorker.Worker().Status.SetConditions(podWorker.Worker().PendingCondition())
 	case corev1.PodUnknown:
 		// If pod is unknown and replicas is zero,then this must be offline
-		if *podWorker.w.Spec.Replicas == 0 {
+		if replicas := podWorker.w.DesiredReplicas(); replicas != nil && *replicas == 0 {
 			podWorker.Worker().Status.SetConditions(podWorker.Worker().OfflineCondition())
 		} else {
 			podWorker.Worker().Status.SetConditions(podWorker.Worker().PendingCondition())
@@ -589,6 +674,37 @@ func (podWorker *PodWorker) AfterStart(ctx context.Context) error {
 
 	podWorker.Worker().Status.PodStatus = *podStatus
 
+	if podWorker.w.Status.Autoscaling != nil {
+		if err := podWorker.updateAutoscalingStatus(ctx, prevReason); err != nil {
+			return errors.Wrap(err, "Failed to update autoscaling status")
+		}
+	}
+
+	return nil
+}
+
+// updateAutoscalingStatus observes the ready replicas, and keeps the worker ready when it is scaled to zero or being woken up
+func (podWorker *PodWorker) updateAutoscalingStatus(ctx context.Context, prevReason arcadiav1alpha1.ConditionReason) error {
+	dep := &appsv1.Deployment{}
+	err := podWorker.c.Get(ctx, types.NamespacedName{Namespace: podWorker.Namespace, Name: podWorker.SuffixedName()}, dep)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	status := podWorker.w.Status.Autoscaling
+	status.ReadyReplicas = dep.Status.ReadyReplicas
+	status.ScaleState = scaleStateOf(status)
+
+	switch status.ScaleState {
+	case arcadiav1alpha1.WorkerScaleStateScaledToZero:
+		podWorker.w.Status.SetConditions(podWorker.w.ScaledCondition(status.ScaleState))
+	case arcadiav1alpha1.WorkerScaleStateWaking:
+		// only when woken up from zero, a new worker is pending as usual
+		// and errors are still reported
+		wokenUp := prevReason == arcadiav1alpha1.ConditionReason(arcadiav1alpha1.WorkerScaleStateScaledToZero) || prevReason == arcadiav1alpha1.ConditionReason(arcadiav1alpha1.WorkerScaleStateWaking)
+		if currReason := podWorker.w.Status.GetCondition(arcadiav1alpha1.TypeReady).Reason; wokenUp && (currReason == "Pending" || currReason == "Offline") {
+			podWorker.w.Status.SetConditions(podWorker.w.ScaledCondition(status.ScaleState))
+		}
+	}
 	return nil
 }
 
@@ -615,12 +731,18 @@ func (podWorker *PodWorker) State(ctx context.Context) (any, error) {
 		return nil, err
 	}
 
-	if len(podList.Items) != 1 {
+	if len(podList.Items) == 0 || (len(podList.Items) > 1 && podWorker.w.Spec.Autoscaling == nil) {
 		return &corev1.PodStatus{
 			Phase:   corev1.PodUnknown,
 			Message: fmt.Sprintf("Expected one pod but got %d", len(podList.Items)),
 		}, nil
 	}
 
+	// an autoscaling worker may have multiple pods, prefer the running one
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			return &pod.Status, nil
+		}
+	}
 	return &podList.Items[0].Status, nil
 }